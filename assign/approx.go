@@ -0,0 +1,225 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// ApproxOptions holds the options for AssignApprox that are not in
+// AssignOptions.
+type ApproxOptions struct {
+	// Seed initializes the random source that orders the sources on every
+	// restart after the first one. Results are the same for the same seed,
+	// inputs and options, unless TimeLimit cuts the search short.
+	Seed int64
+
+	// Restarts is the number of times the search starts over from a new
+	// greedy assignment, taking the sources in random order. Zero means
+	// a single start, taking the sources in the order given.
+	Restarts int
+
+	// Rounds limits the improvement rounds after each start, in which any
+	// two pairs exchange their targets if that lowers their cost. It
+	// defaults to 10, and negative values run rounds until none improves.
+	Rounds int
+
+	// TimeLimit, if not zero, stops the search once that much time went
+	// by, keeping the best assignment found so far.
+	TimeLimit time.Duration
+}
+
+// ApproxResult holds the pairs found by AssignApprox along with a bound
+// on how far from optimal they may be.
+type ApproxResult struct {
+	Pairs []Pair
+
+	// Cost is the total cost of the assignment found, and LowerBound is
+	// at most the total cost of the optimal one, so the optimal cost lies
+	// between them. The bound is the sum of dual variables found by
+	// subtracting the lowest cost of every row and then of every column
+	// of the cost matrix, or the other way around, whichever is higher.
+	Cost       Cost
+	LowerBound Cost
+}
+
+// AssignApprox is similar to Assign, but instead of the optimal solution it
+// returns the best one found by a search that is much faster on large
+// inputs. Every start assigns each source in turn to its cheapest target
+// still available, and then improves that by exchanging targets between
+// pairs. Costs are in terms of the cost matrix even when maximizing, as
+// with AssignDetailed. The approx options may be nil.
+//
+// AssignApprox panics if the MustPair or TieBreak options are set.
+func AssignApprox(sources, targets []any, options *AssignOptions, approx *ApproxOptions) *ApproxResult {
+	if err := options.Validate(); err != nil {
+		panic("assign: invalid options: " + err.Error())
+	}
+	options = options.withDefaults()
+	if options.MustPair != nil || options.TieBreak != nil {
+		panic("assign: AssignApprox does not support the MustPair and TieBreak options")
+	}
+	if approx == nil {
+		approx = &ApproxOptions{}
+	}
+	rounds := approx.Rounds
+	if rounds == 0 {
+		rounds = 10
+	}
+
+	start := time.Now()
+	expired := func() bool {
+		return approx.TimeLimit != 0 && time.Since(start) >= approx.TimeLimit
+	}
+	editCost := options.editCostFunc()
+	if options.Maximize {
+		score := editCost
+		editCost = func(source, target any) Cost {
+			return options.SubCost(options.MaxCost, score(source, target))
+		}
+	}
+
+	buffers := &squareBuffers{}
+	pairCost, splitPairs := options.infeasibleCost(sources, targets, editCost)
+	costs, err := squareCosts(context.Background(), sources, targets, pairCost, options, buffers)
+	if err != nil {
+		panic("assign: internal error: " + err.Error())
+	}
+	size := len(costs)
+
+	rnd := rand.New(rand.NewSource(approx.Seed))
+	order := make([]int, size)
+	for i := range order {
+		order[i] = i
+	}
+	var best []int
+	var bestTotal objective
+	for restart := 0; restart <= approx.Restarts; restart++ {
+		if restart > 0 {
+			if expired() {
+				break
+			}
+			rnd.Shuffle(size, func(a, b int) { order[a], order[b] = order[b], order[a] })
+		}
+		assigned := greedyAssign(costs, order)
+		for round := 0; rounds < 0 || round < rounds; round++ {
+			if !exchangeTargets(costs, assigned, options) || expired() {
+				break
+			}
+		}
+		total := newObjective(options)
+		for i, j := range assigned {
+			total.add(costs[i][j])
+		}
+		if best == nil || total.less(bestTotal) {
+			best, bestTotal = assigned, total
+		}
+	}
+
+	// squarePairs takes the source assigned to each target.
+	optimal := make([]int, size)
+	cost := options.MinCost
+	for i, j := range best {
+		optimal[j] = i
+		cost = options.AddCost(cost, costs[i][j])
+	}
+	pairs := splitPairs(squarePairs(sources, targets, costs, optimal, options))
+	options.finishPairs(pairs, start)
+	return &ApproxResult{
+		Pairs:      pairs,
+		Cost:       cost,
+		LowerBound: lowerBound(costs, options),
+	}
+}
+
+// greedyAssign returns the target assigned to every source when taking
+// the sources in the given order, each with its cheapest available target.
+func greedyAssign(costs [][]Cost, order []int) []int {
+	size := len(costs)
+	assigned := make([]int, size)
+	used := make([]bool, size)
+	for _, i := range order {
+		best := -1
+		for j := 0; j < size; j++ {
+			if !used[j] && (best < 0 || costs[i][j].Less(costs[i][best])) {
+				best = j
+			}
+		}
+		used[best] = true
+		assigned[i] = best
+	}
+	return assigned
+}
+
+// exchangeTargets exchanges the targets assigned to any two sources when
+// that lowers their total cost, and reports whether any were exchanged.
+func exchangeTargets(costs [][]Cost, assigned []int, options *AssignOptions) bool {
+	improved := false
+	for i := range assigned {
+		for k := i + 1; k < len(assigned); k++ {
+			before := newObjective(options)
+			before.add(costs[i][assigned[i]])
+			before.add(costs[k][assigned[k]])
+			after := newObjective(options)
+			after.add(costs[i][assigned[k]])
+			after.add(costs[k][assigned[i]])
+			if after.less(before) {
+				assigned[i], assigned[k] = assigned[k], assigned[i]
+				improved = true
+			}
+		}
+	}
+	return improved
+}
+
+// lowerBound returns a lower bound on the total cost of any assignment in
+// the square cost matrix. Any potentials whose sum never exceeds the cost
+// of pairing their nodes bound it, and reducing the rows and then the
+// columns of the matrix, or the other way around, yields such potentials.
+func lowerBound(costs [][]Cost, options *AssignOptions) Cost {
+	size := len(costs)
+	reduce := func(cost func(i, j int) Cost) Cost {
+		bound := options.MinCost
+		first := make([]Cost, size)
+		for i := range first {
+			first[i] = cost(i, 0)
+			for j := 1; j < size; j++ {
+				if c := cost(i, j); c.Less(first[i]) {
+					first[i] = c
+				}
+			}
+			bound = options.AddCost(bound, first[i])
+		}
+		for j := 0; j < size; j++ {
+			second := options.SubCost(cost(0, j), first[0])
+			for i := 1; i < size; i++ {
+				if c := options.SubCost(cost(i, j), first[i]); c.Less(second) {
+					second = c
+				}
+			}
+			bound = options.AddCost(bound, second)
+		}
+		return bound
+	}
+	rowsFirst := reduce(func(i, j int) Cost { return costs[i][j] })
+	columnsFirst := reduce(func(j, i int) Cost { return costs[i][j] })
+	if rowsFirst.Less(columnsFirst) {
+		return columnsFirst
+	}
+	return rowsFirst
+}
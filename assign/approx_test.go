@@ -0,0 +1,84 @@
+package assign_test
+
+import (
+	"math/rand"
+
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestAssignApprox(c *C) {
+	costs := map[[2]any]assign.IntCost{
+		{"a", "x"}: 1, {"a", "y"}: 2,
+		{"b", "x"}: 2, {"b", "y"}: 100,
+	}
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			if cost, ok := costs[[2]any{source, target}]; ok {
+				return cost
+			}
+			return assign.IntCost(200)
+		},
+	}
+	sources := []any{"a", "b"}
+	targets := []any{"x", "y"}
+
+	// Assigning a to x first is greedy, and exchanging targets fixes it.
+	result := assign.AssignApprox(sources, targets, options, nil)
+	c.Assert(result.Pairs, DeepEquals, []assign.Pair{
+		{Source: "b", Target: "x", SourceIndex: 1, TargetIndex: 0, Cost: assign.IntCost(2), Op: assign.Update},
+		{Source: "a", Target: "y", SourceIndex: 0, TargetIndex: 1, Cost: assign.IntCost(2), Op: assign.Update},
+	})
+	c.Assert(result.Cost, Equals, assign.IntCost(4))
+	// Reducing the rows yields 1 and 2, and then the column of y yields 1.
+	c.Assert(result.LowerBound, Equals, assign.IntCost(4))
+
+	c.Assert(func() {
+		assign.AssignApprox(sources, targets, &assign.AssignOptions{
+			EditCost: options.EditCost,
+			TieBreak: func(a, b any) bool { return a.(string) < b.(string) },
+		}, nil)
+	}, PanicMatches, "assign: AssignApprox does not support the MustPair and TieBreak options")
+}
+
+func (*S) TestAssignApproxRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		n := rnd.Intn(7)
+		m := rnd.Intn(7)
+		p := newProblem(n, m, func() assign.IntCost {
+			return assign.IntCost(rnd.Intn(10))
+		})
+		options := &assign.AssignOptions{
+			EditCost: func(source, target any) assign.Cost {
+				return p.edits[[2]any{source, target}]
+			},
+		}
+		approx := &assign.ApproxOptions{Seed: int64(i), Restarts: i % 4, Rounds: i%3 - 1}
+		c.Logf("Test %d: %d×%d, %+v", i, n, m, *approx)
+
+		result := assign.AssignApprox(p.sources, p.targets, options, approx)
+		seen := make(map[any]bool)
+		var sum assign.IntCost
+		for _, pair := range result.Pairs {
+			for _, node := range []any{pair.Source, pair.Target} {
+				if node != nil {
+					c.Assert(seen[node], Equals, false)
+					seen[node] = true
+				}
+			}
+			c.Assert(pair.Cost, Equals, p.edits[[2]any{pair.Source, pair.Target}])
+			sum += pair.Cost.(assign.IntCost)
+		}
+		c.Assert(seen, HasLen, n+m)
+		c.Assert(result.Cost, Equals, sum)
+
+		_, optimal := p.bruteForce()
+		c.Assert(result.LowerBound.(assign.IntCost) <= optimal, Equals, true)
+		c.Assert(optimal <= sum, Equals, true)
+
+		// The same seed yields the same result.
+		c.Assert(assign.AssignApprox(p.sources, p.targets, options, approx), DeepEquals, result)
+	}
+}
@@ -17,18 +17,139 @@
 
 package assign
 
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/canonical/go-algo/costcache"
+)
+
 type Pair struct {
 	Source any
 	Target any
-	Cost   Cost
+
+	// SourceIndex and TargetIndex hold the position of Source and Target
+	// in the provided slices, or -1 for insertions and deletions
+	// respectively, so that nodes equal to each other can be told apart.
+	SourceIndex int
+	TargetIndex int
+
+	Cost Cost
+	Op   Op
 }
 
+// Op classifies the edit represented by a Pair.
+type Op int
+
+const (
+	// Keep pairs a source with a target at MinCost, or with a target
+	// reported equal to it by the Equal option when that is set.
+	Keep Op = iota + 1
+	// Update pairs a source with a target above MinCost.
+	Update
+	// Insert pairs a target with nil.
+	Insert
+	// Delete pairs a source with nil.
+	Delete
+)
+
+var opNames = []string{"", "keep", "update", "insert", "delete"}
+
+func (op Op) String() string {
+	if op > 0 && int(op) < len(opNames) {
+		return opNames[op]
+	}
+	return fmt.Sprintf("Op(%d)", int(op))
+}
+
+// AssignOptions holds the options for Assign.
+//
+// AddCost, SubCost, MinCost, and MaxCost may be left unset when costs are
+// of the built-in IntCost or FloatCost types. See these types for details.
 type AssignOptions struct {
+	// NodeKey, if set, returns the key identifying a node for the Cache
+	// and CostCache options, and for Verify. Nodes with the same key must
+	// have the same costs. Keys must be comparable, and the nodes are
+	// their own keys if NodeKey is nil.
 	NodeKey  func(node any) any
 	EditCost func(source, target any) Cost
 	AddCost  func(a, b Cost) Cost
 	SubCost  func(a, b Cost) Cost
 
+	// Equal, if set, reports whether source and target are the same, in
+	// which case they cost MinCost to pair, or score MaxCost with the
+	// Maximize option, and EditCost isn't called for them. Only pairs of
+	// equal nodes are then classified as Keep, so nodes that differ are
+	// classified as Update even if pairing them costs MinCost. Equal is
+	// called from the same goroutines as EditCost.
+	Equal func(source, target any) bool
+
+	// DeleteCost and InsertCost, if set, return the cost of deleting a source
+	// and of inserting a target. Otherwise EditCost is called with a nil
+	// target or source for these.
+	DeleteCost func(source any) Cost
+	InsertCost func(target any) Cost
+
+	// Algorithm selects the solver used by Assign. It defaults to Hungarian.
+	Algorithm Algorithm
+
+	// Workers is the number of goroutines calling EditCost concurrently
+	// while the cost matrix is built. Zero or one means EditCost is called
+	// sequentially. Results are the same irrespective of the number of
+	// workers, but EditCost must be safe for concurrent use if it's above one.
+	Workers int
+
+	// MustPair, if set, reports whether source and target must be paired
+	// together, irrespective of their cost. Each node may only be required
+	// to pair with a single other node.
+	MustPair func(source, target any) bool
+
+	// CannotPair, if set, reports whether source and target must not be
+	// paired together. These nodes are then deleted and inserted instead,
+	// and EditCost is not called for them.
+	CannotPair func(source, target any) bool
+
+	// MaxFeasibleCost, if set, is the highest cost at which a source and a
+	// target may be paired. Pairs costing more are deleted and inserted
+	// instead, as done for CannotPair. With either of these options, Assign
+	// keeps a flag for each pair of nodes until it's done, which is small
+	// next to the cost matrix itself.
+	MaxFeasibleCost Cost
+
+	// CostBound, if set, returns a lower bound for the cost of pairing
+	// source with target, and is called before EditCost when MaxFeasibleCost
+	// is set. EditCost isn't called for pairs whose bound is above
+	// MaxFeasibleCost, so a cheap bound, such as the difference in length
+	// of two strings for their edit distance, saves most of the work when
+	// few pairs are feasible.
+	CostBound func(source, target any) Cost
+
+	// TieBreak, if set, reports whether node a sorts before node b, and
+	// must define a strict total order over sources and over targets.
+	// Sources and targets are sorted accordingly before being assigned, so
+	// when several assignments have the same optimal cost the one chosen
+	// and the order of the resulting pairs don't depend on the input order.
+	// It's supported by Assign, AssignContext, Solver, and Hierarchical.
+	// KBest, AssignSparse, AssignDetailed, and AssignApprox panic if it's
+	// set, and AssignMatrix and AssignStream, which take no nodes, ignore it.
+	TieBreak func(a, b any) bool
+
+	// StreamRows is the number of cost matrix rows kept in memory by
+	// AssignStream. It defaults to 64.
+	StreamRows int
+
+	// Maximize makes EditCost return a score rather than a cost, between
+	// MinCost and MaxCost, and the pairs found maximize the total score.
+	// Pairs scoring MinCost are turned into a deletion and an insertion,
+	// and the Cost field of resulting pairs holds their score.
+	Maximize bool
+
 	// MinCost is the minimum possible cost for an edit.
 	// Besides implementing the Cost interface, it must be comparable by identity (==).
 	MinCost Cost
@@ -36,6 +157,88 @@ type AssignOptions struct {
 	// MaxCost is the maximum possible cost for an edit.
 	// Besides implementing the Cost interface, it must be comparable by identity (==)
 	MaxCost Cost
+
+	// Stats, if not nil, is reset and filled with details about each Assign call.
+	Stats *Stats
+
+	// Cache, if not nil, is consulted before EditCost is called, and updated
+	// with its results. Entries are keyed by the NodeKey of the source and
+	// target nodes, or by the nodes themselves if NodeKey is nil, so keys must
+	// identify the node content across every call sharing the cache.
+	Cache *costcache.Cache[Cost]
+
+	// CostCache memoizes EditCost, DeleteCost, and InsertCost within each
+	// Assign call, by the NodeKey of the source and target nodes, so
+	// they're called only once for each pair of distinct keys. This is
+	// worthwhile when many nodes are identical, such as repeated lines
+	// in a file.
+	CostCache bool
+
+	// Progress, if set, is called by the solver every time it completes a
+	// row of the cost matrix, with the number of rows completed so far and
+	// the total number of rows, which may include phantom rows padding the
+	// matrix. The Auction algorithm completes rows all over again on each
+	// of its phases, so it only reports the rows completed in the last one.
+	// Progress is called from the goroutine calling Assign, and isn't
+	// called by KBest.
+	Progress func(done, total int)
+
+	// Trace, if set, is written tables meant for people trying to
+	// understand why some nodes were paired: the cost matrix, the dual
+	// variables and the partial matching after each augmenting path found,
+	// and the final pairs. Costs are shown as seen by the solver, so with
+	// Maximize they are MaxCost minus the scores, except in the final
+	// pairs. The Auction algorithm has no duals to show, the Rectangular
+	// one only shows the final pairs, and KBest shows nothing. The output
+	// is meant for debugging, and its format may change.
+	Trace io.Writer
+}
+
+// Algorithm identifies one of the solvers available to Assign.
+type Algorithm int
+
+const (
+	// Hungarian pads the cost matrix to a square, pairing any excess sources
+	// or targets with phantom nodes that represent their deletion or insertion.
+	Hungarian Algorithm = iota
+
+	// Rectangular works directly on the n×m cost matrix, without phantom
+	// nodes, in O(n²m) time where n is the smaller dimension. It is much
+	// faster than Hungarian when the number of sources and targets differ
+	// significantly. The pairs found have the same total cost, but ties
+	// between equally good assignments may be broken differently.
+	//
+	// The insertion or deletion cost of the nodes left unpaired is folded
+	// into the costs of pairing them, so the cost type must be able to hold
+	// the sum of two costs up to MaxCost.
+	Rectangular
+
+	// Auction uses the auction algorithm by Bertsekas on the same padded
+	// cost matrix as Hungarian. Bids are computed concurrently according to
+	// the Workers option, which makes it scale better on large dense inputs.
+	// It requires costs of the IntCost or FloatCost types, and is exact for
+	// IntCost. With FloatCost, the result may be off the optimum by a small
+	// relative error, and ties may be broken differently than Hungarian.
+	Auction
+)
+
+// Stats holds details about an Assign call, to help telling whether the time
+// is going into the user callbacks or into the solver itself.
+type Stats struct {
+	// EditCalls is the number of times EditCost, DeleteCost, or InsertCost
+	// were called.
+	EditCalls int
+
+	// CallbackTime is the time during which at least one of these callbacks
+	// was running. Calls running at once on several Workers are counted
+	// once, so this never exceeds the time spent in Assign.
+	CallbackTime time.Duration
+
+	// SolverTime is the time spent in Assign outside of EditCost. AddCost,
+	// SubCost, and Less are called from the solver's innermost loop, so
+	// timing them individually would distort the result. They are
+	// accounted for as solver time instead.
+	SolverTime time.Duration
 }
 
 // Assign returns the minimum cost pairs assigning each provided source
@@ -44,9 +247,416 @@ type AssignOptions struct {
 // The cost for each assignment is determined by the provided options.
 //
 // This is an implementation of https://en.wikipedia.org/wiki/Hungarian_algorithm
-// (and hopefully remains O(n^3)) which is one of the well known solutions for the
-// assignment problem: https://en.wikipedia.org/wiki/Assignment_problem
+// in O(n^3) time, where n is the larger of the number of sources and targets,
+// which is one of the well known solutions for the assignment problem:
+// https://en.wikipedia.org/wiki/Assignment_problem
+//
+// Assign panics if the options are invalid or the MustPair and CannotPair
+// options can't be satisfied. Use AssignContext to obtain an error instead.
 func Assign(sources, targets []any, options *AssignOptions) []Pair {
+	result, err := AssignContext(context.Background(), sources, targets, options)
+	if err != nil {
+		panic("assign: " + err.Error())
+	}
+	return result
+}
+
+// AssignContext is similar to Assign, but gives up and returns ctx.Err()
+// if the context is done before the assignment is complete. The context is
+// checked while the costs are computed and on every step of the solver,
+// so a large problem is interrupted within the time taken by a single step.
+// An error is also returned if the options are invalid, as reported by
+// Validate, or if the MustPair and CannotPair options can't be satisfied,
+// and a *CallbackError if EditCost, DeleteCost, or InsertCost panic or
+// return a cost of another type than MinCost.
+func AssignContext(ctx context.Context, sources, targets []any, options *AssignOptions) ([]Pair, error) {
+	buffers := squarePool.Get().(*squareBuffers)
+	defer squarePool.Put(buffers)
+	defer buffers.clear()
+	return assignContext(ctx, sources, targets, options, buffers)
+}
+
+// assignContext implements AssignContext, using buffers for the memory
+// needed by the Hungarian algorithm.
+func assignContext(ctx context.Context, sources, targets []any, options *AssignOptions, buffers *squareBuffers) (result []Pair, err error) {
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid options: %v", err)
+	}
+	options = options.withDefaults()
+	defer func() {
+		if r := recover(); r != nil {
+			callbackErr, ok := r.(*CallbackError)
+			if !ok {
+				panic(r)
+			}
+			result, err = nil, callbackErr
+		}
+	}()
+
+	start := time.Now()
+	editCost := options.editCostFunc()
+	if options.Maximize {
+		score := editCost
+		editCost = func(source, target any) Cost {
+			return options.SubCost(options.MaxCost, score(source, target))
+		}
+	}
+
+	// The indexes of the pairs found are mapped back to the positions of
+	// the nodes in the slices provided, undoing sorting and MustPair.
+	var sourceOrder, targetOrder []int
+	if options.TieBreak != nil {
+		sources, sourceOrder = options.sortNodes(sources)
+		targets, targetOrder = options.sortNodes(targets)
+	}
+
+	forced, sources, targets, restSources, restTargets, err := options.mustPairs(sources, targets, editCost)
+	if err != nil {
+		return nil, err
+	}
+
+	pairCost, splitPairs := options.infeasibleCost(sources, targets, editCost)
+	switch options.Algorithm {
+	case Rectangular:
+		result, err = assignRectangular(ctx, sources, targets, pairCost, options)
+	case Auction:
+		result, err = assignAuction(ctx, sources, targets, pairCost, options)
+	default:
+		result, err = assignSquare(ctx, sources, targets, pairCost, options, buffers)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result = splitPairs(result)
+	reindexPairs(result, restSources, restTargets)
+	result = append(forced, result...)
+	reindexPairs(result, sourceOrder, targetOrder)
+	options.finishPairs(result, start)
+	return result, nil
+}
+
+// sortNodes returns a sorted copy of nodes according to the TieBreak option,
+// and the position in nodes of each of the sorted nodes.
+func (options *AssignOptions) sortNodes(nodes []any) (sorted []any, order []int) {
+	order = make([]int, len(nodes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return options.TieBreak(nodes[order[i]], nodes[order[j]])
+	})
+	sorted = make([]any, len(nodes))
+	for i, k := range order {
+		sorted[i] = nodes[k]
+	}
+	return sorted, order
+}
+
+// reindexPairs replaces the indexes in pairs with the ones they map to in
+// sourceIndex and targetIndex, if these are not nil.
+func reindexPairs(pairs []Pair, sourceIndex, targetIndex []int) {
+	for i := range pairs {
+		pair := &pairs[i]
+		if sourceIndex != nil && pair.SourceIndex >= 0 {
+			pair.SourceIndex = sourceIndex[pair.SourceIndex]
+		}
+		if targetIndex != nil && pair.TargetIndex >= 0 {
+			pair.TargetIndex = targetIndex[pair.TargetIndex]
+		}
+	}
+}
+
+// finishPairs classifies the pairs found by a call that began at start,
+// scores them with the Maximize option, and traces them. It also records
+// the time spent outside of the callbacks in Stats, if set.
+func (options *AssignOptions) finishPairs(pairs []Pair, start time.Time) {
+	options.classifyPairs(pairs)
+	if options.Maximize {
+		options.scorePairs(pairs)
+	}
+	options.tracePairs(pairs)
+	if stats := options.Stats; stats != nil {
+		stats.SolverTime = time.Since(start) - stats.CallbackTime
+	}
+}
+
+// classifyPairs sets the Op field of the given pairs.
+func (options *AssignOptions) classifyPairs(pairs []Pair) {
+	for i := range pairs {
+		pair := &pairs[i]
+		switch {
+		case pair.Source == nil:
+			pair.Op = Insert
+		case pair.Target == nil:
+			pair.Op = Delete
+		case pair.Cost == options.MinCost && (options.Equal == nil || options.equal(pair.Source, pair.Target)):
+			pair.Op = Keep
+		default:
+			pair.Op = Update
+		}
+	}
+}
+
+// scorePairs turns the cost of the given pairs back into their score,
+// for the Maximize option.
+func (options *AssignOptions) scorePairs(pairs []Pair) {
+	for i := range pairs {
+		pairs[i].Cost = options.SubCost(options.MaxCost, pairs[i].Cost)
+	}
+}
+
+// CallbackError reports that a callback computing the cost of pairing
+// Source with Target panicked or returned an invalid cost. Source or
+// Target are nil for deletions and insertions.
+type CallbackError struct {
+	// Callback is the name of the option holding the callback, such as
+	// "EditCost".
+	Callback string
+	Source   any
+	Target   any
+
+	// Err describes the failure. It is the panic value if that is an
+	// error, and wraps it otherwise.
+	Err error
+}
+
+func (e *CallbackError) Error() string {
+	return fmt.Sprintf("%s failed for source %v and target %v: %v", e.Callback, e.Source, e.Target, e.Err)
+}
+
+func (e *CallbackError) Unwrap() error {
+	return e.Err
+}
+
+// edit returns the cost of editing source into target, using the DeleteCost
+// and InsertCost options when set. It panics with a *CallbackError if the
+// callback panics or returns a cost of another type than MinCost.
+func (options *AssignOptions) edit(source, target any) Cost {
+	switch {
+	case target == nil && options.DeleteCost != nil:
+		return options.call("DeleteCost", source, target, func() Cost { return options.DeleteCost(source) })
+	case source == nil && options.InsertCost != nil:
+		return options.call("InsertCost", source, target, func() Cost { return options.InsertCost(target) })
+	}
+	return options.call("EditCost", source, target, func() Cost { return options.EditCost(source, target) })
+}
+
+// call returns the cost computed by f on behalf of the named callback for
+// source and target. It panics with a *CallbackError if f panics or returns
+// a cost of another type than MinCost.
+func (options *AssignOptions) call(callback string, source, target any, f func() Cost) (cost Cost) {
+	defer func() {
+		if r := recover(); r != nil {
+			panic(callbackError(callback, source, target, r))
+		}
+	}()
+
+	cost = f()
+	if reflect.TypeOf(cost) != reflect.TypeOf(options.MinCost) {
+		panic(&CallbackError{
+			Callback: callback,
+			Source:   source,
+			Target:   target,
+			Err:      fmt.Errorf("returned a Cost of type %T instead of %T", cost, options.MinCost),
+		})
+	}
+	return cost
+}
+
+// equal calls the Equal option for source and target. It panics with a
+// *CallbackError if Equal panics.
+func (options *AssignOptions) equal(source, target any) bool {
+	defer func() {
+		if r := recover(); r != nil {
+			panic(callbackError("Equal", source, target, r))
+		}
+	}()
+	return options.Equal(source, target)
+}
+
+// callbackError returns the *CallbackError reporting that the named
+// callback panicked with r for source and target, or r itself if that
+// already is one.
+func callbackError(callback string, source, target any, r any) *CallbackError {
+	if err, ok := r.(*CallbackError); ok {
+		return err
+	}
+	err, ok := r.(error)
+	if !ok {
+		err = fmt.Errorf("panic: %v", r)
+	}
+	return &CallbackError{Callback: callback, Source: source, Target: target, Err: err}
+}
+
+// editCostFunc returns EditCost wrapped to update Stats, CostCache, and Cache when these are set,
+// and to skip all of them for nodes reported the same by Equal. Stats is reset in the process.
+func (options *AssignOptions) editCostFunc() func(source, target any) Cost {
+	editCost := options.edit
+	if stats := options.Stats; stats != nil {
+		*stats = Stats{}
+		// The time is measured from the first of the calls running at once
+		// to the end of the last of them, so that calls made concurrently
+		// by several workers don't add up to more than the elapsed time.
+		var mu sync.Mutex
+		var running int
+		var busyStart time.Time
+		editCost = func(source, target any) Cost {
+			mu.Lock()
+			if running == 0 {
+				busyStart = time.Now()
+			}
+			running++
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				running--
+				if running == 0 {
+					stats.CallbackTime += time.Since(busyStart)
+				}
+				stats.EditCalls++
+				mu.Unlock()
+			}()
+			return options.edit(source, target)
+		}
+	}
+
+	if options.CostCache {
+		uncached := editCost
+		var mu sync.Mutex
+		memo := make(map[[2]any]Cost)
+		editCost = func(source, target any) Cost {
+			key := [2]any{nodeKey(source, options), nodeKey(target, options)}
+			mu.Lock()
+			cost, ok := memo[key]
+			mu.Unlock()
+			if ok {
+				return cost
+			}
+			cost = uncached(source, target)
+			mu.Lock()
+			memo[key] = cost
+			mu.Unlock()
+			return cost
+		}
+	}
+
+	if cache := options.Cache; cache != nil {
+		uncached := editCost
+		editCost = func(source, target any) Cost {
+			skey, tkey := nodeKey(source, options), nodeKey(target, options)
+			if cost, ok := cache.Get(skey, tkey); ok {
+				return cost
+			}
+			cost := uncached(source, target)
+			cache.Put(skey, tkey, cost)
+			return cost
+		}
+	}
+
+	if options.Equal != nil {
+		unmatched := editCost
+		editCost = func(source, target any) Cost {
+			if cost, ok := options.matchCost(source, target); ok {
+				return cost
+			}
+			return unmatched(source, target)
+		}
+	}
+	return editCost
+}
+
+// pairCost returns the cost of pairing source with target, as returned by
+// the function from editCostFunc but without going through Stats and the
+// caches.
+func (options *AssignOptions) pairCost(source, target any) Cost {
+	if cost, ok := options.matchCost(source, target); ok {
+		return cost
+	}
+	return options.edit(source, target)
+}
+
+// matchCost returns the cost of pairing source with target, and true, if
+// the Equal option reports them the same.
+func (options *AssignOptions) matchCost(source, target any) (Cost, bool) {
+	if options.Equal == nil || source == nil || target == nil || !options.equal(source, target) {
+		return nil, false
+	}
+	if options.Maximize {
+		return options.MaxCost, true
+	}
+	return options.MinCost, true
+}
+
+// fillCosts sets costs[i][j] to cost(i, j) for every i < rows and j < cols,
+// spreading the rows across the given number of workers. If cost panics
+// in a worker, the panic is passed on to the calling goroutine.
+func fillCosts(ctx context.Context, costs [][]Cost, rows, cols, workers int, cost func(i, j int) Cost) error {
+	if workers > rows {
+		workers = rows
+	}
+	if workers <= 1 {
+		for i := 0; i < rows; i++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			for j := 0; j < cols; j++ {
+				costs[i][j] = cost(i, j)
+			}
+		}
+		return nil
+	}
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	var failed atomic.Bool
+	var failure any
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil && !failed.Swap(true) {
+					failure = r
+				}
+			}()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= rows || ctx.Err() != nil || failed.Load() {
+					return
+				}
+				for j := 0; j < cols; j++ {
+					costs[i][j] = cost(i, j)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if failed.Load() {
+		panic(failure)
+	}
+	return ctx.Err()
+}
+
+// assignSquare implements Assign for the Hungarian algorithm.
+func assignSquare(ctx context.Context, sources, targets []any, pairCost func(i, j int) Cost, options *AssignOptions, buffers *squareBuffers) ([]Pair, error) {
+	costs, err := squareCosts(ctx, sources, targets, pairCost, options, buffers)
+	if err != nil {
+		return nil, err
+	}
+	options.traceCosts(sources, targets, costs)
+	optimal, err := optimalCost(ctx, costs, options, buffers)
+	if err != nil {
+		return nil, err
+	}
+	return squarePairs(sources, targets, costs, optimal, options), nil
+}
+
+// squareCosts returns the square cost matrix for sources and targets, as
+// required by optimalCost. Sources and targets beyond the length of the
+// other side are paired with phantom nodes representing their deletion or
+// insertion. Costs come from pairCost, which takes the positions of the
+// nodes as returned by indexCost. The matrix is allocated from buffers.
+func squareCosts(ctx context.Context, sources, targets []any, pairCost func(i, j int) Cost, options *AssignOptions, buffers *squareBuffers) ([][]Cost, error) {
 	n := len(sources)
 	m := len(targets)
 
@@ -56,9 +666,8 @@ func Assign(sources, targets []any, options *AssignOptions) []Pair {
 		size = m
 	}
 
-	costs := make([][]Cost, size)
+	costs := buffers.matrix(size)
 	for i := 0; i < size; i++ {
-		costs[i] = make([]Cost, size)
 		for j := 0; j < size; j++ {
 			costs[i][j] = options.MinCost
 		}
@@ -66,15 +675,14 @@ func Assign(sources, targets []any, options *AssignOptions) []Pair {
 
 	// Cost of substitution (source[i] -> target[j]).
 	// Substitutions at MaxCost are later translated to insertions and deletions instead.
-	for i := 0; i < n; i++ {
-		for j := 0; j < m; j++ {
-			costs[i][j] = options.EditCost(sources[i], targets[j])
-		}
+	err := fillCosts(ctx, costs, n, m, options.Workers, pairCost)
+	if err != nil {
+		return nil, err
 	}
 
 	// If n > m, sources i >= m are matched with nil target nodes. This is a deletion.
 	for i := 0; i < n; i++ {
-		cost := options.EditCost(sources[i], nil)
+		cost := pairCost(i, -1)
 		for j := m; j < size; j++ {
 			costs[i][j] = cost
 		}
@@ -82,13 +690,20 @@ func Assign(sources, targets []any, options *AssignOptions) []Pair {
 
 	// If m > n, targets j >= n are matched with nil source nodes. This is an insertion.
 	for j := 0; j < m; j++ {
-		cost := options.EditCost(nil, targets[j])
+		cost := pairCost(-1, j)
 		for i := n; i < size; i++ {
 			costs[i][j] = cost
 		}
 	}
 
-	optimal := optimalCost(costs, options)
+	return costs, nil
+}
+
+// squarePairs returns the pairs for the solution found by optimalCost.
+func squarePairs(sources, targets []any, costs [][]Cost, optimal []int, options *AssignOptions) []Pair {
+	n := len(sources)
+	m := len(targets)
+	size := len(costs)
 
 	var result []Pair
 	for j := 0; j < size; j++ {
@@ -98,24 +713,38 @@ func Assign(sources, targets []any, options *AssignOptions) []Pair {
 		case i < n && j < m:
 			if cost == options.MaxCost {
 				// Remove + Insert
-				result = append(result, Pair{Source: sources[i], Target: nil, Cost: cost})
-				result = append(result, Pair{Source: nil, Target: targets[j], Cost: cost})
+				result = append(result, Pair{Source: sources[i], Target: nil, SourceIndex: i, TargetIndex: -1, Cost: cost})
+				result = append(result, Pair{Source: nil, Target: targets[j], SourceIndex: -1, TargetIndex: j, Cost: cost})
 			} else {
 				// Update
-				result = append(result, Pair{Source: sources[i], Target: targets[j], Cost: cost})
+				result = append(result, Pair{Source: sources[i], Target: targets[j], SourceIndex: i, TargetIndex: j, Cost: cost})
 			}
 		case i < n && j >= m:
 			// Remove
-			result = append(result, Pair{Source: sources[i], Target: nil, Cost: cost})
+			result = append(result, Pair{Source: sources[i], Target: nil, SourceIndex: i, TargetIndex: -1, Cost: cost})
 		case i >= n && j < m:
 			// Insert
-			result = append(result, Pair{Source: nil, Target: targets[i], Cost: cost})
+			result = append(result, Pair{Source: nil, Target: targets[j], SourceIndex: -1, TargetIndex: j, Cost: cost})
 		}
 	}
-
 	return result
 }
 
+// progress calls the Progress option if it is set.
+func (options *AssignOptions) progress(done, total int) {
+	if options.Progress != nil {
+		options.Progress(done, total)
+	}
+}
+
+// nodeKey returns the key identifying node, which is nil for a nil node.
+func nodeKey(node any, options *AssignOptions) any {
+	if node == nil || options.NodeKey == nil {
+		return node
+	}
+	return options.NodeKey(node)
+}
+
 type Cost interface {
 	Less(other Cost) bool
 }
@@ -123,7 +752,21 @@ type Cost interface {
 // optimalCost returns an array where result[j] = i means target node j is matched
 // with source node i. The cost matrix must be square, and costs[i][j] is the cost
 // of matching left node i with right node j.
-func optimalCost(costs [][]Cost, options *AssignOptions) []int {
+//
+// The partial costs kept by the algorithm may become negative, even though
+// the values compared (slacks and deltas) never are. Cost types must thus
+// either represent negative values, saturating only at their own limits, or
+// wrap around on unsigned arithmetic so that the differences come out right.
+// SaturatingAdd and SaturatingSub implement the former for signed types.
+func optimalCost(ctx context.Context, costs [][]Cost, options *AssignOptions, buffers *squareBuffers) ([]int, error) {
+	targetSource, _, _, err := optimalDuals(ctx, costs, options, buffers)
+	return targetSource, err
+}
+
+// optimalDuals is the same as optimalCost, but also returns the final partial
+// costs for source and target nodes, which are the dual variables of the
+// underlying linear program. The returned slices are allocated from buffers.
+func optimalDuals(ctx context.Context, costs [][]Cost, options *AssignOptions, buffers *squareBuffers) (targetSource []int, sourceCost, targetCost []Cost, err error) {
 
 	// The augmented path search works by taking a partial match between source and
 	// target nodes (targetSource), which is better from a cost perspective but not yet
@@ -138,6 +781,12 @@ func optimalCost(costs [][]Cost, options *AssignOptions) []int {
 	// The process of finding this path is similar to Dijkstra's algorithm for finding
 	// the shortest path in a graph, where we explore all possible edges from the current
 	// source node and then choose the edge with the minimum slack to extend the path.
+	//
+	// This takes O(n^3) time overall. Every step of the search visits a target that
+	// wasn't visited before, so there are at most n+1 steps for each source, and each
+	// step takes O(n) time because the minimum slack of every target is kept up to
+	// date along with the visited source owning it, rather than computed again from
+	// all the visited sources.
 
 	// The algorithm uses n+1 sized slices and marker values at n to simplify the logic.
 	n := len(costs)
@@ -146,12 +795,15 @@ func optimalCost(costs [][]Cost, options *AssignOptions) []int {
 	// They maintain the "dual feasibility": sourceCost[i] + targetCost[j] <= cost[i][j].
 	// Edges where sourceCost[i] + targetCost[j] == cost[i][j] are considered "tight",
 	// meaning there is no slack to be removed, and form the equality subgraph.
-	sourceCost := make([]Cost, n+1)
-	targetCost := make([]Cost, n+1)
+	buffers.sourceCost = grow(buffers.sourceCost, n+1)
+	buffers.targetCost = grow(buffers.targetCost, n+1)
+	sourceCost = buffers.sourceCost
+	targetCost = buffers.targetCost
 
 	// targetSource[j] = i stores the source node i matched with target node j.
 	// A value of n means target node j is unmatched.
-	targetSource := make([]int, n+1)
+	buffers.targetSource = grow(buffers.targetSource, n+1)
+	targetSource = buffers.targetSource
 
 	for i := 0; i <= n; i++ {
 		sourceCost[i] = options.MinCost
@@ -159,19 +811,27 @@ func optimalCost(costs [][]Cost, options *AssignOptions) []int {
 		targetSource[i] = n
 	}
 
-	// minSlack[j] stores the minimum slack for target node j, where the slack
-	// is the difference between cost[i][j] and the sum of the partial costs.
-	minSlack := make([]Cost, n+1)
+	// minSlack[j] stores the minimum slack for target node j across the visited
+	// source nodes, where the slack is the difference between cost[i][j] and the
+	// sum of the partial costs. The source owning it is targetSource[targetTrail[j]].
+	buffers.minSlack = grow(buffers.minSlack, n+1)
+	minSlack := buffers.minSlack
 
 	// targetTrail[j] stores the previous target node in the alternating path for target node j.
 	// It is used to flip the matches along the trail when an augmenting path is found.
-	targetTrail := make([]int, n+1)
+	buffers.targetTrail = grow(buffers.targetTrail, n+1)
+	targetTrail := buffers.targetTrail
 
 	// visitedTarget[j] marks target nodes that are already in the trail.
-	visitedTarget := make([]bool, n+1)
+	buffers.visitedTarget = grow(buffers.visitedTarget, n+1)
+	visitedTarget := buffers.visitedTarget
 
 	// Main loop: find a good target for each source node i.
 	for i := 0; i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, nil, err
+		}
+
 		// Start search for an augmenting path starting at source node i.
 		// We use a dummy target node 0 to simplify the algorithm.
 		targetSource[n] = i
@@ -187,10 +847,15 @@ func optimalCost(costs [][]Cost, options *AssignOptions) []int {
 		for targetSource[currentTarget] != n {
 			visitedTarget[currentTarget] = true
 			currentSource := targetSource[currentTarget]
-			delta := options.MaxCost
-			nextTarget := 0
+			var delta Cost
+			nextTarget := -1
 
 			// Find the edge with the minimum slack to an unvisited target node.
+			// Only the slack from the newly visited source needs computing, as the
+			// other visited sources are accounted for in minSlack already. There's
+			// always an unvisited target, since only matched targets and the dummy
+			// one are visited, and the next target is always one of them even if
+			// every slack is at MaxCost, so the search can't go around in circles.
 			for j := 0; j < n; j++ {
 				if !visitedTarget[j] {
 					cost := costs[currentSource][j]
@@ -200,7 +865,7 @@ func optimalCost(costs [][]Cost, options *AssignOptions) []int {
 						minSlack[j] = curSlack
 						targetTrail[j] = currentTarget
 					}
-					if minSlack[j].Less(delta) {
+					if nextTarget < 0 || minSlack[j].Less(delta) {
 						delta = minSlack[j]
 						nextTarget = j
 					}
@@ -264,8 +929,10 @@ func optimalCost(costs [][]Cost, options *AssignOptions) []int {
 			targetSource[currentTarget] = targetSource[previousTarget]
 			currentTarget = previousTarget
 		}
+		options.traceDuals(i, sourceCost[:n], targetCost[:n], targetSource[:n])
+		options.progress(i+1, n)
 	}
 
 	// result[j] = i means target node j is matched with source node i.
-	return targetSource[:n]
+	return targetSource[:n], sourceCost[:n], targetCost[:n], nil
 }
@@ -1,10 +1,17 @@
 package assign_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/rand"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/canonical/go-algo/assign"
+	"github.com/canonical/go-algo/costcache"
+	"github.com/canonical/go-algo/strdist"
 
 	. "gopkg.in/check.v1"
 )
@@ -90,6 +97,524 @@ func (*S) TestTable(c *C) {
 	}
 }
 
+func (*S) TestStats(c *C) {
+	options := deltaOptions(costMap{namePair{"a", "b"}: 1})
+	options.Stats = &assign.Stats{EditCalls: 42}
+	assign.Assign([]any{"a", "x"}, []any{"b"}, options)
+	// Two substitutions, two deletions and one insertion.
+	c.Assert(options.Stats.EditCalls, Equals, 5)
+	c.Assert(options.Stats.CallbackTime > 0, Equals, true)
+	c.Assert(options.Stats.SolverTime > 0, Equals, true)
+}
+
+func (*S) TestStatsWorkers(c *C) {
+	// Calls running at once on several workers are timed once, so the
+	// callback time doesn't exceed the time spent in Assign.
+	nodes := make([]any, 8)
+	for i := range nodes {
+		nodes[i] = i
+	}
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			time.Sleep(time.Millisecond)
+			return assign.IntCost(1)
+		},
+		Workers: 8,
+		Stats:   &assign.Stats{},
+	}
+	start := time.Now()
+	assign.Assign(nodes, nodes, options)
+	elapsed := time.Since(start)
+	c.Assert(options.Stats.EditCalls, Equals, 80)
+	c.Assert(options.Stats.CallbackTime > 0, Equals, true)
+	c.Assert(options.Stats.SolverTime > 0, Equals, true)
+	c.Assert(options.Stats.CallbackTime+options.Stats.SolverTime <= elapsed, Equals, true)
+}
+
+func (*S) TestCache(c *C) {
+	options := deltaOptions(costMap{namePair{"a", "b"}: 1})
+	options.Cache = costcache.New[assign.Cost](100)
+	options.Stats = &assign.Stats{}
+	pairs := assign.Assign([]any{"a", "x"}, []any{"b"}, options)
+	c.Assert(options.Stats.EditCalls, Equals, 5)
+	c.Assert(options.Cache.Len(), Equals, 5)
+
+	cached := assign.Assign([]any{"a", "x"}, []any{"b"}, options)
+	c.Assert(options.Stats.EditCalls, Equals, 0)
+	c.Assert(cached, DeepEquals, pairs)
+	c.Assert(options.Cache.Stats().Hits, Equals, uint64(5))
+}
+
+func (*S) TestProgress(c *C) {
+	sources := []any{"a", "b", "c"}
+	targets := []any{"x", "y"}
+	costs := costMap{{"a", "x"}: 1, {"b", "y"}: 2, {"c", "x"}: 3}
+	for _, algorithm := range []assign.Algorithm{assign.Hungarian, assign.Rectangular, assign.Auction} {
+		c.Logf("Algorithm: %d", algorithm)
+		var calls [][2]int
+		options := &assign.AssignOptions{
+			Algorithm: algorithm,
+			EditCost: func(source, target any) assign.Cost {
+				cost, ok := costs[namePair{nodeName(source), nodeName(target)}]
+				if !ok {
+					return assign.IntCost(10)
+				}
+				return assign.IntCost(cost)
+			},
+			Progress: func(done, total int) {
+				calls = append(calls, [2]int{done, total})
+			},
+		}
+		assign.Assign(sources, targets, options)
+		c.Assert(len(calls) > 0, Equals, true)
+		total := calls[0][1]
+		for i, call := range calls {
+			c.Assert(call[1], Equals, total)
+			if i > 0 {
+				c.Assert(call[0] >= calls[i-1][0], Equals, true)
+			}
+		}
+		c.Assert(calls[len(calls)-1][0], Equals, total)
+		if algorithm != assign.Auction {
+			c.Assert(calls, HasLen, total)
+		}
+	}
+
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost { return assign.IntCost(1) },
+		Progress: func(done, total int) { c.Fatalf("KBest reported progress") },
+	}
+	assign.KBest(sources, targets, 2, options)
+}
+
+func (*S) TestCostCache(c *C) {
+	costs := costMap{{"a", "x"}: 1, {"b", "x"}: 2}
+	sources := []any{"a", "a", "b"}
+	targets := []any{"x", "x"}
+	for _, algorithm := range []assign.Algorithm{assign.Hungarian, assign.Rectangular} {
+		c.Logf("Algorithm: %d", algorithm)
+		options := deltaOptions(costs)
+		options.Algorithm = algorithm
+		options.Stats = &assign.Stats{}
+		pairs := assign.Assign(sources, targets, options)
+		uncached := options.Stats.EditCalls
+
+		options.CostCache = true
+		options.Workers = 2
+		c.Assert(assign.Assign(sources, targets, options), DeepEquals, pairs)
+		// At most two substitutions, two deletions and one insertion.
+		c.Assert(options.Stats.EditCalls <= 5, Equals, true)
+		c.Assert(options.Stats.EditCalls < uncached, Equals, true)
+	}
+
+	// Nodes with the same key share their costs.
+	calls := 0
+	options := &assign.AssignOptions{
+		NodeKey:   func(n any) any { return n.(string)[:1] },
+		CostCache: true,
+		EditCost: func(source, target any) assign.Cost {
+			calls++
+			return assign.IntCost(1)
+		},
+	}
+	assign.Assign([]any{"a1", "a2"}, []any{"x1", "x2"}, options)
+	c.Assert(calls, Equals, 3)
+}
+
+func (*S) TestContext(c *C) {
+	options := deltaOptions(costMap{namePair{"a", "b"}: 1})
+	pairs, err := assign.AssignContext(context.Background(), []any{"a"}, []any{"b"}, options)
+	c.Assert(err, IsNil)
+	c.Assert(pairsCost(pairs), DeepEquals, costMap{namePair{"a", "b"}: 1})
+
+	for _, algorithm := range []assign.Algorithm{assign.Hungarian, assign.Rectangular} {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		options := &assign.AssignOptions{
+			Algorithm: algorithm,
+			EditCost: func(source, target any) assign.Cost {
+				calls++
+				cancel()
+				return assign.IntCost(1)
+			},
+		}
+		pairs, err := assign.AssignContext(ctx, []any{"a", "b", "c"}, []any{"x", "y", "z"}, options)
+		c.Assert(err, Equals, context.Canceled)
+		c.Assert(pairs, IsNil)
+		c.Assert(calls < 9, Equals, true)
+	}
+}
+
+func (*S) TestCallbackError(c *C) {
+	for _, workers := range []int{0, 4} {
+		for _, algorithm := range []assign.Algorithm{assign.Hungarian, assign.Rectangular, assign.Auction} {
+			c.Logf("Algorithm: %d, workers: %d", algorithm, workers)
+			options := &assign.AssignOptions{
+				Algorithm: algorithm,
+				Workers:   workers,
+				EditCost: func(source, target any) assign.Cost {
+					if source == "b" && target == "y" {
+						panic("boom")
+					}
+					return assign.IntCost(1)
+				},
+			}
+			pairs, err := assign.AssignContext(context.Background(), []any{"a", "b"}, []any{"x", "y"}, options)
+			c.Assert(pairs, IsNil)
+			c.Assert(err, ErrorMatches, `EditCost failed for source b and target y: panic: boom`)
+			var callbackErr *assign.CallbackError
+			c.Assert(errors.As(err, &callbackErr), Equals, true)
+			c.Assert(callbackErr.Callback, Equals, "EditCost")
+			c.Assert(callbackErr.Source, Equals, "b")
+			c.Assert(callbackErr.Target, Equals, "y")
+		}
+	}
+
+	failure := errors.New("cannot delete")
+	options := &assign.AssignOptions{
+		EditCost:   func(source, target any) assign.Cost { return assign.IntCost(1) },
+		DeleteCost: func(source any) assign.Cost { panic(failure) },
+	}
+	_, err := assign.AssignContext(context.Background(), []any{"a", "b"}, []any{"x"}, options)
+	c.Assert(err, ErrorMatches, `DeleteCost failed for source a and target <nil>: cannot delete`)
+	c.Assert(errors.Is(err, failure), Equals, true)
+
+	options = &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost { return assign.FloatCost(1) },
+	}
+	_, err = assign.AssignContext(context.Background(), []any{"a"}, []any{"x"}, options)
+	c.Assert(err, ErrorMatches, `EditCost failed for source a and target x: returned a Cost of type assign.FloatCost instead of assign.IntCost`)
+	c.Assert(func() { assign.Assign([]any{"a"}, []any{"x"}, options) }, PanicMatches, `assign: EditCost failed for source a and target x: .*`)
+
+	options = &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost { return nil },
+	}
+	_, err = assign.AssignContext(context.Background(), []any{"a"}, []any{"x"}, options)
+	c.Assert(err, ErrorMatches, `EditCost failed for source a and target x: returned a Cost of type <nil> instead of assign.IntCost`)
+}
+
+func (*S) TestWorkers(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	var sources, targets []any
+	for i := 0; i < 30; i++ {
+		sources = append(sources, fmt.Sprintf("s%d", i))
+		targets = append(targets, fmt.Sprintf("t%d", i))
+	}
+	costs := make(costMap)
+	for _, source := range sources {
+		for _, target := range targets {
+			costs[namePair{source.(string), target.(string)}] = uintCost(rnd.Intn(100))
+		}
+	}
+	for _, algorithm := range []assign.Algorithm{assign.Hungarian, assign.Rectangular} {
+		options := deltaOptions(costs)
+		options.Algorithm = algorithm
+		sequential := assign.Assign(sources, targets[:20], options)
+
+		options.Workers = 4
+		options.Stats = &assign.Stats{}
+		parallel := assign.Assign(sources, targets[:20], options)
+		c.Assert(parallel, DeepEquals, sequential)
+		c.Assert(options.Stats.EditCalls >= 30*20, Equals, true)
+	}
+}
+
+func (*S) TestConstraints(c *C) {
+	costs := map[[2]any]assign.IntCost{
+		{"a", "x"}: 1, {"a", "y"}: 2,
+		{"b", "x"}: 2, {"b", "y"}: 1,
+		{"a", nil}: 3, {"b", nil}: 4,
+		{nil, "x"}: 5, {nil, "y"}: 6,
+	}
+	for _, algorithm := range []assign.Algorithm{assign.Hungarian, assign.Rectangular} {
+		options := &assign.AssignOptions{
+			Algorithm: algorithm,
+			EditCost: func(source, target any) assign.Cost {
+				return costs[[2]any{source, target}]
+			},
+			MustPair: func(source, target any) bool {
+				return source == "a" && target == "y"
+			},
+		}
+		pairs := assign.Assign([]any{"a", "b"}, []any{"x", "y"}, options)
+		c.Assert(pairs, DeepEquals, []assign.Pair{
+			{Source: "a", Target: "y", SourceIndex: 0, TargetIndex: 1, Cost: assign.IntCost(2), Op: assign.Update},
+			{Source: "b", Target: "x", SourceIndex: 1, TargetIndex: 0, Cost: assign.IntCost(2), Op: assign.Update},
+		})
+
+		options.MustPair = nil
+		options.CannotPair = func(source, target any) bool {
+			return target == "x"
+		}
+		pairs = assign.Assign([]any{"a", "b"}, []any{"x", "y"}, options)
+		c.Assert(totalCost(pairs), Equals, assign.IntCost(1+3+5))
+		for _, pair := range pairs {
+			c.Assert(pair.Source != nil && pair.Target == "x", Equals, false)
+		}
+
+		options.MustPair = func(source, target any) bool { return target == "x" }
+		options.CannotPair = nil
+		_, err := assign.AssignContext(context.Background(), []any{"a", "b"}, []any{"x", "y"}, options)
+		c.Assert(err, ErrorMatches, "cannot satisfy constraints: target 0 must pair with sources 0 and 1")
+		c.Assert(func() { assign.Assign([]any{"a", "b"}, []any{"x", "y"}, options) },
+			PanicMatches, "assign: cannot satisfy constraints: .*")
+
+		options.MustPair = func(source, target any) bool { return source == "a" && target == "x" }
+		options.CannotPair = options.MustPair
+		_, err = assign.AssignContext(context.Background(), []any{"a", "b"}, []any{"x", "y"}, options)
+		c.Assert(err, ErrorMatches, "cannot satisfy constraints: source 0 and target 0 must and cannot pair")
+	}
+}
+
+func (*S) TestPairIndex(c *C) {
+	// Equal nodes are told apart by their indexes only.
+	sources := []any{"a", "b", "a", "c"}
+	targets := []any{"b", "a", "a"}
+	for _, algorithm := range algorithms {
+		options := &assign.AssignOptions{
+			Algorithm: algorithm,
+			EditCost: func(source, target any) assign.Cost {
+				if source == target {
+					return assign.IntCost(0)
+				}
+				return assign.IntCost(3)
+			},
+			MustPair: func(source, target any) bool {
+				return source == "b" && target == "b"
+			},
+			TieBreak: func(a, b any) bool {
+				return a.(string) > b.(string)
+			},
+		}
+		c.Logf("Algorithm %d", algorithm)
+		pairs := assign.Assign(sources, targets, options)
+		c.Assert(pairs, HasLen, 4)
+		sourceSeen := make([]bool, len(sources))
+		targetSeen := make([]bool, len(targets))
+		for _, pair := range pairs {
+			if pair.SourceIndex >= 0 {
+				c.Assert(sources[pair.SourceIndex], Equals, pair.Source)
+				c.Assert(sourceSeen[pair.SourceIndex], Equals, false)
+				sourceSeen[pair.SourceIndex] = true
+			} else {
+				c.Assert(pair.Source, IsNil)
+			}
+			if pair.TargetIndex >= 0 {
+				c.Assert(targets[pair.TargetIndex], Equals, pair.Target)
+				c.Assert(targetSeen[pair.TargetIndex], Equals, false)
+				targetSeen[pair.TargetIndex] = true
+			} else {
+				c.Assert(pair.Target, IsNil)
+			}
+		}
+		c.Assert(sourceSeen, DeepEquals, []bool{true, true, true, true})
+		c.Assert(targetSeen, DeepEquals, []bool{true, true, true})
+		c.Assert(totalCost(pairs), Equals, assign.IntCost(3))
+	}
+}
+
+func (*S) TestMaxFeasibleCost(c *C) {
+	// Costs are the edit distance between words, and the bound is the
+	// difference in their lengths.
+	sources := []any{"cat", "horse", "moose", "elephant"}
+	targets := []any{"bat", "house", "giraffe", "ant"}
+	distance := func(a, b string) assign.IntCost {
+		return assign.IntCost(strdist.Distance(a, b, strdist.StandardCost, 0))
+	}
+	for _, algorithm := range algorithms {
+		c.Logf("Algorithm: %d", algorithm)
+		var calls, bounds int
+		options := &assign.AssignOptions{
+			Algorithm: algorithm,
+			EditCost: func(source, target any) assign.Cost {
+				switch {
+				case source == nil:
+					return assign.IntCost(len(target.(string)))
+				case target == nil:
+					return assign.IntCost(len(source.(string)))
+				}
+				calls++
+				return distance(source.(string), target.(string))
+			},
+			MaxFeasibleCost: assign.IntCost(2),
+			CostBound: func(source, target any) assign.Cost {
+				bounds++
+				return assign.IntCost(max(len(source.(string))-len(target.(string)), len(target.(string))-len(source.(string))))
+			},
+		}
+		pairs := assign.Assign(sources, targets, options)
+		c.Assert(pairs, HasLen, 6)
+		paired := make(map[any]any)
+		for _, pair := range pairs {
+			if pair.Source != nil && pair.Target != nil {
+				c.Assert(pair.Cost.(assign.IntCost) <= 2, Equals, true)
+				paired[pair.Source] = pair.Target
+			} else if pair.Source != nil {
+				c.Assert(pair.Cost, Equals, assign.IntCost(len(pair.Source.(string))))
+			} else {
+				c.Assert(pair.Cost, Equals, assign.IntCost(len(pair.Target.(string))))
+			}
+		}
+		c.Assert(paired, DeepEquals, map[any]any{"cat": "bat", "horse": "house"})
+		c.Assert(bounds > 0, Equals, true)
+		c.Assert(calls < len(sources)*len(targets), Equals, true)
+
+		// Without the bound, every pair is computed and then found
+		// infeasible, with the same result.
+		options.CostBound = nil
+		calls = 0
+		c.Assert(assign.Assign(sources, targets, options), DeepEquals, pairs)
+		c.Assert(calls >= len(sources)*len(targets), Equals, true)
+	}
+
+	options := &assign.AssignOptions{
+		EditCost:        func(source, target any) assign.Cost { return assign.IntCost(1) },
+		MaxFeasibleCost: assign.IntCost(1),
+		CostBound:       func(source, target any) assign.Cost { return assign.FloatCost(1) },
+	}
+	_, err := assign.AssignContext(context.Background(), []any{"a"}, []any{"b"}, options)
+	c.Assert(err, ErrorMatches, "CostBound failed for source a and target b: returned a Cost of type assign.FloatCost instead of assign.IntCost")
+}
+
+func (*S) TestMaxFeasibleCostUncomparable(c *C) {
+	// Slices can't be map keys, and infeasible pairs must still be split
+	// once the nodes are sorted back into place.
+	sources := []any{[]int{7}, []int{1}, []int{5}}
+	targets := []any{[]int{4}, []int{2}, []int{9}}
+	for _, algorithm := range algorithms {
+		c.Logf("Algorithm: %d", algorithm)
+		options := &assign.AssignOptions{
+			Algorithm: algorithm,
+			EditCost: func(source, target any) assign.Cost {
+				if source == nil || target == nil {
+					return assign.IntCost(3)
+				}
+				return assign.IntCost(max(source.([]int)[0]-target.([]int)[0], target.([]int)[0]-source.([]int)[0]))
+			},
+			MaxFeasibleCost: assign.IntCost(1),
+			TieBreak: func(a, b any) bool {
+				return a.([]int)[0] > b.([]int)[0]
+			},
+		}
+		pairs, err := assign.AssignContext(context.Background(), sources, targets, options)
+		c.Assert(err, IsNil)
+		c.Assert(assign.Assign(sources, targets, options), DeepEquals, pairs)
+		c.Assert(pairs, HasLen, 4)
+		paired := make(map[int]int)
+		for _, pair := range pairs {
+			if pair.Source != nil {
+				c.Assert(sources[pair.SourceIndex], DeepEquals, pair.Source)
+			}
+			if pair.Target != nil {
+				c.Assert(targets[pair.TargetIndex], DeepEquals, pair.Target)
+			}
+			if pair.Source != nil && pair.Target != nil {
+				c.Assert(pair.Cost, Equals, assign.IntCost(1))
+				paired[pair.Source.([]int)[0]] = pair.Target.([]int)[0]
+			} else {
+				c.Assert(pair.Cost, Equals, assign.IntCost(3))
+			}
+		}
+		c.Assert(paired, DeepEquals, map[int]int{1: 2, 5: 4})
+	}
+}
+
+func (*S) TestTieBreak(c *C) {
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			return assign.IntCost(1)
+		},
+		TieBreak: func(a, b any) bool {
+			return a.(string) < b.(string)
+		},
+	}
+	expected := []assign.Pair{
+		{Source: "a", Target: "x", Cost: assign.IntCost(1), Op: assign.Update},
+		{Source: "b", Target: "y", Cost: assign.IntCost(1), Op: assign.Update},
+		{Source: "c", Target: "z", Cost: assign.IntCost(1), Op: assign.Update},
+	}
+	sources := []any{"a", "b", "c"}
+	targets := []any{"x", "y", "z"}
+	for i := 0; i < 6; i++ {
+		pairs := assign.Assign(sources, targets, options)
+		// Indexes refer to the nodes as provided, before sorting them.
+		for j := range pairs {
+			c.Assert(sources[pairs[j].SourceIndex], Equals, pairs[j].Source)
+			c.Assert(targets[pairs[j].TargetIndex], Equals, pairs[j].Target)
+			pairs[j].SourceIndex, pairs[j].TargetIndex = 0, 0
+		}
+		c.Assert(pairs, DeepEquals, expected)
+
+		// Rotate sources and swap targets around.
+		sources = append(sources[1:], sources[0])
+		targets[0], targets[i%3] = targets[i%3], targets[0]
+	}
+}
+
+func (*S) TestOp(c *C) {
+	options := deltaOptions(costMap{
+		namePair{"a", "a"}: minCost,
+		namePair{"b", "c"}: 1,
+		namePair{"d", "e"}: maxCost,
+	})
+	pairs := assign.Assign([]any{"a", "b", "d", "x"}, []any{"a", "c", "e"}, options)
+	ops := make(map[namePair]assign.Op)
+	for _, pair := range pairs {
+		ops[namePair{nodeName(pair.Source), nodeName(pair.Target)}] = pair.Op
+	}
+	c.Assert(ops, DeepEquals, map[namePair]assign.Op{
+		namePair{"a", "a"}: assign.Keep,
+		namePair{"b", "c"}: assign.Update,
+		namePair{"d", "-"}: assign.Delete,
+		namePair{"x", "-"}: assign.Delete,
+		namePair{"-", "e"}: assign.Insert,
+	})
+	c.Assert(assign.Keep.String(), Equals, "keep")
+	c.Assert(assign.Op(0).String(), Equals, "Op(0)")
+}
+
+func (*S) TestEqual(c *C) {
+	// Case doesn't matter for the cost, but it does for equality.
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			if source == target {
+				panic("EditCost called for equal nodes")
+			}
+			if source == nil || target == nil || !strings.EqualFold(source.(string), target.(string)) {
+				return assign.IntCost(10)
+			}
+			return assign.IntCost(0)
+		},
+		Equal: func(source, target any) bool { return source == target },
+		Stats: &assign.Stats{},
+	}
+	sources := []any{"a", "b"}
+	targets := []any{"a", "B"}
+	pairs := assign.Assign(sources, targets, options)
+	c.Assert(pairs, DeepEquals, []assign.Pair{
+		{Source: "a", Target: "a", SourceIndex: 0, TargetIndex: 0, Cost: assign.IntCost(0), Op: assign.Keep},
+		{Source: "b", Target: "B", SourceIndex: 1, TargetIndex: 1, Cost: assign.IntCost(0), Op: assign.Update},
+	})
+	// Deleting and inserting every node, and the pairs of distinct nodes.
+	c.Assert(options.Stats.EditCalls, Equals, 7)
+	c.Assert(assign.Verify(pairs, sources, targets, options), IsNil)
+
+	options.Maximize = true
+	options.EditCost = func(source, target any) assign.Cost {
+		if source == target {
+			panic("EditCost called for equal nodes")
+		}
+		return assign.IntCost(1)
+	}
+	pairs = assign.Assign(sources, targets, options)
+	c.Assert(pairs[0], DeepEquals, assign.Pair{Source: "a", Target: "a", SourceIndex: 0, TargetIndex: 0, Cost: assign.MaxIntCost, Op: assign.Keep})
+
+	options.Maximize = false
+	options.Equal = func(source, target any) bool { panic("boom") }
+	_, err := assign.AssignContext(context.Background(), sources, targets, options)
+	c.Assert(err, ErrorMatches, "Equal failed for source a and target a: panic: boom")
+}
+
 type deltaTest struct {
 	summary string
 	costs   costMap
@@ -136,9 +661,9 @@ var deltaTests = []deltaTest{{
 	},
 }}
 
-func benchmarkDelta(n int, b *testing.B) {
-	source := make([]any, n)
-	target := make([]any, n)
+func benchmarkInput(n int) (source, target []any, options *assign.AssignOptions) {
+	source = make([]any, n)
+	target = make([]any, n)
 	costs := make(costMap)
 
 	for i := 0; i < n; i++ {
@@ -154,7 +679,11 @@ func benchmarkDelta(n int, b *testing.B) {
 	// which returns maxCost-1. This is high enough to discourage them
 	// in this benchmark's setup.
 
-	options := deltaOptions(costs)
+	return source, target, deltaOptions(costs)
+}
+
+func benchmarkDelta(n int, b *testing.B) {
+	source, target, options := benchmarkInput(n)
 
 	b.ReportAllocs()
 	b.ResetTimer()
@@ -176,6 +705,134 @@ func BenchmarkDelta1000(b *testing.B) {
 	benchmarkDelta(1000, b)
 }
 
+func BenchmarkSolver100(b *testing.B) {
+	source, target, options := benchmarkInput(100)
+	solver := assign.NewSolver(options)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		solver.Assign(source, target)
+	}
+}
+
+// scalingCosts returns cost functions over n sources and n targets
+// identified by their index, including ones known to make the Hungarian
+// algorithm take many steps.
+func scalingCosts(n int) map[string]func(i, j int) assign.IntCost {
+	rnd := rand.New(rand.NewSource(42))
+	random := make([]assign.IntCost, n*n)
+	for k := range random {
+		random[k] = assign.IntCost(rnd.Intn(1000))
+	}
+	return map[string]func(i, j int) assign.IntCost{
+		"random":   func(i, j int) assign.IntCost { return random[i*n+j] },
+		"constant": func(i, j int) assign.IntCost { return 1 },
+		"product":  func(i, j int) assign.IntCost { return assign.IntCost(i * j) },
+		"maximum":  func(i, j int) assign.IntCost { return assign.MaxIntCost },
+	}
+}
+
+// scalingOptions returns options computing costs with f, and counting the
+// calls to SubCost, which happen a constant number of times for each of
+// the innermost steps of the solver.
+func scalingOptions(f func(i, j int) assign.IntCost, calls *int) *assign.AssignOptions {
+	sub := assign.SaturatingSub[assign.IntCost]()
+	return &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			if source == nil || target == nil {
+				return assign.MaxIntCost
+			}
+			return f(source.(int), target.(int))
+		},
+		SubCost: func(a, b assign.Cost) assign.Cost {
+			*calls++
+			return sub(a, b)
+		},
+	}
+}
+
+func scalingNodes(n int) []any {
+	nodes := make([]any, n)
+	for i := range nodes {
+		nodes[i] = i
+	}
+	return nodes
+}
+
+func (*S) TestCubic(c *C) {
+	// Each source takes at most i+1 steps to be matched, as every step visits
+	// a new target and only the i matched targets lead to further steps. Each
+	// step calls SubCost twice for every target not visited yet, and once for
+	// every other target to update the slacks or the partial costs.
+	bound := func(n int) int { return n * (n + 1) / 2 * (3*n + 1) }
+	for _, n := range []int{25, 50, 100, 200} {
+		costs := scalingCosts(n)
+		for _, name := range []string{"random", "constant", "product", "maximum"} {
+			var calls int
+			f := costs[name]
+			nodes := scalingNodes(n)
+			assign.Assign(nodes, nodes, scalingOptions(f, &calls))
+			c.Logf("Test %s with n=%d: %d calls, %.2f n³", name, n, calls, float64(calls)/float64(n*n*n))
+			c.Assert(calls <= bound(n), Equals, true)
+
+			// AssignTyped and AssignInt64 share their own solver, which
+			// takes the same steps without calling SubCost.
+			ints := make([]int, n)
+			for i := range ints {
+				ints[i] = i
+			}
+			unpaired := func(int) assign.IntCost { return assign.MaxIntCost }
+			_, steps := assign.AssignTypedSteps(ints, ints, &assign.TypedOptions[int, int, assign.IntCost]{
+				EditCost:   f,
+				DeleteCost: unpaired,
+				InsertCost: unpaired,
+				MaxCost:    assign.MaxIntCost,
+			})
+			c.Logf("AssignTyped: %d steps", steps)
+			c.Assert(steps <= n*(n+1)/2, Equals, true)
+			matrix := make([][]int64, n)
+			for i := range matrix {
+				matrix[i] = make([]int64, n)
+				for j := range matrix[i] {
+					matrix[i][j] = int64(f(i, j))
+				}
+			}
+			_, _, steps = assign.AssignInt64Steps(matrix)
+			c.Logf("AssignInt64: %d steps", steps)
+			c.Assert(steps <= n*(n+1)/2, Equals, true)
+
+			if n > 25 {
+				// Doubling the size multiplies the work by eight, give or take.
+				var halfCalls int
+				half := scalingNodes(n / 2)
+				assign.Assign(half, half, scalingOptions(scalingCosts(n / 2)[name], &halfCalls))
+				ratio := float64(calls) / float64(halfCalls)
+				c.Logf("Ratio to n=%d: %.2f", n/2, ratio)
+				c.Assert(ratio < 9, Equals, true)
+			}
+		}
+	}
+}
+
+func BenchmarkScaling(b *testing.B) {
+	for _, n := range []int{200, 400, 800} {
+		for _, name := range []string{"random", "product"} {
+			b.Run(fmt.Sprintf("%s/N=%d", name, n), func(b *testing.B) {
+				var calls int
+				nodes := scalingNodes(n)
+				options := scalingOptions(scalingCosts(n)[name], &calls)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					assign.Assign(nodes, nodes, options)
+				}
+				b.ReportMetric(float64(calls)/float64(b.N)/float64(n*n*n), "subs/n³")
+			})
+		}
+	}
+}
+
 func BenchmarkDelta(b *testing.B) {
 	for _, n := range []int{10, 20, 50, 100, 200, 1000} {
 		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
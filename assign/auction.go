@@ -0,0 +1,201 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// assignAuction implements Assign for the Auction algorithm.
+func assignAuction(ctx context.Context, sources, targets []any, pairCost func(i, j int) Cost, options *AssignOptions) ([]Pair, error) {
+	switch options.MaxCost.(type) {
+	case IntCost, FloatCost:
+	default:
+		return nil, fmt.Errorf("auction algorithm requires IntCost or FloatCost costs, got %T", options.MaxCost)
+	}
+	costs, err := squareCosts(ctx, sources, targets, pairCost, options, &squareBuffers{})
+	if err != nil {
+		return nil, err
+	}
+	options.traceCosts(sources, targets, costs)
+	optimal, err := optimalAuction(ctx, costs, options)
+	if err != nil {
+		return nil, err
+	}
+	return squarePairs(sources, targets, costs, optimal, options), nil
+}
+
+// optimalAuction returns an array where result[j] = i means target node j is
+// matched with source node i, like optimalCost. Costs must be IntCost or FloatCost.
+//
+// This is the auction algorithm by Bertsekas ("The auction algorithm: a
+// distributed relaxation method for the assignment problem", 1988), with
+// ε-scaling. Unassigned sources bid for the target that offers them the best
+// value given current prices, raising its price by the difference to their
+// second best option plus ε. Every unassigned source bids at once, so bids
+// may be computed concurrently, and the winner of each target is the highest
+// bidder, or the lowest source index on ties, keeping results deterministic.
+//
+// The final assignment is within n·ε of the optimum. Integer costs are
+// scaled by n+1 so that an ε of 1 yields an optimal assignment. Float costs
+// stop at an ε small enough for the result to be within a relative error of
+// about 1e-9 of the optimum.
+func optimalAuction(ctx context.Context, costs [][]Cost, options *AssignOptions) ([]int, error) {
+	n := len(costs)
+	if n == 0 {
+		return nil, nil
+	}
+
+	_, integer := options.MaxCost.(IntCost)
+	scale := 1.0
+	if integer {
+		scale = float64(n + 1)
+	}
+
+	// benefit[i][j] is what source i gains from being matched with target j.
+	benefit := make([][]float64, n)
+	var maxAbs float64
+	for i := range costs {
+		benefit[i] = make([]float64, n)
+		for j, cost := range costs[i] {
+			var value float64
+			switch cost := cost.(type) {
+			case IntCost:
+				value = float64(cost)
+			case FloatCost:
+				value = float64(cost)
+			default:
+				return nil, fmt.Errorf("auction algorithm requires IntCost or FloatCost costs, got %T", cost)
+			}
+			benefit[i][j] = -value * scale
+			maxAbs = math.Max(maxAbs, math.Abs(benefit[i][j]))
+		}
+	}
+
+	finalEpsilon := 1.0
+	if !integer {
+		finalEpsilon = maxAbs * 1e-9 / float64(n)
+		if finalEpsilon == 0 {
+			finalEpsilon = 1
+		}
+	}
+	epsilon := math.Max(maxAbs/2, finalEpsilon)
+
+	workers := options.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	price := make([]float64, n)
+	sourceTarget := make([]int, n)
+	targetSource := make([]int, n)
+	bidTarget := make([]int, n)
+	bidPrice := make([]float64, n)
+	var unassigned []int
+
+	bid := func(i int) {
+		best, bestValue, secondValue := -1, math.Inf(-1), math.Inf(-1)
+		for j := 0; j < n; j++ {
+			value := benefit[i][j] - price[j]
+			if value > bestValue {
+				best, bestValue, secondValue = j, value, bestValue
+			} else if value > secondValue {
+				secondValue = value
+			}
+		}
+		if math.IsInf(secondValue, -1) {
+			secondValue = bestValue
+		}
+		bidTarget[i] = best
+		bidPrice[i] = price[best] + bestValue - secondValue + epsilon
+	}
+
+	for {
+		for i := 0; i < n; i++ {
+			sourceTarget[i] = -1
+			targetSource[i] = -1
+		}
+		unassigned = unassigned[:0]
+		for i := 0; i < n; i++ {
+			unassigned = append(unassigned, i)
+		}
+
+		for len(unassigned) > 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			// Bidding phase.
+			if workers == 1 || len(unassigned) < 2*workers {
+				for _, i := range unassigned {
+					bid(i)
+				}
+			} else {
+				var wg sync.WaitGroup
+				chunk := (len(unassigned) + workers - 1) / workers
+				for start := 0; start < len(unassigned); start += chunk {
+					end := min(start+chunk, len(unassigned))
+					wg.Add(1)
+					go func(sources []int) {
+						defer wg.Done()
+						for _, i := range sources {
+							bid(i)
+						}
+					}(unassigned[start:end])
+				}
+				wg.Wait()
+			}
+
+			// Assignment phase. Sources are visited in increasing order,
+			// so the lowest index wins among equal bids.
+			winner := make(map[int]int)
+			for _, i := range unassigned {
+				j := bidTarget[i]
+				if w, ok := winner[j]; !ok || bidPrice[i] > bidPrice[w] || bidPrice[i] == bidPrice[w] && i < w {
+					winner[j] = i
+				}
+			}
+			var next []int
+			for _, i := range unassigned {
+				j := bidTarget[i]
+				if winner[j] != i {
+					next = append(next, i)
+					continue
+				}
+				if previous := targetSource[j]; previous >= 0 {
+					sourceTarget[previous] = -1
+					next = append(next, previous)
+				}
+				targetSource[j] = i
+				sourceTarget[i] = j
+				price[j] = bidPrice[i]
+			}
+			unassigned = next
+			if epsilon <= finalEpsilon {
+				options.progress(n-len(unassigned), n)
+			}
+		}
+
+		if epsilon <= finalEpsilon {
+			break
+		}
+		epsilon = math.Max(epsilon/5, finalEpsilon)
+	}
+	return targetSource, nil
+}
@@ -0,0 +1,88 @@
+package assign_test
+
+import (
+	"context"
+	"math"
+	"math/rand"
+
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestAuctionRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		n := rnd.Intn(10)
+		m := rnd.Intn(10)
+		sources := make([]any, n)
+		targets := make([]any, m)
+		for j := range sources {
+			sources[j] = j
+		}
+		for j := range targets {
+			targets[j] = 100 + j
+		}
+		edits := make(map[[2]any]assign.IntCost)
+		for _, source := range append([]any{nil}, sources...) {
+			for _, target := range append([]any{nil}, targets...) {
+				edits[[2]any{source, target}] = assign.IntCost(rnd.Intn(50))
+			}
+		}
+		options := &assign.AssignOptions{
+			EditCost: func(source, target any) assign.Cost {
+				return edits[[2]any{source, target}]
+			},
+		}
+		hungarian := assign.Assign(sources, targets, options)
+		options.Algorithm = assign.Auction
+		auction := assign.Assign(sources, targets, options)
+		c.Assert(totalCost(auction), Equals, totalCost(hungarian))
+
+		options.Workers = 3
+		c.Assert(assign.Assign(sources, targets, options), DeepEquals, auction)
+	}
+}
+
+func (*S) TestAuctionFloat(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 50; i++ {
+		n := 1 + rnd.Intn(8)
+		sources := make([]any, n)
+		targets := make([]any, n)
+		for j := range sources {
+			sources[j] = j
+			targets[j] = 100 + j
+		}
+		edits := make(map[[2]any]assign.FloatCost)
+		for _, source := range append([]any{nil}, sources...) {
+			for _, target := range append([]any{nil}, targets...) {
+				edits[[2]any{source, target}] = assign.FloatCost(rnd.Float64())
+			}
+		}
+		options := &assign.AssignOptions{
+			MaxCost: assign.MaxFloatCost,
+			EditCost: func(source, target any) assign.Cost {
+				return edits[[2]any{source, target}]
+			},
+		}
+		total := func(pairs []assign.Pair) float64 {
+			var total float64
+			for _, pair := range pairs {
+				total += float64(pair.Cost.(assign.FloatCost))
+			}
+			return total
+		}
+		hungarian := total(assign.Assign(sources, targets, options))
+		options.Algorithm = assign.Auction
+		auction := total(assign.Assign(sources, targets, options))
+		c.Assert(math.Abs(auction-hungarian) < 1e-6, Equals, true, Commentf("auction %v, hungarian %v", auction, hungarian))
+	}
+}
+
+func (*S) TestAuctionCostType(c *C) {
+	options := deltaOptions(nil)
+	options.Algorithm = assign.Auction
+	_, err := assign.AssignContext(context.Background(), []any{"a"}, []any{"b"}, options)
+	c.Assert(err, ErrorMatches, "auction algorithm requires IntCost or FloatCost costs, got assign_test.uintCost")
+}
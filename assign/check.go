@@ -0,0 +1,226 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Validate reports whether the options are usable by Assign, returning an
+// error describing the first problem found otherwise. Unset cost arithmetic
+// is taken to be the default for the built-in cost types.
+//
+// Validate only checks the options themselves. CheckCostConsistency also
+// verifies the costs returned by EditCost for actual inputs.
+func (options *AssignOptions) Validate() error {
+	return options.validate(true)
+}
+
+// validate implements Validate, only requiring EditCost if needEdit is true.
+func (options *AssignOptions) validate(needEdit bool) error {
+	if options == nil {
+		return fmt.Errorf("options are nil")
+	}
+	options = options.withDefaults()
+	switch {
+	case needEdit && options.EditCost == nil:
+		return fmt.Errorf("EditCost is not set")
+	case options.AddCost == nil:
+		return fmt.Errorf("AddCost is not set")
+	case options.SubCost == nil:
+		return fmt.Errorf("SubCost is not set")
+	case options.MinCost == nil:
+		return fmt.Errorf("MinCost is not set")
+	case options.MaxCost == nil:
+		return fmt.Errorf("MaxCost is not set")
+	case reflect.TypeOf(options.MinCost) != reflect.TypeOf(options.MaxCost):
+		return fmt.Errorf("MinCost is of type %T but MaxCost is of type %T", options.MinCost, options.MaxCost)
+	case options.Algorithm < Hungarian || options.Algorithm > Auction:
+		return fmt.Errorf("unknown algorithm %d", options.Algorithm)
+	case options.Workers < 0:
+		return fmt.Errorf("Workers is negative: %d", options.Workers)
+	case options.StreamRows < 0:
+		return fmt.Errorf("StreamRows is negative: %d", options.StreamRows)
+	case options.MaxFeasibleCost != nil && reflect.TypeOf(options.MaxFeasibleCost) != reflect.TypeOf(options.MinCost):
+		return fmt.Errorf("MaxFeasibleCost is of type %T but MinCost is of type %T", options.MaxFeasibleCost, options.MinCost)
+	case options.MaxFeasibleCost != nil && options.Maximize:
+		return fmt.Errorf("MaxFeasibleCost cannot be used with Maximize")
+	case options.CostBound != nil && options.MaxFeasibleCost == nil:
+		return fmt.Errorf("CostBound is set without MaxFeasibleCost")
+	}
+
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("cost arithmetic panicked on MinCost and MaxCost: %v", r)
+			}
+		}()
+		if !options.MinCost.Less(options.MaxCost) {
+			err = fmt.Errorf("MinCost %v is not below MaxCost %v", options.MinCost, options.MaxCost)
+			return
+		}
+		if sum := options.AddCost(options.MinCost, options.MaxCost); reflect.TypeOf(sum) != reflect.TypeOf(options.MinCost) {
+			err = fmt.Errorf("AddCost returned a Cost of different dynamic type: %T instead of %T", sum, options.MinCost)
+			return
+		}
+		if diff := options.SubCost(options.MaxCost, options.MinCost); reflect.TypeOf(diff) != reflect.TypeOf(options.MinCost) {
+			err = fmt.Errorf("SubCost returned a Cost of different dynamic type: %T instead of %T", diff, options.MinCost)
+		}
+	}()
+	return err
+}
+
+// maxCheckedCosts limits the number of distinct costs used when verifying
+// the arithmetic in CheckCostConsistency, as the check is quadratic.
+const maxCheckedCosts = 32
+
+// CheckMetric verifies that EditCost behaves as a metric over the provided
+// samples: editing a node into itself costs MinCost, editing a into b costs
+// the same as editing b into a, and editing a into c directly is never more
+// expensive than going through b. Costs equal to MaxCost are considered
+// infinite, so they never violate the triangle inequality.
+//
+// Assign does not require EditCost to be a metric, but cost functions that
+// are expected to be one often produce surprising matchings when they're not.
+// CheckMetric is meant to be used in tests, and returns an error describing
+// the first violation found.
+func CheckMetric(options *AssignOptions, samples []any) error {
+	options = options.withDefaults()
+	n := len(samples)
+	costs := make([][]Cost, n)
+	for i, a := range samples {
+		costs[i] = make([]Cost, n)
+		for j, b := range samples {
+			costs[i][j] = options.EditCost(a, b)
+		}
+	}
+	for i, a := range samples {
+		if cost := costs[i][i]; cost != options.MinCost {
+			return fmt.Errorf("EditCost(%v, %v) is %v, expected MinCost", a, a, cost)
+		}
+	}
+	for i, a := range samples {
+		for j := i + 1; j < n; j++ {
+			if !equalCost(costs[i][j], costs[j][i]) {
+				b := samples[j]
+				return fmt.Errorf("EditCost(%v, %v) is %v but EditCost(%v, %v) is %v", a, b, costs[i][j], b, a, costs[j][i])
+			}
+		}
+	}
+	for i, a := range samples {
+		for j, b := range samples {
+			if costs[i][j] == options.MaxCost {
+				continue
+			}
+			for k, c := range samples {
+				if costs[j][k] == options.MaxCost {
+					continue
+				}
+				through := options.AddCost(costs[i][j], costs[j][k])
+				if through.Less(costs[i][k]) {
+					return fmt.Errorf("EditCost(%v, %v) is %v, above %v via %v", a, c, costs[i][k], through, b)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// CheckCostConsistency verifies that the options are consistent with each
+// other and with the costs returned by EditCost for every pairing of the
+// provided sources and targets, including insertions and deletions:
+//
+//   - All callbacks and cost bounds are set
+//   - MinCost is below MaxCost
+//   - Every cost is within MinCost and MaxCost
+//   - MinCost is the identity for AddCost and SubCost
+//   - SubCost reverts AddCost
+//
+// CheckCostConsistency is meant to be used in tests, and returns an error
+// describing the first inconsistency found.
+func CheckCostConsistency(options *AssignOptions, sources, targets []any) error {
+	options = options.withDefaults()
+	switch {
+	case options.EditCost == nil:
+		return fmt.Errorf("EditCost is not set")
+	case options.AddCost == nil:
+		return fmt.Errorf("AddCost is not set")
+	case options.SubCost == nil:
+		return fmt.Errorf("SubCost is not set")
+	case options.MinCost == nil:
+		return fmt.Errorf("MinCost is not set")
+	case options.MaxCost == nil:
+		return fmt.Errorf("MaxCost is not set")
+	case !options.MinCost.Less(options.MaxCost):
+		return fmt.Errorf("MinCost %v is not below MaxCost %v", options.MinCost, options.MaxCost)
+	}
+
+	var seen []Cost
+	check := func(source, target any) error {
+		cost := options.edit(source, target)
+		if cost.Less(options.MinCost) {
+			return fmt.Errorf("EditCost(%v, %v) is %v, below MinCost %v", source, target, cost, options.MinCost)
+		}
+		if options.MaxCost.Less(cost) {
+			return fmt.Errorf("EditCost(%v, %v) is %v, above MaxCost %v", source, target, cost, options.MaxCost)
+		}
+		if len(seen) < maxCheckedCosts {
+			for _, other := range seen {
+				if equalCost(cost, other) {
+					return nil
+				}
+			}
+			seen = append(seen, cost)
+		}
+		return nil
+	}
+	for _, source := range sources {
+		for _, target := range targets {
+			if err := check(source, target); err != nil {
+				return err
+			}
+		}
+		if err := check(source, nil); err != nil {
+			return err
+		}
+	}
+	for _, target := range targets {
+		if err := check(nil, target); err != nil {
+			return err
+		}
+	}
+
+	for _, a := range seen {
+		if sum := options.AddCost(a, options.MinCost); !equalCost(sum, a) {
+			return fmt.Errorf("AddCost(%v, MinCost) is %v, expected %v", a, sum, a)
+		}
+		if diff := options.SubCost(a, options.MinCost); !equalCost(diff, a) {
+			return fmt.Errorf("SubCost(%v, MinCost) is %v, expected %v", a, diff, a)
+		}
+		for _, b := range seen {
+			if diff := options.SubCost(options.AddCost(a, b), b); !equalCost(diff, a) {
+				return fmt.Errorf("SubCost(AddCost(%v, %v), %v) is %v, expected %v", a, b, b, diff, a)
+			}
+		}
+	}
+	return nil
+}
+
+func equalCost(a, b Cost) bool {
+	return !a.Less(b) && !b.Less(a)
+}
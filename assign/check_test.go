@@ -0,0 +1,163 @@
+package assign_test
+
+import (
+	"context"
+
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+func lengthOptions() *assign.AssignOptions {
+	options := deltaOptions(nil)
+	options.EditCost = func(source, target any) assign.Cost {
+		a, _ := source.(string)
+		b, _ := target.(string)
+		if len(a) > len(b) {
+			return uintCost(len(a) - len(b))
+		}
+		return uintCost(len(b) - len(a))
+	}
+	return options
+}
+
+func (*S) TestCheckMetric(c *C) {
+	samples := []any{"", "a", "ab", "abc"}
+	c.Assert(assign.CheckMetric(lengthOptions(), samples), IsNil)
+
+	options := lengthOptions()
+	options.EditCost = func(source, target any) assign.Cost {
+		if source == target {
+			return uintCost(1)
+		}
+		return uintCost(0)
+	}
+	c.Assert(assign.CheckMetric(options, samples), ErrorMatches, `EditCost\(, \) is 1, expected MinCost`)
+
+	options = lengthOptions()
+	options.EditCost = func(source, target any) assign.Cost {
+		if source == "a" && target == "ab" {
+			return uintCost(2)
+		}
+		return lengthOptions().EditCost(source, target)
+	}
+	c.Assert(assign.CheckMetric(options, samples), ErrorMatches, `EditCost\(a, ab\) is 2 but EditCost\(ab, a\) is 1`)
+
+	options = lengthOptions()
+	options.EditCost = func(source, target any) assign.Cost {
+		if source == target {
+			return uintCost(0)
+		}
+		if source == "a" && target == "abc" || source == "abc" && target == "a" {
+			return uintCost(5)
+		}
+		return uintCost(1)
+	}
+	c.Assert(assign.CheckMetric(options, samples), ErrorMatches, `EditCost\(a, abc\) is 5, above 2 via `)
+}
+
+func (*S) TestCheckCostConsistency(c *C) {
+	options := deltaOptions(costMap{namePair{"a", "b"}: 1})
+	c.Assert(assign.CheckCostConsistency(options, []any{"a"}, []any{"b"}), IsNil)
+
+	options.EditCost = func(source, target any) assign.Cost { return maxCost + 1 }
+	c.Assert(assign.CheckCostConsistency(options, []any{"a"}, []any{"b"}), ErrorMatches,
+		`EditCost\(a, b\) is 2147483649, above MaxCost 2147483648`)
+
+	options = deltaOptions(nil)
+	options.MinCost = uintCost(1)
+	c.Assert(assign.CheckCostConsistency(options, []any{"a"}, []any{"b"}), ErrorMatches,
+		`AddCost\(2147483648, MinCost\) is 2147483649, expected 2147483648`)
+
+	options = deltaOptions(nil)
+	options.MinCost, options.MaxCost = options.MaxCost, options.MinCost
+	c.Assert(assign.CheckCostConsistency(options, nil, nil), ErrorMatches,
+		`MinCost 2147483648 is not below MaxCost 0`)
+
+	options = deltaOptions(nil)
+	options.SubCost = nil
+	c.Assert(assign.CheckCostConsistency(options, nil, nil), ErrorMatches, `SubCost is not set`)
+}
+
+var validateTests = []struct {
+	summary string
+	options *assign.AssignOptions
+	error   string
+}{{
+	summary: "Nil options",
+	options: nil,
+	error:   "options are nil",
+}, {
+	summary: "Missing EditCost",
+	options: &assign.AssignOptions{},
+	error:   "EditCost is not set",
+}, {
+	summary: "Mismatched cost types",
+	options: &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost { return assign.IntCost(0) },
+		MinCost:  assign.IntCost(0),
+		MaxCost:  assign.MaxFloatCost,
+	},
+	error: "MinCost is of type assign.IntCost but MaxCost is of type assign.FloatCost",
+}, {
+	summary: "MaxFeasibleCost of another type",
+	options: &assign.AssignOptions{
+		EditCost:        func(source, target any) assign.Cost { return assign.IntCost(0) },
+		MaxFeasibleCost: assign.FloatCost(1),
+	},
+	error: "MaxFeasibleCost is of type assign.FloatCost but MinCost is of type assign.IntCost",
+}, {
+	summary: "MaxFeasibleCost with Maximize",
+	options: &assign.AssignOptions{
+		EditCost:        func(source, target any) assign.Cost { return assign.IntCost(0) },
+		MaxFeasibleCost: assign.IntCost(1),
+		Maximize:        true,
+	},
+	error: "MaxFeasibleCost cannot be used with Maximize",
+}, {
+	summary: "CostBound without MaxFeasibleCost",
+	options: &assign.AssignOptions{
+		EditCost:  func(source, target any) assign.Cost { return assign.IntCost(0) },
+		CostBound: func(source, target any) assign.Cost { return assign.IntCost(0) },
+	},
+	error: "CostBound is set without MaxFeasibleCost",
+}, {
+	summary: "AddCost with a different type",
+	options: &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost { return assign.IntCost(0) },
+		AddCost:  func(a, b assign.Cost) assign.Cost { return assign.FloatCost(0) },
+	},
+	error: "AddCost returned a Cost of different dynamic type: assign.FloatCost instead of assign.IntCost",
+}, {
+	summary: "Panicking arithmetic",
+	options: &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost { return assign.IntCost(0) },
+		SubCost:  func(a, b assign.Cost) assign.Cost { return a.(uintCost) - b.(uintCost) },
+	},
+	error: "cost arithmetic panicked on MinCost and MaxCost: .*",
+}, {
+	summary: "Unknown algorithm",
+	options: &assign.AssignOptions{
+		EditCost:  func(source, target any) assign.Cost { return assign.IntCost(0) },
+		Algorithm: assign.Algorithm(42),
+	},
+	error: "unknown algorithm 42",
+}, {
+	summary: "Valid options",
+	options: deltaOptions(nil),
+}}
+
+func (*S) TestValidate(c *C) {
+	for _, test := range validateTests {
+		c.Logf("Summary: %s", test.summary)
+		err := test.options.Validate()
+		if test.error == "" {
+			c.Assert(err, IsNil)
+			continue
+		}
+		c.Assert(err, ErrorMatches, test.error)
+		_, err = assign.AssignContext(context.Background(), []any{"a"}, []any{"b"}, test.options)
+		c.Assert(err, ErrorMatches, "invalid options: "+test.error)
+		c.Assert(func() { assign.Assign([]any{"a"}, []any{"b"}, test.options) }, PanicMatches, "assign: invalid options: "+test.error)
+	}
+}
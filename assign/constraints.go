@@ -0,0 +1,160 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"fmt"
+)
+
+// mustPairs returns the pairs forced by the MustPair option, the sources
+// and targets left to be assigned, and the position of each of those in
+// sources and targets. An error is returned if a node must be paired with
+// more than one other node, or if a pair is both required and forbidden.
+func (options *AssignOptions) mustPairs(sources, targets []any, editCost func(source, target any) Cost) (forced []Pair, restSources, restTargets []any, sourceIndex, targetIndex []int, err error) {
+	if options.MustPair == nil {
+		return nil, sources, targets, nil, nil, nil
+	}
+	sourceTarget := make([]int, len(sources))
+	targetSource := make([]int, len(targets))
+	for j := range targetSource {
+		targetSource[j] = -1
+	}
+	for i, source := range sources {
+		sourceTarget[i] = -1
+		for j, target := range targets {
+			if !options.MustPair(source, target) {
+				continue
+			}
+			if sourceTarget[i] >= 0 {
+				return nil, nil, nil, nil, nil, fmt.Errorf("cannot satisfy constraints: source %d must pair with targets %d and %d", i, sourceTarget[i], j)
+			}
+			if targetSource[j] >= 0 {
+				return nil, nil, nil, nil, nil, fmt.Errorf("cannot satisfy constraints: target %d must pair with sources %d and %d", j, targetSource[j], i)
+			}
+			if options.CannotPair != nil && options.CannotPair(source, target) {
+				return nil, nil, nil, nil, nil, fmt.Errorf("cannot satisfy constraints: source %d and target %d must and cannot pair", i, j)
+			}
+			sourceTarget[i] = j
+			targetSource[j] = i
+		}
+	}
+	// The slices of indexes are not nil, so that reindexPairs uses them.
+	sourceIndex = []int{}
+	targetIndex = []int{}
+	for i, source := range sources {
+		if j := sourceTarget[i]; j >= 0 {
+			forced = append(forced, Pair{Source: source, Target: targets[j], SourceIndex: i, TargetIndex: j, Cost: editCost(source, targets[j])})
+		} else {
+			restSources = append(restSources, source)
+			sourceIndex = append(sourceIndex, i)
+		}
+	}
+	for j, target := range targets {
+		if targetSource[j] < 0 {
+			restTargets = append(restTargets, target)
+			targetIndex = append(targetIndex, j)
+		}
+	}
+	return forced, restSources, restTargets, sourceIndex, targetIndex, nil
+}
+
+// indexCost returns editCost as a function of the positions of the nodes
+// in sources and targets, where a position of -1 stands for a nil node.
+func indexCost(sources, targets []any, editCost func(source, target any) Cost) func(i, j int) Cost {
+	return func(i, j int) Cost {
+		var source, target any
+		if i >= 0 {
+			source = sources[i]
+		}
+		if j >= 0 {
+			target = targets[j]
+		}
+		return editCost(source, target)
+	}
+}
+
+// infeasibleCost returns editCost for the nodes in sources and targets,
+// as indexCost does, modified so that pairs forbidden by the CannotPair
+// option, or costing more than the MaxFeasibleCost option, cost the same
+// as deleting the source and inserting the target, up to MaxCost. It also
+// returns a function that splits such pairs in the solution into a
+// deletion and an insertion, which must be called before the indexes of
+// the pairs are mapped to other positions.
+func (options *AssignOptions) infeasibleCost(sources, targets []any, editCost func(source, target any) Cost) (pairCost func(i, j int) Cost, split func(pairs []Pair) []Pair) {
+	if options.CannotPair == nil && options.MaxFeasibleCost == nil {
+		return indexCost(sources, targets, editCost), func(pairs []Pair) []Pair { return pairs }
+	}
+
+	// Infeasible pairs are recorded by the position of their nodes, as the
+	// nodes themselves may not be comparable. Each pair is only costed once,
+	// by a single goroutine, so the entries are set without locking.
+	m := len(targets)
+	infeasible := make([]bool, len(sources)*m)
+	feasible := func(source, target any) (Cost, bool) {
+		if options.CannotPair != nil && options.CannotPair(source, target) {
+			return nil, false
+		}
+		if options.MaxFeasibleCost == nil {
+			return editCost(source, target), true
+		}
+		if options.CostBound == nil || !options.MaxFeasibleCost.Less(options.bound(source, target)) {
+			if cost := editCost(source, target); !options.MaxFeasibleCost.Less(cost) {
+				return cost, true
+			}
+		}
+		return nil, false
+	}
+
+	unpaired := indexCost(sources, targets, editCost)
+	pairCost = func(i, j int) Cost {
+		if i < 0 || j < 0 {
+			return unpaired(i, j)
+		}
+		source, target := sources[i], targets[j]
+		if cost, ok := feasible(source, target); ok {
+			return cost
+		}
+		infeasible[i*m+j] = true
+		cost := options.AddCost(editCost(source, nil), editCost(nil, target))
+		if options.MaxCost.Less(cost) {
+			return options.MaxCost
+		}
+		return cost
+	}
+
+	split = func(pairs []Pair) []Pair {
+		var result []Pair
+		for _, pair := range pairs {
+			if pair.SourceIndex >= 0 && pair.TargetIndex >= 0 && infeasible[pair.SourceIndex*m+pair.TargetIndex] {
+				result = append(result,
+					Pair{Source: pair.Source, Target: nil, SourceIndex: pair.SourceIndex, TargetIndex: -1, Cost: editCost(pair.Source, nil)},
+					Pair{Source: nil, Target: pair.Target, SourceIndex: -1, TargetIndex: pair.TargetIndex, Cost: editCost(nil, pair.Target)},
+				)
+				continue
+			}
+			result = append(result, pair)
+		}
+		return result
+	}
+	return pairCost, split
+}
+
+// bound returns the lower bound for the cost of pairing source with target
+// computed by the CostBound option. It panics with a *CallbackError if the
+// callback panics or returns a cost of another type than MinCost.
+func (options *AssignOptions) bound(source, target any) Cost {
+	return options.call("CostBound", source, target, func() Cost { return options.CostBound(source, target) })
+}
@@ -0,0 +1,166 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"math"
+)
+
+// IntCost is a Cost holding an integer.
+//
+// When costs are of this type, AssignOptions may leave AddCost, SubCost,
+// MinCost, and MaxCost unset, in which case MinCost defaults to zero and
+// MaxCost defaults to MaxIntCost. The default arithmetic saturates rather
+// than overflowing, as done by SaturatingAdd and SaturatingSub.
+type IntCost int64
+
+func (c IntCost) Less(other Cost) bool { return c < other.(IntCost) }
+
+// FloatCost is a Cost holding a floating point number.
+//
+// When costs are of this type, AssignOptions may leave AddCost and SubCost
+// unset, and also MinCost and MaxCost as long as one of them is set, so that
+// the cost type is known. MinCost defaults to zero, and MaxCost defaults to
+// MaxFloatCost.
+type FloatCost float64
+
+func (c FloatCost) Less(other Cost) bool { return c < other.(FloatCost) }
+
+const (
+	// MaxIntCost is the default MaxCost for IntCost costs.
+	MaxIntCost IntCost = math.MaxInt32
+
+	// MaxFloatCost is the default MaxCost for FloatCost costs. It's an integer
+	// small enough that the arithmetic in the solver does not lose precision
+	// with costs at the other end of the range.
+	MaxFloatCost FloatCost = math.MaxInt32
+)
+
+var (
+	addIntCost = SaturatingAdd[IntCost]()
+	subIntCost = SaturatingSub[IntCost]()
+
+	addFloatCost = SaturatingAdd[FloatCost]()
+	subFloatCost = SaturatingSub[FloatCost]()
+)
+
+// SignedCost is the constraint for the cost types supported by SaturatingAdd
+// and SaturatingSub.
+//
+// Unsigned types are deliberately left out. The solvers keep partial costs
+// that are often negative, which unsigned types can only represent by
+// wrapping around, so their AddCost and SubCost must wrap rather than
+// saturate. The solvers never compare the wrapped values themselves, only
+// differences that are known to be non-negative.
+type SignedCost interface {
+	~int64 | ~float64
+	Cost
+}
+
+// SaturatingAdd returns an AddCost function for costs of type T that
+// saturates at the limits of T instead of overflowing.
+func SaturatingAdd[T SignedCost]() func(a, b Cost) Cost {
+	return func(a, b Cost) Cost {
+		return saturatingAdd(a.(T), b.(T))
+	}
+}
+
+// SaturatingSub returns a SubCost function for costs of type T that
+// saturates at the limits of T instead of overflowing.
+func SaturatingSub[T SignedCost]() func(a, b Cost) Cost {
+	return func(a, b Cost) Cost {
+		x, y := a.(T), b.(T)
+		if y == minSigned[T]() {
+			// -y overflows, so handle it separately.
+			if x >= 0 {
+				return maxSigned[T]()
+			}
+			return x - y
+		}
+		return saturatingAdd(x, -y)
+	}
+}
+
+func saturatingAdd[T SignedCost](x, y T) T {
+	sum := x + y
+	switch {
+	case y > 0 && sum < x:
+		return maxSigned[T]()
+	case y < 0 && sum > x:
+		return minSigned[T]()
+	}
+	return sum
+}
+
+// maxSigned and minSigned return the limits of T, which are infinite for
+// floating point types.
+func maxSigned[T SignedCost]() T {
+	var one T = 1
+	if one/2 > 0 {
+		return T(math.Inf(1))
+	}
+	return T(math.MaxInt64)
+}
+
+func minSigned[T SignedCost]() T {
+	var one T = 1
+	if one/2 > 0 {
+		return T(math.Inf(-1))
+	}
+	return T(math.MinInt64)
+}
+
+// withDefaults returns the options with the unset cost arithmetic filled in
+// for the built-in cost types, or the options themselves if nothing is missing.
+func (options *AssignOptions) withDefaults() *AssignOptions {
+	if options.AddCost != nil && options.SubCost != nil && options.MinCost != nil && options.MaxCost != nil {
+		return options
+	}
+	kind := options.MinCost
+	if kind == nil {
+		kind = options.MaxCost
+	}
+	result := *options
+	switch kind.(type) {
+	case nil, IntCost:
+		if result.MinCost == nil {
+			result.MinCost = IntCost(0)
+		}
+		if result.MaxCost == nil {
+			result.MaxCost = MaxIntCost
+		}
+		if result.AddCost == nil {
+			result.AddCost = addIntCost
+		}
+		if result.SubCost == nil {
+			result.SubCost = subIntCost
+		}
+	case FloatCost:
+		if result.MinCost == nil {
+			result.MinCost = FloatCost(0)
+		}
+		if result.MaxCost == nil {
+			result.MaxCost = MaxFloatCost
+		}
+		if result.AddCost == nil {
+			result.AddCost = addFloatCost
+		}
+		if result.SubCost == nil {
+			result.SubCost = subFloatCost
+		}
+	}
+	return &result
+}
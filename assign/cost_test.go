@@ -0,0 +1,128 @@
+package assign_test
+
+import (
+	"math"
+
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestIntCostDefaults(c *C) {
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			if source == nil || target == nil {
+				return assign.IntCost(10)
+			}
+			a, b := source.(int), target.(int)
+			if a > b {
+				return assign.IntCost(a - b)
+			}
+			return assign.IntCost(b - a)
+		},
+	}
+	pairs := assign.Assign([]any{1, 5, 20}, []any{4, 2}, options)
+	c.Assert(pairs, DeepEquals, []assign.Pair{
+		{Source: 5, Target: 4, SourceIndex: 1, TargetIndex: 0, Cost: assign.IntCost(1), Op: assign.Update},
+		{Source: 1, Target: 2, SourceIndex: 0, TargetIndex: 1, Cost: assign.IntCost(1), Op: assign.Update},
+		{Source: 20, Target: nil, SourceIndex: 2, TargetIndex: -1, Cost: assign.IntCost(10), Op: assign.Delete},
+	})
+	c.Assert(assign.CheckCostConsistency(options, []any{1, 5, 20}, []any{4, 2}), IsNil)
+}
+
+func (*S) TestFloatCostDefaults(c *C) {
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			if source == nil || target == nil {
+				return assign.FloatCost(0.5)
+			}
+			if source == target {
+				return assign.FloatCost(0)
+			}
+			return assign.MaxFloatCost
+		},
+		MaxCost: assign.MaxFloatCost,
+	}
+	pairs := assign.Assign([]any{"a", "b"}, []any{"b", "c"}, options)
+	c.Assert(pairs, DeepEquals, []assign.Pair{
+		{Source: "b", Target: "b", SourceIndex: 1, TargetIndex: 0, Cost: assign.FloatCost(0), Op: assign.Keep},
+		{Source: "a", Target: nil, SourceIndex: 0, TargetIndex: -1, Cost: assign.MaxFloatCost, Op: assign.Delete},
+		{Source: nil, Target: "c", SourceIndex: -1, TargetIndex: 1, Cost: assign.MaxFloatCost, Op: assign.Insert},
+	})
+}
+
+func (*S) TestMaximize(c *C) {
+	scores := map[[2]any]int{
+		{"a", "x"}: 10, {"a", "y"}: 8,
+		{"b", "x"}: 9, {"b", "y"}: 1,
+		{"c", "x"}: 0, {"c", "y"}: 0,
+	}
+	for _, algorithm := range []assign.Algorithm{assign.Hungarian, assign.Rectangular} {
+		options := &assign.AssignOptions{
+			Algorithm: algorithm,
+			Maximize:  true,
+			EditCost: func(source, target any) assign.Cost {
+				return assign.IntCost(scores[[2]any{source, target}])
+			},
+		}
+		pairs := assign.Assign([]any{"a", "b", "c"}, []any{"x", "y"}, options)
+		bySource := make(map[any]assign.Pair)
+		for _, pair := range pairs {
+			bySource[pair.Source] = pair
+		}
+		c.Assert(bySource, DeepEquals, map[any]assign.Pair{
+			"a": {Source: "a", Target: "y", SourceIndex: 0, TargetIndex: 1, Cost: assign.IntCost(8), Op: assign.Update},
+			"b": {Source: "b", Target: "x", SourceIndex: 1, TargetIndex: 0, Cost: assign.IntCost(9), Op: assign.Update},
+			"c": {Source: "c", Target: nil, SourceIndex: 2, TargetIndex: -1, Cost: assign.IntCost(0), Op: assign.Delete},
+		})
+	}
+
+	options := &assign.AssignOptions{
+		Maximize: true,
+		EditCost: func(source, target any) assign.Cost { return assign.IntCost(0) },
+	}
+	edges := []assign.Edge{{0, 0, assign.IntCost(3)}, {0, 1, assign.IntCost(5)}, {1, 0, assign.IntCost(4)}}
+	pairs := assign.AssignSparse([]any{"a", "b"}, []any{"x", "y"}, edges, options)
+	c.Assert(pairs, DeepEquals, []assign.Pair{
+		{Source: "a", Target: "y", SourceIndex: 0, TargetIndex: 1, Cost: assign.IntCost(5), Op: assign.Update},
+		{Source: "b", Target: "x", SourceIndex: 1, TargetIndex: 0, Cost: assign.IntCost(4), Op: assign.Update},
+	})
+}
+
+func (*S) TestInsertDeleteCost(c *C) {
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			if source == nil || target == nil {
+				panic("EditCost called for an insertion or deletion")
+			}
+			return assign.IntCost(5)
+		},
+		DeleteCost: func(source any) assign.Cost { return assign.IntCost(1) },
+		InsertCost: func(target any) assign.Cost { return assign.IntCost(2) },
+	}
+	pairs := assign.Assign([]any{"a"}, []any{"x", "y"}, options)
+	c.Assert(pairs, DeepEquals, []assign.Pair{
+		{Source: "a", Target: "x", SourceIndex: 0, TargetIndex: 0, Cost: assign.IntCost(5), Op: assign.Update},
+		{Source: nil, Target: "y", SourceIndex: -1, TargetIndex: 1, Cost: assign.IntCost(2), Op: assign.Insert},
+	})
+	c.Assert(assign.CheckCostConsistency(options, []any{"a"}, []any{"x", "y"}), IsNil)
+}
+
+type int64Cost int64
+
+func (c int64Cost) Less(other assign.Cost) bool { return c < other.(int64Cost) }
+
+func (*S) TestSaturating(c *C) {
+	add := assign.SaturatingAdd[int64Cost]()
+	sub := assign.SaturatingSub[int64Cost]()
+	c.Assert(add(int64Cost(1), int64Cost(2)), Equals, int64Cost(3))
+	c.Assert(add(int64Cost(math.MaxInt64), int64Cost(1)), Equals, int64Cost(math.MaxInt64))
+	c.Assert(add(int64Cost(math.MinInt64), int64Cost(-1)), Equals, int64Cost(math.MinInt64))
+	c.Assert(sub(int64Cost(1), int64Cost(3)), Equals, int64Cost(-2))
+	c.Assert(sub(int64Cost(math.MinInt64), int64Cost(1)), Equals, int64Cost(math.MinInt64))
+	c.Assert(sub(int64Cost(0), int64Cost(math.MinInt64)), Equals, int64Cost(math.MaxInt64))
+	c.Assert(sub(int64Cost(-1), int64Cost(math.MinInt64)), Equals, int64Cost(math.MaxInt64))
+
+	fadd := assign.SaturatingAdd[assign.FloatCost]()
+	c.Assert(fadd(assign.FloatCost(math.MaxFloat64), assign.FloatCost(math.MaxFloat64)), Equals, assign.FloatCost(math.Inf(1)))
+}
@@ -0,0 +1,15 @@
+package assign
+
+import "math"
+
+// AssignTypedSteps is the same as AssignTyped, also returning the number
+// of steps taken by its solver.
+func AssignTypedSteps[S, T any, C Number](sources []S, targets []T, options *TypedOptions[S, T, C]) ([]TypedPair[S, T, C], int) {
+	return assignTyped(sources, targets, options)
+}
+
+// AssignInt64Steps is the same as AssignInt64, also returning the number
+// of steps taken by its solver.
+func AssignInt64Steps(costs [][]int64) ([]int, int64, int) {
+	return assignNumbers(costs, int64(math.MaxInt64))
+}
@@ -0,0 +1,86 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"context"
+)
+
+// HierarchicalPair is a pair found by Hierarchical, along with the pairs
+// found among the children of its source and target when they were paired
+// with each other.
+type HierarchicalPair struct {
+	Pair
+	Children []HierarchicalPair
+}
+
+// Hierarchical assigns trees of nodes top down: sources are first assigned
+// into targets as done by Assign, and then the children of every source
+// paired with a target are assigned into the children of that target, and
+// so on, with the children of each node returned by the children function.
+// Nodes under a deleted source or an inserted target aren't assigned, as
+// they go along with it.
+//
+// Children are never paired across parents that weren't paired with each
+// other, so the pairs found respect the structure of the trees, and each
+// assignment only holds the children of two nodes rather than every node
+// of the trees. The pairs aren't those of an optimal assignment of all the
+// nodes at once, so EditCost should account for the children of nodes
+// when their similarity matters for pairing the nodes themselves.
+//
+// The SourceIndex and TargetIndex of pairs hold the position of nodes
+// among the children of their parent, or among sources and targets at the
+// top. Hierarchical panics as Assign does. Use HierarchicalContext to
+// obtain an error instead.
+func Hierarchical(sources, targets []any, children func(node any) []any, options *AssignOptions) []HierarchicalPair {
+	result, err := HierarchicalContext(context.Background(), sources, targets, children, options)
+	if err != nil {
+		panic("assign: " + err.Error())
+	}
+	return result
+}
+
+// HierarchicalContext is similar to Hierarchical, but returns an error in
+// the same cases as AssignContext.
+func HierarchicalContext(ctx context.Context, sources, targets []any, children func(node any) []any, options *AssignOptions) ([]HierarchicalPair, error) {
+	buffers := squarePool.Get().(*squareBuffers)
+	defer squarePool.Put(buffers)
+	defer buffers.clear()
+	return assignHierarchical(ctx, sources, targets, children, options, buffers)
+}
+
+func assignHierarchical(ctx context.Context, sources, targets []any, children func(node any) []any, options *AssignOptions, buffers *squareBuffers) ([]HierarchicalPair, error) {
+	pairs, err := assignContext(ctx, sources, targets, options, buffers)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]HierarchicalPair, len(pairs))
+	for i, pair := range pairs {
+		result[i].Pair = pair
+		if pair.Source == nil || pair.Target == nil {
+			continue
+		}
+		sourceChildren, targetChildren := children(pair.Source), children(pair.Target)
+		if len(sourceChildren) == 0 && len(targetChildren) == 0 {
+			continue
+		}
+		result[i].Children, err = assignHierarchical(ctx, sourceChildren, targetChildren, children, options, buffers)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,106 @@
+package assign_test
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+type treeNode struct {
+	name     string
+	children []any
+}
+
+func tree(name string, children ...*treeNode) *treeNode {
+	node := &treeNode{name: name}
+	for _, child := range children {
+		node.children = append(node.children, child)
+	}
+	return node
+}
+
+func treeChildren(node any) []any {
+	return node.(*treeNode).children
+}
+
+var treeOptions = &assign.AssignOptions{
+	EditCost: func(source, target any) assign.Cost {
+		if source == nil || target == nil {
+			return assign.IntCost(1)
+		}
+		if source.(*treeNode).name == target.(*treeNode).name {
+			return assign.IntCost(0)
+		}
+		return assign.MaxIntCost
+	},
+}
+
+func (*S) TestHierarchical(c *C) {
+	x, y, z := tree("x"), tree("y"), tree("z")
+	a := tree("a", x, y)
+	b := tree("b", tree("z"))
+	x2, y2 := tree("x"), tree("y")
+	a2 := tree("a", y2, x2)
+	c2 := tree("c", z)
+
+	pairs := assign.Hierarchical([]any{a, b}, []any{a2, c2}, treeChildren, treeOptions)
+	c.Assert(pairs, DeepEquals, []assign.HierarchicalPair{{
+		Pair: assign.Pair{Source: a, Target: a2, SourceIndex: 0, TargetIndex: 0, Cost: assign.IntCost(0), Op: assign.Keep},
+		Children: []assign.HierarchicalPair{
+			{Pair: assign.Pair{Source: y, Target: y2, SourceIndex: 1, TargetIndex: 0, Cost: assign.IntCost(0), Op: assign.Keep}},
+			{Pair: assign.Pair{Source: x, Target: x2, SourceIndex: 0, TargetIndex: 1, Cost: assign.IntCost(0), Op: assign.Keep}},
+		},
+	}, {
+		Pair: assign.Pair{Source: b, Target: nil, SourceIndex: 1, TargetIndex: -1, Cost: assign.MaxIntCost, Op: assign.Delete},
+	}, {
+		Pair: assign.Pair{Source: nil, Target: c2, SourceIndex: -1, TargetIndex: 1, Cost: assign.MaxIntCost, Op: assign.Insert},
+	}})
+
+	c.Assert(assign.Hierarchical(nil, nil, treeChildren, treeOptions), HasLen, 0)
+	c.Assert(func() { assign.Hierarchical([]any{a}, []any{a2}, treeChildren, &assign.AssignOptions{}) },
+		PanicMatches, `assign: .*EditCost.*`)
+}
+
+func randomTree(rnd *rand.Rand, depth int) *treeNode {
+	node := tree(fmt.Sprint(rnd.Intn(4)))
+	if depth > 0 {
+		for i := rnd.Intn(4); i > 0; i-- {
+			node.children = append(node.children, randomTree(rnd, depth-1))
+		}
+	}
+	return node
+}
+
+func (*S) TestHierarchicalRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		var sources, targets []any
+		for j := rnd.Intn(4); j > 0; j-- {
+			sources = append(sources, randomTree(rnd, 3))
+		}
+		for j := rnd.Intn(4); j > 0; j-- {
+			targets = append(targets, randomTree(rnd, 3))
+		}
+		c.Logf("Test %d: %d sources, %d targets", i, len(sources), len(targets))
+		checkHierarchical(c, assign.Hierarchical(sources, targets, treeChildren, treeOptions), sources, targets)
+	}
+}
+
+// checkHierarchical checks that the pairs found at every level are an
+// optimal assignment of the nodes at that level, and that children are
+// only assigned under nodes paired with each other.
+func checkHierarchical(c *C, pairs []assign.HierarchicalPair, sources, targets []any) {
+	flat := make([]assign.Pair, len(pairs))
+	for i, pair := range pairs {
+		flat[i] = pair.Pair
+		if pair.Source == nil || pair.Target == nil {
+			c.Assert(pair.Children, HasLen, 0)
+			continue
+		}
+		checkHierarchical(c, pair.Children, treeChildren(pair.Source), treeChildren(pair.Target))
+	}
+	c.Assert(assign.Verify(flat, sources, targets, treeOptions), IsNil)
+}
@@ -0,0 +1,204 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"container/heap"
+	"context"
+)
+
+// Solution is one of the assignments returned by KBest.
+type Solution struct {
+	Pairs []Pair
+
+	// Cost is the total cost of the assignment. A pair at MaxCost that is
+	// split into a deletion and an insertion is only accounted for once.
+	Cost Cost
+}
+
+// KBest returns up to k assignments of sources into targets in increasing
+// order of cost, the first one being an optimal assignment as returned by
+// Assign. Fewer than k solutions are returned if there aren't as many
+// different assignments.
+//
+// This is an implementation of Murty's algorithm ("An algorithm for ranking
+// all the assignments in order of increasing cost", 1968), on top of the
+// Rectangular solver irrespective of the Algorithm option. The solution
+// space is partitioned by forcing and forbidding pairs, so each solution
+// requires solving up to min(len(sources), len(targets)) subproblems.
+// Pairs required by MustPair are part of every solution, and pairs ruled
+// out by CannotPair or MaxFeasibleCost are deleted and inserted instead,
+// as done by Assign.
+//
+// KBest panics if the Maximize or TieBreak options are set, or if the
+// MustPair and CannotPair options can't be satisfied.
+func KBest(sources, targets []any, k int, options *AssignOptions) []Solution {
+	if err := options.Validate(); err != nil {
+		panic("assign: invalid options: " + err.Error())
+	}
+	options = options.withDefaults()
+	if options.Maximize || options.TieBreak != nil {
+		panic("assign: KBest does not support the Maximize and TieBreak options")
+	}
+	if options.Progress != nil {
+		// Rows are solved over and over for each of the solutions.
+		quiet := *options
+		quiet.Progress = nil
+		options = &quiet
+	}
+	if k <= 0 {
+		return nil
+	}
+
+	editCost := options.editCostFunc()
+	forced, sources, targets, sourceIndex, targetIndex, err := options.mustPairs(sources, targets, editCost)
+	if err != nil {
+		panic("assign: " + err.Error())
+	}
+	forcedCost := options.MinCost
+	for _, pair := range forced {
+		forcedCost = options.AddCost(forcedCost, pair.Cost)
+	}
+
+	ctx := context.Background()
+	pairCost, splitPairs := options.infeasibleCost(sources, targets, editCost)
+	problem, err := newRectangularProblem(ctx, sources, targets, pairCost, options)
+	if err != nil {
+		panic("assign: internal error: " + err.Error())
+	}
+
+	solve := func(node *murtyNode) bool {
+		allowed := node.allowed(problem.rows, problem.cols)
+		rowCol, err := optimalRectangular(ctx, problem.costs, problem.cols, allowed, options)
+		if err != nil {
+			return false
+		}
+		node.rowCol = rowCol
+		node.cost = problem.total(rowCol, options)
+		return true
+	}
+
+	root := &murtyNode{forced: make([]int, problem.rows)}
+	for r := range root.forced {
+		root.forced[r] = -1
+	}
+	solve(root)
+
+	queue := &murtyQueue{}
+	queue.push(root)
+
+	var result []Solution
+	for queue.Len() > 0 && len(result) < k {
+		node := heap.Pop(queue).(*murtyNode)
+		pairs := splitPairs(problem.pairs(node.rowCol, options))
+		reindexPairs(pairs, sourceIndex, targetIndex)
+		pairs = append(forced[:len(forced):len(forced)], pairs...)
+		options.classifyPairs(pairs)
+		result = append(result, Solution{Pairs: pairs, Cost: options.AddCost(forcedCost, node.cost)})
+
+		// Partition the remaining solutions of node so that the i-th child
+		// keeps the first i free rows of this solution, but not the next one.
+		forced := append([]int(nil), node.forced...)
+		for r, c := range node.rowCol {
+			if node.forced[r] >= 0 {
+				continue
+			}
+			child := &murtyNode{
+				forced:    append([]int(nil), forced...),
+				forbidden: append(node.forbidden[:len(node.forbidden):len(node.forbidden)], [2]int{r, c}),
+			}
+			if solve(child) {
+				queue.push(child)
+			}
+			forced[r] = c
+		}
+	}
+	return result
+}
+
+// murtyNode is a subset of the solution space where each row r is paired with
+// forced[r] if that's not -1, and no row r is paired with c if {r, c} is in
+// forbidden. The best solution in the subset pairs row r with rowCol[r].
+type murtyNode struct {
+	forced    []int
+	forbidden [][2]int
+	rowCol    []int
+	cost      Cost
+	seq       int
+}
+
+func (node *murtyNode) allowed(rows, cols int) [][]bool {
+	allowed := make([][]bool, rows)
+	for r := range allowed {
+		allowed[r] = make([]bool, cols)
+		for c := range allowed[r] {
+			allowed[r][c] = true
+		}
+	}
+	for r, c := range node.forced {
+		if c < 0 {
+			continue
+		}
+		for i := 0; i < rows; i++ {
+			allowed[i][c] = false
+		}
+		for j := 0; j < cols; j++ {
+			allowed[r][j] = false
+		}
+		allowed[r][c] = true
+	}
+	for _, pair := range node.forbidden {
+		allowed[pair[0]][pair[1]] = false
+	}
+	return allowed
+}
+
+// murtyQueue is a heap of nodes ordered by cost, and by the order in which
+// they were pushed on ties so results are deterministic.
+type murtyQueue struct {
+	nodes []*murtyNode
+	seq   int
+}
+
+func (q *murtyQueue) push(node *murtyNode) {
+	node.seq = q.seq
+	q.seq++
+	heap.Push(q, node)
+}
+
+func (q *murtyQueue) Len() int { return len(q.nodes) }
+
+func (q *murtyQueue) Less(i, j int) bool {
+	a, b := q.nodes[i], q.nodes[j]
+	if a.cost.Less(b.cost) {
+		return true
+	}
+	if b.cost.Less(a.cost) {
+		return false
+	}
+	return a.seq < b.seq
+}
+
+func (q *murtyQueue) Swap(i, j int) { q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i] }
+
+func (q *murtyQueue) Push(x any) { q.nodes = append(q.nodes, x.(*murtyNode)) }
+
+func (q *murtyQueue) Pop() any {
+	last := len(q.nodes) - 1
+	node := q.nodes[last]
+	q.nodes = q.nodes[:last]
+	return node
+}
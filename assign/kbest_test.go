@@ -0,0 +1,184 @@
+package assign_test
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestKBest(c *C) {
+	costs := map[[2]any]assign.IntCost{
+		{"a", "x"}: 1, {"a", "y"}: 2,
+		{"b", "x"}: 3, {"b", "y"}: 5,
+	}
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			if cost, ok := costs[[2]any{source, target}]; ok {
+				return cost
+			}
+			return assign.IntCost(10)
+		},
+	}
+	solutions := assign.KBest([]any{"a", "b"}, []any{"x", "y"}, 10, options)
+	c.Assert(solutions, DeepEquals, []assign.Solution{{
+		Pairs: []assign.Pair{{"a", "y", 0, 1, assign.IntCost(2), assign.Update}, {"b", "x", 1, 0, assign.IntCost(3), assign.Update}},
+		Cost:  assign.IntCost(5),
+	}, {
+		Pairs: []assign.Pair{{"a", "x", 0, 0, assign.IntCost(1), assign.Update}, {"b", "y", 1, 1, assign.IntCost(5), assign.Update}},
+		Cost:  assign.IntCost(6),
+	}})
+
+	c.Assert(assign.KBest([]any{"a", "b"}, []any{"x", "y"}, 1, options), HasLen, 1)
+	c.Assert(assign.KBest([]any{"a", "b"}, []any{"x", "y"}, 0, options), HasLen, 0)
+	c.Assert(assign.KBest(nil, nil, 3, options), DeepEquals, []assign.Solution{{Cost: assign.IntCost(0)}})
+}
+
+func (*S) TestKBestConstraints(c *C) {
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			if source == target {
+				return assign.IntCost(0)
+			}
+			return assign.IntCost(1)
+		},
+		CannotPair: func(source, target any) bool { return source == "a" },
+	}
+	c.Assert(assign.KBest([]any{"a"}, []any{"a"}, 3, options), DeepEquals, []assign.Solution{{
+		Pairs: []assign.Pair{{"a", nil, 0, -1, assign.IntCost(1), assign.Delete}, {nil, "a", -1, 0, assign.IntCost(1), assign.Insert}},
+		Cost:  assign.IntCost(2),
+	}})
+
+	// Pairs that must be made are part of every solution.
+	options.CannotPair = nil
+	options.MustPair = func(source, target any) bool { return source == "a" && target == "c" }
+	c.Assert(assign.KBest([]any{"a", "b"}, []any{"b", "c"}, 3, options), DeepEquals, []assign.Solution{{
+		Pairs: []assign.Pair{{"a", "c", 0, 1, assign.IntCost(1), assign.Update}, {"b", "b", 1, 0, assign.IntCost(0), assign.Keep}},
+		Cost:  assign.IntCost(1),
+	}})
+
+	options.CannotPair = func(source, target any) bool { return true }
+	c.Assert(func() { assign.KBest([]any{"a"}, []any{"c"}, 1, options) }, PanicMatches, "assign: cannot satisfy constraints: source 0 and target 0 must and cannot pair")
+
+	options = &assign.AssignOptions{
+		EditCost: options.EditCost,
+		TieBreak: func(a, b any) bool { return a.(string) < b.(string) },
+	}
+	c.Assert(func() { assign.KBest([]any{"a"}, []any{"c"}, 1, options) }, PanicMatches, "assign: KBest does not support the Maximize and TieBreak options")
+}
+
+func (*S) TestKBestMaxFeasibleCost(c *C) {
+	costs := map[[2]any]assign.IntCost{
+		{"a", "x"}: 1, {"a", "y"}: 2,
+		{"b", "x"}: 3, {"b", "y"}: 5,
+	}
+	var calls int
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			if cost, ok := costs[[2]any{source, target}]; ok {
+				calls++
+				return cost
+			}
+			return assign.IntCost(10)
+		},
+		MaxFeasibleCost: assign.IntCost(3),
+		CostBound: func(source, target any) assign.Cost {
+			if source == "b" && target == "y" {
+				return assign.IntCost(4)
+			}
+			return assign.IntCost(0)
+		},
+	}
+	solutions := assign.KBest([]any{"a", "b"}, []any{"x", "y"}, 10, options)
+	c.Assert(solutions, DeepEquals, []assign.Solution{{
+		Pairs: []assign.Pair{{"a", "y", 0, 1, assign.IntCost(2), assign.Update}, {"b", "x", 1, 0, assign.IntCost(3), assign.Update}},
+		Cost:  assign.IntCost(5),
+	}, {
+		Pairs: []assign.Pair{
+			{"a", "x", 0, 0, assign.IntCost(1), assign.Update},
+			{"b", nil, 1, -1, assign.IntCost(10), assign.Delete},
+			{nil, "y", -1, 1, assign.IntCost(10), assign.Insert},
+		},
+		Cost: assign.IntCost(21),
+	}})
+	c.Assert(calls, Equals, 3)
+}
+
+func (*S) TestKBestRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 50; i++ {
+		n := rnd.Intn(5)
+		m := rnd.Intn(5)
+		sources := make([]any, n)
+		targets := make([]any, m)
+		for j := range sources {
+			sources[j] = j
+		}
+		for j := range targets {
+			targets[j] = 100 + j
+		}
+		edits := make(map[[2]any]assign.IntCost)
+		for _, source := range append([]any{nil}, sources...) {
+			for _, target := range append([]any{nil}, targets...) {
+				edits[[2]any{source, target}] = assign.IntCost(rnd.Intn(20))
+			}
+		}
+		options := &assign.AssignOptions{
+			EditCost: func(source, target any) assign.Cost {
+				return edits[[2]any{source, target}]
+			},
+		}
+
+		// Enumerate every assignment of the smaller side into the larger one.
+		rows, cols := n, m
+		if n > m {
+			rows, cols = m, n
+		}
+		var all []int
+		var enumerate func(r int, used []bool, total assign.IntCost)
+		enumerate = func(r int, used []bool, total assign.IntCost) {
+			if r == rows {
+				for col := 0; col < cols; col++ {
+					if !used[col] {
+						if n > m {
+							total += edits[[2]any{sources[col], nil}]
+						} else {
+							total += edits[[2]any{nil, targets[col]}]
+						}
+					}
+				}
+				all = append(all, int(total))
+				return
+			}
+			for col := 0; col < cols; col++ {
+				if used[col] {
+					continue
+				}
+				used[col] = true
+				var cost assign.IntCost
+				if n > m {
+					cost = edits[[2]any{sources[col], targets[r]}]
+				} else {
+					cost = edits[[2]any{sources[r], targets[col]}]
+				}
+				enumerate(r+1, used, total+cost)
+				used[col] = false
+			}
+		}
+		enumerate(0, make([]bool, cols), 0)
+		sort.Ints(all)
+
+		solutions := assign.KBest(sources, targets, 8, options)
+		if len(all) > 8 {
+			all = all[:8]
+		}
+		var got []int
+		for _, solution := range solutions {
+			got = append(got, int(solution.Cost.(assign.IntCost)))
+			c.Assert(totalCost(solution.Pairs), Equals, solution.Cost)
+		}
+		c.Assert(got, DeepEquals, all)
+	}
+}
@@ -0,0 +1,85 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"context"
+	"fmt"
+)
+
+// AssignMatrix returns the minimum cost assignment of rows into columns
+// for the provided cost matrix, where costs[i][j] is the cost of assigning
+// row i to column j. The result holds the column assigned to each row, or
+// -1 if the row is left unassigned, which happens when there are more rows
+// than columns, or when the best column for a row costs MaxCost.
+//
+// EditCost and the options related to it are unused, and options may be nil
+// if costs are of the IntCost type. AssignMatrix panics if the matrix rows
+// have different lengths.
+func AssignMatrix(costs [][]Cost, options *AssignOptions) []int {
+	if options == nil {
+		options = &AssignOptions{}
+	}
+	if err := options.validate(false); err != nil {
+		panic("assign: invalid options: " + err.Error())
+	}
+	options = options.withDefaults()
+
+	n := len(costs)
+	m := 0
+	if n > 0 {
+		m = len(costs[0])
+	}
+	for i := range costs {
+		if len(costs[i]) != m {
+			panic(fmt.Sprintf("assign: matrix row %d has %d columns, expected %d", i, len(costs[i]), m))
+		}
+	}
+
+	result := make([]int, n)
+	for i := range result {
+		result[i] = -1
+	}
+	if n == 0 || m == 0 {
+		return result
+	}
+
+	// optimalRectangular needs no more rows than columns.
+	matrix := costs
+	if n > m {
+		matrix = make([][]Cost, m)
+		for j := range matrix {
+			matrix[j] = make([]Cost, n)
+			for i := range costs {
+				matrix[j][i] = costs[i][j]
+			}
+		}
+	}
+	rowCol, err := optimalRectangular(context.Background(), matrix, len(matrix[0]), nil, options)
+	if err != nil {
+		panic("assign: internal error: " + err.Error())
+	}
+	for r, c := range rowCol {
+		i, j := r, c
+		if n > m {
+			i, j = c, r
+		}
+		if costs[i][j] != options.MaxCost {
+			result[i] = j
+		}
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+package assign_test
+
+import (
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+func intMatrix(rows [][]int) [][]assign.Cost {
+	result := make([][]assign.Cost, len(rows))
+	for i, row := range rows {
+		for _, cost := range row {
+			result[i] = append(result[i], assign.IntCost(cost))
+		}
+	}
+	return result
+}
+
+var matrixTests = []struct {
+	summary string
+	costs   [][]int
+	result  []int
+}{{
+	summary: "Empty matrix",
+	costs:   nil,
+	result:  []int{},
+}, {
+	summary: "Square",
+	costs: [][]int{
+		{4, 1, 3},
+		{2, 0, 5},
+		{3, 2, 2},
+	},
+	result: []int{1, 0, 2},
+}, {
+	summary: "More columns than rows",
+	costs: [][]int{
+		{7, 3, 9, 1},
+		{2, 8, 1, 4},
+	},
+	result: []int{3, 2},
+}, {
+	summary: "More rows than columns",
+	costs: [][]int{
+		{7, 2},
+		{3, 8},
+		{1, 9},
+	},
+	result: []int{1, -1, 0},
+}, {
+	summary: "Max cost is left unassigned",
+	costs: [][]int{
+		{1, 2},
+		{int(assign.MaxIntCost), int(assign.MaxIntCost)},
+	},
+	result: []int{0, -1},
+}}
+
+func (*S) TestAssignMatrix(c *C) {
+	for _, test := range matrixTests {
+		c.Logf("Summary: %s", test.summary)
+		c.Assert(assign.AssignMatrix(intMatrix(test.costs), nil), DeepEquals, test.result)
+	}
+	c.Assert(func() { assign.AssignMatrix(intMatrix([][]int{{1, 2}, {3}}), nil) },
+		PanicMatches, "assign: matrix row 1 has 1 columns, expected 2")
+}
@@ -0,0 +1,139 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"fmt"
+	"math"
+)
+
+// AssignFloat64 returns the minimum cost assignment of rows into columns for
+// the provided cost matrix, where costs[i][j] is the cost of assigning row i
+// to column j, along with its total cost. The result holds the column
+// assigned to each row, or -1 for the rows left out when there are more rows
+// than columns.
+//
+// Unlike AssignMatrix, this works with plain arithmetic rather than with the
+// Cost interface, which makes it several times faster, and costs may be
+// negative. There's no MaxCost, so every row is assigned when possible.
+// AssignFloat64 panics if the matrix rows have different lengths.
+func AssignFloat64(costs [][]float64) ([]int, float64) {
+	result, total, _ := assignNumbers(costs, math.Inf(1))
+	return result, total
+}
+
+// AssignInt64 is the same as AssignFloat64 for integer costs. Costs must be
+// far enough from the limits of int64 for their sums not to overflow.
+func AssignInt64(costs [][]int64) ([]int, int64) {
+	result, total, _ := assignNumbers(costs, math.MaxInt64)
+	return result, total
+}
+
+// Matrix is a matrix of float64 costs. It's the part of the Matrix
+// interface of gonum.org/v1/gonum/mat used by AssignDense, so that gonum
+// matrices such as *mat.Dense may be assigned directly.
+type Matrix interface {
+	Dims() (r, c int)
+	At(i, j int) float64
+}
+
+// Float64Matrix adapts costs[i][j] to the Matrix interface. Its rows must
+// all have the same length.
+type Float64Matrix [][]float64
+
+func (m Float64Matrix) Dims() (r, c int) {
+	if len(m) == 0 {
+		return 0, 0
+	}
+	return len(m), len(m[0])
+}
+
+func (m Float64Matrix) At(i, j int) float64 {
+	return m[i][j]
+}
+
+// AssignDense returns the minimum cost assignment of rows into columns for
+// the provided cost matrix, as AssignFloat64 does, as pairs holding the
+// row and the column assigned to it, in order of their rows, along with
+// its total cost. Rows left out when there are more rows than columns
+// aren't in any pair.
+func AssignDense(costs Matrix) (pairs [][2]int, total float64) {
+	rows, ok := costs.(Float64Matrix)
+	if !ok {
+		n, m := costs.Dims()
+		rows = make(Float64Matrix, n)
+		for i := range rows {
+			rows[i] = make([]float64, m)
+			for j := range rows[i] {
+				rows[i][j] = costs.At(i, j)
+			}
+		}
+	}
+	result, total := AssignFloat64(rows)
+	for i, j := range result {
+		if j >= 0 {
+			pairs = append(pairs, [2]int{i, j})
+		}
+	}
+	return pairs, total
+}
+
+// assignNumbers implements AssignFloat64 and AssignInt64, also returning
+// the number of steps taken by optimalNumber.
+func assignNumbers[C Number](costs [][]C, inf C) ([]int, C, int) {
+	n := len(costs)
+	m := 0
+	if n > 0 {
+		m = len(costs[0])
+	}
+	for i := range costs {
+		if len(costs[i]) != m {
+			panic(fmt.Sprintf("assign: matrix row %d has %d columns, expected %d", i, len(costs[i]), m))
+		}
+	}
+
+	result := make([]int, n)
+	for i := range result {
+		result[i] = -1
+	}
+	if n == 0 || m == 0 {
+		return result, 0, 0
+	}
+
+	// Pad the matrix to a square with zero costs, which doesn't change the
+	// optimal assignment as every solution has the same number of padded cells.
+	size := max(n, m)
+	matrix := costs
+	if n != m {
+		matrix = make([][]C, size)
+		for i := range matrix {
+			matrix[i] = make([]C, size)
+			if i < n {
+				copy(matrix[i], costs[i])
+			}
+		}
+	}
+
+	var total C
+	optimal, steps := optimalNumber(matrix, inf)
+	for j, i := range optimal {
+		if i < n && j < m {
+			result[i] = j
+			total += costs[i][j]
+		}
+	}
+	return result, total, steps
+}
@@ -0,0 +1,151 @@
+package assign_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestAssignInt64(c *C) {
+	for _, test := range matrixTests {
+		if test.summary == "Max cost is left unassigned" {
+			continue
+		}
+		c.Logf("Summary: %s", test.summary)
+		var costs [][]int64
+		for _, row := range test.costs {
+			var costsRow []int64
+			for _, cost := range row {
+				costsRow = append(costsRow, int64(cost))
+			}
+			costs = append(costs, costsRow)
+		}
+		result, _ := assign.AssignInt64(costs)
+		c.Assert(result, DeepEquals, test.result)
+	}
+}
+
+func (*S) TestAssignNumbersRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		n := 1 + rnd.Intn(12)
+		m := 1 + rnd.Intn(12)
+		costs := make([][]assign.Cost, n)
+		ints := make([][]int64, n)
+		floats := make([][]float64, n)
+		for r := 0; r < n; r++ {
+			for j := 0; j < m; j++ {
+				cost := rnd.Intn(200) - 50
+				costs[r] = append(costs[r], assign.IntCost(cost+50))
+				ints[r] = append(ints[r], int64(cost))
+				floats[r] = append(floats[r], float64(cost)/4)
+			}
+		}
+		expected := assign.AssignMatrix(costs, nil)
+		var total int64
+		for r, j := range expected {
+			if j >= 0 {
+				total += int64(costs[r][j].(assign.IntCost)) - 50
+			}
+		}
+
+		result, intTotal := assign.AssignInt64(ints)
+		c.Assert(intTotal, Equals, total)
+		var check int64
+		for r, j := range result {
+			if j >= 0 {
+				check += ints[r][j]
+			}
+		}
+		c.Assert(check, Equals, total)
+
+		_, floatTotal := assign.AssignFloat64(floats)
+		c.Assert(math.Abs(floatTotal-float64(total)/4) < 1e-9, Equals, true)
+	}
+}
+
+// denseMatrix mimics the dense matrices of gonum, with elements stored
+// by row and a transpose method.
+type denseMatrix struct {
+	rows, cols int
+	data       []float64
+}
+
+func (m *denseMatrix) Dims() (r, c int)    { return m.rows, m.cols }
+func (m *denseMatrix) At(i, j int) float64 { return m.data[i*m.cols+j] }
+
+func (m *denseMatrix) T() assign.Matrix {
+	t := &denseMatrix{rows: m.cols, cols: m.rows, data: make([]float64, len(m.data))}
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			t.data[j*t.cols+i] = m.At(i, j)
+		}
+	}
+	return t
+}
+
+func (*S) TestAssignDense(c *C) {
+	costs := &denseMatrix{rows: 3, cols: 2, data: []float64{
+		4, 0.5,
+		2, 0,
+		3, 2.5,
+	}}
+	pairs, total := assign.AssignDense(costs)
+	c.Assert(pairs, DeepEquals, [][2]int{{0, 1}, {1, 0}})
+	c.Assert(total, Equals, 2.5)
+
+	pairs, total = assign.AssignDense(costs.T())
+	c.Assert(pairs, DeepEquals, [][2]int{{0, 1}, {1, 0}})
+	c.Assert(total, Equals, 2.5)
+
+	pairs, total = assign.AssignDense(assign.Float64Matrix{{1, 2}, {0, 5}})
+	c.Assert(pairs, DeepEquals, [][2]int{{0, 1}, {1, 0}})
+	c.Assert(total, Equals, 2.0)
+
+	pairs, total = assign.AssignDense(assign.Float64Matrix(nil))
+	c.Assert(pairs, HasLen, 0)
+	c.Assert(total, Equals, 0.0)
+}
+
+func (*S) TestAssignDenseRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		n := 1 + rnd.Intn(12)
+		m := 1 + rnd.Intn(12)
+		dense := &denseMatrix{rows: n, cols: m}
+		floats := make([][]float64, n)
+		for r := 0; r < n; r++ {
+			for j := 0; j < m; j++ {
+				cost := float64(rnd.Intn(200)-50) / 4
+				dense.data = append(dense.data, cost)
+				floats[r] = append(floats[r], cost)
+			}
+		}
+		c.Logf("Test %d: %dx%d", i, n, m)
+		result, expected := assign.AssignFloat64(floats)
+		pairs, total := assign.AssignDense(dense)
+		c.Assert(total, Equals, expected)
+		c.Assert(pairs, HasLen, min(n, m))
+		for _, pair := range pairs {
+			c.Assert(result[pair[0]], Equals, pair[1])
+		}
+	}
+}
+
+func BenchmarkAssignFloat64(b *testing.B) {
+	rnd := rand.New(rand.NewSource(42))
+	costs := make([][]float64, 300)
+	for i := range costs {
+		for j := 0; j < 300; j++ {
+			costs[i] = append(costs[i], rnd.Float64())
+		}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		assign.AssignFloat64(costs)
+	}
+}
@@ -0,0 +1,198 @@
+package assign_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+var algorithms = []assign.Algorithm{assign.Hungarian, assign.Rectangular, assign.Auction}
+
+// problem is an assignment problem between small integer nodes, with the
+// cost of every edit, including deletions and insertions, found in edits.
+type problem struct {
+	sources []any
+	targets []any
+	edits   map[[2]any]assign.IntCost
+}
+
+// newProblem returns a problem with n sources and m targets, and the cost
+// of each edit obtained from cost.
+func newProblem(n, m int, cost func() assign.IntCost) *problem {
+	p := &problem{
+		sources: make([]any, n),
+		targets: make([]any, m),
+		edits:   make(map[[2]any]assign.IntCost),
+	}
+	for i := range p.sources {
+		p.sources[i] = i
+	}
+	for j := range p.targets {
+		p.targets[j] = 100 + j
+	}
+	for _, source := range append([]any{nil}, p.sources...) {
+		for _, target := range append([]any{nil}, p.targets...) {
+			p.edits[[2]any{source, target}] = cost()
+		}
+	}
+	return p
+}
+
+func (p *problem) assign(algorithm assign.Algorithm) []assign.Pair {
+	return assign.Assign(p.sources, p.targets, &assign.AssignOptions{
+		Algorithm: algorithm,
+		EditCost: func(source, target any) assign.Cost {
+			return p.edits[[2]any{source, target}]
+		},
+	})
+}
+
+// bruteForce returns the lowest cost of the problem, found by trying every
+// permutation of the square matrix padded with deletions and insertions.
+// Costs at MaxIntCost are counted in maxed rather than added to sum, and
+// any solution with fewer of them is better.
+func (p *problem) bruteForce() (maxed int, sum assign.IntCost) {
+	n, m := len(p.sources), len(p.targets)
+	size := max(n, m)
+	cost := func(i, j int) assign.IntCost {
+		switch {
+		case i < n && j < m:
+			return p.edits[[2]any{p.sources[i], p.targets[j]}]
+		case i < n:
+			return p.edits[[2]any{p.sources[i], nil}]
+		case j < m:
+			return p.edits[[2]any{nil, p.targets[j]}]
+		}
+		return 0
+	}
+	maxed = size + 1
+	used := make([]bool, size)
+	var permute func(i, curMaxed int, curSum assign.IntCost)
+	permute = func(i, curMaxed int, curSum assign.IntCost) {
+		if i == size {
+			if curMaxed < maxed || curMaxed == maxed && curSum < sum {
+				maxed, sum = curMaxed, curSum
+			}
+			return
+		}
+		for j := 0; j < size; j++ {
+			if used[j] {
+				continue
+			}
+			used[j] = true
+			if c := cost(i, j); c == assign.MaxIntCost {
+				permute(i+1, curMaxed+1, curSum)
+			} else {
+				permute(i+1, curMaxed, curSum+c)
+			}
+			used[j] = false
+		}
+	}
+	permute(0, 0, 0)
+	return maxed, sum
+}
+
+// check returns an error if pairs do not use every node exactly once, at
+// its index and cost, and at the lowest total cost found by bruteForce.
+func (p *problem) check(pairs []assign.Pair) error {
+	n, m := len(p.sources), len(p.targets)
+	seen := make(map[any]bool)
+	deletes := 0
+	maxed := 0
+	var sum assign.IntCost
+	for _, pair := range pairs {
+		if pair.Source == nil && pair.Target == nil {
+			return fmt.Errorf("pair has nil source and target")
+		}
+		if index := pair.SourceIndex; index < 0 && pair.Source != nil || index >= 0 && p.sources[index] != pair.Source {
+			return fmt.Errorf("pair (%v, %v) has source index %d", pair.Source, pair.Target, index)
+		}
+		if index := pair.TargetIndex; index < 0 && pair.Target != nil || index >= 0 && p.targets[index] != pair.Target {
+			return fmt.Errorf("pair (%v, %v) has target index %d", pair.Source, pair.Target, index)
+		}
+		for _, node := range []any{pair.Source, pair.Target} {
+			if node != nil {
+				if seen[node] {
+					return fmt.Errorf("node %v used more than once", node)
+				}
+				seen[node] = true
+			}
+		}
+		cost := p.edits[[2]any{pair.Source, pair.Target}]
+		if pair.Cost != cost && pair.Cost != assign.MaxIntCost {
+			return fmt.Errorf("pair (%v, %v) has cost %v, expected %v", pair.Source, pair.Target, pair.Cost, cost)
+		}
+		if pair.Target == nil {
+			deletes++
+		}
+		if pair.Cost == assign.MaxIntCost {
+			maxed++
+		} else {
+			sum += pair.Cost.(assign.IntCost)
+		}
+	}
+	if len(seen) != n+m {
+		return fmt.Errorf("pairs use %d nodes, expected %d", len(seen), n+m)
+	}
+	// Pairs at MaxCost are split into a deletion and an insertion that
+	// stand for a single entry of the square matrix.
+	maxed -= deletes - max(0, n-m)
+	bestMaxed, bestSum := p.bruteForce()
+	if maxed != bestMaxed || sum != bestSum {
+		return fmt.Errorf("pairs cost %d + %d*MaxCost, expected %d + %d*MaxCost", sum, maxed, bestSum, bestMaxed)
+	}
+	return nil
+}
+
+func (*S) TestAssignProperties(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		n := rnd.Intn(6)
+		m := rnd.Intn(6)
+		// Few distinct costs make for many ties.
+		p := newProblem(n, m, func() assign.IntCost {
+			if rnd.Intn(6) == 0 {
+				return assign.MaxIntCost
+			}
+			return assign.IntCost(rnd.Intn(4))
+		})
+		for _, algorithm := range algorithms {
+			c.Logf("Test %d: %d×%d, algorithm %d", i, n, m, algorithm)
+			c.Assert(p.check(p.assign(algorithm)), IsNil)
+		}
+	}
+}
+
+// FuzzAssign compares the solution of every algorithm with the one found
+// by brute force, for problems of up to 6×6 nodes with costs taken from
+// data. A byte of 0xff stands for MaxIntCost.
+func FuzzAssign(f *testing.F) {
+	f.Add(uint8(0), uint8(0), []byte{})
+	f.Add(uint8(3), uint8(3), []byte{0, 1, 2, 3, 4, 5})
+	f.Add(uint8(2), uint8(5), []byte{7, 0xff, 1, 1, 0, 3})
+	f.Add(uint8(5), uint8(2), []byte{2, 0xff, 0xff, 4, 0, 1, 1})
+	f.Add(uint8(4), uint8(4), []byte{0xff})
+	f.Fuzz(func(t *testing.T, n, m uint8, data []byte) {
+		k := 0
+		p := newProblem(int(n%7), int(m%7), func() assign.IntCost {
+			if len(data) == 0 {
+				return 0
+			}
+			b := data[k%len(data)]
+			k++
+			if b == 0xff {
+				return assign.MaxIntCost
+			}
+			return assign.IntCost(b)
+		})
+		for _, algorithm := range algorithms {
+			if err := p.check(p.assign(algorithm)); err != nil {
+				t.Fatalf("algorithm %d: %v", algorithm, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,314 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"context"
+	"errors"
+)
+
+var errInfeasible = errors.New("no feasible assignment")
+
+// assignRectangular implements Assign for the Rectangular algorithm.
+func assignRectangular(ctx context.Context, sources, targets []any, pairCost func(i, j int) Cost, options *AssignOptions) ([]Pair, error) {
+	problem, err := newRectangularProblem(ctx, sources, targets, pairCost, options)
+	if err != nil {
+		return nil, err
+	}
+	rowCol, err := optimalRectangular(ctx, problem.costs, problem.cols, nil, options)
+	if err != nil {
+		return nil, err
+	}
+	return problem.pairs(rowCol, options), nil
+}
+
+// rectangularProblem holds the cost matrix for sources and targets in the
+// shape expected by optimalRectangular.
+type rectangularProblem struct {
+	sources    []any
+	targets    []any
+	transposed bool
+	rows       int
+	cols       int
+	costs      [][]Cost
+	unpaired   []Cost
+	shift      []Cost
+}
+
+// newRectangularProblem returns the problem for sources and targets, with
+// costs from pairCost, which takes the positions of the nodes as returned
+// by indexCost.
+func newRectangularProblem(ctx context.Context, sources, targets []any, pairCost func(i, j int) Cost, options *AssignOptions) (*rectangularProblem, error) {
+	n := len(sources)
+	m := len(targets)
+
+	// Rows are the smaller side, so every row is paired with a column,
+	// and the columns left unpaired are inserted or deleted.
+	p := &rectangularProblem{
+		sources:    sources,
+		targets:    targets,
+		transposed: n > m,
+		rows:       n,
+		cols:       m,
+	}
+	if p.transposed {
+		p.rows, p.cols = m, n
+	}
+
+	// The cost of leaving column c unpaired is folded into the cost of
+	// pairing it, which is the same as if the column was unpaired in every
+	// solution and pairing it saved that cost. Since costs can't be negative,
+	// the maximum unpaired cost is added as well, which doesn't change the
+	// result as every solution pairs exactly one column per row.
+	p.unpaired = make([]Cost, p.cols)
+	maxUnpaired := options.MinCost
+	for c := 0; c < p.cols; c++ {
+		if p.transposed {
+			p.unpaired[c] = pairCost(c, -1)
+		} else {
+			p.unpaired[c] = pairCost(-1, c)
+		}
+		if maxUnpaired.Less(p.unpaired[c]) {
+			maxUnpaired = p.unpaired[c]
+		}
+	}
+	p.shift = make([]Cost, p.cols)
+	for c := 0; c < p.cols; c++ {
+		p.shift[c] = options.SubCost(maxUnpaired, p.unpaired[c])
+	}
+
+	p.costs = make([][]Cost, p.rows)
+	for r := 0; r < p.rows; r++ {
+		p.costs[r] = make([]Cost, p.cols)
+	}
+	err := fillCosts(ctx, p.costs, p.rows, p.cols, options.Workers, func(r, c int) Cost {
+		return options.AddCost(pairCost(p.index(r, c)), p.shift[c])
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// node returns the source and target nodes for the given row and column.
+func (p *rectangularProblem) node(row, col int) (source, target any) {
+	if p.transposed {
+		return p.sources[col], p.targets[row]
+	}
+	return p.sources[row], p.targets[col]
+}
+
+// index returns the index of the source and of the target for the given
+// row and column.
+func (p *rectangularProblem) index(row, col int) (i, j int) {
+	if p.transposed {
+		return col, row
+	}
+	return row, col
+}
+
+// pairCost returns the cost of pairing the given row and column, without
+// the shift applied to the cost matrix.
+func (p *rectangularProblem) pairCost(row, col int, options *AssignOptions) Cost {
+	return options.SubCost(p.costs[row][col], p.shift[col])
+}
+
+// total returns the cost of the solution where row r is paired with rowCol[r].
+func (p *rectangularProblem) total(rowCol []int, options *AssignOptions) Cost {
+	total := options.MinCost
+	paired := make([]bool, p.cols)
+	for r, c := range rowCol {
+		paired[c] = true
+		total = options.AddCost(total, p.pairCost(r, c, options))
+	}
+	for c := 0; c < p.cols; c++ {
+		if !paired[c] {
+			total = options.AddCost(total, p.unpaired[c])
+		}
+	}
+	return total
+}
+
+// pairs returns the pairs for the solution where row r is paired with rowCol[r].
+func (p *rectangularProblem) pairs(rowCol []int, options *AssignOptions) []Pair {
+	colRow := make([]int, p.cols)
+	for c := range colRow {
+		colRow[c] = -1
+	}
+	for r, c := range rowCol {
+		colRow[c] = r
+	}
+
+	var result []Pair
+	for r := 0; r < p.rows; r++ {
+		c := rowCol[r]
+		source, target := p.node(r, c)
+		i, j := p.index(r, c)
+		cost := p.pairCost(r, c, options)
+		if cost == options.MaxCost {
+			// Remove + Insert
+			result = append(result, Pair{Source: source, Target: nil, SourceIndex: i, TargetIndex: -1, Cost: cost})
+			result = append(result, Pair{Source: nil, Target: target, SourceIndex: -1, TargetIndex: j, Cost: cost})
+		} else {
+			// Update
+			result = append(result, Pair{Source: source, Target: target, SourceIndex: i, TargetIndex: j, Cost: cost})
+		}
+	}
+	for c := 0; c < p.cols; c++ {
+		if colRow[c] >= 0 {
+			continue
+		}
+		if p.transposed {
+			// Remove
+			result = append(result, Pair{Source: p.sources[c], Target: nil, SourceIndex: c, TargetIndex: -1, Cost: p.unpaired[c]})
+		} else {
+			// Insert
+			result = append(result, Pair{Source: nil, Target: p.targets[c], SourceIndex: -1, TargetIndex: c, Cost: p.unpaired[c]})
+		}
+	}
+	return result
+}
+
+// optimalRectangular returns an array where result[r] = c means row r is
+// matched with column c. The cost matrix must have no more rows than
+// columns, and costs[r][c] is the cost of matching row r with column c.
+// If allowed is not nil, row r may only be matched with column c when
+// allowed[r][c] is true, and errInfeasible is returned if that's not possible.
+//
+// This is the shortest augmenting path algorithm described by Crouse in
+// "On implementing 2D rectangular assignment algorithms" (2016), which is
+// close in spirit to optimalCost. Each row in turn is added to the matching
+// by finding the cheapest path from it to an unmatched column, alternating
+// between unmatched and matched edges, using Dijkstra's algorithm over the
+// reduced costs. The dual variables rowCost and colCost keep every reduced
+// cost (cost - rowCost - colCost) non-negative, so all values compared are
+// non-negative and the algorithm works with unsigned cost types.
+func optimalRectangular(ctx context.Context, costs [][]Cost, cols int, allowed [][]bool, options *AssignOptions) ([]int, error) {
+	costRow := func(r int) []Cost { return costs[r] }
+	return optimalRows(ctx, len(costs), cols, costRow, allowed, options)
+}
+
+// optimalRows is the same as optimalRectangular, but obtains the costs for
+// each row from costRow when needed, rather than from a full cost matrix.
+// The slice returned by costRow is only used until costRow is called again.
+func optimalRows(ctx context.Context, rows, cols int, costRow func(r int) []Cost, allowed [][]bool, options *AssignOptions) ([]int, error) {
+	rowCost := make([]Cost, rows)
+	colCost := make([]Cost, cols)
+	rowCol := make([]int, rows)
+	colRow := make([]int, cols)
+	for r := range rowCost {
+		rowCost[r] = options.MinCost
+		rowCol[r] = -1
+	}
+	for c := range colCost {
+		colCost[c] = options.MinCost
+		colRow[c] = -1
+	}
+
+	// pathCost[c] is the cost of the shortest path found so far from the
+	// current row to column c, and pathRow[c] the row preceding c in it.
+	pathCost := make([]Cost, cols)
+	pathFound := make([]bool, cols)
+	pathRow := make([]int, cols)
+	visitedRow := make([]bool, rows)
+	visitedCol := make([]bool, cols)
+
+	// remaining holds the columns not yet visited in its first unvisited entries.
+	remaining := make([]int, cols)
+
+	for current := 0; current < rows; current++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for c := 0; c < cols; c++ {
+			pathFound[c] = false
+			visitedCol[c] = false
+			remaining[c] = c
+		}
+		for r := 0; r < rows; r++ {
+			visitedRow[r] = false
+		}
+		unvisited := cols
+
+		minCost := options.MinCost
+		row := current
+		sink := -1
+		for sink == -1 {
+			visitedRow[row] = true
+			next := -1
+			var lowest Cost
+			costs := costRow(row)
+			for k := 0; k < unvisited; k++ {
+				c := remaining[k]
+				if allowed == nil || allowed[row][c] {
+					reduced := options.SubCost(options.SubCost(options.AddCost(minCost, costs[c]), rowCost[row]), colCost[c])
+					if !pathFound[c] || reduced.Less(pathCost[c]) {
+						pathCost[c] = reduced
+						pathRow[c] = row
+						pathFound[c] = true
+					}
+				}
+				if !pathFound[c] {
+					continue
+				}
+				// On ties prefer unmatched columns, as they end the path.
+				if next == -1 || pathCost[c].Less(lowest) || colRow[c] == -1 && !lowest.Less(pathCost[c]) {
+					lowest = pathCost[c]
+					next = k
+				}
+			}
+
+			if next == -1 {
+				return nil, errInfeasible
+			}
+			minCost = lowest
+			c := remaining[next]
+			visitedCol[c] = true
+			unvisited--
+			remaining[next] = remaining[unvisited]
+			if colRow[c] == -1 {
+				sink = c
+			} else {
+				row = colRow[c]
+			}
+		}
+
+		// Update the dual variables so the edges in the path become tight.
+		rowCost[current] = options.AddCost(rowCost[current], minCost)
+		for r := 0; r < rows; r++ {
+			if visitedRow[r] && r != current {
+				rowCost[r] = options.AddCost(rowCost[r], options.SubCost(minCost, pathCost[rowCol[r]]))
+			}
+		}
+		for c := 0; c < cols; c++ {
+			if visitedCol[c] {
+				colCost[c] = options.SubCost(colCost[c], options.SubCost(minCost, pathCost[c]))
+			}
+		}
+
+		// Flip the edges along the path, as described in optimalCost.
+		for c := sink; ; {
+			r := pathRow[c]
+			colRow[c] = r
+			rowCol[r], c = c, rowCol[r]
+			if r == current {
+				break
+			}
+		}
+		options.progress(current+1, rows)
+	}
+	return rowCol, nil
+}
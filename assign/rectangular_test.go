@@ -0,0 +1,146 @@
+package assign_test
+
+import (
+	"math/rand"
+
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestRectangularRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		n := rnd.Intn(8)
+		m := rnd.Intn(8)
+		sources := make([]any, n)
+		targets := make([]any, m)
+		for j := range sources {
+			sources[j] = j
+		}
+		for j := range targets {
+			targets[j] = 100 + j
+		}
+		edits := make(map[[2]any]assign.IntCost)
+		for _, source := range append([]any{nil}, sources...) {
+			for _, target := range append([]any{nil}, targets...) {
+				edits[[2]any{source, target}] = assign.IntCost(rnd.Intn(20))
+			}
+		}
+		options := &assign.AssignOptions{
+			EditCost: func(source, target any) assign.Cost {
+				return edits[[2]any{source, target}]
+			},
+		}
+		hungarian := assign.Assign(sources, targets, options)
+		options.Algorithm = assign.Rectangular
+		rectangular := assign.Assign(sources, targets, options)
+
+		c.Assert(totalCost(rectangular), Equals, totalCost(hungarian))
+		seen := make(map[any]bool)
+		for _, pair := range rectangular {
+			for _, node := range []any{pair.Source, pair.Target} {
+				if node != nil {
+					c.Assert(seen[node], Equals, false)
+					seen[node] = true
+				}
+			}
+		}
+		c.Assert(seen, HasLen, n+m)
+	}
+}
+
+func (*S) TestRectangularBruteForce(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		n := rnd.Intn(6)
+		m := rnd.Intn(6)
+		if i%2 == 0 && n > m {
+			// Favor wide inputs, where targets must be inserted.
+			n, m = m, n
+		}
+		sources := make([]any, n)
+		targets := make([]any, m)
+		for j := range sources {
+			sources[j] = j
+		}
+		for j := range targets {
+			targets[j] = 100 + j
+		}
+		edits := make(map[[2]any]assign.IntCost)
+		for _, source := range append([]any{nil}, sources...) {
+			for _, target := range append([]any{nil}, targets...) {
+				edits[[2]any{source, target}] = assign.IntCost(rnd.Intn(20))
+			}
+		}
+		editCost := func(source, target any) assign.Cost {
+			return edits[[2]any{source, target}]
+		}
+		best := bruteForceCost(sources, targets, edits)
+
+		for _, algorithm := range algorithms {
+			c.Logf("Test %d: %d×%d, algorithm %d", i, n, m, algorithm)
+			pairs := assign.Assign(sources, targets, &assign.AssignOptions{
+				Algorithm: algorithm,
+				EditCost:  editCost,
+			})
+			c.Assert(totalCost(pairs), Equals, best)
+			seen := make(map[any]bool)
+			for _, pair := range pairs {
+				c.Assert(pair.Cost, Equals, edits[[2]any{pair.Source, pair.Target}])
+				for _, node := range []any{pair.Source, pair.Target} {
+					if node != nil {
+						c.Assert(seen[node], Equals, false)
+						seen[node] = true
+					}
+				}
+			}
+			c.Assert(seen, HasLen, n+m)
+		}
+	}
+}
+
+// bruteForceCost returns the lowest total cost of pairing sources
+// with targets, trying every source against every unused target
+// and against deletion, and inserting the targets left unused.
+// As in Assign, as many pairs as possible are made, so only the
+// sources in excess of the targets may be deleted.
+func bruteForceCost(sources, targets []any, edits map[[2]any]assign.IntCost) assign.IntCost {
+	used := make([]bool, len(targets))
+	deletions := max(0, len(sources)-len(targets))
+	var try func(i int) assign.IntCost
+	try = func(i int) assign.IntCost {
+		if i == len(sources) {
+			var total assign.IntCost
+			for j, target := range targets {
+				if !used[j] {
+					total += edits[[2]any{nil, target}]
+				}
+			}
+			return total
+		}
+		best := assign.MaxIntCost
+		if deletions > 0 {
+			deletions--
+			best = edits[[2]any{sources[i], nil}] + try(i+1)
+			deletions++
+		}
+		for j, target := range targets {
+			if !used[j] {
+				used[j] = true
+				best = min(best, edits[[2]any{sources[i], target}]+try(i+1))
+				used[j] = false
+			}
+		}
+		return best
+	}
+	return try(0)
+}
+
+func totalCost(pairs []assign.Pair) assign.IntCost {
+	var total assign.IntCost
+	for _, pair := range pairs {
+		total += pair.Cost.(assign.IntCost)
+	}
+	return total
+}
@@ -0,0 +1,95 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"context"
+	"time"
+)
+
+// AssignResult holds the pairs found by AssignDetailed along with the
+// dual variables of the solution, which allow telling how far from
+// optimal the alternative pairings were.
+type AssignResult struct {
+	Pairs []Pair
+
+	// SourcePotentials[i] and TargetPotentials[j] are the dual variables
+	// for sources[i] and targets[j]. Their sum never exceeds the cost of
+	// pairing these nodes, and is equal to it for nodes paired together.
+	// Potentials may be negative, and thus wrap around with unsigned cost
+	// types, but the result of ReducedCost is accurate regardless.
+	SourcePotentials []Cost
+	TargetPotentials []Cost
+
+	costs   [][]Cost
+	options *AssignOptions
+}
+
+// ReducedCost returns how much the cost of pairing sources[i] with
+// targets[j] exceeds the sum of their potentials. It is MinCost for nodes
+// paired in the solution, and for other nodes it is a lower bound on how
+// much the total cost would increase if they were paired together.
+func (r *AssignResult) ReducedCost(i, j int) Cost {
+	options := r.options
+	return options.SubCost(options.SubCost(r.costs[i][j], r.SourcePotentials[i]), r.TargetPotentials[j])
+}
+
+// AssignDetailed is similar to Assign, but also returns the dual variables
+// of the solution. It always uses the Hungarian algorithm, and costs and
+// potentials are in terms of the cost matrix even when maximizing.
+//
+// AssignDetailed panics if the MustPair or TieBreak options are set.
+func AssignDetailed(sources, targets []any, options *AssignOptions) *AssignResult {
+	if err := options.Validate(); err != nil {
+		panic("assign: invalid options: " + err.Error())
+	}
+	options = options.withDefaults()
+	if options.MustPair != nil || options.TieBreak != nil {
+		panic("assign: AssignDetailed does not support the MustPair and TieBreak options")
+	}
+
+	start := time.Now()
+	editCost := options.editCostFunc()
+	if options.Maximize {
+		score := editCost
+		editCost = func(source, target any) Cost {
+			return options.SubCost(options.MaxCost, score(source, target))
+		}
+	}
+
+	ctx := context.Background()
+	buffers := &squareBuffers{}
+	pairCost, splitPairs := options.infeasibleCost(sources, targets, editCost)
+	costs, err := squareCosts(ctx, sources, targets, pairCost, options, buffers)
+	if err != nil {
+		panic("assign: internal error: " + err.Error())
+	}
+	options.traceCosts(sources, targets, costs)
+	optimal, sourceCost, targetCost, err := optimalDuals(ctx, costs, options, buffers)
+	if err != nil {
+		panic("assign: internal error: " + err.Error())
+	}
+
+	pairs := splitPairs(squarePairs(sources, targets, costs, optimal, options))
+	options.finishPairs(pairs, start)
+	return &AssignResult{
+		Pairs:            pairs,
+		SourcePotentials: sourceCost[:len(sources)],
+		TargetPotentials: targetCost[:len(targets)],
+		costs:            costs,
+		options:          options,
+	}
+}
@@ -0,0 +1,45 @@
+package assign_test
+
+import (
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestAssignDetailed(c *C) {
+	costs := map[[2]any]assign.IntCost{
+		{"a", "x"}: 1, {"a", "y"}: 4, {"a", "z"}: 9,
+		{"b", "x"}: 2, {"b", "y"}: 3, {"b", "z"}: 5,
+	}
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			if cost, ok := costs[[2]any{source, target}]; ok {
+				return cost
+			}
+			return assign.IntCost(6)
+		},
+	}
+	sources := []any{"a", "b"}
+	targets := []any{"x", "y", "z"}
+	result := assign.AssignDetailed(sources, targets, options)
+	c.Assert(result.Pairs, DeepEquals, assign.Assign(sources, targets, options))
+	c.Assert(result.SourcePotentials, HasLen, 2)
+	c.Assert(result.TargetPotentials, HasLen, 3)
+
+	paired := make(map[[2]any]bool)
+	for _, pair := range result.Pairs {
+		paired[[2]any{pair.Source, pair.Target}] = true
+	}
+	for i, source := range sources {
+		for j, target := range targets {
+			reduced := result.ReducedCost(i, j)
+			if paired[[2]any{source, target}] {
+				c.Assert(reduced, Equals, assign.IntCost(0))
+			} else {
+				c.Assert(reduced.(assign.IntCost) >= 0, Equals, true)
+			}
+		}
+	}
+	// Pairing a with y and b with x costs 4+2 rather than 1+3.
+	c.Assert(result.ReducedCost(0, 1), Equals, assign.IntCost(2))
+}
@@ -0,0 +1,103 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"context"
+	"sync"
+)
+
+// Solver assigns sources into targets as Assign does, but keeps the memory
+// used by the Hungarian algorithm across calls, so that code calling it in
+// a hot path doesn't allocate the cost matrix and other buffers every time.
+// Assign itself draws these buffers from a shared pool, which helps to a
+// lesser degree.
+//
+// A Solver must not be used concurrently.
+type Solver struct {
+	options *AssignOptions
+	buffers squareBuffers
+}
+
+// NewSolver returns a Solver using the provided options for every call.
+func NewSolver(options *AssignOptions) *Solver {
+	return &Solver{options: options}
+}
+
+// Assign is the same as the Assign function, using the solver's options.
+func (s *Solver) Assign(sources, targets []any) []Pair {
+	result, err := s.AssignContext(context.Background(), sources, targets)
+	if err != nil {
+		panic("assign: " + err.Error())
+	}
+	return result
+}
+
+// AssignContext is the same as the AssignContext function, using the
+// solver's options.
+func (s *Solver) AssignContext(ctx context.Context, sources, targets []any) ([]Pair, error) {
+	result, err := assignContext(ctx, sources, targets, s.options, &s.buffers)
+	s.buffers.clear()
+	return result, err
+}
+
+// Reset releases the memory held by the solver.
+func (s *Solver) Reset() {
+	s.buffers = squareBuffers{}
+}
+
+// squareBuffers holds the memory used by squareCosts and optimalDuals.
+type squareBuffers struct {
+	cells         []Cost
+	rows          [][]Cost
+	sourceCost    []Cost
+	targetCost    []Cost
+	minSlack      []Cost
+	targetSource  []int
+	targetTrail   []int
+	visitedTarget []bool
+}
+
+var squarePool = sync.Pool{
+	New: func() any { return &squareBuffers{} },
+}
+
+// matrix returns a size×size matrix backed by the buffers.
+func (b *squareBuffers) matrix(size int) [][]Cost {
+	b.cells = grow(b.cells, size*size)
+	b.rows = grow(b.rows, size)
+	for i := range b.rows {
+		b.rows[i] = b.cells[i*size : (i+1)*size : (i+1)*size]
+	}
+	return b.rows
+}
+
+// clear drops the references to costs held by the buffers, so they may be
+// garbage collected, while keeping the memory itself.
+func (b *squareBuffers) clear() {
+	clear(b.cells)
+	clear(b.sourceCost)
+	clear(b.targetCost)
+	clear(b.minSlack)
+}
+
+// grow returns s resized to n elements, reallocating it if needed.
+func grow[T any](s []T, n int) []T {
+	if cap(s) < n {
+		return make([]T, n)
+	}
+	return s[:n]
+}
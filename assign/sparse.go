@@ -0,0 +1,261 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/canonical/go-algo/pqueue"
+)
+
+// Edge is a feasible pairing between sources[Source] and targets[Target]
+// with the given cost, as provided to AssignSparse.
+type Edge struct {
+	Source int
+	Target int
+	Cost   Cost
+}
+
+// AssignSparse is similar to Assign, but only the provided edges are
+// considered for pairing sources with targets. Any other pair is taken as
+// having MaxCost, so the respective nodes may only be deleted or inserted.
+//
+// EditCost is only called to obtain the cost of deleting each source and
+// inserting each target, and memory use grows with the number of edges
+// rather than with the product of the number of sources and targets.
+// With e edges and n+m nodes, this runs in O((n+m)·(n+m+e)·log(n+m)) time.
+//
+// Unlike Assign, any source may be deleted and any target inserted even
+// when their numbers match, so no pair is ever split for having MaxCost.
+// If there are multiple edges for the same pair, the cheapest one is used,
+// or the one with the highest score when maximizing. Edges between nodes
+// ruled out by CannotPair, or costing more than MaxFeasibleCost, are
+// dropped. CostBound is unused, as the costs of edges are already known.
+//
+// AssignSparse panics if an edge refers to a node out of range, or if the
+// MustPair or TieBreak options are set.
+func AssignSparse(sources, targets []any, edges []Edge, options *AssignOptions) []Pair {
+	if err := options.Validate(); err != nil {
+		panic("assign: invalid options: " + err.Error())
+	}
+	options = options.withDefaults()
+	if options.MustPair != nil || options.TieBreak != nil {
+		panic("assign: AssignSparse does not support the MustPair and TieBreak options")
+	}
+
+	start := time.Now()
+	editCost := options.editCostFunc()
+	if options.Maximize {
+		score := editCost
+		editCost = func(source, target any) Cost {
+			return options.SubCost(options.MaxCost, score(source, target))
+		}
+	}
+
+	n := len(sources)
+	m := len(targets)
+
+	// The graph has a left node per source and per target insertion (n+j),
+	// and a right node per target and per source deletion (m+i). Besides
+	// the real edges, source i may be deleted by pairing it with m+i, and
+	// target j may be inserted by pairing n+j with it.
+	//
+	// The phantom insertion and deletion nodes left over must be paired
+	// with each other at no cost. Any pairing will do, so rather than
+	// connecting all of them, n+j is only connected to m+i when there's
+	// an edge from i to j. That's enough for every solution to remain
+	// possible: if i is paired with j, their phantom nodes are left over
+	// and may be paired together.
+	size := n + m
+	graph := make([][]sparseEdge, size)
+	for _, edge := range edges {
+		if edge.Source < 0 || edge.Source >= n || edge.Target < 0 || edge.Target >= m {
+			panic(fmt.Sprintf("assign: edge (%d, %d) out of range for %d sources and %d targets", edge.Source, edge.Target, n, m))
+		}
+		if options.CannotPair != nil && options.CannotPair(sources[edge.Source], targets[edge.Target]) {
+			continue
+		}
+		if options.MaxFeasibleCost != nil && options.MaxFeasibleCost.Less(edge.Cost) {
+			continue
+		}
+		cost := edge.Cost
+		if options.Maximize {
+			cost = options.SubCost(options.MaxCost, cost)
+		}
+		if !cost.Less(options.MaxCost) {
+			continue
+		}
+		graph[edge.Source] = append(graph[edge.Source], sparseEdge{edge.Target, cost})
+		graph[n+edge.Target] = append(graph[n+edge.Target], sparseEdge{m + edge.Source, options.MinCost})
+	}
+	for i := 0; i < n; i++ {
+		graph[i] = append(graph[i], sparseEdge{m + i, editCost(sources[i], nil)})
+	}
+	for j := 0; j < m; j++ {
+		graph[n+j] = append(graph[n+j], sparseEdge{j, editCost(nil, targets[j])})
+	}
+
+	match := optimalSparse(graph, options)
+
+	var result []Pair
+	for i := 0; i < n; i++ {
+		e := graph[i][match[i]]
+		if e.col == m+i {
+			// Remove
+			result = append(result, Pair{Source: sources[i], Target: nil, SourceIndex: i, TargetIndex: -1, Cost: e.cost})
+		} else {
+			// Update
+			result = append(result, Pair{Source: sources[i], Target: targets[e.col], SourceIndex: i, TargetIndex: e.col, Cost: e.cost})
+		}
+	}
+	for j := 0; j < m; j++ {
+		e := graph[n+j][match[n+j]]
+		if e.col == j {
+			// Insert
+			result = append(result, Pair{Source: nil, Target: targets[j], SourceIndex: -1, TargetIndex: j, Cost: e.cost})
+		}
+	}
+	options.finishPairs(result, start)
+	return result
+}
+
+type sparseEdge struct {
+	col  int
+	cost Cost
+}
+
+// optimalSparse returns an array where result[r] = k means row r is matched
+// through its edge graph[r][k]. The graph must be square and have a perfect
+// matching.
+//
+// This is the same shortest augmenting path algorithm used by
+// optimalRectangular, with a heap taking the place of the linear scan for
+// the closest column, so only existing edges are ever considered.
+func optimalSparse(graph [][]sparseEdge, options *AssignOptions) []int {
+	size := len(graph)
+
+	rowCost := make([]Cost, size)
+	colCost := make([]Cost, size)
+	rowEdge := make([]int, size)
+	colRow := make([]int, size)
+	for i := 0; i < size; i++ {
+		rowCost[i] = options.MinCost
+		colCost[i] = options.MinCost
+		rowEdge[i] = -1
+		colRow[i] = -1
+	}
+
+	pathCost := make([]Cost, size)
+	pathRow := make([]int, size)
+	pathEdge := make([]int, size)
+	pathFound := make([]bool, size)
+	rowCostFound := make([]Cost, size)
+	visitedCol := make([]bool, size)
+	var visitedRows, touchedCols []int
+
+	// Columns are taken by path cost, preferring unmatched columns on ties
+	// as they end the path.
+	queue := pqueue.New(func(a, b sparsePriority) bool {
+		if a.cost.Less(b.cost) {
+			return true
+		}
+		return !b.cost.Less(a.cost) && !a.matched && b.matched
+	})
+
+	for current := 0; current < size; current++ {
+		for _, c := range touchedCols {
+			pathFound[c] = false
+			visitedCol[c] = false
+		}
+		touchedCols = touchedCols[:0]
+		visitedRows = visitedRows[:0]
+		queue.Clear()
+
+		minCost := options.MinCost
+		row := current
+		sink := -1
+		for sink == -1 {
+			visitedRows = append(visitedRows, row)
+			rowCostFound[row] = minCost
+			for k, e := range graph[row] {
+				c := e.col
+				if visitedCol[c] {
+					continue
+				}
+				reduced := options.SubCost(options.SubCost(options.AddCost(minCost, e.cost), rowCost[row]), colCost[c])
+				priority := sparsePriority{reduced, colRow[c] != -1}
+				if !pathFound[c] {
+					pathFound[c] = true
+					touchedCols = append(touchedCols, c)
+					queue.Push(c, priority)
+				} else if !reduced.Less(pathCost[c]) {
+					continue
+				} else {
+					queue.UpdatePriority(c, priority)
+				}
+				pathCost[c] = reduced
+				pathRow[c] = row
+				pathEdge[c] = k
+			}
+			if queue.Len() == 0 {
+				panic("assign: internal error: sparse graph has no perfect matching")
+			}
+
+			c, _ := queue.Pop()
+			minCost = pathCost[c]
+			visitedCol[c] = true
+			if colRow[c] == -1 {
+				sink = c
+			} else {
+				row = colRow[c]
+			}
+		}
+
+		// Update the dual variables so the edges in the path become tight.
+		for _, r := range visitedRows {
+			rowCost[r] = options.AddCost(rowCost[r], options.SubCost(minCost, rowCostFound[r]))
+		}
+		for _, c := range touchedCols {
+			if visitedCol[c] {
+				colCost[c] = options.SubCost(colCost[c], options.SubCost(minCost, pathCost[c]))
+			}
+		}
+
+		// Flip the edges along the path, as described in optimalCost.
+		for c := sink; ; {
+			r := pathRow[c]
+			next := -1
+			if rowEdge[r] >= 0 {
+				next = graph[r][rowEdge[r]].col
+			}
+			colRow[c] = r
+			rowEdge[r] = pathEdge[c]
+			if r == current {
+				break
+			}
+			c = next
+		}
+		options.progress(current+1, size)
+	}
+	return rowEdge
+}
+
+// sparsePriority is the priority of a column in the queue of optimalSparse.
+type sparsePriority struct {
+	cost    Cost
+	matched bool
+}
@@ -0,0 +1,154 @@
+package assign_test
+
+import (
+	"math/rand"
+
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestSparse(c *C) {
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			return assign.IntCost(10)
+		},
+	}
+	edges := []assign.Edge{
+		{Source: 0, Target: 1, Cost: assign.IntCost(3)},
+		{Source: 1, Target: 1, Cost: assign.IntCost(1)},
+		{Source: 1, Target: 0, Cost: assign.IntCost(4)},
+		{Source: 2, Target: 0, Cost: assign.MaxIntCost},
+	}
+	pairs := assign.AssignSparse([]any{"a", "b", "c"}, []any{"x", "y"}, edges, options)
+	c.Assert(pairs, DeepEquals, []assign.Pair{
+		{Source: "a", Target: "y", SourceIndex: 0, TargetIndex: 1, Cost: assign.IntCost(3), Op: assign.Update},
+		{Source: "b", Target: "x", SourceIndex: 1, TargetIndex: 0, Cost: assign.IntCost(4), Op: assign.Update},
+		{Source: "c", Target: nil, SourceIndex: 2, TargetIndex: -1, Cost: assign.IntCost(10), Op: assign.Delete},
+	})
+
+	pairs = assign.AssignSparse([]any{"a"}, []any{"x"}, nil, options)
+	c.Assert(pairs, DeepEquals, []assign.Pair{
+		{Source: "a", Target: nil, SourceIndex: 0, TargetIndex: -1, Cost: assign.IntCost(10), Op: assign.Delete},
+		{Source: nil, Target: "x", SourceIndex: -1, TargetIndex: 0, Cost: assign.IntCost(10), Op: assign.Insert},
+	})
+
+	c.Assert(assign.AssignSparse(nil, nil, nil, options), HasLen, 0)
+	c.Assert(func() { assign.AssignSparse([]any{"a"}, nil, []assign.Edge{{0, 0, assign.IntCost(1)}}, options) },
+		PanicMatches, `assign: edge \(0, 0\) out of range for 1 sources and 0 targets`)
+}
+
+func (*S) TestSparseConstraints(c *C) {
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			return assign.IntCost(1)
+		},
+		CannotPair: func(source, target any) bool { return source == "a" },
+	}
+	edges := []assign.Edge{{Source: 0, Target: 0, Cost: assign.IntCost(0)}}
+	c.Assert(assign.AssignSparse([]any{"a"}, []any{"a"}, edges, options), DeepEquals, []assign.Pair{
+		{Source: "a", Target: nil, SourceIndex: 0, TargetIndex: -1, Cost: assign.IntCost(1), Op: assign.Delete},
+		{Source: nil, Target: "a", SourceIndex: -1, TargetIndex: 0, Cost: assign.IntCost(1), Op: assign.Insert},
+	})
+
+	options.MustPair = func(source, target any) bool { return true }
+	c.Assert(func() { assign.AssignSparse([]any{"a"}, []any{"a"}, edges, options) },
+		PanicMatches, "assign: AssignSparse does not support the MustPair and TieBreak options")
+
+	options.MustPair = nil
+	options.TieBreak = func(a, b any) bool { return a.(string) < b.(string) }
+	c.Assert(func() { assign.AssignSparse([]any{"a"}, []any{"a"}, edges, options) },
+		PanicMatches, "assign: AssignSparse does not support the MustPair and TieBreak options")
+}
+
+func (*S) TestSparseMaxFeasibleCost(c *C) {
+	options := &assign.AssignOptions{
+		EditCost: func(source, target any) assign.Cost {
+			return assign.IntCost(10)
+		},
+	}
+	edges := []assign.Edge{{Source: 0, Target: 0, Cost: assign.IntCost(5)}}
+	c.Assert(assign.AssignSparse([]any{"a"}, []any{"x"}, edges, options), DeepEquals, []assign.Pair{
+		{Source: "a", Target: "x", SourceIndex: 0, TargetIndex: 0, Cost: assign.IntCost(5), Op: assign.Update},
+	})
+	options.MaxFeasibleCost = assign.IntCost(3)
+	c.Assert(assign.AssignSparse([]any{"a"}, []any{"x"}, edges, options), DeepEquals, []assign.Pair{
+		{Source: "a", Target: nil, SourceIndex: 0, TargetIndex: -1, Cost: assign.IntCost(10), Op: assign.Delete},
+		{Source: nil, Target: "x", SourceIndex: -1, TargetIndex: 0, Cost: assign.IntCost(10), Op: assign.Insert},
+	})
+}
+
+func (*S) TestSparseRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		n := rnd.Intn(8)
+		m := rnd.Intn(8)
+		sources := make([]any, n)
+		targets := make([]any, m)
+		for j := range sources {
+			sources[j] = j
+		}
+		for j := range targets {
+			targets[j] = 100 + j
+		}
+		edits := make(map[[2]any]assign.IntCost)
+		for _, source := range append([]any{nil}, sources...) {
+			for _, target := range append([]any{nil}, targets...) {
+				edits[[2]any{source, target}] = assign.MaxIntCost
+				if source == nil || target == nil || rnd.Intn(3) == 0 {
+					edits[[2]any{source, target}] = assign.IntCost(rnd.Intn(20))
+				}
+			}
+		}
+		var edges []assign.Edge
+		for j := range sources {
+			for k := range targets {
+				if cost := edits[[2]any{sources[j], targets[k]}]; cost != assign.MaxIntCost {
+					edges = append(edges, assign.Edge{Source: j, Target: k, Cost: cost})
+				}
+			}
+		}
+		options := &assign.AssignOptions{
+			EditCost: func(source, target any) assign.Cost {
+				return edits[[2]any{source, target}]
+			},
+		}
+		sparse := assign.AssignSparse(sources, targets, edges, options)
+
+		// Solve the same problem densely, with explicit nodes representing
+		// the insertion of each target and the deletion of each source.
+		type phantom struct{ node any }
+		extended := &assign.AssignOptions{
+			EditCost: func(source, target any) assign.Cost {
+				ps, sok := source.(phantom)
+				pt, tok := target.(phantom)
+				switch {
+				case sok && tok:
+					return assign.IntCost(0)
+				case sok:
+					if ps.node == target {
+						return edits[[2]any{nil, target}]
+					}
+				case tok:
+					if pt.node == source {
+						return edits[[2]any{source, nil}]
+					}
+				default:
+					return edits[[2]any{source, target}]
+				}
+				return assign.MaxIntCost
+			},
+		}
+		var esources, etargets []any
+		esources = append(esources, sources...)
+		etargets = append(etargets, targets...)
+		for _, target := range targets {
+			esources = append(esources, phantom{target})
+		}
+		for _, source := range sources {
+			etargets = append(etargets, phantom{source})
+		}
+		dense := assign.Assign(esources, etargets, extended)
+		c.Assert(totalCost(sparse), Equals, totalCost(dense))
+	}
+}
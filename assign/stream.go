@@ -0,0 +1,123 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"container/list"
+	"context"
+)
+
+// defaultStreamRows is the number of rows kept in memory by AssignStream
+// when the StreamRows option is unset.
+const defaultStreamRows = 64
+
+// AssignStream is similar to AssignMatrix, but the cost matrix is never
+// held in memory as a whole. Instead, costRow is called to fill costs with
+// the costs of assigning the given row to each of the cols columns whenever
+// that row is needed, and only the StreamRows most recently used rows are
+// kept in memory. Memory use is thus O(StreamRows·cols) rather than
+// O(rows·cols), at the expense of calling costRow multiple times for the
+// same row, so it must be cheap to call and return the same costs every time.
+//
+// Each row is paired with a column, or left unassigned if there are more
+// rows than columns or the best column for it costs MaxCost. The result
+// holds the column assigned to each row, or -1.
+func AssignStream(rows, cols int, costRow func(row int, costs []Cost), options *AssignOptions) []int {
+	if options == nil {
+		options = &AssignOptions{}
+	}
+	if err := options.validate(false); err != nil {
+		panic("assign: invalid options: " + err.Error())
+	}
+	options = options.withDefaults()
+
+	result := make([]int, rows)
+	for i := range result {
+		result[i] = -1
+	}
+	if rows == 0 || cols == 0 {
+		return result
+	}
+
+	// optimalRows needs no more rows than columns, so when there are more
+	// rows the extra columns are phantoms which cost MaxCost for every row.
+	width := cols
+	if rows > cols {
+		width = rows
+	}
+
+	capacity := options.StreamRows
+	if capacity <= 0 {
+		capacity = defaultStreamRows
+	}
+	cache := &rowCache{
+		capacity: capacity,
+		entries:  make(map[int]*list.Element),
+		fill: func(row int, costs []Cost) {
+			costRow(row, costs[:cols])
+			for c := cols; c < width; c++ {
+				costs[c] = options.MaxCost
+			}
+		},
+		width: width,
+	}
+
+	rowCol, err := optimalRows(context.Background(), rows, width, cache.get, nil, options)
+	if err != nil {
+		panic("assign: internal error: " + err.Error())
+	}
+	for r, c := range rowCol {
+		if c < cols && cache.get(r)[c] != options.MaxCost {
+			result[r] = c
+		}
+	}
+	return result
+}
+
+// rowCache holds the most recently used rows of a cost matrix.
+type rowCache struct {
+	capacity int
+	width    int
+	fill     func(row int, costs []Cost)
+	order    list.List
+	entries  map[int]*list.Element
+}
+
+type rowCacheEntry struct {
+	row   int
+	costs []Cost
+}
+
+func (cache *rowCache) get(row int) []Cost {
+	if elem, ok := cache.entries[row]; ok {
+		cache.order.MoveToFront(elem)
+		return elem.Value.(*rowCacheEntry).costs
+	}
+	var entry *rowCacheEntry
+	if cache.order.Len() < cache.capacity {
+		entry = &rowCacheEntry{costs: make([]Cost, cache.width)}
+	} else {
+		// Reuse the least recently used row's buffer.
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		entry = oldest.Value.(*rowCacheEntry)
+		delete(cache.entries, entry.row)
+	}
+	entry.row = row
+	cache.fill(row, entry.costs)
+	cache.entries[row] = cache.order.PushFront(entry)
+	return entry.costs
+}
@@ -0,0 +1,53 @@
+package assign_test
+
+import (
+	"math/rand"
+
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestAssignStream(c *C) {
+	for _, test := range matrixTests {
+		c.Logf("Summary: %s", test.summary)
+		costs := intMatrix(test.costs)
+		cols := 0
+		if len(costs) > 0 {
+			cols = len(costs[0])
+		}
+		result := assign.AssignStream(len(costs), cols, func(row int, buf []assign.Cost) {
+			copy(buf, costs[row])
+		}, &assign.AssignOptions{StreamRows: 1})
+		c.Assert(result, DeepEquals, test.result)
+	}
+}
+
+func (*S) TestAssignStreamRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 50; i++ {
+		rows := 1 + rnd.Intn(30)
+		cols := 1 + rnd.Intn(30)
+		costs := make([][]assign.Cost, rows)
+		for r := range costs {
+			for j := 0; j < cols; j++ {
+				costs[r] = append(costs[r], assign.IntCost(rnd.Intn(100)))
+			}
+		}
+		total := func(result []int) (total assign.IntCost) {
+			for r, c := range result {
+				if c >= 0 {
+					total += costs[r][c].(assign.IntCost)
+				}
+			}
+			return total
+		}
+		calls := 0
+		result := assign.AssignStream(rows, cols, func(row int, buf []assign.Cost) {
+			calls++
+			copy(buf, costs[row])
+		}, &assign.AssignOptions{StreamRows: 4})
+		c.Assert(total(result), Equals, total(assign.AssignMatrix(costs, nil)))
+		c.Assert(calls >= rows, Equals, true)
+	}
+}
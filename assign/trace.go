@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// The functions below write the tables requested by the Trace option.
+// In the cost matrix and the duals, sources and targets are labeled s0,
+// s1, ... and t0, t1, ... by their position in the matrix, which follows
+// the order they're solved in, after TieBreak and MustPair are applied.
+// The final pairs are labeled by the position of their nodes in the
+// slices provided to Assign, as reported by SourceIndex and TargetIndex.
+
+// traceCosts writes the cost matrix for sources and targets, with the
+// phantom rows and columns labeled as insertions and deletions.
+func (options *AssignOptions) traceCosts(sources, targets []any, costs [][]Cost) {
+	if options.Trace == nil {
+		return
+	}
+	tw := newTraceTable(options.Trace, "cost matrix (%d sources, %d targets):", len(sources), len(targets))
+	for j := range costs {
+		fmt.Fprintf(tw, "\t%s", traceLabel("t", j, nodeAt(targets, j), fmt.Sprintf("t%d (delete)", j)))
+	}
+	fmt.Fprintln(tw)
+	for i, row := range costs {
+		fmt.Fprint(tw, traceLabel("s", i, nodeAt(sources, i), fmt.Sprintf("s%d (insert)", i)))
+		for _, cost := range row {
+			fmt.Fprintf(tw, "\t%s", options.traceCost(cost))
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+}
+
+// traceDuals writes the partial costs of every source and target, and the
+// target matched with each source so far, after the augmenting path
+// starting at source was flipped.
+func (options *AssignOptions) traceDuals(source int, sourceCost, targetCost []Cost, targetSource []int) {
+	if options.Trace == nil {
+		return
+	}
+	n := len(sourceCost)
+	sourceTarget := make([]int, n)
+	for i := range sourceTarget {
+		sourceTarget[i] = -1
+	}
+	for j, i := range targetSource {
+		if i < n {
+			sourceTarget[i] = j
+		}
+	}
+	tw := newTraceTable(options.Trace, "duals after augmenting s%d:", source)
+	fmt.Fprintln(tw, "source\tdual\tmatch\ttarget\tdual")
+	for k := 0; k < n; k++ {
+		match := "-"
+		if sourceTarget[k] >= 0 {
+			match = fmt.Sprintf("t%d", sourceTarget[k])
+		}
+		fmt.Fprintf(tw, "s%d\t%s\t%s\tt%d\t%s\n", k, options.traceCost(sourceCost[k]), match, k, options.traceCost(targetCost[k]))
+	}
+	tw.Flush()
+}
+
+// tracePairs writes the pairs found, and their total cost.
+func (options *AssignOptions) tracePairs(pairs []Pair) {
+	if options.Trace == nil {
+		return
+	}
+	tw := newTraceTable(options.Trace, "pairs:")
+	fmt.Fprintln(tw, "source\ttarget\tcost\top")
+	total := options.MinCost
+	for _, pair := range pairs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			traceLabel("s", pair.SourceIndex, pair.Source, "-"),
+			traceLabel("t", pair.TargetIndex, pair.Target, "-"),
+			options.traceCost(pair.Cost), pair.Op)
+		total = options.AddCost(total, pair.Cost)
+	}
+	fmt.Fprintf(tw, "total\t\t%s\n", options.traceCost(total))
+	tw.Flush()
+}
+
+// newTraceTable writes the formatted title to w, and returns a tabwriter
+// for the table following it.
+func newTraceTable(w io.Writer, title string, args ...any) *tabwriter.Writer {
+	fmt.Fprintf(w, title+"\n", args...)
+	return tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+}
+
+// nodeAt returns nodes[i], or nil if i is beyond the nodes provided.
+func nodeAt(nodes []any, i int) any {
+	if i < len(nodes) {
+		return nodes[i]
+	}
+	return nil
+}
+
+// traceLabel returns the label for node at position i, or phantom if
+// node is nil.
+func traceLabel(prefix string, i int, node any, phantom string) string {
+	if node == nil {
+		return phantom
+	}
+	return fmt.Sprintf("%s%d %v", prefix, i, node)
+}
+
+// traceCost returns cost formatted for the trace, with MaxCost shown as
+// "max" since it stands for pairs that shouldn't happen.
+func (options *AssignOptions) traceCost(cost Cost) string {
+	if cost == options.MaxCost {
+		return "max"
+	}
+	return fmt.Sprint(cost)
+}
@@ -0,0 +1,72 @@
+package assign_test
+
+import (
+	"bytes"
+
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+const hungarianTrace = `cost matrix (3 sources, 2 targets):
+        t0 bb  t1 dd  t2 (delete)
+s0 a    1      1      2
+s1 bb   0      1      2
+s2 ccc  1      max    2
+duals after augmenting s0:
+source  dual  match  target  dual
+s0      1     t0     t0      0
+s1      0     -      t1      0
+s2      0     -      t2      0
+duals after augmenting s1:
+source  dual  match  target  dual
+s0      1     t1     t0      0
+s1      0     t0     t1      0
+s2      0     -      t2      0
+duals after augmenting s2:
+source  dual  match  target  dual
+s0      1     t1     t0      -1
+s1      1     t0     t1      0
+s2      2     t2     t2      0
+pairs:
+source  target  cost  op
+s1 bb   t0 bb   0     keep
+s0 a    t1 dd   1     update
+s2 ccc  -       2     delete
+total           3
+`
+
+func traceCost(source, target any) assign.Cost {
+	switch {
+	case source == nil || target == nil:
+		return assign.IntCost(2)
+	case source == target:
+		return assign.IntCost(0)
+	case source == "ccc" && target == "dd":
+		return assign.MaxIntCost
+	}
+	return assign.IntCost(1)
+}
+
+func (*S) TestTrace(c *C) {
+	var buf bytes.Buffer
+	sources := []any{"a", "bb", "ccc"}
+	targets := []any{"bb", "dd"}
+	options := &assign.AssignOptions{EditCost: traceCost, Trace: &buf}
+	assign.Assign(sources, targets, options)
+	c.Assert(buf.String(), Equals, hungarianTrace)
+
+	// Only the final pairs are traced by the Rectangular algorithm, with
+	// the positions provided rather than the ones after sorting.
+	buf.Reset()
+	options.Algorithm = assign.Rectangular
+	options.TieBreak = func(a, b any) bool { return a.(string) > b.(string) }
+	assign.Assign(sources, targets, options)
+	c.Assert(buf.String(), Equals, `pairs:
+source  target  cost  op
+s0 a    t1 dd   1     update
+s1 bb   t0 bb   0     keep
+s2 ccc  -       2     delete
+total           3
+`)
+}
@@ -0,0 +1,205 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+// Number is the set of cost types supported by AssignTyped.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// TypedOptions holds the options for AssignTyped.
+type TypedOptions[S, T any, C Number] struct {
+	// EditCost returns the cost of editing source into target. It must
+	// be set.
+	EditCost func(source S, target T) C
+
+	// DeleteCost returns the cost of deleting source. Deletions cost
+	// MaxCost if it's nil.
+	DeleteCost func(source S) C
+
+	// InsertCost returns the cost of inserting target. Insertions cost
+	// MaxCost if it's nil.
+	InsertCost func(target T) C
+
+	// MaxCost is the maximum possible cost for an edit. Edits at MaxCost
+	// are reported as a deletion plus an insertion. All costs must be
+	// within zero and MaxCost.
+	MaxCost C
+}
+
+// TypedPair is the result type of AssignTyped.
+type TypedPair[S, T any, C Number] struct {
+	Source S
+	Target T
+
+	// SourceIndex and TargetIndex hold the position of Source and Target
+	// in the provided slices, or -1 for insertions and deletions respectively,
+	// in which case the respective Source or Target field holds its zero value.
+	SourceIndex int
+	TargetIndex int
+
+	Cost C
+}
+
+// AssignTyped is equivalent to Assign, but works with statically typed nodes
+// and numeric costs, avoiding the overhead of interface values and dynamic
+// dispatch in the solver. The zero value of the cost type plays the role of
+// MinCost, and insertions and deletions are priced by the respective options
+// rather than by calling EditCost with nil nodes. AssignTyped panics if
+// EditCost is nil.
+func AssignTyped[S, T any, C Number](sources []S, targets []T, options *TypedOptions[S, T, C]) []TypedPair[S, T, C] {
+	pairs, _ := assignTyped(sources, targets, options)
+	return pairs
+}
+
+// assignTyped implements AssignTyped, also returning the number of steps
+// taken by optimalNumber.
+func assignTyped[S, T any, C Number](sources []S, targets []T, options *TypedOptions[S, T, C]) ([]TypedPair[S, T, C], int) {
+	if options.EditCost == nil {
+		panic("assign: AssignTyped requires the EditCost option")
+	}
+	n := len(sources)
+	m := len(targets)
+
+	size := n
+	if m > n {
+		size = m
+	}
+
+	costs := make([][]C, size)
+	for i := 0; i < size; i++ {
+		costs[i] = make([]C, size)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < m; j++ {
+			costs[i][j] = options.EditCost(sources[i], targets[j])
+		}
+	}
+	for i := 0; i < n; i++ {
+		cost := options.MaxCost
+		if options.DeleteCost != nil {
+			cost = options.DeleteCost(sources[i])
+		}
+		for j := m; j < size; j++ {
+			costs[i][j] = cost
+		}
+	}
+	for j := 0; j < m; j++ {
+		cost := options.MaxCost
+		if options.InsertCost != nil {
+			cost = options.InsertCost(targets[j])
+		}
+		for i := n; i < size; i++ {
+			costs[i][j] = cost
+		}
+	}
+
+	optimal, steps := optimalNumber(costs, options.MaxCost)
+
+	var result []TypedPair[S, T, C]
+	for j := 0; j < size; j++ {
+		i := optimal[j]
+		cost := costs[i][j]
+		switch {
+		case i < n && j < m:
+			if cost == options.MaxCost {
+				result = append(result, TypedPair[S, T, C]{Source: sources[i], SourceIndex: i, TargetIndex: -1, Cost: cost})
+				result = append(result, TypedPair[S, T, C]{SourceIndex: -1, Target: targets[j], TargetIndex: j, Cost: cost})
+			} else {
+				result = append(result, TypedPair[S, T, C]{Source: sources[i], SourceIndex: i, Target: targets[j], TargetIndex: j, Cost: cost})
+			}
+		case i < n && j >= m:
+			result = append(result, TypedPair[S, T, C]{Source: sources[i], SourceIndex: i, TargetIndex: -1, Cost: cost})
+		case i >= n && j < m:
+			result = append(result, TypedPair[S, T, C]{SourceIndex: -1, Target: targets[j], TargetIndex: j, Cost: cost})
+		}
+	}
+	return result, steps
+}
+
+// optimalNumber is the same algorithm as optimalCost, working with numeric
+// costs directly. See optimalCost for details on how it works. It also
+// returns the number of steps taken by the search, for tests to check.
+func optimalNumber[C Number](costs [][]C, maxCost C) ([]int, int) {
+	n := len(costs)
+
+	sourceCost := make([]C, n+1)
+	targetCost := make([]C, n+1)
+	targetSource := make([]int, n+1)
+	for i := 0; i <= n; i++ {
+		targetSource[i] = n
+	}
+	minSlack := make([]C, n+1)
+	targetTrail := make([]int, n+1)
+	visitedTarget := make([]bool, n+1)
+
+	var steps int
+	for i := 0; i < n; i++ {
+		targetSource[n] = i
+		currentTarget := n
+
+		for j := 0; j <= n; j++ {
+			minSlack[j] = maxCost
+			targetTrail[j] = n
+			visitedTarget[j] = false
+		}
+
+		for targetSource[currentTarget] != n {
+			steps++
+			visitedTarget[currentTarget] = true
+			currentSource := targetSource[currentTarget]
+			var delta C
+			nextTarget := -1
+
+			row := costs[currentSource]
+			for j := 0; j < n; j++ {
+				if !visitedTarget[j] {
+					curSlack := row[j] - sourceCost[currentSource] - targetCost[j]
+					if curSlack < minSlack[j] {
+						minSlack[j] = curSlack
+						targetTrail[j] = currentTarget
+					}
+					if nextTarget < 0 || minSlack[j] < delta {
+						delta = minSlack[j]
+						nextTarget = j
+					}
+				}
+			}
+
+			for j := 0; j <= n; j++ {
+				if visitedTarget[j] {
+					i := targetSource[j]
+					sourceCost[i] += delta
+					targetCost[j] -= delta
+				} else {
+					minSlack[j] -= delta
+				}
+			}
+
+			currentTarget = nextTarget
+		}
+
+		for currentTarget != n {
+			previousTarget := targetTrail[currentTarget]
+			targetSource[currentTarget] = targetSource[previousTarget]
+			currentTarget = previousTarget
+		}
+	}
+
+	return targetSource[:n], steps
+}
@@ -0,0 +1,143 @@
+package assign_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+func typedOptions(costs costMap) *assign.TypedOptions[string, string, uint32] {
+	editCost := costFunc(costs)
+	return &assign.TypedOptions[string, string, uint32]{
+		EditCost:   func(source, target string) uint32 { return uint32(editCost(source, target).(uintCost)) },
+		DeleteCost: func(source string) uint32 { return uint32(editCost(source, nil).(uintCost)) },
+		InsertCost: func(target string) uint32 { return uint32(editCost(nil, target).(uintCost)) },
+		MaxCost:    uint32(maxCost),
+	}
+}
+
+func typedPairsCost(pairs []assign.TypedPair[string, string, uint32]) costMap {
+	var result = make(costMap)
+	for _, pair := range pairs {
+		source, target := pair.Source, pair.Target
+		if pair.SourceIndex < 0 {
+			source = "-"
+		}
+		if pair.TargetIndex < 0 {
+			target = "-"
+		}
+		result[namePair{source, target}] = uintCost(pair.Cost)
+	}
+	return result
+}
+
+func toStrings(nodes []any) []string {
+	result := make([]string, len(nodes))
+	for i, node := range nodes {
+		result[i] = node.(string)
+	}
+	return result
+}
+
+func (*S) TestTypedEmpty(c *C) {
+	pairs := assign.AssignTyped(nil, nil, typedOptions(nil))
+	c.Assert(pairs, HasLen, 0)
+}
+
+func (*S) TestTypedTable(c *C) {
+	for _, test := range deltaTests {
+		c.Logf("Summary: %s", test.summary)
+		options := typedOptions(test.costs)
+		pairs := assign.AssignTyped(toStrings(test.source), toStrings(test.target), options)
+		c.Assert(typedPairsCost(pairs), DeepEquals, test.result)
+	}
+}
+
+func (*S) TestTypedIndexes(c *C) {
+	options := typedOptions(costMap{namePair{"a", "a"}: 0})
+	pairs := assign.AssignTyped([]string{"x", "a"}, []string{"a"}, options)
+	c.Assert(pairs, DeepEquals, []assign.TypedPair[string, string, uint32]{
+		{Source: "a", SourceIndex: 1, Target: "a", TargetIndex: 0, Cost: 0},
+		{Source: "x", SourceIndex: 0, Target: "", TargetIndex: -1, Cost: uint32(maxCost - 1)},
+	})
+}
+
+func (*S) TestTypedFloat(c *C) {
+	options := &assign.TypedOptions[float64, float64, float64]{
+		EditCost: func(source, target float64) float64 {
+			if source > target {
+				return source - target
+			}
+			return target - source
+		},
+		DeleteCost: func(source float64) float64 { return 10 },
+		InsertCost: func(target float64) float64 { return 10 },
+		MaxCost:    100,
+	}
+	pairs := assign.AssignTyped([]float64{1.5, 3.25, 8}, []float64{3, 1}, options)
+	c.Assert(pairs, DeepEquals, []assign.TypedPair[float64, float64, float64]{
+		{Source: 3.25, SourceIndex: 1, Target: 3, TargetIndex: 0, Cost: 0.25},
+		{Source: 1.5, SourceIndex: 0, Target: 1, TargetIndex: 1, Cost: 0.5},
+		{Source: 8, SourceIndex: 2, Target: 0, TargetIndex: -1, Cost: 10},
+	})
+}
+
+func (*S) TestTypedDefaultCosts(c *C) {
+	// Without DeleteCost and InsertCost, unpaired nodes cost MaxCost.
+	options := &assign.TypedOptions[int, int, int]{
+		EditCost: func(source, target int) int { return source * target },
+		MaxCost:  10,
+	}
+	pairs := assign.AssignTyped([]int{1, 2, 3}, []int{4}, options)
+	c.Assert(pairs, DeepEquals, []assign.TypedPair[int, int, int]{
+		{Source: 1, SourceIndex: 0, Target: 4, TargetIndex: 0, Cost: 4},
+		{Source: 2, SourceIndex: 1, TargetIndex: -1, Cost: 10},
+		{Source: 3, SourceIndex: 2, TargetIndex: -1, Cost: 10},
+	})
+	pairs = assign.AssignTyped([]int{5}, []int{2, 1}, options)
+	c.Assert(pairs, DeepEquals, []assign.TypedPair[int, int, int]{
+		{SourceIndex: -1, Target: 2, TargetIndex: 0, Cost: 10},
+		{Source: 5, SourceIndex: 0, Target: 1, TargetIndex: 1, Cost: 5},
+	})
+
+	options.EditCost = nil
+	c.Assert(func() { assign.AssignTyped([]int{1}, []int{1}, options) }, PanicMatches, "assign: AssignTyped requires the EditCost option")
+}
+
+func benchmarkTyped(n int, b *testing.B) {
+	source := make([]int, n)
+	target := make([]int, n)
+	for i := 0; i < n; i++ {
+		source[i] = i
+		target[i] = i
+	}
+	options := &assign.TypedOptions[int, int, uint32]{
+		EditCost: func(source, target int) uint32 {
+			if source == target {
+				return 1
+			}
+			return uint32(maxCost)
+		},
+		DeleteCost: func(int) uint32 { return uint32(maxCost - 1) },
+		InsertCost: func(int) uint32 { return uint32(maxCost - 1) },
+		MaxCost:    uint32(maxCost),
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		assign.AssignTyped(source, target, options)
+	}
+}
+
+func BenchmarkTyped(b *testing.B) {
+	for _, n := range []int{10, 20, 50, 100, 200, 1000} {
+		b.Run(fmt.Sprintf("N=%d", n), func(b *testing.B) {
+			benchmarkTyped(n, b)
+		})
+	}
+}
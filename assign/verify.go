@@ -0,0 +1,218 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assign
+
+import (
+	"fmt"
+)
+
+// maxVerifiedSolutions limits the number of solutions enumerated by Verify
+// when checking that pairs are optimal.
+const maxVerifiedSolutions = 100000
+
+// Verify checks that pairs is a valid assignment of sources into targets
+// according to options, as returned by Assign, and returns an error
+// describing the first problem found otherwise. In particular:
+//
+//   - every source and target is used exactly once
+//   - no pair has both a nil source and a nil target
+//   - the cost of each pair matches what the options report for it, with
+//     pairs at MaxCost possibly split into a deletion and an insertion
+//
+// When the number of possible solutions is small enough, Verify also
+// enumerates all of them to check that the total cost of pairs is optimal,
+// considering costs at MaxCost to be above any sum of lower costs.
+// This is skipped when the MustPair, CannotPair, MaxFeasibleCost, or
+// Maximize options are set. Nodes are identified by their NodeKey, or by
+// the nodes themselves if NodeKey is nil, so keys must be comparable.
+//
+// Verify is meant to be used in tests, to validate custom Cost
+// implementations and options against the solver.
+func Verify(pairs []Pair, sources, targets []any, options *AssignOptions) error {
+	if err := options.Validate(); err != nil {
+		return fmt.Errorf("invalid options: %v", err)
+	}
+	options = options.withDefaults()
+
+	remaining := func(nodes []any) map[any]int {
+		counts := make(map[any]int)
+		for _, node := range nodes {
+			counts[nodeKey(node, options)]++
+		}
+		return counts
+	}
+	sourceCount := remaining(sources)
+	targetCount := remaining(targets)
+
+	var deletes int
+	for _, pair := range pairs {
+		if pair.Source == nil && pair.Target == nil {
+			return fmt.Errorf("pair has nil source and target")
+		}
+		if pair.Source != nil {
+			key := nodeKey(pair.Source, options)
+			if sourceCount[key] == 0 {
+				return fmt.Errorf("source %v is unknown or used more than once", pair.Source)
+			}
+			sourceCount[key]--
+		}
+		if pair.Target != nil {
+			key := nodeKey(pair.Target, options)
+			if targetCount[key] == 0 {
+				return fmt.Errorf("target %v is unknown or used more than once", pair.Target)
+			}
+			targetCount[key]--
+		}
+		cost := options.pairCost(pair.Source, pair.Target)
+		if pair.Cost != cost && (pair.Source != nil && pair.Target != nil || pair.Cost != options.MaxCost) {
+			return fmt.Errorf("pair (%v, %v) has cost %v, expected %v", pair.Source, pair.Target, pair.Cost, cost)
+		}
+		if pair.Target == nil {
+			deletes++
+		}
+	}
+	for _, source := range sources {
+		if sourceCount[nodeKey(source, options)] > 0 {
+			return fmt.Errorf("source %v is missing from pairs", source)
+		}
+	}
+	for _, target := range targets {
+		if targetCount[nodeKey(target, options)] > 0 {
+			return fmt.Errorf("target %v is missing from pairs", target)
+		}
+	}
+
+	if options.MustPair != nil || options.CannotPair != nil || options.MaxFeasibleCost != nil || options.Maximize {
+		return nil
+	}
+
+	// Pairs at MaxCost are split into a deletion and an insertion, but only
+	// accounted for once in the objective. All deletions beyond the excess
+	// of sources over targets must come from such splits.
+	n, m := len(sources), len(targets)
+	total := newObjective(options)
+	for _, pair := range pairs {
+		total.add(pair.Cost)
+	}
+	total.maxed -= deletes - max(0, n-m)
+
+	best, ok := bruteForce(sources, targets, options)
+	if ok && best.less(total) {
+		return fmt.Errorf("pairs cost %v, but there's a solution costing %v", total, best)
+	}
+	return nil
+}
+
+// objective is the total cost of a solution. Costs at MaxCost are counted
+// separately and considered above any sum of lower costs, so that the
+// objective does not overflow when several pairs are at MaxCost.
+type objective struct {
+	options *AssignOptions
+	maxed   int
+	sum     Cost
+}
+
+func newObjective(options *AssignOptions) objective {
+	return objective{options: options, sum: options.MinCost}
+}
+
+func (o *objective) add(cost Cost) {
+	if cost == o.options.MaxCost {
+		o.maxed++
+	} else {
+		o.sum = o.options.AddCost(o.sum, cost)
+	}
+}
+
+func (o objective) less(other objective) bool {
+	if o.maxed != other.maxed {
+		return o.maxed < other.maxed
+	}
+	return o.sum.Less(other.sum)
+}
+
+func (o objective) String() string {
+	if o.maxed == 0 {
+		return fmt.Sprint(o.sum)
+	}
+	return fmt.Sprintf("%v + %d*MaxCost", o.sum, o.maxed)
+}
+
+// bruteForce returns the minimum total cost of assigning sources into
+// targets by enumerating all possible solutions, or false if there are too many.
+func bruteForce(sources, targets []any, options *AssignOptions) (objective, bool) {
+	rows, cols := len(sources), len(targets)
+	transposed := rows > cols
+	if transposed {
+		rows, cols = cols, rows
+	}
+	solutions := 1
+	for i := 0; i < rows; i++ {
+		solutions *= cols - i
+		if solutions > maxVerifiedSolutions {
+			return objective{}, false
+		}
+	}
+
+	costs := make([][]Cost, rows)
+	for r := range costs {
+		costs[r] = make([]Cost, cols)
+		for c := range costs[r] {
+			if transposed {
+				costs[r][c] = options.pairCost(sources[c], targets[r])
+			} else {
+				costs[r][c] = options.pairCost(sources[r], targets[c])
+			}
+		}
+	}
+	unpaired := make([]Cost, cols)
+	for c := range unpaired {
+		if transposed {
+			unpaired[c] = options.pairCost(sources[c], nil)
+		} else {
+			unpaired[c] = options.pairCost(nil, targets[c])
+		}
+	}
+
+	var best objective
+	var found bool
+	used := make([]bool, cols)
+	var enumerate func(r int, total objective)
+	enumerate = func(r int, total objective) {
+		if r == rows {
+			for c := 0; c < cols; c++ {
+				if !used[c] {
+					total.add(unpaired[c])
+				}
+			}
+			if !found || total.less(best) {
+				best, found = total, true
+			}
+			return
+		}
+		for c := 0; c < cols; c++ {
+			if !used[c] {
+				used[c] = true
+				next := total
+				next.add(costs[r][c])
+				enumerate(r+1, next)
+				used[c] = false
+			}
+		}
+	}
+	enumerate(0, newObjective(options))
+	return best, true
+}
@@ -0,0 +1,86 @@
+package assign_test
+
+import (
+	"math/rand"
+
+	"github.com/canonical/go-algo/assign"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestVerify(c *C) {
+	for _, test := range deltaTests {
+		c.Logf("Summary: %s", test.summary)
+		options := deltaOptions(test.costs)
+		pairs := assign.Assign(test.source, test.target, options)
+		c.Assert(assign.Verify(pairs, test.source, test.target, options), IsNil)
+	}
+
+	options := deltaOptions(costMap{namePair{"a", "x"}: 1, namePair{"a", "y"}: 5, namePair{"b", "y"}: 1})
+	sources := []any{"a", "b"}
+	targets := []any{"x", "y"}
+	verify := func(pairs ...assign.Pair) error {
+		return assign.Verify(pairs, sources, targets, options)
+	}
+	c.Assert(verify(
+		assign.Pair{Source: "a", Target: "x", Cost: uintCost(1)},
+		assign.Pair{Source: "b", Target: "y", Cost: uintCost(1)},
+	), IsNil)
+	c.Assert(verify(
+		assign.Pair{Source: "a", Target: "x", Cost: uintCost(1)},
+	), ErrorMatches, "source b is missing from pairs")
+	c.Assert(verify(
+		assign.Pair{Source: "a", Target: "x", Cost: uintCost(1)},
+		assign.Pair{Source: "a", Target: "y", Cost: uintCost(5)},
+	), ErrorMatches, "source a is unknown or used more than once")
+	c.Assert(verify(
+		assign.Pair{Source: "a", Target: "x", Cost: uintCost(2)},
+		assign.Pair{Source: "b", Target: "y", Cost: uintCost(1)},
+	), ErrorMatches, `pair \(a, x\) has cost 2, expected 1`)
+	c.Assert(verify(
+		assign.Pair{Source: "a", Target: "y", Cost: uintCost(5)},
+		assign.Pair{Source: "b", Target: "x", Cost: maxCost},
+		assign.Pair{Source: nil, Target: nil},
+	), ErrorMatches, "pair has nil source and target")
+	c.Assert(verify(
+		assign.Pair{Source: "a", Target: "y", Cost: uintCost(5)},
+		assign.Pair{Source: "b", Target: nil, Cost: maxCost},
+		assign.Pair{Source: nil, Target: "x", Cost: maxCost},
+	), ErrorMatches, `pairs cost 5 \+ 1\*MaxCost, but there's a solution costing 2`)
+}
+
+func (*S) TestVerifyRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		n := rnd.Intn(6)
+		m := rnd.Intn(6)
+		sources := make([]any, n)
+		targets := make([]any, m)
+		for j := range sources {
+			sources[j] = j
+		}
+		for j := range targets {
+			targets[j] = 100 + j
+		}
+		edits := make(map[[2]any]assign.IntCost)
+		for _, source := range append([]any{nil}, sources...) {
+			for _, target := range append([]any{nil}, targets...) {
+				edits[[2]any{source, target}] = assign.IntCost(rnd.Intn(20))
+				if rnd.Intn(5) == 0 {
+					edits[[2]any{source, target}] = assign.MaxIntCost
+				}
+			}
+		}
+		for _, algorithm := range algorithms {
+			c.Logf("Test %d: %d×%d, algorithm %d", i, n, m, algorithm)
+			options := &assign.AssignOptions{
+				Algorithm: algorithm,
+				EditCost: func(source, target any) assign.Cost {
+					return edits[[2]any{source, target}]
+				},
+			}
+			pairs := assign.Assign(sources, targets, options)
+			c.Assert(assign.Verify(pairs, sources, targets, options), IsNil)
+		}
+	}
+}
@@ -0,0 +1,686 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package blossom computes maximum weight matchings in general graphs,
+// where both ends of a pairing come from the same set of nodes, such as
+// when pairing reviewers with each other.
+//
+// For bipartite graphs, where nodes on one side are only ever paired with
+// nodes on the other side, the assign package is simpler and faster.
+package blossom
+
+import (
+	"fmt"
+)
+
+// Edge is an undirected edge between nodes U and V with the given weight.
+type Edge struct {
+	U, V   int
+	Weight int64
+}
+
+// MaxWeight returns a matching over nodes 0 to n-1 with the largest total
+// weight among the provided edges, as a slice where result[v] is the node
+// paired with v, or -1 if v is unpaired. Edges with non-positive weight
+// are never worth pairing unless maxCardinality is true, in which case the
+// result is the matching with the largest weight among those pairing as
+// many nodes as possible.
+//
+// This is Edmonds' blossom algorithm, in the primal-dual formulation
+// described by Galil in "Efficient algorithms for finding maximum matching
+// in graphs" (1986), following the structure of the implementation by Joris
+// van Rantwijk. Odd cycles found while growing alternating trees are shrunk
+// into blossoms, which are treated as single nodes until the dual variables
+// require them to be expanded again. It runs in O(n³) time.
+//
+// MaxWeight panics if an edge refers to nodes out of range, or is a loop.
+func MaxWeight(n int, edges []Edge, maxCardinality bool) []int {
+	for _, e := range edges {
+		if e.U < 0 || e.U >= n || e.V < 0 || e.V >= n {
+			panic(fmt.Sprintf("blossom: edge (%d, %d) is out of range for %d nodes", e.U, e.V, n))
+		}
+		if e.U == e.V {
+			panic(fmt.Sprintf("blossom: edge (%d, %d) is a loop", e.U, e.V))
+		}
+	}
+	m := newMatcher(n, edges)
+	m.solve(maxCardinality)
+
+	result := make([]int, n)
+	for v := range result {
+		result[v] = -1
+		if m.mate[v] >= 0 {
+			result[v] = m.endpoint(m.mate[v])
+		}
+	}
+	return result
+}
+
+// Labels of top-level blossoms in the alternating trees. The breadcrumb
+// label is used temporarily to mark blossoms visited by scanBlossom.
+const (
+	free       = 0
+	labelS     = 1
+	labelT     = 2
+	breadcrumb = 4
+)
+
+// matcher holds the state of the algorithm. Nodes are numbered from 0 to
+// n-1 and non-trivial blossoms from n to 2n-1. Each edge k has endpoints
+// 2k and 2k+1, at nodes edges[k].U and edges[k].V respectively, so that
+// p^1 is the endpoint at the other end of the edge of endpoint p.
+type matcher struct {
+	n     int
+	edges []Edge
+
+	// neighbors[v] holds the remote endpoints of the edges incident to v.
+	neighbors [][]int
+
+	// mate[v] is the remote endpoint of the edge pairing v, or -1.
+	mate []int
+
+	// label[b] is the label of top-level blossom b, also set on the base
+	// node of T-blossoms, and labelEnd[b] the remote endpoint of the edge
+	// through which b obtained its label, or -1 for tree roots.
+	label    []int
+	labelEnd []int
+
+	// inBlossom[v] is the top-level blossom containing node v.
+	inBlossom []int
+
+	// parent[b] is the immediate blossom containing b, or -1 for top-level
+	// blossoms. children[b] holds the sub-blossoms of blossom b, in order
+	// around the cycle starting from the one holding its base, and
+	// endpoints[b] the endpoints connecting consecutive sub-blossoms,
+	// where endpoints[b][i] connects children[b][i] to children[b][i+1].
+	parent    []int
+	children  [][]int
+	endpoints [][]int
+	base      []int
+
+	// bestEdge[b] is the least slack edge from blossom b to a different
+	// S-blossom, and bestEdges[b] the least slack edges from non-trivial
+	// S-blossom b to each other S-blossom, or nil if not computed.
+	bestEdge  []int
+	bestEdges [][]int
+
+	unused []int
+
+	// dual holds twice the dual variables of nodes, followed by those of
+	// blossoms, so the slack of every edge stays an integer.
+	dual []int64
+
+	allowed []bool
+	queue   []int
+}
+
+func newMatcher(n int, edges []Edge) *matcher {
+	m := &matcher{
+		n:         n,
+		edges:     edges,
+		neighbors: make([][]int, n),
+		mate:      make([]int, n),
+		label:     make([]int, 2*n),
+		labelEnd:  make([]int, 2*n),
+		inBlossom: make([]int, n),
+		parent:    make([]int, 2*n),
+		children:  make([][]int, 2*n),
+		endpoints: make([][]int, 2*n),
+		base:      make([]int, 2*n),
+		bestEdge:  make([]int, 2*n),
+		bestEdges: make([][]int, 2*n),
+		dual:      make([]int64, 2*n),
+		allowed:   make([]bool, len(edges)),
+	}
+	var maxWeight int64
+	for k, e := range edges {
+		m.neighbors[e.U] = append(m.neighbors[e.U], 2*k+1)
+		m.neighbors[e.V] = append(m.neighbors[e.V], 2*k)
+		maxWeight = max(maxWeight, e.Weight)
+	}
+	for v := 0; v < n; v++ {
+		m.mate[v] = -1
+		m.inBlossom[v] = v
+		m.base[v] = v
+		m.dual[v] = maxWeight
+	}
+	for b := 0; b < 2*n; b++ {
+		m.parent[b] = -1
+		m.labelEnd[b] = -1
+		if b >= n {
+			m.base[b] = -1
+		}
+	}
+	for b := 2*n - 1; b >= n; b-- {
+		m.unused = append(m.unused, b)
+	}
+	return m
+}
+
+// endpoint returns the node at endpoint p.
+func (m *matcher) endpoint(p int) int {
+	if p&1 == 0 {
+		return m.edges[p/2].U
+	}
+	return m.edges[p/2].V
+}
+
+// slack returns twice the slack of edge k.
+func (m *matcher) slack(k int) int64 {
+	e := m.edges[k]
+	return m.dual[e.U] + m.dual[e.V] - 2*e.Weight
+}
+
+// leaves returns the nodes contained in blossom b.
+func (m *matcher) leaves(b int) []int {
+	if b < m.n {
+		return []int{b}
+	}
+	var result []int
+	for _, child := range m.children[b] {
+		result = append(result, m.leaves(child)...)
+	}
+	return result
+}
+
+// at returns s[i], with negative indices counting from the end.
+func at(s []int, i int) int {
+	if i < 0 {
+		i += len(s)
+	}
+	return s[i]
+}
+
+func indexOf(s []int, value int) int {
+	for i, v := range s {
+		if v == value {
+			return i
+		}
+	}
+	panic("blossom: internal error: child not found in blossom")
+}
+
+// assignLabel labels node w and its top-level blossom with t, having
+// reached it through endpoint p. The mate of the base of a T-blossom
+// is labeled S in turn.
+func (m *matcher) assignLabel(w, t, p int) {
+	b := m.inBlossom[w]
+	m.label[w], m.label[b] = t, t
+	m.labelEnd[w], m.labelEnd[b] = p, p
+	m.bestEdge[w], m.bestEdge[b] = -1, -1
+	if t == labelS {
+		m.queue = append(m.queue, m.leaves(b)...)
+		return
+	}
+	base := m.base[b]
+	m.assignLabel(m.endpoint(m.mate[base]), labelS, m.mate[base]^1)
+}
+
+// scanBlossom traces back from nodes v and w towards the roots of their
+// trees, returning the base of the new blossom formed by the edge between
+// them, or -1 if they are in different trees and an augmenting path exists.
+func (m *matcher) scanBlossom(v, w int) int {
+	var path []int
+	base := -1
+	for v != -1 || w != -1 {
+		b := m.inBlossom[v]
+		if m.label[b]&breadcrumb != 0 {
+			base = m.base[b]
+			break
+		}
+		path = append(path, b)
+		m.label[b] = breadcrumb | labelS
+		if m.labelEnd[b] == -1 {
+			v = -1
+		} else {
+			v = m.endpoint(m.labelEnd[b])
+			b = m.inBlossom[v]
+			v = m.endpoint(m.labelEnd[b])
+		}
+		if w != -1 {
+			v, w = w, v
+		}
+	}
+	for _, b := range path {
+		m.label[b] = labelS
+	}
+	return base
+}
+
+// addBlossom shrinks the cycle formed by edge k and the tree paths from
+// its ends up to base into a new S-blossom.
+func (m *matcher) addBlossom(base, k int) {
+	v, w := m.edges[k].U, m.edges[k].V
+	bb := m.inBlossom[base]
+	bv := m.inBlossom[v]
+	bw := m.inBlossom[w]
+
+	b := m.unused[len(m.unused)-1]
+	m.unused = m.unused[:len(m.unused)-1]
+	m.base[b] = base
+	m.parent[b] = -1
+	m.parent[bb] = b
+
+	var path, endps []int
+	for bv != bb {
+		m.parent[bv] = b
+		path = append(path, bv)
+		endps = append(endps, m.labelEnd[bv])
+		v = m.endpoint(m.labelEnd[bv])
+		bv = m.inBlossom[v]
+	}
+	path = append(path, bb)
+	reverse(path)
+	reverse(endps)
+	endps = append(endps, 2*k)
+	for bw != bb {
+		m.parent[bw] = b
+		path = append(path, bw)
+		endps = append(endps, m.labelEnd[bw]^1)
+		w = m.endpoint(m.labelEnd[bw])
+		bw = m.inBlossom[w]
+	}
+	m.children[b] = path
+	m.endpoints[b] = endps
+
+	m.label[b] = labelS
+	m.labelEnd[b] = m.labelEnd[bb]
+	m.dual[b] = 0
+	for _, v := range m.leaves(b) {
+		if m.label[m.inBlossom[v]] == labelT {
+			// T-nodes become S-nodes within the blossom.
+			m.queue = append(m.queue, v)
+		}
+		m.inBlossom[v] = b
+	}
+
+	// Compute the least slack edges from the new blossom to each other
+	// S-blossom, from those of its sub-blossoms.
+	bestTo := make([]int, 2*m.n)
+	for i := range bestTo {
+		bestTo[i] = -1
+	}
+	for _, bv := range path {
+		var lists [][]int
+		if m.bestEdges[bv] == nil {
+			for _, v := range m.leaves(bv) {
+				list := make([]int, len(m.neighbors[v]))
+				for i, p := range m.neighbors[v] {
+					list[i] = p / 2
+				}
+				lists = append(lists, list)
+			}
+		} else {
+			lists = [][]int{m.bestEdges[bv]}
+		}
+		for _, list := range lists {
+			for _, k := range list {
+				// j is the end of the edge outside the new blossom.
+				j := m.edges[k].V
+				if m.inBlossom[j] == b {
+					j = m.edges[k].U
+				}
+				bj := m.inBlossom[j]
+				if bj != b && m.label[bj] == labelS && (bestTo[bj] == -1 || m.slack(k) < m.slack(bestTo[bj])) {
+					bestTo[bj] = k
+				}
+			}
+		}
+		m.bestEdges[bv] = nil
+		m.bestEdge[bv] = -1
+	}
+	m.bestEdges[b] = []int{}
+	for _, k := range bestTo {
+		if k != -1 {
+			m.bestEdges[b] = append(m.bestEdges[b], k)
+		}
+	}
+	m.bestEdge[b] = -1
+	for _, k := range m.bestEdges[b] {
+		if m.bestEdge[b] == -1 || m.slack(k) < m.slack(m.bestEdge[b]) {
+			m.bestEdge[b] = k
+		}
+	}
+}
+
+// expandBlossom turns the sub-blossoms of top-level blossom b into
+// top-level blossoms. Unless endStage is true, b is a T-blossom and its
+// sub-blossoms are relabeled to keep the alternating tree consistent.
+func (m *matcher) expandBlossom(b int, endStage bool) {
+	for _, s := range m.children[b] {
+		m.parent[s] = -1
+		switch {
+		case s < m.n:
+			m.inBlossom[s] = s
+		case endStage && m.dual[s] == 0:
+			m.expandBlossom(s, endStage)
+		default:
+			for _, v := range m.leaves(s) {
+				m.inBlossom[v] = s
+			}
+		}
+	}
+
+	if !endStage && m.label[b] == labelT {
+		// Relabel the sub-blossoms along the even length path from the one
+		// through which b was reached into the one holding its base.
+		children := m.children[b]
+		endps := m.endpoints[b]
+		entry := m.inBlossom[m.endpoint(m.labelEnd[b]^1)]
+		j := indexOf(children, entry)
+		var step, trick int
+		if j&1 != 0 {
+			j -= len(children)
+			step = 1
+		} else {
+			step = -1
+			trick = 1
+		}
+		p := m.labelEnd[b]
+		for j != 0 {
+			m.label[m.endpoint(p^1)] = free
+			m.label[m.endpoint(at(endps, j-trick)^trick^1)] = free
+			m.assignLabel(m.endpoint(p^1), labelT, p)
+			m.allowed[at(endps, j-trick)/2] = true
+			j += step
+			p = at(endps, j-trick) ^ trick
+			m.allowed[p/2] = true
+			j += step
+		}
+		bv := at(children, j)
+		m.label[m.endpoint(p^1)], m.label[bv] = labelT, labelT
+		m.labelEnd[m.endpoint(p^1)], m.labelEnd[bv] = p, p
+		m.bestEdge[bv] = -1
+		j += step
+
+		// Sub-blossoms on the other side of the cycle stay unlabeled,
+		// unless one of their nodes was reached from outside.
+		for at(children, j) != entry {
+			bv := at(children, j)
+			if m.label[bv] == labelS {
+				j += step
+				continue
+			}
+			found := -1
+			for _, v := range m.leaves(bv) {
+				if m.label[v] != free {
+					found = v
+					break
+				}
+			}
+			if found >= 0 {
+				m.label[found] = free
+				m.label[m.endpoint(m.mate[m.base[bv]])] = free
+				m.assignLabel(found, labelT, m.labelEnd[found])
+			}
+			j += step
+		}
+	}
+
+	m.label[b], m.labelEnd[b] = -1, -1
+	m.children[b], m.endpoints[b] = nil, nil
+	m.base[b] = -1
+	m.bestEdges[b] = nil
+	m.bestEdge[b] = -1
+	m.unused = append(m.unused, b)
+}
+
+// augmentBlossom swaps matched and unmatched edges along the path from
+// node v within blossom b to its base, making v the new base.
+func (m *matcher) augmentBlossom(b, v int) {
+	t := v
+	for m.parent[t] != b {
+		t = m.parent[t]
+	}
+	if t >= m.n {
+		m.augmentBlossom(t, v)
+	}
+	children := m.children[b]
+	endps := m.endpoints[b]
+	i := indexOf(children, t)
+	j := i
+	var step, trick int
+	if i&1 != 0 {
+		j -= len(children)
+		step = 1
+	} else {
+		step = -1
+		trick = 1
+	}
+	for j != 0 {
+		j += step
+		t = at(children, j)
+		p := at(endps, j-trick) ^ trick
+		if t >= m.n {
+			m.augmentBlossom(t, m.endpoint(p))
+		}
+		j += step
+		t = at(children, j)
+		if t >= m.n {
+			m.augmentBlossom(t, m.endpoint(p^1))
+		}
+		m.mate[m.endpoint(p)] = p ^ 1
+		m.mate[m.endpoint(p^1)] = p
+	}
+	m.children[b] = append(append([]int(nil), children[i:]...), children[:i]...)
+	m.endpoints[b] = append(append([]int(nil), endps[i:]...), endps[:i]...)
+	m.base[b] = m.base[m.children[b][0]]
+}
+
+// augmentMatching swaps matched and unmatched edges along the augmenting
+// path through edge k, between the roots of two alternating trees.
+func (m *matcher) augmentMatching(k int) {
+	for _, start := range [2][2]int{{m.edges[k].U, 2*k + 1}, {m.edges[k].V, 2 * k}} {
+		s, p := start[0], start[1]
+		for {
+			bs := m.inBlossom[s]
+			if bs >= m.n {
+				m.augmentBlossom(bs, s)
+			}
+			m.mate[s] = p
+			if m.labelEnd[bs] == -1 {
+				// Reached the root.
+				break
+			}
+			t := m.endpoint(m.labelEnd[bs])
+			bt := m.inBlossom[t]
+			s = m.endpoint(m.labelEnd[bt])
+			j := m.endpoint(m.labelEnd[bt] ^ 1)
+			if bt >= m.n {
+				m.augmentBlossom(bt, j)
+			}
+			m.mate[j] = m.labelEnd[bt]
+			p = m.labelEnd[bt] ^ 1
+		}
+	}
+}
+
+// solve runs one stage per augmentation, each growing alternating trees
+// from the unpaired nodes and adjusting the dual variables until an
+// augmenting path is found or the matching is known to be optimal.
+func (m *matcher) solve(maxCardinality bool) {
+	n := m.n
+	for stage := 0; stage < n; stage++ {
+		for b := 0; b < 2*n; b++ {
+			m.label[b] = free
+			m.bestEdge[b] = -1
+			if b >= n {
+				m.bestEdges[b] = nil
+			}
+		}
+		for k := range m.allowed {
+			m.allowed[k] = false
+		}
+		m.queue = m.queue[:0]
+		for v := 0; v < n; v++ {
+			if m.mate[v] == -1 && m.label[m.inBlossom[v]] == free {
+				m.assignLabel(v, labelS, -1)
+			}
+		}
+
+		augmented := false
+		for {
+			for len(m.queue) > 0 && !augmented {
+				v := m.queue[len(m.queue)-1]
+				m.queue = m.queue[:len(m.queue)-1]
+				for _, p := range m.neighbors[v] {
+					k := p / 2
+					w := m.endpoint(p)
+					if m.inBlossom[v] == m.inBlossom[w] {
+						continue
+					}
+					var kslack int64
+					if !m.allowed[k] {
+						kslack = m.slack(k)
+						if kslack <= 0 {
+							m.allowed[k] = true
+						}
+					}
+					switch {
+					case m.allowed[k] && m.label[m.inBlossom[w]] == free:
+						m.assignLabel(w, labelT, p^1)
+					case m.allowed[k] && m.label[m.inBlossom[w]] == labelS:
+						if base := m.scanBlossom(v, w); base >= 0 {
+							m.addBlossom(base, k)
+						} else {
+							m.augmentMatching(k)
+							augmented = true
+						}
+					case m.allowed[k] && m.label[w] == free:
+						// w is in a T-blossom but not yet reached itself.
+						m.label[w] = labelT
+						m.labelEnd[w] = p ^ 1
+					case m.allowed[k]:
+					case m.label[m.inBlossom[w]] == labelS:
+						b := m.inBlossom[v]
+						if m.bestEdge[b] == -1 || kslack < m.slack(m.bestEdge[b]) {
+							m.bestEdge[b] = k
+						}
+					case m.label[w] == free:
+						if m.bestEdge[w] == -1 || kslack < m.slack(m.bestEdge[w]) {
+							m.bestEdge[w] = k
+						}
+					}
+					if augmented {
+						break
+					}
+				}
+			}
+			if augmented {
+				break
+			}
+
+			// No augmenting path with the current duals, so find the
+			// largest change keeping them feasible.
+			deltaType := -1
+			var delta int64
+			deltaEdge, deltaBlossom := -1, -1
+			if !maxCardinality {
+				deltaType = 1
+				delta = minDual(m.dual[:n])
+			}
+			for v := 0; v < n; v++ {
+				if m.label[m.inBlossom[v]] == free && m.bestEdge[v] != -1 {
+					if d := m.slack(m.bestEdge[v]); deltaType == -1 || d < delta {
+						delta, deltaType, deltaEdge = d, 2, m.bestEdge[v]
+					}
+				}
+			}
+			for b := 0; b < 2*n; b++ {
+				if m.parent[b] == -1 && m.label[b] == labelS && m.bestEdge[b] != -1 {
+					if d := m.slack(m.bestEdge[b]) / 2; deltaType == -1 || d < delta {
+						delta, deltaType, deltaEdge = d, 3, m.bestEdge[b]
+					}
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				if m.base[b] >= 0 && m.parent[b] == -1 && m.label[b] == labelT && (deltaType == -1 || m.dual[b] < delta) {
+					delta, deltaType, deltaBlossom = m.dual[b], 4, b
+				}
+			}
+			if deltaType == -1 {
+				// Only possible with maxCardinality, when no further
+				// improvement is possible.
+				deltaType = 1
+				delta = max(0, minDual(m.dual[:n]))
+			}
+
+			for v := 0; v < n; v++ {
+				switch m.label[m.inBlossom[v]] {
+				case labelS:
+					m.dual[v] -= delta
+				case labelT:
+					m.dual[v] += delta
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				if m.base[b] >= 0 && m.parent[b] == -1 {
+					switch m.label[b] {
+					case labelS:
+						m.dual[b] += delta
+					case labelT:
+						m.dual[b] -= delta
+					}
+				}
+			}
+
+			switch deltaType {
+			case 1:
+				// The matching is optimal.
+			case 2:
+				m.allowed[deltaEdge] = true
+				i, j := m.edges[deltaEdge].U, m.edges[deltaEdge].V
+				if m.label[m.inBlossom[i]] == free {
+					i = j
+				}
+				m.queue = append(m.queue, i)
+				continue
+			case 3:
+				m.allowed[deltaEdge] = true
+				m.queue = append(m.queue, m.edges[deltaEdge].U)
+				continue
+			case 4:
+				m.expandBlossom(deltaBlossom, false)
+				continue
+			}
+			break
+		}
+		if !augmented {
+			break
+		}
+
+		// Expand S-blossoms that ended the stage with a zero dual, as they
+		// would otherwise keep edges out of future augmenting paths.
+		for b := n; b < 2*n; b++ {
+			if m.parent[b] == -1 && m.base[b] >= 0 && m.label[b] == labelS && m.dual[b] == 0 {
+				m.expandBlossom(b, true)
+			}
+		}
+	}
+}
+
+func minDual(duals []int64) int64 {
+	result := duals[0]
+	for _, d := range duals[1:] {
+		result = min(result, d)
+	}
+	return result
+}
+
+func reverse(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
@@ -0,0 +1,180 @@
+package blossom_test
+
+import (
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/blossom"
+)
+
+type maxWeightTest struct {
+	summary        string
+	n              int
+	edges          []blossom.Edge
+	maxCardinality bool
+	mate           []int
+}
+
+var maxWeightTests = []maxWeightTest{{
+	summary: "Empty graph",
+	n:       0,
+	mate:    []int{},
+}, {
+	summary: "No edges",
+	n:       2,
+	mate:    []int{-1, -1},
+}, {
+	summary: "Single edge",
+	n:       2,
+	edges:   []blossom.Edge{{0, 1, 1}},
+	mate:    []int{1, 0},
+}, {
+	summary: "Heavier edge wins",
+	n:       3,
+	edges:   []blossom.Edge{{0, 1, 10}, {1, 2, 11}},
+	mate:    []int{-1, 2, 1},
+}, {
+	summary: "Maximum weight is not maximum cardinality",
+	n:       4,
+	edges:   []blossom.Edge{{0, 1, 5}, {1, 2, 11}, {2, 3, 5}},
+	mate:    []int{-1, 2, 1, -1},
+}, {
+	summary:        "Maximum cardinality",
+	n:              4,
+	edges:          []blossom.Edge{{0, 1, 5}, {1, 2, 11}, {2, 3, 5}},
+	maxCardinality: true,
+	mate:           []int{1, 0, 3, 2},
+}, {
+	summary: "Negative weights",
+	n:       4,
+	edges:   []blossom.Edge{{0, 1, 2}, {0, 2, -2}, {1, 2, 1}, {1, 3, -1}, {2, 3, -6}},
+	mate:    []int{1, 0, -1, -1},
+}, {
+	summary:        "Negative weights with maximum cardinality",
+	n:              4,
+	edges:          []blossom.Edge{{0, 1, 2}, {0, 2, -2}, {1, 2, 1}, {1, 3, -1}, {2, 3, -6}},
+	maxCardinality: true,
+	mate:           []int{2, 3, 0, 1},
+}, {
+	summary: "S-blossom",
+	n:       4,
+	edges:   []blossom.Edge{{0, 1, 8}, {0, 2, 9}, {1, 2, 10}, {2, 3, 7}},
+	mate:    []int{1, 0, 3, 2},
+}, {
+	summary: "S-blossom augmented through",
+	n:       6,
+	edges:   []blossom.Edge{{0, 1, 8}, {0, 2, 9}, {1, 2, 10}, {2, 3, 7}, {0, 5, 5}, {3, 4, 6}},
+	mate:    []int{5, 2, 1, 4, 3, 0},
+}, {
+	summary: "T-blossom",
+	n:       6,
+	edges:   []blossom.Edge{{0, 1, 9}, {0, 2, 8}, {1, 2, 10}, {0, 3, 5}, {3, 4, 4}, {0, 5, 3}},
+	mate:    []int{5, 2, 1, 4, 3, 0},
+}, {
+	summary: "Nested S-blossoms",
+	n:       6,
+	edges:   []blossom.Edge{{0, 1, 9}, {0, 2, 9}, {1, 2, 10}, {1, 3, 8}, {2, 4, 8}, {3, 4, 10}, {4, 5, 6}},
+	mate:    []int{2, 3, 0, 1, 5, 4},
+}, {
+	summary: "Nested S-blossoms relabeled",
+	n:       8,
+	edges: []blossom.Edge{
+		{0, 1, 10}, {0, 6, 10}, {1, 2, 12}, {2, 3, 20}, {2, 4, 20},
+		{3, 4, 25}, {4, 5, 10}, {5, 6, 10}, {6, 7, 8},
+	},
+	mate: []int{1, 0, 3, 2, 5, 4, 7, 6},
+}, {
+	summary: "Nested S-blossoms expanded",
+	n:       8,
+	edges: []blossom.Edge{
+		{0, 1, 8}, {0, 2, 8}, {1, 2, 10}, {1, 3, 12}, {2, 4, 12},
+		{3, 4, 14}, {3, 5, 12}, {4, 6, 12}, {5, 6, 14}, {6, 7, 12},
+	},
+	mate: []int{1, 0, 4, 5, 2, 3, 7, 6},
+}}
+
+func (*S) TestMaxWeight(c *C) {
+	for _, test := range maxWeightTests {
+		c.Logf("Summary: %s", test.summary)
+		mate := blossom.MaxWeight(test.n, test.edges, test.maxCardinality)
+		c.Assert(mate, DeepEquals, test.mate)
+	}
+}
+
+func (*S) TestPanics(c *C) {
+	c.Assert(func() { blossom.MaxWeight(2, []blossom.Edge{{0, 2, 1}}, false) }, PanicMatches,
+		`blossom: edge \(0, 2\) is out of range for 2 nodes`)
+	c.Assert(func() { blossom.MaxWeight(2, []blossom.Edge{{1, 1, 1}}, false) }, PanicMatches,
+		`blossom: edge \(1, 1\) is a loop`)
+}
+
+// bruteForce returns the best cardinality and weight over all matchings,
+// preferring cardinality first if maxCardinality is true.
+func bruteForce(n int, edges []blossom.Edge, maxCardinality bool) (int, int64) {
+	used := make([]bool, n)
+	bestCount, bestWeight := 0, int64(0)
+	var visit func(k, count int, weight int64)
+	visit = func(k, count int, weight int64) {
+		if k == len(edges) {
+			better := weight > bestWeight
+			if maxCardinality {
+				better = count > bestCount || count == bestCount && weight > bestWeight
+			}
+			if better {
+				bestCount, bestWeight = count, weight
+			}
+			return
+		}
+		visit(k+1, count, weight)
+		e := edges[k]
+		if !used[e.U] && !used[e.V] {
+			used[e.U], used[e.V] = true, true
+			visit(k+1, count+1, weight+e.Weight)
+			used[e.U], used[e.V] = false, false
+		}
+	}
+	visit(0, 0, 0)
+	return bestCount, bestWeight
+}
+
+func (*S) TestRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 500; i++ {
+		n := 1 + rnd.Intn(8)
+		var edges []blossom.Edge
+		for u := 0; u < n; u++ {
+			for v := u + 1; v < n; v++ {
+				if rnd.Intn(2) == 0 {
+					edges = append(edges, blossom.Edge{u, v, rnd.Int63n(20) - 5})
+				}
+			}
+		}
+		maxCardinality := i%2 == 0
+		mate := blossom.MaxWeight(n, edges, maxCardinality)
+
+		weights := make(map[[2]int]int64)
+		for _, e := range edges {
+			weights[[2]int{e.U, e.V}] = e.Weight
+			weights[[2]int{e.V, e.U}] = e.Weight
+		}
+		count, weight := 0, int64(0)
+		for v, w := range mate {
+			if w < 0 {
+				continue
+			}
+			c.Assert(mate[w], Equals, v)
+			edgeWeight, ok := weights[[2]int{v, w}]
+			c.Assert(ok, Equals, true)
+			if v < w {
+				count++
+				weight += edgeWeight
+			}
+		}
+		expectedCount, expectedWeight := bruteForce(n, edges, maxCardinality)
+		if maxCardinality {
+			c.Assert(count, Equals, expectedCount, Commentf("%v", edges))
+		}
+		c.Assert(weight, Equals, expectedWeight, Commentf("%v", edges))
+	}
+}
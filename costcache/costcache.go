@@ -0,0 +1,123 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package costcache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Key identifies a cached cost. Both Source and Target must be comparable,
+// and must identify the compared values across every call sharing the cache.
+type Key struct {
+	Source any
+	Target any
+}
+
+// Cache is a size-bounded cache of costs computed for (source, target)
+// pairs. It is safe for concurrent use, so a single cache may be shared by
+// several Assign calls running in different goroutines, through their
+// Cache option.
+//
+// When the cache is full, the least recently used entry is evicted.
+type Cache[V any] struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[Key]*list.Element
+	recent   list.List
+	stats    Stats
+}
+
+type entry[V any] struct {
+	key   Key
+	value V
+}
+
+// Stats holds cache usage counters.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// HitRate returns the fraction of lookups that were found in the cache,
+// or zero if there were no lookups yet.
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// New returns a cache holding at most capacity entries.
+func New[V any](capacity int) *Cache[V] {
+	if capacity <= 0 {
+		panic("costcache: capacity must be positive")
+	}
+	return &Cache[V]{
+		capacity: capacity,
+		entries:  make(map[Key]*list.Element, capacity),
+	}
+}
+
+// Get returns the cost cached for the (source, target) pair, and whether
+// it was found. Every call is accounted for as a hit or a miss.
+func (c *Cache[V]) Get(source, target any) (value V, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[Key{source, target}]
+	if !ok {
+		c.stats.Misses++
+		return value, false
+	}
+	c.stats.Hits++
+	c.recent.MoveToFront(elem)
+	return elem.Value.(*entry[V]).value, true
+}
+
+// Put caches value as the cost for the (source, target) pair.
+func (c *Cache[V]) Put(source, target any, value V) {
+	key := Key{source, target}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*entry[V]).value = value
+		c.recent.MoveToFront(elem)
+		return
+	}
+	if len(c.entries) >= c.capacity {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		delete(c.entries, oldest.Value.(*entry[V]).key)
+		c.stats.Evictions++
+	}
+	c.entries[key] = c.recent.PushFront(&entry[V]{key, value})
+}
+
+// Len returns the number of entries currently in the cache.
+func (c *Cache[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Stats returns the usage counters accumulated since the cache was created.
+func (c *Cache[V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
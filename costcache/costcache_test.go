@@ -0,0 +1,69 @@
+package costcache_test
+
+import (
+	"sync"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/costcache"
+)
+
+func (s *S) TestGetPut(c *C) {
+	cache := costcache.New[int](10)
+	_, ok := cache.Get("a", "b")
+	c.Assert(ok, Equals, false)
+	cache.Put("a", "b", 1)
+	cache.Put("a", nil, 2)
+	value, ok := cache.Get("a", "b")
+	c.Assert(ok, Equals, true)
+	c.Assert(value, Equals, 1)
+	value, ok = cache.Get("a", nil)
+	c.Assert(ok, Equals, true)
+	c.Assert(value, Equals, 2)
+	_, ok = cache.Get("b", "a")
+	c.Assert(ok, Equals, false)
+	c.Assert(cache.Len(), Equals, 2)
+	c.Assert(cache.Stats(), Equals, costcache.Stats{Hits: 2, Misses: 2})
+	c.Assert(cache.Stats().HitRate(), Equals, 0.5)
+}
+
+func (s *S) TestEviction(c *C) {
+	cache := costcache.New[int](2)
+	cache.Put("a", "a", 1)
+	cache.Put("b", "b", 2)
+	cache.Get("a", "a")
+	cache.Put("c", "c", 3)
+	c.Assert(cache.Len(), Equals, 2)
+	_, ok := cache.Get("b", "b")
+	c.Assert(ok, Equals, false)
+	_, ok = cache.Get("a", "a")
+	c.Assert(ok, Equals, true)
+	_, ok = cache.Get("c", "c")
+	c.Assert(ok, Equals, true)
+	c.Assert(cache.Stats().Evictions, Equals, uint64(1))
+}
+
+func (s *S) TestEmptyHitRate(c *C) {
+	cache := costcache.New[int](1)
+	c.Assert(cache.Stats().HitRate(), Equals, 0.0)
+}
+
+func (s *S) TestConcurrent(c *C) {
+	cache := costcache.New[int](50)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if _, ok := cache.Get(i%100, g); !ok {
+					cache.Put(i%100, g, i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	c.Assert(cache.Len(), Equals, 50)
+	stats := cache.Stats()
+	c.Assert(stats.Hits+stats.Misses, Equals, uint64(8000))
+}
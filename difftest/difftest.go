@@ -0,0 +1,144 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package difftest compares diffs and patches produced in tests against
+// golden files, after normalizing details that are not meaningful for the
+// comparison. Running the tests with the -update flag rewrites the golden
+// files with the produced content instead.
+package difftest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Update reports whether golden files should be rewritten rather than
+// compared. It is set by the -update command line flag.
+var Update = flag.Bool("update", false, "update golden files instead of comparing against them")
+
+// Options defines how content is normalized before being compared.
+type Options struct {
+	// JSON parses the content as a JSON document and formats it again with
+	// sorted object keys and consistent indentation.
+	JSON bool
+
+	// FloatPrecision, if positive, formats all decimal numbers with at most
+	// this many significant digits.
+	FloatPrecision int
+
+	// SortLines sorts the lines of the content, for outputs where the
+	// order of lines is not meaningful.
+	SortLines bool
+}
+
+var floatExp = regexp.MustCompile(`-?[0-9]+(\.[0-9]+([eE][-+]?[0-9]+)?|[eE][-+]?[0-9]+)`)
+
+// Normalize returns data normalized according to options.
+func Normalize(data []byte, options *Options) ([]byte, error) {
+	if options == nil {
+		return data, nil
+	}
+	if options.JSON {
+		var doc any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("cannot normalize JSON: %v", err)
+		}
+		normalized, err := json.MarshalIndent(doc, "", "\t")
+		if err != nil {
+			return nil, fmt.Errorf("cannot normalize JSON: %v", err)
+		}
+		data = append(normalized, '\n')
+	}
+	if options.FloatPrecision > 0 {
+		data = floatExp.ReplaceAllFunc(data, func(number []byte) []byte {
+			f, err := strconv.ParseFloat(string(number), 64)
+			if err != nil {
+				return number
+			}
+			return []byte(strconv.FormatFloat(f, 'g', options.FloatPrecision, 64))
+		})
+	}
+	if options.SortLines {
+		trailing := bytes.HasSuffix(data, []byte("\n"))
+		lines := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+		sort.Slice(lines, func(i, j int) bool { return bytes.Compare(lines[i], lines[j]) < 0 })
+		data = bytes.Join(lines, []byte("\n"))
+		if trailing {
+			data = append(data, '\n')
+		}
+	}
+	return data, nil
+}
+
+// Compare normalizes got and compares it against the content of the golden
+// file at path, normalized in the same way. If Update is set, the golden
+// file is written with the normalized content instead.
+func Compare(path string, got []byte, options *Options) error {
+	got, err := Normalize(got, options)
+	if err != nil {
+		return err
+	}
+	if *Update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(path, got, 0644)
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read golden file (run with -update to create it): %v", err)
+	}
+	want, err = Normalize(want, options)
+	if err != nil {
+		return fmt.Errorf("golden file %s: %v", path, err)
+	}
+	if bytes.Equal(got, want) {
+		return nil
+	}
+	gotLines := bytes.Split(got, []byte("\n"))
+	wantLines := bytes.Split(want, []byte("\n"))
+	line := 0
+	for line < len(gotLines) && line < len(wantLines) && bytes.Equal(gotLines[line], wantLines[line]) {
+		line++
+	}
+	return fmt.Errorf("content differs from golden file %s at line %d (run with -update to regenerate it):\n got: %s\nwant: %s",
+		path, line+1, lineAt(gotLines, line), lineAt(wantLines, line))
+}
+
+func lineAt(lines [][]byte, i int) string {
+	if i < len(lines) {
+		return strconv.Quote(string(lines[i]))
+	}
+	return "<end of content>"
+}
+
+// T is the subset of testing.TB and gocheck's *C used by Check.
+type T interface {
+	Fatalf(format string, args ...any)
+}
+
+// Check calls Compare and fails the test with the resulting error, if any.
+func Check(t T, path string, got []byte, options *Options) {
+	if err := Compare(path, got, options); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
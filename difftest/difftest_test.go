@@ -0,0 +1,67 @@
+package difftest_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/difftest"
+)
+
+type normalizeTest struct {
+	data    string
+	options *difftest.Options
+	result  string
+}
+
+var normalizeTests = []normalizeTest{{
+	data:   "b\na\n",
+	result: "b\na\n",
+}, {
+	data:    "b\nc\na\n",
+	options: &difftest.Options{SortLines: true},
+	result:  "a\nb\nc\n",
+}, {
+	data:    "x = 0.30000000000000004, y = 1e-7, z = 12, w = 2.50",
+	options: &difftest.Options{FloatPrecision: 6},
+	result:  "x = 0.3, y = 1e-07, z = 12, w = 2.5",
+}, {
+	data:    `{"b": [1, 2], "a": {"d": true, "c": null}}`,
+	options: &difftest.Options{JSON: true},
+	result:  "{\n\t\"a\": {\n\t\t\"c\": null,\n\t\t\"d\": true\n\t},\n\t\"b\": [\n\t\t1,\n\t\t2\n\t]\n}\n",
+}}
+
+func (s *S) TestNormalize(c *C) {
+	for _, test := range normalizeTests {
+		c.Logf("Test: %q", test.data)
+		result, err := difftest.Normalize([]byte(test.data), test.options)
+		c.Assert(err, IsNil)
+		c.Assert(string(result), Equals, test.result)
+	}
+	_, err := difftest.Normalize([]byte("{"), &difftest.Options{JSON: true})
+	c.Assert(err, ErrorMatches, "cannot normalize JSON: .*")
+}
+
+func (s *S) TestCompare(c *C) {
+	path := filepath.Join(c.MkDir(), "testdata", "diff.golden")
+	options := &difftest.Options{SortLines: true}
+
+	err := difftest.Compare(path, []byte("b\na\n"), options)
+	c.Assert(err, ErrorMatches, `cannot read golden file \(run with -update to create it\): .*`)
+
+	*difftest.Update = true
+	err = difftest.Compare(path, []byte("b\na\n"), options)
+	*difftest.Update = false
+	c.Assert(err, IsNil)
+	data, err := os.ReadFile(path)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "a\nb\n")
+
+	c.Assert(difftest.Compare(path, []byte("a\nb\n"), options), IsNil)
+	c.Assert(difftest.Compare(path, []byte("b\na\n"), options), IsNil)
+	err = difftest.Compare(path, []byte("a\nc\n"), options)
+	c.Assert(err, ErrorMatches, `(?s)content differs from golden file .*diff.golden at line 2 \(run with -update to regenerate it\):\n got: "c"\nwant: "b"`)
+	err = difftest.Compare(path, []byte("a\n"), nil)
+	c.Assert(err, ErrorMatches, `(?s).* at line 2 .*\n got: ""\nwant: "b"`)
+}
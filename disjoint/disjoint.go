@@ -0,0 +1,209 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package disjoint implements disjoint sets, also known as union-find,
+// which keep track of elements split into groups that are merged over
+// time, such as the clusters formed by matching pairs of elements, or the
+// trees joined by Kruskal's algorithm.
+//
+// Sets are trees with their root as representative. Finding the root
+// compresses the path to it, and merging two trees hangs the one of lower
+// rank under the other, so any sequence of operations takes nearly
+// constant time per operation.
+package disjoint
+
+import (
+	"fmt"
+)
+
+// Sets holds disjoint sets of elements identified by integers from zero up
+// to the number of elements.
+type Sets struct {
+	parent []int
+	rank   []uint8
+	count  int
+}
+
+// New returns n elements, each in a set of its own.
+func New(n int) *Sets {
+	s := &Sets{}
+	for i := 0; i < n; i++ {
+		s.Add()
+	}
+	return s
+}
+
+// Add adds an element in a set of its own, and returns it.
+func (s *Sets) Add() int {
+	x := len(s.parent)
+	s.parent = append(s.parent, x)
+	s.rank = append(s.rank, 0)
+	s.count++
+	return x
+}
+
+// Len returns the number of elements.
+func (s *Sets) Len() int {
+	return len(s.parent)
+}
+
+// Count returns the number of sets.
+func (s *Sets) Count() int {
+	return s.count
+}
+
+// Find returns the representative of the set holding x, which is the same
+// for all elements in the set until it's merged with another one.
+func (s *Sets) Find(x int) int {
+	if x < 0 || x >= len(s.parent) {
+		panic(fmt.Sprintf("disjoint: element %d is out of range for %d elements", x, len(s.parent)))
+	}
+	root := x
+	for s.parent[root] != root {
+		root = s.parent[root]
+	}
+	for s.parent[x] != root {
+		s.parent[x], x = root, s.parent[x]
+	}
+	return root
+}
+
+// Union merges the sets holding x and y, and reports whether they were
+// different sets.
+func (s *Sets) Union(x, y int) bool {
+	x, y = s.Find(x), s.Find(y)
+	if x == y {
+		return false
+	}
+	if s.rank[x] < s.rank[y] {
+		x, y = y, x
+	}
+	s.parent[y] = x
+	if s.rank[x] == s.rank[y] {
+		s.rank[x]++
+	}
+	s.count--
+	return true
+}
+
+// Same reports whether x and y are in the same set.
+func (s *Sets) Same(x, y int) bool {
+	return s.Find(x) == s.Find(y)
+}
+
+// Groups returns the elements of every set, each in increasing order, and
+// the sets in order of their lowest element.
+func (s *Sets) Groups() [][]int {
+	group := make([]int, len(s.parent))
+	for i := range group {
+		group[i] = -1
+	}
+	groups := make([][]int, 0, s.count)
+	for x := range s.parent {
+		root := s.Find(x)
+		if group[root] < 0 {
+			group[root] = len(groups)
+			groups = append(groups, nil)
+		}
+		groups[group[root]] = append(groups[group[root]], x)
+	}
+	return groups
+}
+
+// Keyed holds disjoint sets of elements identified by keys of type K,
+// which are added as they're first seen.
+type Keyed[K comparable] struct {
+	sets  Sets
+	index map[K]int
+	keys  []K
+}
+
+// NewKeyed returns disjoint sets with no elements.
+func NewKeyed[K comparable]() *Keyed[K] {
+	return &Keyed[K]{index: make(map[K]int)}
+}
+
+// Add adds key in a set of its own unless it's known already, and reports
+// whether it was added.
+func (k *Keyed[K]) Add(key K) bool {
+	if _, ok := k.index[key]; ok {
+		return false
+	}
+	k.add(key)
+	return true
+}
+
+func (k *Keyed[K]) add(key K) int {
+	x, ok := k.index[key]
+	if !ok {
+		x = k.sets.Add()
+		k.index[key] = x
+		k.keys = append(k.keys, key)
+	}
+	return x
+}
+
+// Len returns the number of keys.
+func (k *Keyed[K]) Len() int {
+	return len(k.keys)
+}
+
+// Count returns the number of sets.
+func (k *Keyed[K]) Count() int {
+	return k.sets.Count()
+}
+
+// Contains reports whether key is known.
+func (k *Keyed[K]) Contains(key K) bool {
+	_, ok := k.index[key]
+	return ok
+}
+
+// Find returns the representative of the set holding key, adding key in a
+// set of its own if it's not known.
+func (k *Keyed[K]) Find(key K) K {
+	return k.keys[k.sets.Find(k.add(key))]
+}
+
+// Union merges the sets holding a and b, adding them if they're not known,
+// and reports whether they were different sets.
+func (k *Keyed[K]) Union(a, b K) bool {
+	return k.sets.Union(k.add(a), k.add(b))
+}
+
+// Same reports whether a and b are in the same set. Keys that aren't known
+// are only in the same set as themselves.
+func (k *Keyed[K]) Same(a, b K) bool {
+	x, aok := k.index[a]
+	y, bok := k.index[b]
+	if !aok || !bok {
+		return a == b
+	}
+	return k.sets.Same(x, y)
+}
+
+// Groups returns the keys of every set, in the order they were added, and
+// the sets in the order their first key was added.
+func (k *Keyed[K]) Groups() [][]K {
+	groups := k.sets.Groups()
+	result := make([][]K, len(groups))
+	for i, group := range groups {
+		result[i] = make([]K, len(group))
+		for j, x := range group {
+			result[i][j] = k.keys[x]
+		}
+	}
+	return result
+}
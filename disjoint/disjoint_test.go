@@ -0,0 +1,88 @@
+package disjoint_test
+
+import (
+	"math/rand"
+
+	"github.com/canonical/go-algo/disjoint"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestSets(c *C) {
+	s := disjoint.New(6)
+	c.Assert(s.Len(), Equals, 6)
+	c.Assert(s.Count(), Equals, 6)
+	c.Assert(s.Union(0, 3), Equals, true)
+	c.Assert(s.Union(4, 3), Equals, true)
+	c.Assert(s.Union(0, 4), Equals, false)
+	c.Assert(s.Union(5, 2), Equals, true)
+	c.Assert(s.Same(0, 4), Equals, true)
+	c.Assert(s.Same(0, 5), Equals, false)
+	c.Assert(s.Find(4), Equals, s.Find(0))
+	c.Assert(s.Count(), Equals, 3)
+	c.Assert(s.Groups(), DeepEquals, [][]int{{0, 3, 4}, {1}, {2, 5}})
+
+	c.Assert(s.Add(), Equals, 6)
+	c.Assert(s.Count(), Equals, 4)
+	c.Assert(func() { s.Find(7) }, PanicMatches, "disjoint: element 7 is out of range for 7 elements")
+	c.Assert(disjoint.New(0).Groups(), HasLen, 0)
+}
+
+func (*S) TestSetsRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		n := 1 + rnd.Intn(30)
+		c.Logf("Test %d: %d elements", i, n)
+		s := disjoint.New(n)
+		// The naive model labels every element with its set.
+		label := make([]int, n)
+		for x := range label {
+			label[x] = x
+		}
+		count := n
+		for op := 0; op < 2*n; op++ {
+			x, y := rnd.Intn(n), rnd.Intn(n)
+			merged := label[x] != label[y]
+			c.Assert(s.Union(x, y), Equals, merged)
+			if merged {
+				old := label[y]
+				for z := range label {
+					if label[z] == old {
+						label[z] = label[x]
+					}
+				}
+				count--
+			}
+			c.Assert(s.Count(), Equals, count)
+			a, b := rnd.Intn(n), rnd.Intn(n)
+			c.Assert(s.Same(a, b), Equals, label[a] == label[b])
+		}
+		total := 0
+		for _, group := range s.Groups() {
+			for _, x := range group {
+				c.Assert(label[x], Equals, label[group[0]])
+			}
+			total += len(group)
+		}
+		c.Assert(total, Equals, n)
+	}
+}
+
+func (*S) TestKeyed(c *C) {
+	k := disjoint.NewKeyed[string]()
+	c.Assert(k.Add("a"), Equals, true)
+	c.Assert(k.Add("a"), Equals, false)
+	c.Assert(k.Union("b", "c"), Equals, true)
+	c.Assert(k.Union("d", "b"), Equals, true)
+	c.Assert(k.Union("c", "d"), Equals, false)
+	c.Assert(k.Len(), Equals, 4)
+	c.Assert(k.Count(), Equals, 2)
+	c.Assert(k.Same("b", "d"), Equals, true)
+	c.Assert(k.Same("a", "b"), Equals, false)
+	c.Assert(k.Same("x", "x"), Equals, true)
+	c.Assert(k.Same("x", "a"), Equals, false)
+	c.Assert(k.Contains("x"), Equals, false)
+	c.Assert(k.Find("c"), Equals, k.Find("d"))
+	c.Assert(k.Find("e"), Equals, "e")
+	c.Assert(k.Groups(), DeepEquals, [][]string{{"a"}, {"b", "c", "d"}, {"e"}})
+}
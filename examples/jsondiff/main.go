@@ -1,241 +1,605 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
-	"reflect"
+	"path/filepath"
+	"strings"
 
-	"github.com/canonical/editdelta/assign"
-	"github.com/canonical/editdelta/listdist"
+	"github.com/canonical/go-algo/jsondiff"
 )
 
-// Custom Cost type, mirroring the one used in edit_test.go for consistency.
-type uintCost uint32
-
-func (u uintCost) Less(other assign.Cost) bool { return u < other.(uintCost) }
-func (u uintCost) String() string              { return fmt.Sprint(uint32(u)) }
+func formatValue(val any) string {
+	b, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("<error marshalling: %v>", err)
+	}
+	return string(b)
+}
 
 var (
-	minCost = uintCost(0)
-	maxCost = uintCost(1 << 31) // A very large cost to represent "impossible" or highly undesirable edits.
+	format  = flag.String("format", "pretty", "output format: pretty, compact (one line per change), json (array of changes), patch (RFC 6902 JSON Patch), merge (RFC 7386 JSON Merge Patch), or html (side-by-side report)")
+	color   = flag.String("color", "auto", "color pretty output: always, never, or auto when writing to a terminal")
+	input   = flag.String("input", "auto", "input syntax: json, yaml, or auto to pick by file extension")
+	output  = flag.String("output", "auto", "syntax of patch documents: json, yaml, or auto to use that of the second file")
+	scope   = flag.String("path", "", "only compare the values at `path`, such as .spec.containers")
+	quiet   = flag.Bool("q", false, "print nothing, only exit with 1 if the documents differ")
+	summary = flag.Bool("summary", false, "print the number of changes of each kind and how similar the documents are, rather than the changes")
+	stream  = flag.Int("stream", 0, "read JSON documents as they are compared, diffing the values at this depth independently, for documents too large to diff at once (compact and json formats only)")
+	pointer = flag.Bool("pointer", false, "print paths as RFC 6901 JSON Pointers, such as /a/b/0, in the compact and json formats")
 )
 
-type jsonValue struct {
-	path string
-	data any
-}
+// arrayKeys holds the -array-key flags, mapping array paths to the member
+// identifying their elements.
+type arrayKeys map[string]string
 
-func flatten(nodes []any, data any, currentPath string) []any {
-	nodes = append(nodes, jsonValue{path: currentPath, data: data})
-	switch data := data.(type) {
-	case map[string]any:
-		for k, subdata := range data {
-			var newPath string
-			if currentPath == "." {
-				newPath = "." + k
-			} else {
-				newPath = currentPath + "." + k
-			}
-			nodes = flatten(nodes, subdata, newPath)
-		}
-	case []any:
-		for i, subdata := range data {
-			newPath := fmt.Sprintf("%s[%d]", currentPath, i)
-			nodes = flatten(nodes, subdata, newPath)
-		}
+func (keys arrayKeys) String() string {
+	var pairs []string
+	for path, key := range keys {
+		pairs = append(pairs, path+"="+key)
 	}
-	return nodes
+	return strings.Join(pairs, ",")
 }
 
-func isScalar(data any) bool {
-	switch reflect.TypeOf(data).Kind() {
-	case reflect.Bool, reflect.Int, reflect.Float64, reflect.String:
-		return true
+func (keys arrayKeys) Set(value string) error {
+	path, key, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected <path>=<key>, got %q", value)
 	}
-	return false
+	if !strings.HasPrefix(path, ".") {
+		path = "." + path
+	}
+	keys[path] = key
+	return nil
 }
 
-func editCost(source, target any) assign.Cost {
-	if source == nil || target == nil {
-		return maxCost
+// ignorePatterns holds the -ignore flags.
+type ignorePatterns []*jsondiff.Pattern
+
+func (patterns *ignorePatterns) String() string {
+	var texts []string
+	for _, pattern := range *patterns {
+		texts = append(texts, pattern.String())
 	}
+	return strings.Join(texts, ",")
+}
 
-	svalue, sok := source.(jsonValue)
-	tvalue, tok := target.(jsonValue)
-	if !sok || !tok {
-		panic("unknown value type")
+func (patterns *ignorePatterns) Set(value string) error {
+	pattern, err := jsondiff.ParsePattern(value)
+	if err != nil {
+		return err
 	}
+	*patterns = append(*patterns, pattern)
+	return nil
+}
 
-	isSourceRoot := svalue.path == "."
-	isTargetRoot := tvalue.path == "."
+var options = &jsondiff.Options{ArrayKeys: make(arrayKeys)}
 
-	if isSourceRoot && isTargetRoot {
-		// Matching root-to-root has zero cost.
-		return minCost
+func main() {
+	flag.Var(arrayKeys(options.ArrayKeys), "array-key", "match elements of the array at `path=key` by their key member (repeatable)")
+	flag.Var((*ignorePatterns)(&options.Ignore), "ignore", "ignore the values at paths matching `pattern`, such as .a.b, .*.b[*], or /regexp/ (repeatable)")
+	flag.Float64Var(&options.Tolerance, "tolerance", 0, "consider numbers equal if they differ by at most this much")
+	flag.Float64Var(&options.RelativeTolerance, "rel-tolerance", 0, "consider numbers equal if they differ by at most this fraction of the larger one")
+	flag.BoolVar(&options.NumericEqual, "numeric", false, "compare numbers by value, so that 1 and 1.0 are equal")
+	flag.BoolVar(&options.IgnoreCase, "ignore-case", false, "compare strings regardless of case")
+	flag.BoolVar(&options.TrimSpace, "trim-space", false, "compare strings without leading and trailing white space")
+	flag.BoolVar(&options.NullAbsent, "null-absent", false, "consider members holding null the same as missing members")
+	flag.BoolVar(&options.IgnoreOrder, "ignore-order", false, "ignore the order of array elements")
+	flag.IntVar(&options.MaxDepth, "max-depth", 0, "compare objects and arrays below depth `N` as a whole")
+	flag.BoolVar(&options.NoMoves, "no-moves", false, "report values moved as removed and added")
+	flag.Float64Var(&options.MoveSimilarity, "move-similarity", 0, "report values moved as removed and added unless at least this similar, from 0 to 1, like git diff -M")
+	flag.BoolVar(&options.SameDepth, "same-depth", false, "only report values moved within the same depth")
+	flag.IntVar(&options.MaxDepthChange, "max-depth-change", 0, "only report values moved up or down at most `N` levels")
+	flag.Parse()
+	if flag.NArg() != 2 {
+		fmt.Fprintf(os.Stderr, "Usage: %s <file1|dir1|-> <file2|dir2|->\n", os.Args[0])
+		os.Exit(2)
+	}
+	// Like cmp and diff, exit with 0 if the documents are equal, 1 if they
+	// differ, and 2 on errors.
+	differ, err := run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
 	}
-	if isSourceRoot || isTargetRoot {
-		// Matching a root object to anything other than the other root is disallowed.
-		return maxCost
+	if differ {
+		os.Exit(1)
 	}
+}
 
-	sdata := svalue.data
-	tdata := tvalue.data
-
-	stype := reflect.TypeOf(sdata)
-	ttype := reflect.TypeOf(tdata)
+// document is a document read from the command line arguments.
+type document struct {
+	// label names the document in the output, and name names it within
+	// the directory it was read from, if any.
+	label  string
+	name   string
+	syntax string
+	value  any
+}
 
-	// Disallow conversions between scalars or different types.
-	// If types are fundamentally different, it's an impossible direct transformation,
-	// so return MaxCost, which will become a delete + insert.
-	if stype != ttype {
-		return maxCost
+// readDocuments reads the documents in the file or directory at path, or
+// in the standard input if path is "-". Files may hold several documents,
+// as concatenated JSON values, such as NDJSON, or as a YAML stream, and
+// directories hold the documents of their files, in the order of their
+// names.
+func readDocuments(path string) ([]*document, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read standard input: %v", err)
+		}
+		return decodeDocuments(data, "stdin", "", "")
 	}
-
-	// Same type, now compare content based on type.
-	switch sdata := sdata.(type) {
-	case nil:
-		return minCost
-	case bool, int, float64, string:
-		if reflect.DeepEqual(sdata, tdata) {
-			return minCost
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %v", path, err)
+	}
+	if !info.IsDir() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s: %v", path, err)
+		}
+		return decodeDocuments(data, path, "", filepath.Ext(path))
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %v", path, err)
+	}
+	var docs []*document
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
 		}
-		if svalue.path == tvalue.path {
-			return uintCost(1)
+		file := filepath.Join(path, entry.Name())
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read %s: %v", file, err)
 		}
-		return maxCost // Replace.
-	case map[string]any:
-		tmap := tdata.(map[string]any)
-		matchingKeys := 0
-		allKeys := make(map[string]struct{})
+		fileDocs, err := decodeDocuments(data, file, entry.Name(), filepath.Ext(file))
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, fileDocs...)
+	}
+	return docs, nil
+}
 
-		// Collect all unique keys from both maps and count matching keys
-		for k := range sdata {
-			allKeys[k] = struct{}{}
-			if _, ok := tmap[k]; ok {
-				matchingKeys++
+// decodeDocuments decodes the documents in data, read from the file with
+// the given label, name, and extension.
+func decodeDocuments(data []byte, label, name, ext string) ([]*document, error) {
+	syntax := *input
+	if syntax == "auto" {
+		syntax = "json"
+		switch strings.ToLower(ext) {
+		case ".yaml", ".yml":
+			syntax = "yaml"
+		}
+	}
+	var values []any
+	switch syntax {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		if options.NumericEqual {
+			// Keep numbers as written, so that large ones are compared exactly.
+			dec.UseNumber()
+		}
+		for {
+			var value any
+			err := dec.Decode(&value)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("cannot unmarshal %s: %v", label, err)
 			}
+			values = append(values, value)
 		}
-		for k := range tmap {
-			allKeys[k] = struct{}{}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("cannot unmarshal %s: no document found", label)
 		}
-
-		totalUniqueKeys := len(allKeys)
-		if totalUniqueKeys == 0 {
-			return minCost
+	case "yaml":
+		var err error
+		values, err = jsondiff.DecodeYAMLDocuments(data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot unmarshal %s: %v", label, err)
+		}
+		if len(values) == 0 {
+			// An empty YAML file holds a null document.
+			values = []any{nil}
 		}
-		return uintCost(totalUniqueKeys - matchingKeys)
-
-	case []any:
-		tdata := tdata.([]any)
-		return uintCost(listdist.Distance(sdata, tdata, listdist.StandardCost, 0))
-
 	default:
-		return maxCost
+		return nil, fmt.Errorf("unknown input syntax %q", syntax)
 	}
-}
-
-func formatValue(val any) string {
-	b, err := json.Marshal(val)
-	if err != nil {
-		return fmt.Sprintf("<error marshalling: %v>", err)
+	docs := make([]*document, len(values))
+	for i, value := range values {
+		docs[i] = &document{label: label, name: name, syntax: syntax, value: value}
+		if len(values) > 1 {
+			docs[i].label = fmt.Sprintf("%s#%d", label, i+1)
+			docs[i].name = fmt.Sprintf("%s#%d", name, i+1)
+		}
 	}
-	return string(b)
+	return docs, nil
 }
 
-func main() {
-	flag.Parse()
-	if flag.NArg() != 2 {
-		fmt.Fprintf(os.Stderr, "Usage: %s <file1> <file2>\n", os.Args[0])
-		os.Exit(1)
+// pairDocuments pairs the documents read from both arguments, by name if
+// both are directories, or by position otherwise. Documents without a
+// counterpart are paired with nil.
+func pairDocuments(docs1, docs2 []*document) [][2]*document {
+	var pairs [][2]*document
+	if docs1[0].name == "" || docs2[0].name == "" {
+		for i := 0; i < max(len(docs1), len(docs2)); i++ {
+			var pair [2]*document
+			if i < len(docs1) {
+				pair[0] = docs1[i]
+			}
+			if i < len(docs2) {
+				pair[1] = docs2[i]
+			}
+			pairs = append(pairs, pair)
+		}
+		return pairs
 	}
-	if err := run(); err != nil {
-		fmt.Fprintf(os.Stderr, "error: %v\n", err)
-		os.Exit(1)
+	byName := make(map[string]*document)
+	for _, doc := range docs2 {
+		byName[doc.name] = doc
 	}
+	for _, doc := range docs1 {
+		pairs = append(pairs, [2]*document{doc, byName[doc.name]})
+		delete(byName, doc.name)
+	}
+	for _, doc := range docs2 {
+		if byName[doc.name] != nil {
+			pairs = append(pairs, [2]*document{nil, doc})
+		}
+	}
+	return pairs
 }
 
-func run() error {
-	file1Path := flag.Arg(0)
-	file2Path := flag.Arg(1)
+// jsonResult holds the changes between a pair of documents, as printed by
+// the json format when comparing several of them.
+type jsonResult struct {
+	A       string       `json:"a,omitempty"`
+	B       string       `json:"b,omitempty"`
+	Changes []jsonChange `json:"changes"`
+}
 
-	data1, err := ioutil.ReadFile(file1Path)
+// run prints the changes between the documents, and reports whether there
+// are any.
+func run() (differ bool, err error) {
+	if flag.Arg(0) == "-" && flag.Arg(1) == "-" {
+		return false, fmt.Errorf("cannot read both documents from the standard input")
+	}
+	if *stream > 0 {
+		return runStream()
+	}
+	if *summary && *scope != "" {
+		return false, fmt.Errorf("cannot use -path with -summary")
+	}
+	docs1, err := readDocuments(flag.Arg(0))
 	if err != nil {
-		return fmt.Errorf("cannot read %s: %v", file1Path, err)
+		return false, err
 	}
-
-	data2, err := ioutil.ReadFile(file2Path)
+	docs2, err := readDocuments(flag.Arg(1))
 	if err != nil {
-		return fmt.Errorf("cannot read %s: %v", file2Path, err)
+		return false, err
+	}
+	if len(docs1) == 0 || len(docs2) == 0 {
+		return false, fmt.Errorf("no documents to compare")
+	}
+	pairs := pairDocuments(docs1, docs2)
+	if len(pairs) > 1 && *format == "html" && !*summary {
+		return false, fmt.Errorf("cannot use the html format with several documents")
+	}
+	if len(pairs) == 1 {
+		return diffDocuments(pairs[0][0], pairs[0][1], false, nil)
 	}
 
-	var json1, json2 any
-	if err := json.Unmarshal(data1, &json1); err != nil {
-		return fmt.Errorf("cannot unmarshal %s: %v", file1Path, err)
+	// Results are reported for every pair of documents, with a header
+	// for those that differ, or in a single array for the json format.
+	results := []jsonResult{}
+	for _, pair := range pairs {
+		doc1, doc2 := pair[0], pair[1]
+		var result *jsonResult
+		if *format == "json" && !*summary {
+			result = &jsonResult{Changes: []jsonChange{}}
+		}
+		switch {
+		case doc1 == nil || doc2 == nil:
+			differ = true
+			only := doc1
+			op := jsondiff.Remove
+			if only == nil {
+				only, op = doc2, jsondiff.Add
+			}
+			if *quiet {
+				continue
+			}
+			if result == nil {
+				fmt.Printf("Only in %s\n", only.label)
+				continue
+			}
+			change := jsondiff.Change{Op: op, Path: jsondiff.Path{}, Old: only.value, New: only.value}
+			result.Changes = append(result.Changes, newJSONChange(change))
+			if op == jsondiff.Remove {
+				result.A = only.label
+			} else {
+				result.B = only.label
+			}
+		default:
+			pairDiffers, err := diffDocuments(doc1, doc2, true, result)
+			if err != nil {
+				return false, err
+			}
+			differ = differ || pairDiffers
+			if result != nil {
+				result.A, result.B = doc1.label, doc2.label
+			}
+		}
+		if result != nil {
+			results = append(results, *result)
+		}
 	}
-	if err := json.Unmarshal(data2, &json2); err != nil {
-		return fmt.Errorf("cannot unmarshal %s: %v", file2Path, err)
+	if *format == "json" && !*quiet && !*summary {
+		return differ, printDocument(results, "json")
 	}
+	return differ, nil
+}
 
-	sources := flatten(nil, json1, ".")
-	targets := flatten(nil, json2, ".")
+// diffDocuments prints the changes between doc1 and doc2, preceded by a
+// header naming them if they differ and header is set, or adds them to
+// result instead if it isn't nil, and reports whether there are any.
+func diffDocuments(doc1, doc2 *document, header bool, result *jsonResult) (differ bool, err error) {
+	json1, json2 := doc1.value, doc2.value
+	syntax := doc2.syntax
+	if *output != "auto" {
+		syntax = *output
+	}
 
-	options := &assign.AssignOptions{
-		NodeKey: func(node any) any {
-			// NodeKey is used to identify nodes. For JSONNode, the path serves as a unique identifier.
-			// While not directly used by the Hungarian algorithm for cost, it's a required field
-			// and provides a logical identifier for the nodes.
-			if jn, ok := node.(jsonValue); ok {
-				return jn.path
+	// Ignoring values and the order of arrays make the changes lead to a
+	// document that differs from the second one in these respects, and so
+	// does comparing a single path.
+	var changes []jsondiff.Change
+	if *scope != "" {
+		path, err := jsondiff.ParsePath(*scope)
+		if err != nil {
+			return false, err
+		}
+		changes, err = jsondiff.DiffAt(json1, json2, path, options)
+		if err != nil {
+			return false, fmt.Errorf("%s: %v", doc1.label, err)
+		}
+	} else {
+		changes = jsondiff.Diff(json1, json2, options)
+	}
+	differ = len(changes) > 0
+	if *quiet {
+		return differ, nil
+	}
+	if *summary {
+		if header {
+			if !differ {
+				return false, nil
 			}
-			return nil // Should not happen with valid JSONNode inputs
-		},
-		EditCost: editCost,
-		AddCost: func(a, b assign.Cost) assign.Cost {
-			return a.(uintCost) + b.(uintCost)
-		},
-		SubCost: func(a, b assign.Cost) assign.Cost {
-			return a.(uintCost) - b.(uintCost)
-		},
-		MinCost: minCost,
-		MaxCost: maxCost,
-	}
-
-	pairs := assign.Assign(sources, targets, options)
-
-	// Process and print the diff results in the requested format.
-	for _, p := range pairs {
-		svalue, sok := p.Source.(jsonValue)
-		tvalue, tok := p.Target.(jsonValue)
+			fmt.Printf("--- %s\n+++ %s\n", doc1.label, doc2.label)
+		}
+		fmt.Println(jsondiff.Summarize(json1, json2, changes, options))
+		return differ, nil
+	}
+	json2, err = jsondiff.Apply(json1, changes)
+	if err != nil {
+		return false, err
+	}
+	if result != nil {
+		for _, change := range changes {
+			result.Changes = append(result.Changes, newJSONChange(change))
+		}
+		return differ, nil
+	}
+	if header {
+		if !differ {
+			return false, nil
+		}
+		fmt.Printf("--- %s\n+++ %s\n", doc1.label, doc2.label)
+	}
+
+	switch *format {
+	case "pretty":
+		useColor, err := colorOutput()
+		if err != nil {
+			return false, err
+		}
+		fmt.Print(jsondiff.Format(json1, json2, changes, &jsondiff.FormatOptions{Color: useColor}))
+	case "compact", "text":
+		printText(changes)
+	case "html":
+		fmt.Print(jsondiff.FormatHTML(json1, json2, changes, &jsondiff.HTMLOptions{AName: doc1.label, BName: doc2.label}))
+	case "json":
+		list := []jsonChange{}
+		for _, change := range changes {
+			list = append(list, newJSONChange(change))
+		}
+		return differ, printDocument(list, "json")
+	case "patch":
+		patch := jsondiff.Patch(json1, json2, changes)
+		if patch == nil {
+			patch = []jsondiff.Operation{}
+		}
+		return differ, printDocument(patch, syntax)
+	case "merge":
+		patch, err := jsondiff.MergePatch(json1, json2)
+		if err != nil {
+			return false, err
+		}
+		return differ, printDocument(patch, syntax)
+	default:
+		return false, fmt.Errorf("unknown output format %q", *format)
+	}
+	return differ, nil
+}
+
+// errDiffer stops streaming at the first change when printing nothing.
+var errDiffer = errors.New("documents differ")
 
+// runStream prints the changes between the documents as they are found,
+// and reports whether there are any.
+func runStream() (differ bool, err error) {
+	explicit := false
+	flag.Visit(func(f *flag.Flag) { explicit = explicit || f.Name == "format" })
+	if explicit && *format != "compact" && *format != "text" && *format != "json" {
+		return false, fmt.Errorf("cannot use the %s format with -stream", *format)
+	}
+	if *scope != "" {
+		return false, fmt.Errorf("cannot use -path with -stream")
+	}
+	if *summary {
+		return false, fmt.Errorf("cannot use -summary with -stream")
+	}
+	var files []*os.File
+	for _, path := range flag.Args() {
+		if path == "-" {
+			files = append(files, os.Stdin)
+			continue
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return false, fmt.Errorf("cannot read %s: %v", path, err)
+		}
+		defer f.Close()
+		files = append(files, f)
+	}
+	streamOptions := &jsondiff.StreamOptions{Options: *options, Depth: *stream}
+
+	// The json format prints the array one change at a time, as they are
+	// found.
+	sep := "[\n  "
+	err = jsondiff.DiffStream(bufio.NewReader(files[0]), bufio.NewReader(files[1]), streamOptions, func(change jsondiff.Change) error {
+		differ = true
 		switch {
-		case sok && !tok:
-			fmt.Printf("Drop: old%s\n", svalue.path)
-		case !sok && tok:
-			if tvalue.path == "." && (reflect.DeepEqual(tvalue.data, map[string]any{}) || reflect.DeepEqual(tvalue.data, []any{})) {
-				continue
+		case *quiet:
+			return errDiffer
+		case *format == "json":
+			data, err := json.Marshal(newJSONChange(change))
+			if err != nil {
+				return err
 			}
-			fmt.Printf(" Add: new%s = %s\n", tvalue.path, formatValue(tvalue.data))
-		case sok && tok:
-			if svalue.path == tvalue.path {
-				if svalue.path == "." && tvalue.path == "." {
-					continue
-				}
-				if isScalar(svalue.data) && !reflect.DeepEqual(svalue.data, tvalue.data) {
-					fmt.Printf(" Set: new%s = %s\n", svalue.path, formatValue(tvalue.data))
-				}
+			fmt.Print(sep, string(data))
+			sep = ",\n  "
+		default:
+			printText([]jsondiff.Change{change})
+		}
+		return nil
+	})
+	if err == errDiffer {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if *format == "json" {
+		if differ {
+			fmt.Println("\n]")
+		} else {
+			fmt.Println("[]")
+		}
+	}
+	return differ, nil
+}
+
+// colorOutput reports whether the output is to be colored.
+func colorOutput() (bool, error) {
+	switch *color {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		info, err := os.Stdout.Stat()
+		return err == nil && info.Mode()&os.ModeCharDevice != 0 && os.Getenv("NO_COLOR") == "", nil
+	}
+	return false, fmt.Errorf("unknown color mode %q", *color)
+}
+
+func printDocument(value any, syntax string) error {
+	switch syntax {
+	case "json":
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case "yaml":
+		// Operations are encoded through JSON, which gives them the
+		// same members in both syntaxes.
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		var doc any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		data, err = jsondiff.EncodeYAML(doc)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+	default:
+		return fmt.Errorf("unknown output syntax %q", syntax)
+	}
+	return nil
+}
+
+// jsonChange is a change as printed by the json format. Value holds the
+// new value, or the old one for values removed.
+type jsonChange struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value"`
+}
+
+func newJSONChange(change jsondiff.Change) jsonChange {
+	c := jsonChange{Op: change.Op.String(), Path: pathString(change.Path), Value: change.New}
+	switch change.Op {
+	case jsondiff.Remove:
+		c.Value = change.Old
+	case jsondiff.Move:
+		c.From = pathString(change.From)
+	}
+	return c
+}
+
+// pathString returns path as printed, which is a JSON Pointer with the
+// -pointer flag, since keys holding dots or brackets make the usual form
+// ambiguous.
+func pathString(path jsondiff.Path) string {
+	if *pointer {
+		return path.Pointer()
+	}
+	return path.String()
+}
+
+func printText(changes []jsondiff.Change) {
+	for _, change := range changes {
+		switch change.Op {
+		case jsondiff.Remove:
+			fmt.Printf("Drop: old%s\n", pathString(change.Path))
+		case jsondiff.Add:
+			fmt.Printf(" Add: new%s = %s\n", pathString(change.Path), formatValue(change.New))
+		case jsondiff.Set:
+			fmt.Printf(" Set: new%s = %s\n", pathString(change.Path), formatValue(change.New))
+		case jsondiff.Move:
+			if formatValue(change.Old) != formatValue(change.New) {
+				fmt.Printf("Move: old%s => new%s = %s\n", pathString(change.From), pathString(change.Path), formatValue(change.New))
 			} else {
-				if reflect.DeepEqual(svalue.data, tvalue.data) {
-					fmt.Printf("Move: old%s => new%s\n", svalue.path, tvalue.path)
-				} else if isScalar(svalue.data) && isScalar(tvalue.data) {
-					fmt.Printf("Move: old%s => new%s = %s\n", svalue.path, tvalue.path, formatValue(tvalue.data))
-				} else {
-					fmt.Printf("Move: old%s => new%s\n", svalue.path, tvalue.path)
-				}
+				fmt.Printf("Move: old%s => new%s\n", pathString(change.From), pathString(change.Path))
 			}
 		}
 	}
-	return nil
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/canonical/go-algo/schedule"
+)
+
+func main() {
+	agents := []*schedule.Agent{
+		{Name: "alice", Capacity: 2, Skills: []string{"go", "sql"}},
+		{Name: "bob", Capacity: 1, Skills: []string{"go"}},
+		{Name: "carol", Capacity: 2, Skills: []string{"docs"}},
+	}
+	tasks := []*schedule.Task{
+		{Name: "fix-crash", Requires: []string{"go"}, Priority: 2},
+		{Name: "migrate-db", Requires: []string{"go", "sql"}, Priority: 1},
+		{Name: "write-guide", Requires: []string{"docs"}},
+		{Name: "refactor", Requires: []string{"go"}},
+		{Name: "tune-queries", Requires: []string{"sql"}},
+	}
+
+	// Agents take longer with tasks outside of what they usually do.
+	hours := map[string]int64{
+		"alice/fix-crash": 3,
+		"bob/fix-crash":   2,
+		"alice/refactor":  5,
+		"bob/refactor":    8,
+	}
+	cost := func(agent *schedule.Agent, task *schedule.Task) (int64, bool) {
+		if h, ok := hours[agent.Name+"/"+task.Name]; ok {
+			return h, true
+		}
+		return 4, true
+	}
+
+	plan, err := schedule.Solve(agents, tasks, &schedule.Options{Cost: cost})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, a := range plan.Assignments {
+		fmt.Printf("%-12s => %-6s (%d hours)\n", a.Task.Name, a.Agent.Name, a.Cost)
+	}
+	for _, task := range plan.Unassigned {
+		fmt.Printf("%-12s => unassigned\n", task.Name)
+	}
+	fmt.Printf("Total: %d hours\n", plan.Cost)
+}
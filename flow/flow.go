@@ -0,0 +1,299 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package flow computes maximum flows and minimum cost flows in directed
+// networks with integer capacities and costs.
+//
+// Many matching problems reduce to flows. For example, assigning workers
+// with capacities to tasks, or allowing many-to-many assignments, is a
+// minimum cost flow from a source connected to every worker into a sink
+// connected to every task.
+package flow
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// Graph is a directed network where flow is sent along edges with limited
+// capacity. Flows found by MaxFlow and MinCostFlow are kept in the graph,
+// and further calls add to them until Reset is called.
+type Graph struct {
+	// edges holds every edge followed by its reverse residual edge,
+	// so the reverse of edge e is e^1.
+	edges []edge
+	adj   [][]int
+}
+
+type edge struct {
+	to       int
+	capacity int64
+	cost     int64
+	flow     int64
+}
+
+// New returns a graph with n nodes and no edges.
+func New(n int) *Graph {
+	return &Graph{adj: make([][]int, n)}
+}
+
+// Nodes returns the number of nodes in the graph.
+func (g *Graph) Nodes() int {
+	return len(g.adj)
+}
+
+// AddEdge adds an edge from one node to another with the given capacity
+// and cost per unit of flow, and returns its id for use with Flow.
+func (g *Graph) AddEdge(from, to int, capacity, cost int64) int {
+	if from < 0 || from >= len(g.adj) || to < 0 || to >= len(g.adj) {
+		panic(fmt.Sprintf("flow: edge from %d to %d is out of range for %d nodes", from, to, len(g.adj)))
+	}
+	if capacity < 0 {
+		panic(fmt.Sprintf("flow: edge from %d to %d has negative capacity %d", from, to, capacity))
+	}
+	id := len(g.edges)
+	g.edges = append(g.edges, edge{to: to, capacity: capacity, cost: cost})
+	g.edges = append(g.edges, edge{to: from, capacity: 0, cost: -cost})
+	g.adj[from] = append(g.adj[from], id)
+	g.adj[to] = append(g.adj[to], id+1)
+	return id / 2
+}
+
+// Flow returns the flow currently sent along the edge with the given id.
+func (g *Graph) Flow(id int) int64 {
+	return g.edges[2*id].flow
+}
+
+// Reset removes all flow from the graph.
+func (g *Graph) Reset() {
+	for i := range g.edges {
+		g.edges[i].flow = 0
+	}
+}
+
+func (g *Graph) residual(e int) int64 {
+	return g.edges[e].capacity - g.edges[e].flow
+}
+
+func (g *Graph) push(e int, amount int64) {
+	g.edges[e].flow += amount
+	g.edges[e^1].flow -= amount
+}
+
+// MaxFlow sends as much flow as possible from source into sink, and
+// returns the amount sent. Edge costs are ignored.
+//
+// This is Dinic's algorithm, which repeatedly builds the level graph of
+// shortest paths from source in the residual network with a breadth-first
+// search, and then saturates it with depth-first searches that only move
+// from one level to the next. It runs in O(V²E) time, and considerably
+// faster on unit capacity networks such as those of bipartite matchings.
+func (g *Graph) MaxFlow(source, sink int) int64 {
+	g.checkNodes(source, sink)
+	if source == sink {
+		return 0
+	}
+	n := len(g.adj)
+	level := make([]int, n)
+	next := make([]int, n)
+	queue := make([]int, 0, n)
+
+	var augment func(node int, limit int64) int64
+	augment = func(node int, limit int64) int64 {
+		if node == sink {
+			return limit
+		}
+		for ; next[node] < len(g.adj[node]); next[node]++ {
+			e := g.adj[node][next[node]]
+			to := g.edges[e].to
+			if level[to] != level[node]+1 || g.residual(e) == 0 {
+				continue
+			}
+			if pushed := augment(to, min(limit, g.residual(e))); pushed > 0 {
+				g.push(e, pushed)
+				return pushed
+			}
+		}
+		return 0
+	}
+
+	var total int64
+	for {
+		for i := range level {
+			level[i] = -1
+		}
+		level[source] = 0
+		queue = append(queue[:0], source)
+		for i := 0; i < len(queue); i++ {
+			node := queue[i]
+			for _, e := range g.adj[node] {
+				if to := g.edges[e].to; level[to] < 0 && g.residual(e) > 0 {
+					level[to] = level[node] + 1
+					queue = append(queue, to)
+				}
+			}
+		}
+		if level[sink] < 0 {
+			return total
+		}
+		for i := range next {
+			next[i] = 0
+		}
+		for {
+			pushed := augment(source, math.MaxInt64)
+			if pushed == 0 {
+				break
+			}
+			total += pushed
+		}
+	}
+}
+
+// MinCostFlow sends up to limit units of flow from source into sink, or
+// as much as possible if limit is negative, at the lowest total cost.
+// It returns the amount of flow sent and its total cost.
+//
+// This is the successive shortest paths algorithm, which repeatedly sends
+// flow along the cheapest path from source to sink in the residual network.
+// Paths are found with Dijkstra's algorithm over costs reduced by node
+// potentials, which keep them non-negative. Edge costs may be negative, as
+// long as no cycle has a negative total cost, in which case the initial
+// potentials are computed with the Bellman-Ford algorithm. It runs in
+// O(F·E·log V) time, where F is the amount of flow sent.
+//
+// The flow already in the graph must have been found by MinCostFlow, so
+// that the residual network has no negative cycles.
+func (g *Graph) MinCostFlow(source, sink int, limit int64) (flow, cost int64) {
+	g.checkNodes(source, sink)
+	if source == sink {
+		return 0, 0
+	}
+	if limit < 0 {
+		limit = math.MaxInt64
+	}
+	n := len(g.adj)
+	potential := g.potentials(source)
+	dist := make([]int64, n)
+	prev := make([]int, n)
+	done := make([]bool, n)
+	queue := &distQueue{}
+
+	for flow < limit {
+		for i := range dist {
+			dist[i] = math.MaxInt64
+			prev[i] = -1
+			done[i] = false
+		}
+		dist[source] = 0
+		queue.items = append(queue.items[:0], distItem{source, 0})
+		for queue.Len() > 0 {
+			node := heap.Pop(queue).(distItem).node
+			if done[node] {
+				continue
+			}
+			done[node] = true
+			for _, e := range g.adj[node] {
+				to := g.edges[e].to
+				if done[to] || g.residual(e) == 0 || potential[to] == math.MaxInt64 {
+					continue
+				}
+				reduced := g.edges[e].cost + potential[node] - potential[to]
+				if d := dist[node] + reduced; d < dist[to] {
+					dist[to] = d
+					prev[to] = e
+					heap.Push(queue, distItem{to, d})
+				}
+			}
+		}
+		if !done[sink] {
+			break
+		}
+		for i := range potential {
+			if done[i] {
+				potential[i] += dist[i]
+			}
+		}
+
+		amount := limit - flow
+		for node := sink; node != source; node = g.edges[prev[node]^1].to {
+			amount = min(amount, g.residual(prev[node]))
+		}
+		for node := sink; node != source; node = g.edges[prev[node]^1].to {
+			g.push(prev[node], amount)
+			cost += amount * g.edges[prev[node]].cost
+		}
+		flow += amount
+	}
+	return flow, cost
+}
+
+// potentials returns the cost of the cheapest path from source into each
+// node in the residual network, or math.MaxInt64 for unreachable nodes.
+func (g *Graph) potentials(source int) []int64 {
+	n := len(g.adj)
+	potential := make([]int64, n)
+	for i := range potential {
+		potential[i] = math.MaxInt64
+	}
+	potential[source] = 0
+	for round := 0; round < n; round++ {
+		changed := false
+		for node := 0; node < n; node++ {
+			if potential[node] == math.MaxInt64 {
+				continue
+			}
+			for _, e := range g.adj[node] {
+				to := g.edges[e].to
+				if g.residual(e) > 0 && potential[node]+g.edges[e].cost < potential[to] {
+					potential[to] = potential[node] + g.edges[e].cost
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			return potential
+		}
+	}
+	panic("flow: residual network has a negative cost cycle")
+}
+
+func (g *Graph) checkNodes(source, sink int) {
+	if source < 0 || source >= len(g.adj) || sink < 0 || sink >= len(g.adj) {
+		panic(fmt.Sprintf("flow: source %d or sink %d is out of range for %d nodes", source, sink, len(g.adj)))
+	}
+}
+
+// distQueue is a priority queue of nodes ordered by their distance.
+// Nodes may be pushed more than once, and stale entries are skipped.
+type distQueue struct {
+	items []distItem
+}
+
+type distItem struct {
+	node int
+	dist int64
+}
+
+func (q *distQueue) Len() int           { return len(q.items) }
+func (q *distQueue) Less(i, j int) bool { return q.items[i].dist < q.items[j].dist }
+func (q *distQueue) Swap(i, j int)      { q.items[i], q.items[j] = q.items[j], q.items[i] }
+func (q *distQueue) Push(x any)         { q.items = append(q.items, x.(distItem)) }
+
+func (q *distQueue) Pop() any {
+	last := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+	return last
+}
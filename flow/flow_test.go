@@ -0,0 +1,128 @@
+package flow_test
+
+import (
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/assign"
+	"github.com/canonical/go-algo/flow"
+)
+
+func (*S) TestMaxFlow(c *C) {
+	// The classic example from CLRS, with a maximum flow of 23.
+	g := flow.New(6)
+	g.AddEdge(0, 1, 16, 0)
+	g.AddEdge(0, 2, 13, 0)
+	g.AddEdge(1, 2, 10, 0)
+	g.AddEdge(2, 1, 4, 0)
+	g.AddEdge(1, 3, 12, 0)
+	g.AddEdge(3, 2, 9, 0)
+	g.AddEdge(2, 4, 14, 0)
+	g.AddEdge(4, 3, 7, 0)
+	g.AddEdge(3, 5, 20, 0)
+	g.AddEdge(4, 5, 4, 0)
+	c.Assert(g.MaxFlow(0, 5), Equals, int64(23))
+	c.Assert(g.MaxFlow(0, 5), Equals, int64(0))
+	g.Reset()
+	c.Assert(g.MaxFlow(0, 5), Equals, int64(23))
+	g.Reset()
+	c.Assert(g.MaxFlow(5, 0), Equals, int64(0))
+	c.Assert(g.MaxFlow(0, 0), Equals, int64(0))
+}
+
+func (*S) TestMinCostFlow(c *C) {
+	g := flow.New(4)
+	direct := g.AddEdge(0, 3, 2, 10)
+	g.AddEdge(0, 1, 3, 1)
+	g.AddEdge(1, 2, 2, 1)
+	g.AddEdge(2, 3, 5, 1)
+	g.AddEdge(1, 3, 1, 5)
+
+	sent, cost := g.MinCostFlow(0, 3, 1)
+	c.Assert([]int64{sent, cost}, DeepEquals, []int64{1, 3})
+	sent, cost = g.MinCostFlow(0, 3, -1)
+	c.Assert([]int64{sent, cost}, DeepEquals, []int64{4, 3 + 6 + 20})
+	c.Assert(g.Flow(direct), Equals, int64(2))
+}
+
+func (*S) TestNegativeCosts(c *C) {
+	g := flow.New(3)
+	g.AddEdge(0, 1, 1, 5)
+	g.AddEdge(0, 2, 1, 1)
+	g.AddEdge(2, 1, 1, -3)
+	sent, cost := g.MinCostFlow(0, 1, -1)
+	c.Assert([]int64{sent, cost}, DeepEquals, []int64{2, 3})
+
+	g = flow.New(2)
+	g.AddEdge(0, 1, 1, -1)
+	g.AddEdge(1, 0, 1, -1)
+	c.Assert(func() { g.MinCostFlow(0, 1, -1) }, PanicMatches, "flow: residual network has a negative cost cycle")
+}
+
+func (*S) TestPanics(c *C) {
+	g := flow.New(2)
+	c.Assert(func() { g.AddEdge(0, 2, 1, 0) }, PanicMatches, "flow: edge from 0 to 2 is out of range for 2 nodes")
+	c.Assert(func() { g.AddEdge(0, 1, -1, 0) }, PanicMatches, "flow: edge from 0 to 1 has negative capacity -1")
+	c.Assert(func() { g.MaxFlow(0, 3) }, PanicMatches, "flow: source 0 or sink 3 is out of range for 2 nodes")
+}
+
+// TestAssignment compares minimum cost flows against assign.AssignInt64
+// on assignment problems, where every source is connected to every target.
+func (*S) TestAssignment(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 50; i++ {
+		n := 1 + rnd.Intn(8)
+		costs := make([][]int64, n)
+		g := flow.New(2*n + 2)
+		source, sink := 2*n, 2*n+1
+		for r := range costs {
+			costs[r] = make([]int64, n)
+			g.AddEdge(source, r, 1, 0)
+			g.AddEdge(n+r, sink, 1, 0)
+			for col := range costs[r] {
+				costs[r][col] = rnd.Int63n(100)
+				g.AddEdge(r, n+col, 1, costs[r][col])
+			}
+		}
+		_, expected := assign.AssignInt64(costs)
+		sent, cost := g.MinCostFlow(source, sink, -1)
+		c.Assert(sent, Equals, int64(n))
+		c.Assert(cost, Equals, expected)
+
+		g.Reset()
+		c.Assert(g.MaxFlow(source, sink), Equals, int64(n))
+	}
+}
+
+// TestRandom checks that MaxFlow and MinCostFlow agree on the amount of
+// flow, and that the flow found is feasible and conserved.
+func (*S) TestRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		n := 2 + rnd.Intn(8)
+		g := flow.New(n)
+		type edge struct{ from, to, id int }
+		var edges []edge
+		for e := rnd.Intn(4 * n); e > 0; e-- {
+			from, to := rnd.Intn(n), rnd.Intn(n)
+			id := g.AddEdge(from, to, rnd.Int63n(10), rnd.Int63n(10))
+			edges = append(edges, edge{from, to, id})
+		}
+		maxFlow := g.MaxFlow(0, n-1)
+		g.Reset()
+		sent, _ := g.MinCostFlow(0, n-1, -1)
+		c.Assert(sent, Equals, maxFlow)
+
+		balance := make([]int64, n)
+		for _, e := range edges {
+			c.Assert(g.Flow(e.id) >= 0, Equals, true)
+			balance[e.from] -= g.Flow(e.id)
+			balance[e.to] += g.Flow(e.id)
+		}
+		for node := 1; node < n-1; node++ {
+			c.Assert(balance[node], Equals, int64(0))
+		}
+		c.Assert(balance[n-1], Equals, sent)
+	}
+}
@@ -0,0 +1,172 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graph implements algorithms over directed graphs whose edges are
+// weighted with costs of the same model used by the assign package, so any
+// assign.Cost, including the built-in IntCost and FloatCost types, may
+// weigh them.
+package graph
+
+import (
+	"fmt"
+
+	"github.com/canonical/go-algo/assign"
+)
+
+// Graph is a directed graph with nodes identified by integers from zero up
+// to the number of nodes, and edges weighted by a cost. Edges are kept in
+// the order they were added, which is the order in which the algorithms
+// consider them, so results are deterministic.
+type Graph struct {
+	adj [][]Edge
+}
+
+// Edge is an edge from one node to another with the given cost.
+type Edge struct {
+	From int
+	To   int
+	Cost assign.Cost
+}
+
+// New returns a graph with n nodes and no edges.
+func New(n int) *Graph {
+	return &Graph{adj: make([][]Edge, n)}
+}
+
+// Nodes returns the number of nodes in the graph.
+func (g *Graph) Nodes() int {
+	return len(g.adj)
+}
+
+// AddEdge adds an edge from one node to another with the given cost.
+// Several edges may join the same nodes, and an edge may join a node to
+// itself.
+func (g *Graph) AddEdge(from, to int, cost assign.Cost) {
+	if from < 0 || from >= len(g.adj) || to < 0 || to >= len(g.adj) {
+		panic(fmt.Sprintf("graph: edge from %d to %d is out of range for %d nodes", from, to, len(g.adj)))
+	}
+	if cost == nil {
+		panic(fmt.Sprintf("graph: edge from %d to %d has nil cost", from, to))
+	}
+	g.adj[from] = append(g.adj[from], Edge{From: from, To: to, Cost: cost})
+}
+
+// Edges returns the edges leaving node, in the order they were added.
+// The returned slice must not be modified.
+func (g *Graph) Edges(node int) []Edge {
+	g.checkNode(node)
+	return g.adj[node]
+}
+
+func (g *Graph) checkNode(node int) {
+	if node < 0 || node >= len(g.adj) {
+		panic(fmt.Sprintf("graph: node %d is out of range for %d nodes", node, len(g.adj)))
+	}
+}
+
+// Options holds the cost arithmetic used by the algorithms.
+//
+// Both fields may be left unset when costs are of the built-in IntCost
+// type, and AddCost may be left unset for FloatCost as long as ZeroCost is
+// set, so that the cost type is known. The default arithmetic saturates
+// rather than overflowing, as done for assign.AssignOptions.
+type Options struct {
+	// AddCost returns the cost of a path made of two paths costing a and b.
+	AddCost func(a, b assign.Cost) assign.Cost
+
+	// ZeroCost is the cost of a path with no edges. Edges costing less
+	// than that are negative, and only supported by BellmanFord.
+	// It must be comparable by identity (==).
+	ZeroCost assign.Cost
+}
+
+var (
+	addIntCost   = assign.SaturatingAdd[assign.IntCost]()
+	addFloatCost = assign.SaturatingAdd[assign.FloatCost]()
+)
+
+// withDefaults returns the options with the unset cost arithmetic filled in
+// for the built-in cost types. The options may be nil.
+func (options *Options) withDefaults() *Options {
+	var result Options
+	if options != nil {
+		result = *options
+	}
+	switch result.ZeroCost.(type) {
+	case nil, assign.IntCost:
+		if result.ZeroCost == nil {
+			result.ZeroCost = assign.IntCost(0)
+		}
+		if result.AddCost == nil {
+			result.AddCost = addIntCost
+		}
+	case assign.FloatCost:
+		if result.AddCost == nil {
+			result.AddCost = addFloatCost
+		}
+	}
+	if result.AddCost == nil {
+		panic(fmt.Sprintf("graph: AddCost is not set for costs of type %T", result.ZeroCost))
+	}
+	return &result
+}
+
+// Paths holds the cheapest paths from a source node to every node reachable
+// from it, as found by Dijkstra and BellmanFord.
+type Paths struct {
+	source int
+	costs  []assign.Cost
+	prev   []int
+}
+
+func newPaths(source, n int) *Paths {
+	p := &Paths{
+		source: source,
+		costs:  make([]assign.Cost, n),
+		prev:   make([]int, n),
+	}
+	for i := range p.prev {
+		p.prev[i] = -1
+	}
+	return p
+}
+
+// Source returns the node the paths start from.
+func (p *Paths) Source() int {
+	return p.source
+}
+
+// Cost returns the cost of the cheapest path from the source to node, and
+// whether node is reachable at all.
+func (p *Paths) Cost(node int) (cost assign.Cost, ok bool) {
+	cost = p.costs[node]
+	return cost, cost != nil
+}
+
+// To returns the nodes along the cheapest path from the source to node,
+// including both, or nil if node isn't reachable from the source.
+func (p *Paths) To(node int) []int {
+	if p.costs[node] == nil {
+		return nil
+	}
+	var path []int
+	for ; node >= 0; node = p.prev[node] {
+		path = append(path, node)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
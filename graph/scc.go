@@ -0,0 +1,143 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"sort"
+)
+
+// Components returns the strongly connected components of the graph, which
+// are the largest sets of nodes that can all be reached from each other.
+// Every node is in exactly one component, which may hold that node alone.
+// Components are returned in topological order, so edges between them only
+// go from a component to one after it, and the nodes in each are sorted.
+//
+// This is Tarjan's algorithm, running in O(V + E) time. The depth-first
+// search is done with an explicit stack, so deep graphs are fine. It
+// supersedes the tarjan package, which does the same recursively for nodes
+// named by strings, and is kept for its existing users.
+func (g *Graph) Components() [][]int {
+	n := len(g.adj)
+	const unvisited = -1
+	index := make([]int, n)
+	lowlink := make([]int, n)
+	onStack := make([]bool, n)
+	for i := range index {
+		index[i] = unvisited
+	}
+	var stack []int
+	var components [][]int
+
+	// frame is a node being visited, with the next of its edges to follow.
+	type frame struct {
+		node int
+		next int
+	}
+	var frames []frame
+	visited := 0
+	visit := func(node int) {
+		index[node], lowlink[node] = visited, visited
+		visited++
+		stack = append(stack, node)
+		onStack[node] = true
+		frames = append(frames, frame{node: node})
+	}
+	for root := range g.adj {
+		if index[root] != unvisited {
+			continue
+		}
+		visit(root)
+		for len(frames) > 0 {
+			f := &frames[len(frames)-1]
+			node := f.node
+			if f.next < len(g.adj[node]) {
+				to := g.adj[node][f.next].To
+				f.next++
+				if index[to] == unvisited {
+					visit(to)
+				} else if onStack[to] {
+					lowlink[node] = min(lowlink[node], index[to])
+				}
+				continue
+			}
+			frames = frames[:len(frames)-1]
+			if len(frames) > 0 {
+				parent := frames[len(frames)-1].node
+				lowlink[parent] = min(lowlink[parent], lowlink[node])
+			}
+			if lowlink[node] != index[node] {
+				continue
+			}
+			// Node is the root of a component, made of the nodes above it.
+			var component []int
+			for {
+				last := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[last] = false
+				component = append(component, last)
+				if last == node {
+					break
+				}
+			}
+			sort.Ints(component)
+			components = append(components, component)
+		}
+	}
+
+	// Tarjan's algorithm finds components in reverse topological order.
+	for i, j := 0, len(components)-1; i < j; i, j = i+1, j-1 {
+		components[i], components[j] = components[j], components[i]
+	}
+	return components
+}
+
+// Condense returns the condensation of the graph, which has a node for
+// each of its strongly connected components, as returned by Components,
+// and an edge from one component to another if any edge goes from a node
+// in the first to a node in the second. The edge costs the lowest of
+// these. The condensation has no cycles, and its nodes are numbered in
+// topological order, so node i of dag stands for components[i].
+func (g *Graph) Condense() (dag *Graph, components [][]int) {
+	components = g.Components()
+	componentOf := make([]int, len(g.adj))
+	for c, nodes := range components {
+		for _, node := range nodes {
+			componentOf[node] = c
+		}
+	}
+	dag = New(len(components))
+	// The position of each edge added in the edges leaving its component.
+	added := make(map[[2]int]int)
+	for c, nodes := range components {
+		for _, node := range nodes {
+			for _, e := range g.adj[node] {
+				to := componentOf[e.To]
+				if to == c {
+					continue
+				}
+				if i, ok := added[[2]int{c, to}]; ok {
+					if e.Cost.Less(dag.adj[c][i].Cost) {
+						dag.adj[c][i].Cost = e.Cost
+					}
+					continue
+				}
+				added[[2]int{c, to}] = len(dag.adj[c])
+				dag.AddEdge(c, to, e.Cost)
+			}
+		}
+	}
+	return dag, components
+}
@@ -0,0 +1,80 @@
+package graph_test
+
+import (
+	"math/rand"
+
+	"github.com/canonical/go-algo/assign"
+	"github.com/canonical/go-algo/graph"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestComponents(c *C) {
+	successors := [][]int{
+		0: {1, 2},
+		1: {0, 4},
+		2: {3},
+		3: {2, 4},
+		4: {5},
+		5: {6},
+		6: {7},
+		7: {5, 8},
+		8: {},
+	}
+	g := graph.New(len(successors))
+	for from, tos := range successors {
+		for _, to := range tos {
+			g.AddEdge(from, to, assign.IntCost(from+to))
+		}
+	}
+	c.Assert(g.Components(), DeepEquals, [][]int{{0, 1}, {2, 3}, {4}, {5, 6, 7}, {8}})
+
+	dag, components := g.Condense()
+	c.Assert(components, DeepEquals, g.Components())
+	c.Assert(dag.Nodes(), Equals, 5)
+	c.Assert(dag.Edges(0), DeepEquals, []graph.Edge{
+		{From: 0, To: 1, Cost: assign.IntCost(2)},
+		{From: 0, To: 2, Cost: assign.IntCost(5)},
+	})
+	c.Assert(dag.Edges(1), DeepEquals, []graph.Edge{{From: 1, To: 2, Cost: assign.IntCost(7)}})
+	c.Assert(dag.Edges(3), DeepEquals, []graph.Edge{{From: 3, To: 4, Cost: assign.IntCost(15)}})
+
+	c.Assert(graph.New(0).Components(), HasLen, 0)
+}
+
+func (*S) TestComponentsRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		n := 1 + rnd.Intn(10)
+		edges := rnd.Intn(2 * n)
+		c.Logf("Test %d: %d nodes, %d edges", i, n, edges)
+
+		g := graph.New(n)
+		for e := 0; e < edges; e++ {
+			g.AddEdge(rnd.Intn(n), rnd.Intn(n), assign.IntCost(rnd.Intn(5)))
+		}
+		_, reachable := floydWarshall(g)
+
+		dag, components := g.Condense()
+		componentOf := make([]int, n)
+		seen := 0
+		for k, nodes := range components {
+			for _, node := range nodes {
+				componentOf[node] = k
+				seen++
+			}
+		}
+		c.Assert(seen, Equals, n)
+		for a := 0; a < n; a++ {
+			for b := 0; b < n; b++ {
+				mutual := reachable[a][b] && reachable[b][a]
+				c.Assert(componentOf[a] == componentOf[b], Equals, mutual)
+			}
+			for _, e := range g.Edges(a) {
+				c.Assert(componentOf[a] <= componentOf[e.To], Equals, true)
+			}
+		}
+		_, err := dag.TopoSort()
+		c.Assert(err, IsNil)
+	}
+}
@@ -0,0 +1,181 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"fmt"
+
+	"github.com/canonical/go-algo/assign"
+	"github.com/canonical/go-algo/pqueue"
+)
+
+// Dijkstra returns the cheapest paths from source to every node reachable
+// from it, found with Dijkstra's algorithm in O(E log V) time. When several
+// paths cost the same, the one found first is kept. The options may be nil.
+//
+// Dijkstra panics if it finds an edge with negative cost. Use BellmanFord
+// for these.
+func (g *Graph) Dijkstra(source int, options *Options) *Paths {
+	g.checkNode(source)
+	paths := newPaths(source, len(g.adj))
+	g.search(paths, -1, nil, options.withDefaults())
+	return paths
+}
+
+// AStar returns the nodes along the cheapest path from source to target,
+// including both, and its cost, or a nil path if target isn't reachable
+// from source. It's Dijkstra's algorithm guided by a heuristic estimating
+// the cost of the cheapest path from each node to target, so that nodes
+// closer to target are visited first, and the search may stop much earlier.
+//
+// The path found is the cheapest as long as the heuristic never estimates
+// a cost above the actual one. Nodes may be visited more than once if the
+// estimate for a node is above the estimate for its neighbors plus the
+// cost of getting there, so such heuristics are best avoided. The options
+// may be nil.
+//
+// AStar panics if it finds an edge with negative cost.
+func (g *Graph) AStar(source, target int, heuristic func(node int) assign.Cost, options *Options) (path []int, cost assign.Cost) {
+	g.checkNode(source)
+	g.checkNode(target)
+	if heuristic == nil {
+		panic("graph: AStar called without a heuristic")
+	}
+	paths := newPaths(source, len(g.adj))
+	g.search(paths, target, heuristic, options.withDefaults())
+	cost, _ = paths.Cost(target)
+	return paths.To(target), cost
+}
+
+// search fills paths with the cheapest paths from its source, stopping
+// once the path to target is known if target isn't negative. Nodes are
+// visited in order of their cost plus heuristic, if that is not nil.
+func (g *Graph) search(paths *Paths, target int, heuristic func(node int) assign.Cost, options *Options) {
+	priority := func(node int, cost assign.Cost) assign.Cost {
+		if heuristic == nil {
+			return cost
+		}
+		return options.AddCost(cost, heuristic(node))
+	}
+	// Nodes are taken by priority, and then by node number.
+	queue := pqueue.New(func(a, b nodePriority) bool {
+		if a.priority.Less(b.priority) {
+			return true
+		}
+		return !b.priority.Less(a.priority) && a.node < b.node
+	})
+	source := paths.source
+	paths.costs[source] = options.ZeroCost
+	queue.Push(source, nodePriority{source, priority(source, options.ZeroCost)})
+	for queue.Len() > 0 {
+		node, _ := queue.Pop()
+		if node == target {
+			return
+		}
+		for _, e := range g.adj[node] {
+			if e.Cost.Less(options.ZeroCost) {
+				panic(fmt.Sprintf("graph: edge from %d to %d has negative cost %v", e.From, e.To, e.Cost))
+			}
+			cost := options.AddCost(paths.costs[node], e.Cost)
+			if old := paths.costs[e.To]; old != nil && !cost.Less(old) {
+				continue
+			}
+			paths.costs[e.To] = cost
+			paths.prev[e.To] = node
+			// Nodes visited already are queued again if the heuristic
+			// overestimated the cost through them.
+			if p := (nodePriority{e.To, priority(e.To, cost)}); queue.Contains(e.To) {
+				queue.UpdatePriority(e.To, p)
+			} else {
+				queue.Push(e.To, p)
+			}
+		}
+	}
+}
+
+// nodePriority is the priority of a node in the queue of search.
+type nodePriority struct {
+	node     int
+	priority assign.Cost
+}
+
+// NegativeCycleError reports that a cycle of edges costing less than zero
+// in total is reachable from the source, so paths through it have no
+// lowest cost.
+type NegativeCycleError struct {
+	// Cycle holds the nodes along the cycle in the order of its edges,
+	// starting from its lowest node, which isn't repeated at the end.
+	Cycle []int
+}
+
+func (e *NegativeCycleError) Error() string {
+	return "negative cost cycle: " + formatCycle(e.Cycle)
+}
+
+// BellmanFord returns the cheapest paths from source to every node
+// reachable from it, found with the Bellman-Ford algorithm in O(VE) time.
+// Unlike Dijkstra, edges may have negative costs. When several paths cost
+// the same, the one found first is kept. The options may be nil.
+//
+// BellmanFord returns a *NegativeCycleError if a cycle with negative total
+// cost is reachable from source.
+func (g *Graph) BellmanFord(source int, options *Options) (*Paths, error) {
+	g.checkNode(source)
+	options = options.withDefaults()
+	n := len(g.adj)
+	paths := newPaths(source, n)
+	paths.costs[source] = options.ZeroCost
+
+	// After n-1 rounds every cheapest path is known, so any edge still
+	// lowering a cost in the round after that closes a negative cycle.
+	for round := 0; round < n; round++ {
+		last := -1
+		for node, edges := range g.adj {
+			if paths.costs[node] == nil {
+				continue
+			}
+			for _, e := range edges {
+				cost := options.AddCost(paths.costs[node], e.Cost)
+				if old := paths.costs[e.To]; old == nil || cost.Less(old) {
+					paths.costs[e.To] = cost
+					paths.prev[e.To] = node
+					last = e.To
+				}
+			}
+		}
+		if last < 0 {
+			return paths, nil
+		}
+		if round == n-1 {
+			return nil, &NegativeCycleError{Cycle: paths.cycle(last)}
+		}
+	}
+	return paths, nil
+}
+
+// cycle returns the cycle of previous nodes leading to node, starting
+// from its lowest node. Node must have been lowered in the last round of
+// BellmanFord, so going back once for each node ends up in the cycle.
+func (p *Paths) cycle(node int) []int {
+	for range p.prev {
+		node = p.prev[node]
+	}
+	cycle := []int{node}
+	for prev := p.prev[node]; prev != node; prev = p.prev[prev] {
+		cycle = append(cycle, prev)
+	}
+	return backwardCycle(cycle)
+}
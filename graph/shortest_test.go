@@ -0,0 +1,224 @@
+package graph_test
+
+import (
+	"math/rand"
+
+	"github.com/canonical/go-algo/assign"
+	"github.com/canonical/go-algo/graph"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestDijkstra(c *C) {
+	g := graph.New(5)
+	g.AddEdge(0, 1, assign.IntCost(4))
+	g.AddEdge(0, 2, assign.IntCost(1))
+	g.AddEdge(2, 1, assign.IntCost(2))
+	g.AddEdge(1, 3, assign.IntCost(1))
+	g.AddEdge(2, 3, assign.IntCost(5))
+
+	paths := g.Dijkstra(0, nil)
+	c.Assert(paths.Source(), Equals, 0)
+	cost, ok := paths.Cost(3)
+	c.Assert(cost, Equals, assign.IntCost(4))
+	c.Assert(ok, Equals, true)
+	c.Assert(paths.To(3), DeepEquals, []int{0, 2, 1, 3})
+	c.Assert(paths.To(0), DeepEquals, []int{0})
+	cost, ok = paths.Cost(4)
+	c.Assert(cost, IsNil)
+	c.Assert(ok, Equals, false)
+	c.Assert(paths.To(4), IsNil)
+
+	g.AddEdge(3, 4, assign.IntCost(-1))
+	c.Assert(func() { g.Dijkstra(0, nil) }, PanicMatches, "graph: edge from 3 to 4 has negative cost -1")
+	c.Assert(func() { g.AddEdge(0, 5, assign.IntCost(1)) }, PanicMatches, "graph: edge from 0 to 5 is out of range for 5 nodes")
+}
+
+func (*S) TestFloatCost(c *C) {
+	g := graph.New(3)
+	g.AddEdge(0, 1, assign.FloatCost(0.5))
+	g.AddEdge(1, 2, assign.FloatCost(0.25))
+	g.AddEdge(0, 2, assign.FloatCost(1))
+	paths := g.Dijkstra(0, &graph.Options{ZeroCost: assign.FloatCost(0)})
+	cost, _ := paths.Cost(2)
+	c.Assert(cost, Equals, assign.FloatCost(0.75))
+	c.Assert(paths.To(2), DeepEquals, []int{0, 1, 2})
+}
+
+func (*S) TestAStar(c *C) {
+	// A grid where the heuristic is the Manhattan distance to the corner.
+	const size = 5
+	g := graph.New(size * size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if x+1 < size {
+				g.AddEdge(y*size+x, y*size+x+1, assign.IntCost(1))
+				g.AddEdge(y*size+x+1, y*size+x, assign.IntCost(1))
+			}
+			if y+1 < size {
+				g.AddEdge(y*size+x, (y+1)*size+x, assign.IntCost(1))
+				g.AddEdge((y+1)*size+x, y*size+x, assign.IntCost(1))
+			}
+		}
+	}
+	heuristic := func(node int) assign.Cost {
+		return assign.IntCost(2*(size-1) - node%size - node/size)
+	}
+	path, cost := g.AStar(0, size*size-1, heuristic, nil)
+	c.Assert(cost, Equals, assign.IntCost(2*(size-1)))
+	c.Assert(path, HasLen, 2*(size-1)+1)
+	c.Assert(path[0], Equals, 0)
+	c.Assert(path[len(path)-1], Equals, size*size-1)
+
+	g = graph.New(2)
+	path, cost = g.AStar(0, 1, heuristic, nil)
+	c.Assert(path, IsNil)
+	c.Assert(cost, IsNil)
+}
+
+func (*S) TestBellmanFord(c *C) {
+	g := graph.New(4)
+	g.AddEdge(0, 1, assign.IntCost(4))
+	g.AddEdge(0, 2, assign.IntCost(5))
+	g.AddEdge(2, 1, assign.IntCost(-3))
+	g.AddEdge(1, 3, assign.IntCost(2))
+	paths, err := g.BellmanFord(0, nil)
+	c.Assert(err, IsNil)
+	cost, _ := paths.Cost(3)
+	c.Assert(cost, Equals, assign.IntCost(4))
+	c.Assert(paths.To(3), DeepEquals, []int{0, 2, 1, 3})
+
+	g.AddEdge(3, 2, assign.IntCost(0))
+	_, err = g.BellmanFord(0, nil)
+	c.Assert(err, ErrorMatches, "negative cost cycle: 1 -> 3 -> 2 -> 1")
+	c.Assert(err.(*graph.NegativeCycleError).Cycle, DeepEquals, []int{1, 3, 2})
+
+	// Cycles that can't be reached don't matter.
+	g = graph.New(3)
+	g.AddEdge(1, 2, assign.IntCost(-1))
+	g.AddEdge(2, 1, assign.IntCost(-1))
+	paths, err = g.BellmanFord(0, nil)
+	c.Assert(err, IsNil)
+	c.Assert(paths.To(1), IsNil)
+}
+
+// floydWarshall returns the cost of the cheapest path between every two
+// nodes of g, with ok set if there is one.
+func floydWarshall(g *graph.Graph) (dist [][]assign.IntCost, ok [][]bool) {
+	n := g.Nodes()
+	dist = make([][]assign.IntCost, n)
+	ok = make([][]bool, n)
+	for i := range dist {
+		dist[i] = make([]assign.IntCost, n)
+		ok[i] = make([]bool, n)
+		ok[i][i] = true
+		for _, e := range g.Edges(i) {
+			cost := e.Cost.(assign.IntCost)
+			if !ok[i][e.To] || cost < dist[i][e.To] {
+				dist[i][e.To], ok[i][e.To] = cost, true
+			}
+		}
+	}
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if ok[i][k] && ok[k][j] && (!ok[i][j] || dist[i][k]+dist[k][j] < dist[i][j]) {
+					dist[i][j], ok[i][j] = dist[i][k]+dist[k][j], true
+				}
+			}
+		}
+	}
+	return dist, ok
+}
+
+// pathCost returns the cost of the cheapest edges along path.
+func pathCost(c *C, g *graph.Graph, path []int) assign.IntCost {
+	var total assign.IntCost
+	for i := 1; i < len(path); i++ {
+		found := false
+		var best assign.IntCost
+		for _, e := range g.Edges(path[i-1]) {
+			if e.To == path[i] && (!found || e.Cost.(assign.IntCost) < best) {
+				best, found = e.Cost.(assign.IntCost), true
+			}
+		}
+		c.Assert(found, Equals, true)
+		total += best
+	}
+	return total
+}
+
+func (*S) TestShortestRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		n := 1 + rnd.Intn(8)
+		edges := rnd.Intn(3 * n)
+		negative := i%2 == 1
+		c.Logf("Test %d: %d nodes, %d edges, negative %v", i, n, edges, negative)
+
+		g := graph.New(n)
+		for e := 0; e < edges; e++ {
+			cost := assign.IntCost(rnd.Intn(10))
+			if negative {
+				cost -= 2
+			}
+			g.AddEdge(rnd.Intn(n), rnd.Intn(n), cost)
+		}
+		dist, reachable := floydWarshall(g)
+		cyclic := make([]bool, n)
+		for v := 0; v < n; v++ {
+			cyclic[v] = dist[v][v] < 0
+		}
+
+		for source := 0; source < n; source++ {
+			paths, err := g.BellmanFord(source, nil)
+			cycleReachable := false
+			for v := 0; v < n; v++ {
+				cycleReachable = cycleReachable || reachable[source][v] && cyclic[v]
+			}
+			if cycleReachable {
+				c.Assert(err, FitsTypeOf, &graph.NegativeCycleError{})
+				cycle := err.(*graph.NegativeCycleError).Cycle
+				c.Assert(reachable[source][cycle[0]], Equals, true)
+				c.Assert(pathCost(c, g, append(cycle, cycle[0])) < 0, Equals, true)
+				continue
+			}
+			c.Assert(err, IsNil)
+			all := []*graph.Paths{paths}
+			if !negative {
+				all = append(all, g.Dijkstra(source, nil))
+			}
+			for _, paths := range all {
+				for target := 0; target < n; target++ {
+					cost, ok := paths.Cost(target)
+					c.Assert(ok, Equals, reachable[source][target])
+					if !ok {
+						c.Assert(paths.To(target), IsNil)
+						continue
+					}
+					c.Assert(cost, Equals, dist[source][target])
+					c.Assert(pathCost(c, g, paths.To(target)), Equals, cost)
+				}
+			}
+			if negative {
+				continue
+			}
+			for target := 0; target < n; target++ {
+				// Half the remaining cost is an admissible estimate.
+				heuristic := func(node int) assign.Cost {
+					if !reachable[node][target] {
+						return assign.IntCost(0)
+					}
+					return dist[node][target] / 2
+				}
+				path, cost := g.AStar(source, target, heuristic, nil)
+				if !reachable[source][target] {
+					c.Assert(path, IsNil)
+					continue
+				}
+				c.Assert(cost, Equals, dist[source][target])
+				c.Assert(pathCost(c, g, path), Equals, cost)
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"container/heap"
+	"strconv"
+	"strings"
+)
+
+// CycleError reports that the graph has a cycle, so its nodes have no
+// topological order.
+type CycleError struct {
+	// Cycle holds the nodes along the cycle in the order of its edges,
+	// starting from its lowest node, which isn't repeated at the end.
+	Cycle []int
+}
+
+func (e *CycleError) Error() string {
+	return "cycle: " + formatCycle(e.Cycle)
+}
+
+// TopoSort returns the nodes of the graph in topological order, so that
+// every edge goes from a node to one after it. Edge costs are ignored.
+// Among the nodes whose predecessors are all placed, the lowest one is
+// placed first, so the order only depends on the edges, and not on the
+// order they were added in. This is Kahn's algorithm, running in
+// O(E + V log V) time.
+//
+// TopoSort returns a *CycleError holding one of the cycles if there is
+// any, which for dependency graphs is the chain of dependencies that can't
+// be satisfied.
+func (g *Graph) TopoSort() ([]int, error) {
+	n := len(g.adj)
+	indegree := make([]int, n)
+	for _, edges := range g.adj {
+		for _, e := range edges {
+			indegree[e.To]++
+		}
+	}
+	ready := &intHeap{}
+	for node, degree := range indegree {
+		if degree == 0 {
+			ready.nodes = append(ready.nodes, node)
+		}
+	}
+	order := make([]int, 0, n)
+	for ready.Len() > 0 {
+		node := heap.Pop(ready).(int)
+		order = append(order, node)
+		for _, e := range g.adj[node] {
+			indegree[e.To]--
+			if indegree[e.To] == 0 {
+				heap.Push(ready, e.To)
+			}
+		}
+	}
+	if len(order) < n {
+		return nil, &CycleError{Cycle: g.remainingCycle(indegree)}
+	}
+	return order, nil
+}
+
+// remainingCycle returns a cycle among the nodes left with a positive
+// indegree by TopoSort. Each of these has a predecessor that is also left,
+// so going back through them from any of them ends up in a cycle.
+func (g *Graph) remainingCycle(indegree []int) []int {
+	prev := make([]int, len(g.adj))
+	start := -1
+	for node, edges := range g.adj {
+		if indegree[node] == 0 {
+			continue
+		}
+		if start < 0 {
+			start = node
+		}
+		for _, e := range edges {
+			if indegree[e.To] > 0 {
+				prev[e.To] = node
+			}
+		}
+	}
+	seen := make([]bool, len(g.adj))
+	node := start
+	for !seen[node] {
+		seen[node] = true
+		node = prev[node]
+	}
+	cycle := []int{node}
+	for p := prev[node]; p != node; p = prev[p] {
+		cycle = append(cycle, p)
+	}
+	return backwardCycle(cycle)
+}
+
+// backwardCycle returns the cycle of nodes found going backwards along
+// its edges, in the order of its edges and starting from its lowest node.
+func backwardCycle(backward []int) []int {
+	lowest := 0
+	for i, node := range backward {
+		if node < backward[lowest] {
+			lowest = i
+		}
+	}
+	cycle := make([]int, 0, len(backward))
+	for i := range backward {
+		cycle = append(cycle, backward[(lowest-i+len(backward))%len(backward)])
+	}
+	return cycle
+}
+
+// formatCycle returns the nodes along cycle, ending with the first one.
+func formatCycle(cycle []int) string {
+	nodes := make([]string, 0, len(cycle)+1)
+	for _, node := range cycle {
+		nodes = append(nodes, strconv.Itoa(node))
+	}
+	return strings.Join(append(nodes, nodes[0]), " -> ")
+}
+
+// intHeap is a priority queue of nodes taking the lowest one first.
+type intHeap struct {
+	nodes []int
+}
+
+func (h *intHeap) Len() int           { return len(h.nodes) }
+func (h *intHeap) Less(i, j int) bool { return h.nodes[i] < h.nodes[j] }
+func (h *intHeap) Swap(i, j int)      { h.nodes[i], h.nodes[j] = h.nodes[j], h.nodes[i] }
+func (h *intHeap) Push(x any)         { h.nodes = append(h.nodes, x.(int)) }
+
+func (h *intHeap) Pop() any {
+	last := h.nodes[len(h.nodes)-1]
+	h.nodes = h.nodes[:len(h.nodes)-1]
+	return last
+}
@@ -0,0 +1,89 @@
+package graph_test
+
+import (
+	"math/rand"
+
+	"github.com/canonical/go-algo/assign"
+	"github.com/canonical/go-algo/graph"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestTopoSort(c *C) {
+	g := graph.New(6)
+	g.AddEdge(5, 2, assign.IntCost(0))
+	g.AddEdge(5, 0, assign.IntCost(0))
+	g.AddEdge(4, 0, assign.IntCost(0))
+	g.AddEdge(4, 1, assign.IntCost(0))
+	g.AddEdge(2, 3, assign.IntCost(0))
+	g.AddEdge(3, 1, assign.IntCost(0))
+	order, err := g.TopoSort()
+	c.Assert(err, IsNil)
+	c.Assert(order, DeepEquals, []int{4, 5, 0, 2, 3, 1})
+
+	order, err = graph.New(0).TopoSort()
+	c.Assert(err, IsNil)
+	c.Assert(order, HasLen, 0)
+
+	g.AddEdge(1, 5, assign.IntCost(0))
+	order, err = g.TopoSort()
+	c.Assert(order, IsNil)
+	c.Assert(err, ErrorMatches, "cycle: 1 -> 5 -> 2 -> 3 -> 1")
+	c.Assert(err.(*graph.CycleError).Cycle, DeepEquals, []int{1, 5, 2, 3})
+
+	g = graph.New(2)
+	g.AddEdge(1, 1, assign.IntCost(0))
+	_, err = g.TopoSort()
+	c.Assert(err, ErrorMatches, "cycle: 1 -> 1")
+}
+
+func (*S) TestTopoSortRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		n := 1 + rnd.Intn(10)
+		edges := rnd.Intn(2 * n)
+		c.Logf("Test %d: %d nodes, %d edges", i, n, edges)
+
+		type edge struct{ from, to int }
+		var added []edge
+		for e := 0; e < edges; e++ {
+			added = append(added, edge{rnd.Intn(n), rnd.Intn(n)})
+		}
+		g := graph.New(n)
+		for _, e := range added {
+			g.AddEdge(e.from, e.to, assign.IntCost(0))
+		}
+		order, err := g.TopoSort()
+
+		// The order doesn't depend on the order of the edges.
+		rnd.Shuffle(len(added), func(a, b int) { added[a], added[b] = added[b], added[a] })
+		shuffled := graph.New(n)
+		for _, e := range added {
+			shuffled.AddEdge(e.from, e.to, assign.IntCost(0))
+		}
+		shuffledOrder, _ := shuffled.TopoSort()
+		c.Assert(shuffledOrder, DeepEquals, order)
+
+		if err != nil {
+			cycle := err.(*graph.CycleError).Cycle
+			for k, from := range cycle {
+				to := cycle[(k+1)%len(cycle)]
+				found := false
+				for _, e := range g.Edges(from) {
+					found = found || e.To == to
+				}
+				c.Assert(found, Equals, true)
+				c.Assert(from >= cycle[0], Equals, true)
+			}
+			continue
+		}
+		position := make([]int, n)
+		for k, node := range order {
+			position[node] = k
+		}
+		c.Assert(order, HasLen, n)
+		for _, e := range added {
+			c.Assert(position[e.from] < position[e.to], Equals, true)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package interval finds intervals holding a point or overlapping another
+// interval with an interval tree, and schedules intervals that can't
+// overlap, such as tasks taking some time each.
+//
+// Intervals are half-open, holding their start but not their end, so an
+// interval ending where another starts doesn't overlap it.
+package interval
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+)
+
+// Interval holds the points from Start up to but not including End.
+type Interval[T cmp.Ordered] struct {
+	Start T
+	End   T
+}
+
+// Empty reports whether the interval holds no points, which is the case
+// when it ends where it starts.
+func (iv Interval[T]) Empty() bool {
+	return iv.End <= iv.Start
+}
+
+// Contains reports whether point is within the interval.
+func (iv Interval[T]) Contains(point T) bool {
+	return iv.Start <= point && point < iv.End
+}
+
+// Overlaps reports whether the interval shares any point with other.
+func (iv Interval[T]) Overlaps(other Interval[T]) bool {
+	return iv.Start < other.End && other.Start < iv.End && !iv.Empty() && !other.Empty()
+}
+
+// Tree is an interval tree over a fixed set of intervals, finding those
+// holding a point or overlapping another interval in O(log n + k) time for
+// k intervals found. It's built in O(n log n) time and O(n) space, and is
+// safe for concurrent use.
+//
+// The intervals are kept sorted by their start, forming a balanced binary
+// search tree in which the middle interval of every range is the root of
+// the ranges on either side, and each root holds the highest end in its
+// range, so ranges ending before a query starts are skipped.
+type Tree[T cmp.Ordered] struct {
+	intervals []Interval[T]
+	order     []int
+	maxEnd    []T
+}
+
+// NewTree returns a tree holding the given intervals. Queries report the
+// intervals found by their index in intervals.
+//
+// NewTree panics if an interval ends before it starts.
+func NewTree[T cmp.Ordered](intervals []Interval[T]) *Tree[T] {
+	t := &Tree[T]{
+		intervals: make([]Interval[T], len(intervals)),
+		order:     make([]int, len(intervals)),
+		maxEnd:    make([]T, len(intervals)),
+	}
+	for i, iv := range intervals {
+		if iv.End < iv.Start {
+			panic(fmt.Sprintf("interval: interval %d ends at %v before it starts at %v", i, iv.End, iv.Start))
+		}
+		t.order[i] = i
+	}
+	sort.SliceStable(t.order, func(a, b int) bool {
+		return intervals[t.order[a]].Start < intervals[t.order[b]].Start
+	})
+	for k, i := range t.order {
+		t.intervals[k] = intervals[i]
+	}
+	if len(intervals) > 0 {
+		t.build(0, len(intervals))
+	}
+	return t
+}
+
+// build sets the highest end of the range from lo up to hi at its middle,
+// and returns it. The range must not be empty.
+func (t *Tree[T]) build(lo, hi int) T {
+	mid := int(uint(lo+hi) >> 1)
+	end := t.intervals[mid].End
+	if lo < mid {
+		end = max(end, t.build(lo, mid))
+	}
+	if mid+1 < hi {
+		end = max(end, t.build(mid+1, hi))
+	}
+	t.maxEnd[mid] = end
+	return end
+}
+
+// Len returns the number of intervals in the tree.
+func (t *Tree[T]) Len() int {
+	return len(t.intervals)
+}
+
+// Stab returns the index of every interval holding point, in order of
+// their start, and then of their index.
+func (t *Tree[T]) Stab(point T) []int {
+	var found []int
+	t.search(0, len(t.intervals), func(iv Interval[T]) (after bool, match bool) {
+		return point < iv.Start, iv.Contains(point)
+	}, point, &found)
+	return found
+}
+
+// Overlap returns the index of every interval overlapping query, in order
+// of their start, and then of their index. Nothing overlaps an empty query.
+func (t *Tree[T]) Overlap(query Interval[T]) []int {
+	var found []int
+	if query.Empty() {
+		return nil
+	}
+	t.search(0, len(t.intervals), func(iv Interval[T]) (after bool, match bool) {
+		return query.End <= iv.Start, iv.Overlaps(query)
+	}, query.Start, &found)
+	return found
+}
+
+// search appends to found the intervals in the range from lo up to hi
+// for which test reports a match, skipping those ending at or before from,
+// and those starting after the first one test reports to be after the
+// query.
+func (t *Tree[T]) search(lo, hi int, test func(iv Interval[T]) (after, match bool), from T, found *[]int) {
+	if lo >= hi {
+		return
+	}
+	mid := int(uint(lo+hi) >> 1)
+	if t.maxEnd[mid] <= from {
+		return
+	}
+	t.search(lo, mid, test, from, found)
+	after, match := test(t.intervals[mid])
+	if match {
+		*found = append(*found, t.order[mid])
+	}
+	if !after {
+		t.search(mid+1, hi, test, from, found)
+	}
+}
@@ -0,0 +1,74 @@
+package interval_test
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/canonical/go-algo/interval"
+
+	. "gopkg.in/check.v1"
+)
+
+type iv = interval.Interval[int]
+
+func (*S) TestInterval(c *C) {
+	c.Assert(iv{1, 3}.Contains(1), Equals, true)
+	c.Assert(iv{1, 3}.Contains(3), Equals, false)
+	c.Assert(iv{1, 3}.Overlaps(iv{2, 5}), Equals, true)
+	c.Assert(iv{1, 3}.Overlaps(iv{3, 5}), Equals, false)
+	c.Assert(iv{1, 5}.Overlaps(iv{2, 2}), Equals, false)
+	c.Assert(iv{2, 2}.Empty(), Equals, true)
+}
+
+func (*S) TestTree(c *C) {
+	intervals := []iv{{5, 10}, {0, 3}, {2, 8}, {8, 9}, {2, 4}, {12, 12}}
+	tree := interval.NewTree(intervals)
+	c.Assert(tree.Len(), Equals, 6)
+	c.Assert(tree.Stab(2), DeepEquals, []int{1, 2, 4})
+	c.Assert(tree.Stab(8), DeepEquals, []int{0, 3})
+	c.Assert(tree.Stab(10), IsNil)
+	c.Assert(tree.Stab(12), IsNil)
+	c.Assert(tree.Overlap(iv{3, 6}), DeepEquals, []int{2, 4, 0})
+	c.Assert(tree.Overlap(iv{9, 20}), DeepEquals, []int{0})
+	c.Assert(tree.Overlap(iv{4, 4}), IsNil)
+	c.Assert(interval.NewTree[int](nil).Stab(0), IsNil)
+
+	c.Assert(func() { interval.NewTree([]iv{{3, 2}}) }, PanicMatches, "interval: interval 0 ends at 2 before it starts at 3")
+}
+
+func (*S) TestTreeRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	random := func() iv {
+		start := rnd.Intn(50)
+		return iv{start, start + rnd.Intn(10)}
+	}
+	for i := 0; i < 200; i++ {
+		intervals := make([]iv, rnd.Intn(30))
+		for k := range intervals {
+			intervals[k] = random()
+		}
+		c.Logf("Test %d: %v", i, intervals)
+		tree := interval.NewTree(intervals)
+
+		// Results are sorted by start, and then by index.
+		expect := func(match func(iv) bool) []int {
+			var found []int
+			for k, other := range intervals {
+				if match(other) {
+					found = append(found, k)
+				}
+			}
+			sort.SliceStable(found, func(a, b int) bool {
+				return intervals[found[a]].Start < intervals[found[b]].Start
+			})
+			return found
+		}
+		for point := -1; point < 62; point++ {
+			c.Assert(tree.Stab(point), DeepEquals, expect(func(other iv) bool { return other.Contains(point) }))
+		}
+		for q := 0; q < 20; q++ {
+			query := random()
+			c.Assert(tree.Overlap(query), DeepEquals, expect(query.Overlaps))
+		}
+	}
+}
@@ -0,0 +1,178 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package interval
+
+import (
+	"cmp"
+	"container/heap"
+	"fmt"
+	"sort"
+
+	"github.com/canonical/go-algo/assign"
+)
+
+// Schedule returns the index of the intervals of highest total weight
+// that don't overlap each other, in order of their start, and their total
+// weight. This is the weighted interval scheduling problem, solved with
+// dynamic programming in O(n log n) time. Empty intervals and those with
+// weights that aren't positive are never chosen, and among the sets of intervals with
+// the highest weight, the one chosen only depends on the input.
+//
+// Schedule panics if the number of weights doesn't match the number of
+// intervals, or if an interval ends before it starts.
+func Schedule[T cmp.Ordered, W assign.Number](intervals []Interval[T], weights []W) (chosen []int, total W) {
+	if len(weights) != len(intervals) {
+		panic(fmt.Sprintf("interval: Schedule got %d weights for %d intervals", len(weights), len(intervals)))
+	}
+	n := len(intervals)
+	byEnd := make([]int, n)
+	for i, iv := range intervals {
+		if iv.End < iv.Start {
+			panic(fmt.Sprintf("interval: interval %d ends at %v before it starts at %v", i, iv.End, iv.Start))
+		}
+		byEnd[i] = i
+	}
+	sort.SliceStable(byEnd, func(a, b int) bool {
+		return intervals[byEnd[a]].End < intervals[byEnd[b]].End
+	})
+
+	// best[k] is the highest weight of the first k intervals by end, and
+	// taken[k] reports whether it's reached by taking the kth of them.
+	best := make([]W, n+1)
+	taken := make([]bool, n+1)
+	// prev[k] is the number of intervals by end that finish before the
+	// kth of them starts, and so may be taken along with it.
+	prev := make([]int, n+1)
+	for k := 1; k <= n; k++ {
+		iv := intervals[byEnd[k-1]]
+		prev[k] = sort.Search(k-1, func(p int) bool {
+			return intervals[byEnd[p]].End > iv.Start
+		})
+		best[k] = best[k-1]
+		if w := weights[byEnd[k-1]]; w > 0 && !iv.Empty() {
+			if with := best[prev[k]] + w; with > best[k] {
+				best[k], taken[k] = with, true
+			}
+		}
+	}
+	for k := n; k > 0; {
+		if taken[k] {
+			chosen = append(chosen, byEnd[k-1])
+			k = prev[k]
+		} else {
+			k--
+		}
+	}
+	// Intervals were collected from the last one to end, and those that
+	// don't overlap start in the same order they end.
+	for i, j := 0, len(chosen)-1; i < j; i, j = i+1, j-1 {
+		chosen[i], chosen[j] = chosen[j], chosen[i]
+	}
+	return chosen, best[n]
+}
+
+// Partition assigns each interval to a lane, so that intervals in the same
+// lane don't overlap, using as few lanes as possible, and returns the lane
+// of each interval and the number of lanes. This is the number of tasks
+// running at the busiest time, and the lanes may be resources such as
+// workers or rooms. Intervals are taken in order of their start, each
+// into the lowest lane free by then. Empty intervals overlap nothing, and
+// are assigned to lane zero. It runs in O(n log n) time.
+//
+// Partition panics if an interval ends before it starts.
+func Partition[T cmp.Ordered](intervals []Interval[T]) (lanes []int, count int) {
+	n := len(intervals)
+	byStart := make([]int, n)
+	for i, iv := range intervals {
+		if iv.End < iv.Start {
+			panic(fmt.Sprintf("interval: interval %d ends at %v before it starts at %v", i, iv.End, iv.Start))
+		}
+		byStart[i] = i
+	}
+	sort.SliceStable(byStart, func(a, b int) bool {
+		return intervals[byStart[a]].Start < intervals[byStart[b]].Start
+	})
+
+	lanes = make([]int, n)
+	busy := &laneHeap[T]{}
+	free := &intHeap{}
+	empty := false
+	for _, i := range byStart {
+		iv := intervals[i]
+		if iv.Empty() {
+			empty = true
+			continue
+		}
+		for busy.Len() > 0 && busy.items[0].end <= iv.Start {
+			heap.Push(free, heap.Pop(busy).(laneEnd[T]).lane)
+		}
+		lane := count
+		if free.Len() > 0 {
+			lane = heap.Pop(free).(int)
+		} else {
+			count++
+		}
+		lanes[i] = lane
+		heap.Push(busy, laneEnd[T]{lane, iv.End})
+	}
+	if empty {
+		count = max(count, 1)
+	}
+	return lanes, count
+}
+
+// laneHeap is a priority queue of busy lanes, taking the one that ends
+// first, and then the lowest one.
+type laneHeap[T cmp.Ordered] struct {
+	items []laneEnd[T]
+}
+
+type laneEnd[T cmp.Ordered] struct {
+	lane int
+	end  T
+}
+
+func (h *laneHeap[T]) Len() int { return len(h.items) }
+
+func (h *laneHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+	return a.end < b.end || a.end == b.end && a.lane < b.lane
+}
+
+func (h *laneHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *laneHeap[T]) Push(x any)    { h.items = append(h.items, x.(laneEnd[T])) }
+
+func (h *laneHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
+
+// intHeap is a priority queue of lanes taking the lowest one first.
+type intHeap struct {
+	items []int
+}
+
+func (h *intHeap) Len() int           { return len(h.items) }
+func (h *intHeap) Less(i, j int) bool { return h.items[i] < h.items[j] }
+func (h *intHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *intHeap) Push(x any)         { h.items = append(h.items, x.(int)) }
+
+func (h *intHeap) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
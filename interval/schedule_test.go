@@ -0,0 +1,124 @@
+package interval_test
+
+import (
+	"math/rand"
+
+	"github.com/canonical/go-algo/interval"
+
+	. "gopkg.in/check.v1"
+)
+
+func (*S) TestSchedule(c *C) {
+	intervals := []iv{{0, 3}, {2, 5}, {4, 7}, {6, 9}, {3, 4}, {1, 1}}
+	chosen, total := interval.Schedule(intervals, []int{2, 4, 4, 7, 1, 100})
+	c.Assert(chosen, DeepEquals, []int{1, 3})
+	c.Assert(total, Equals, 11)
+
+	chosen, fraction := interval.Schedule(intervals, []float64{0.5, 0.5, 0.5, 0.5, 0.5, 0.5})
+	c.Assert(chosen, DeepEquals, []int{0, 4, 2})
+	c.Assert(fraction, Equals, 1.5)
+
+	chosen, total = interval.Schedule([]iv{{0, 1}}, []int{-1})
+	c.Assert(chosen, IsNil)
+	c.Assert(total, Equals, 0)
+
+	c.Assert(func() { interval.Schedule(intervals, []int{1}) }, PanicMatches, "interval: Schedule got 1 weights for 6 intervals")
+}
+
+func (*S) TestScheduleRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		n := rnd.Intn(10)
+		intervals := make([]iv, n)
+		weights := make([]int, n)
+		for k := range intervals {
+			start := rnd.Intn(20)
+			intervals[k] = iv{start, start + rnd.Intn(6)}
+			weights[k] = rnd.Intn(10) - 2
+		}
+		c.Logf("Test %d: %v %v", i, intervals, weights)
+		chosen, total := interval.Schedule(intervals, weights)
+
+		sum := 0
+		for k, a := range chosen {
+			sum += weights[a]
+			if k > 0 {
+				c.Assert(intervals[chosen[k-1]].End <= intervals[a].Start, Equals, true)
+			}
+		}
+		c.Assert(sum, Equals, total)
+
+		// Try every subset of intervals.
+		best := 0
+		for set := 0; set < 1<<n; set++ {
+			weight := 0
+			ok := true
+			for a := 0; a < n && ok; a++ {
+				if set&(1<<a) == 0 {
+					continue
+				}
+				if intervals[a].Empty() {
+					ok = false
+				}
+				weight += weights[a]
+				for b := a + 1; b < n; b++ {
+					if set&(1<<b) != 0 && intervals[a].Overlaps(intervals[b]) {
+						ok = false
+					}
+				}
+			}
+			if ok {
+				best = max(best, weight)
+			}
+		}
+		c.Assert(total, Equals, best)
+	}
+}
+
+func (*S) TestPartition(c *C) {
+	intervals := []iv{{0, 3}, {1, 4}, {3, 5}, {4, 6}, {2, 2}, {0, 1}}
+	lanes, count := interval.Partition(intervals)
+	c.Assert(lanes, DeepEquals, []int{0, 1, 0, 1, 0, 1})
+	c.Assert(count, Equals, 2)
+
+	lanes, count = interval.Partition([]iv{{1, 1}})
+	c.Assert(lanes, DeepEquals, []int{0})
+	c.Assert(count, Equals, 1)
+
+	lanes, count = interval.Partition[int](nil)
+	c.Assert(lanes, HasLen, 0)
+	c.Assert(count, Equals, 0)
+}
+
+func (*S) TestPartitionRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		intervals := make([]iv, rnd.Intn(30))
+		for k := range intervals {
+			start := rnd.Intn(30)
+			intervals[k] = iv{start, start + 1 + rnd.Intn(8)}
+		}
+		c.Logf("Test %d: %v", i, intervals)
+		lanes, count := interval.Partition(intervals)
+
+		busiest := 0
+		for point := 0; point < 40; point++ {
+			busy := 0
+			for _, other := range intervals {
+				if other.Contains(point) {
+					busy++
+				}
+			}
+			busiest = max(busiest, busy)
+		}
+		c.Assert(count, Equals, busiest)
+		for a := range intervals {
+			c.Assert(lanes[a] < count, Equals, true)
+			for b := a + 1; b < len(intervals); b++ {
+				if lanes[a] == lanes[b] {
+					c.Assert(intervals[a].Overlaps(intervals[b]), Equals, false)
+				}
+			}
+		}
+	}
+}
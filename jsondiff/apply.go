@@ -0,0 +1,233 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondiff
+
+import (
+	"fmt"
+)
+
+// Apply returns the document resulting from applying changes to doc, which
+// is left unchanged. Applying the changes returned by Diff(a, b) to a
+// results in a document equal to b.
+//
+// Changes are applied all at once rather than in order, following the
+// rules Diff uses to report them: every value of doc that isn't removed or
+// moved elsewhere stays under the same key or index of its parent, wherever
+// the parent goes, unless it's set to another value; and values added or
+// moved take the place given by their path. Values moved keep the values
+// under them that aren't changed, while values added and scalars moved
+// become their New value, which is shared with the result.
+//
+// Apply returns an error if the changes refer to values missing from doc,
+// or would leave a value in an ambiguous or incomplete state, such as two
+// values under the same key or an array with no value at some index.
+func Apply(doc any, changes []Change) (any, error) {
+	ap := &applier{
+		doc:      doc,
+		targets:  make(map[string]*Change),
+		children: make(map[string][]*Change),
+		removed:  make(map[string]bool),
+		moved:    make(map[string]bool),
+	}
+	for i := range changes {
+		change := &changes[i]
+		switch change.Op {
+		case Remove, Move:
+			from := change.From
+			if change.Op == Remove {
+				from = change.Path
+			}
+			if len(from) == 0 {
+				return nil, fmt.Errorf("cannot %s the document root", change.Op)
+			}
+			if _, err := lookup(doc, from); err != nil {
+				return nil, fmt.Errorf("cannot %s %s: %v", change.Op, from, err)
+			}
+			key := from.String()
+			if ap.removed[key] || ap.moved[key] {
+				return nil, fmt.Errorf("cannot %s %s: value already removed or moved", change.Op, from)
+			}
+			if change.Op == Remove {
+				ap.removed[key] = true
+				continue
+			}
+			ap.moved[key] = true
+		case Add, Set:
+		default:
+			return nil, fmt.Errorf("invalid change operation: %v", change.Op)
+		}
+		if len(change.Path) == 0 {
+			if change.Op != Set {
+				return nil, fmt.Errorf("cannot %s the document root", change.Op)
+			}
+			return change.New, nil
+		}
+		key := change.Path.String()
+		if ap.targets[key] != nil {
+			return nil, fmt.Errorf("cannot change %s more than once", change.Path)
+		}
+		ap.targets[key] = change
+		if change.Op != Set {
+			parent := change.Path[:len(change.Path)-1].String()
+			ap.children[parent] = append(ap.children[parent], change)
+		}
+	}
+	return ap.build(doc, Path{}, Path{})
+}
+
+type applier struct {
+	doc any
+
+	// targets holds the changes adding, moving, or setting a value by the
+	// string form of their path, and children holds the changes adding
+	// or moving a value by the string form of the path of its parent.
+	targets  map[string]*Change
+	children map[string][]*Change
+
+	// removed and moved hold the string form of the paths in doc of the
+	// values removed and moved elsewhere.
+	removed map[string]bool
+	moved   map[string]bool
+}
+
+// build returns the value at path in the new document, which came from
+// value at source in doc.
+func (ap *applier) build(value any, source, path Path) (any, error) {
+	switch value := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(value))
+		for key, elem := range value {
+			elem, ok, err := ap.stay(elem, append(source[:len(source):len(source)], key), append(path[:len(path):len(path)], key))
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				result[key] = elem
+			}
+		}
+		for _, change := range ap.children[path.String()] {
+			key, ok := change.Path[len(change.Path)-1].(string)
+			if !ok {
+				return nil, fmt.Errorf("cannot %s %s: parent is not an array", change.Op, change.Path)
+			}
+			if _, ok := result[key]; ok {
+				return nil, fmt.Errorf("cannot %s %s: value is in place", change.Op, change.Path)
+			}
+			elem, err := ap.arrive(change)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = elem
+		}
+		return result, nil
+
+	case []any:
+		elems := make(map[int]any)
+		for i, elem := range value {
+			elem, ok, err := ap.stay(elem, append(source[:len(source):len(source)], i), append(path[:len(path):len(path)], i))
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				elems[i] = elem
+			}
+		}
+		for _, change := range ap.children[path.String()] {
+			i, ok := change.Path[len(change.Path)-1].(int)
+			if !ok {
+				return nil, fmt.Errorf("cannot %s %s: parent is not an object", change.Op, change.Path)
+			}
+			if _, ok := elems[i]; ok {
+				return nil, fmt.Errorf("cannot %s %s: value is in place", change.Op, change.Path)
+			}
+			elem, err := ap.arrive(change)
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = elem
+		}
+		result := make([]any, len(elems))
+		for i := range result {
+			elem, ok := elems[i]
+			if !ok {
+				return nil, fmt.Errorf("array at %s has no value at index %d", path, i)
+			}
+			result[i] = elem
+		}
+		return result, nil
+	}
+	return value, nil
+}
+
+// stay returns the value at path in the new document for the value at
+// source in doc, which is under the same key or index, and whether the
+// value is there at all.
+func (ap *applier) stay(value any, source, path Path) (any, bool, error) {
+	sourceKey := source.String()
+	if ap.removed[sourceKey] || ap.moved[sourceKey] {
+		return nil, false, nil
+	}
+	change := ap.targets[path.String()]
+	switch {
+	case change == nil:
+		value, err := ap.build(value, source, path)
+		return value, err == nil, err
+	case change.Op == Set:
+		return change.New, true, nil
+	}
+	return nil, false, fmt.Errorf("cannot %s %s: value in place is not removed or moved", change.Op, change.Path)
+}
+
+// arrive returns the value added or moved by change.
+func (ap *applier) arrive(change *Change) (any, error) {
+	if change.Op == Add {
+		return change.New, nil
+	}
+	value, err := lookup(ap.doc, change.From)
+	if err != nil {
+		return nil, err
+	}
+	if isContainer(value) {
+		return ap.build(value, change.From, change.Path)
+	}
+	return change.New, nil
+}
+
+// lookup returns the value at path in doc.
+func lookup(doc any, path Path) (any, error) {
+	value := doc
+	for i, elem := range path {
+		switch container := value.(type) {
+		case map[string]any:
+			key, ok := elem.(string)
+			if !ok {
+				return nil, fmt.Errorf("no value at %s", path[:i+1])
+			}
+			if value, ok = container[key]; !ok {
+				return nil, fmt.Errorf("no value at %s", path[:i+1])
+			}
+		case []any:
+			index, ok := elem.(int)
+			if !ok || index < 0 || index >= len(container) {
+				return nil, fmt.Errorf("no value at %s", path[:i+1])
+			}
+			value = container[index]
+		default:
+			return nil, fmt.Errorf("no value at %s", path[:i+1])
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,99 @@
+package jsondiff_test
+
+import (
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/jsondiff"
+)
+
+func (*S) TestApply(c *C) {
+	for _, test := range diffTests {
+		c.Logf("Summary: %s", test.summary)
+		a, b := decode(c, test.a), decode(c, test.b)
+		before := encode(a)
+		result, err := jsondiff.Apply(a, jsondiff.Diff(a, b, nil))
+		c.Assert(err, IsNil)
+		c.Assert(encode(result), Equals, encode(b))
+		c.Assert(encode(a), Equals, before)
+	}
+}
+
+func (*S) TestApplyRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		a := randomValue(rnd, 3)
+		b := mutate(rnd, decode(c, encode(a)), 3)
+		c.Logf("Test: %s => %s", encode(a), encode(b))
+		result, err := jsondiff.Apply(a, jsondiff.Diff(a, b, nil))
+		c.Assert(err, IsNil)
+		c.Assert(encode(result), Equals, encode(b))
+	}
+}
+
+var applyErrorTests = []struct {
+	summary string
+	doc     string
+	changes []jsondiff.Change
+	error   string
+}{{
+	summary: "Removing a missing value",
+	doc:     `{"a": 1}`,
+	changes: []jsondiff.Change{{Op: jsondiff.Remove, Path: jsondiff.Path{"b"}}},
+	error:   `cannot remove .b: no value at .b`,
+}, {
+	summary: "Moving from a missing value",
+	doc:     `{"a": [1]}`,
+	changes: []jsondiff.Change{{Op: jsondiff.Move, From: jsondiff.Path{"a", 1}, Path: jsondiff.Path{"b"}}},
+	error:   `cannot move .a\[1\]: no value at .a\[1\]`,
+}, {
+	summary: "Removing the root",
+	doc:     `{"a": 1}`,
+	changes: []jsondiff.Change{{Op: jsondiff.Remove, Path: jsondiff.Path{}}},
+	error:   `cannot remove the document root`,
+}, {
+	summary: "Adding over a value in place",
+	doc:     `{"a": 1}`,
+	changes: []jsondiff.Change{{Op: jsondiff.Add, Path: jsondiff.Path{"a"}, New: 2}},
+	error:   `cannot add .a: value in place is not removed or moved`,
+}, {
+	summary: "Adding past the end of an array",
+	doc:     `[1]`,
+	changes: []jsondiff.Change{{Op: jsondiff.Add, Path: jsondiff.Path{2}, New: 2}},
+	error:   `array at . has no value at index 1`,
+}, {
+	summary: "Changing a value twice",
+	doc:     `{"a": 1}`,
+	changes: []jsondiff.Change{
+		{Op: jsondiff.Add, Path: jsondiff.Path{"b"}, New: 2},
+		{Op: jsondiff.Add, Path: jsondiff.Path{"b"}, New: 3},
+	},
+	error: `cannot change .b more than once`,
+}, {
+	summary: "Removing a value twice",
+	doc:     `{"a": 1}`,
+	changes: []jsondiff.Change{
+		{Op: jsondiff.Remove, Path: jsondiff.Path{"a"}},
+		{Op: jsondiff.Move, From: jsondiff.Path{"a"}, Path: jsondiff.Path{"b"}},
+	},
+	error: `cannot move .a: value already removed or moved`,
+}, {
+	summary: "Adding an index to an object",
+	doc:     `{"a": {}}`,
+	changes: []jsondiff.Change{{Op: jsondiff.Add, Path: jsondiff.Path{"a", 0}, New: 1}},
+	error:   `cannot add .a\[0\]: parent is not an array`,
+}, {
+	summary: "Invalid operation",
+	doc:     `{}`,
+	changes: []jsondiff.Change{{Op: jsondiff.Op(42)}},
+	error:   `invalid change operation: Op\(42\)`,
+}}
+
+func (*S) TestApplyErrors(c *C) {
+	for _, test := range applyErrorTests {
+		c.Logf("Summary: %s", test.summary)
+		_, err := jsondiff.Apply(decode(c, test.doc), test.changes)
+		c.Assert(err, ErrorMatches, test.error)
+	}
+}
@@ -0,0 +1,503 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondiff
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// encodingVersion is the version of the encoding written by EncodeChanges
+// and EncodePatch.
+const encodingVersion = 1
+
+// The kind of list encoded, following the version.
+const (
+	encodedChanges = 'c'
+	encodedPatch   = 'p'
+)
+
+// The tags identifying the type of encoded values.
+const (
+	tagNull = iota
+	tagFalse
+	tagTrue
+	tagInt
+	tagFloat
+	tagNumber
+	tagString
+	tagArray
+	tagObject
+)
+
+var patchOps = []string{"add", "remove", "replace", "move", "copy", "test"}
+
+// EncodeChanges returns a compact binary encoding of changes, which
+// DecodeChanges turns back into the same changes, so that they may be
+// stored or sent elsewhere and applied there.
+//
+// The encoding starts with a version byte and a byte telling changes from
+// patches, followed by a table of every string found in the changes, as
+// object keys, in paths, or as values, so that each of them is only held
+// once, and then by the changes themselves, as varints referring to the
+// strings by their position in the table. Numbers holding integers are
+// encoded as varints as well, and other numbers in 8 bytes.
+//
+// Values must be of the types produced by encoding/json when decoding into
+// an any value, including json.Number, or EncodeChanges returns an error.
+func EncodeChanges(changes []Change) ([]byte, error) {
+	e := newEncoder()
+	e.uvarint(uint64(len(changes)))
+	for _, change := range changes {
+		if change.Op < Add || change.Op > Move {
+			return nil, fmt.Errorf("cannot encode change with invalid operation %v", change.Op)
+		}
+		e.uvarint(uint64(change.Op))
+		if err := e.path(change.Path); err != nil {
+			return nil, err
+		}
+		if change.Op == Move {
+			if err := e.path(change.From); err != nil {
+				return nil, err
+			}
+		}
+		if err := e.value(change.Old); err != nil {
+			return nil, err
+		}
+		if err := e.value(change.New); err != nil {
+			return nil, err
+		}
+	}
+	return e.finish(encodedChanges), nil
+}
+
+// DecodeChanges returns the changes encoded in data by EncodeChanges. It
+// returns an error if data isn't such an encoding, or is of a version it
+// doesn't know.
+func DecodeChanges(data []byte) ([]Change, error) {
+	d, err := newDecoder(data, encodedChanges)
+	if err != nil {
+		return nil, err
+	}
+	count, err := d.count()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, d.finish()
+	}
+	changes := make([]Change, count)
+	for i := range changes {
+		change := &changes[i]
+		op, err := d.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if op < uint64(Add) || op > uint64(Move) {
+			return nil, fmt.Errorf("invalid change operation %d", op)
+		}
+		change.Op = Op(op)
+		if change.Path, err = d.path(); err != nil {
+			return nil, err
+		}
+		if change.Op == Move {
+			if change.From, err = d.path(); err != nil {
+				return nil, err
+			}
+		}
+		if change.Old, err = d.value(); err != nil {
+			return nil, err
+		}
+		if change.New, err = d.value(); err != nil {
+			return nil, err
+		}
+	}
+	return changes, d.finish()
+}
+
+// EncodePatch returns a compact binary encoding of the JSON Patch
+// operations in patch, in the form described for EncodeChanges, which
+// DecodePatch turns back into the same operations.
+func EncodePatch(patch []Operation) ([]byte, error) {
+	e := newEncoder()
+	e.uvarint(uint64(len(patch)))
+	for _, op := range patch {
+		code := -1
+		for i, name := range patchOps {
+			if op.Op == name {
+				code = i
+			}
+		}
+		if code < 0 {
+			return nil, fmt.Errorf("cannot encode patch with invalid operation %q", op.Op)
+		}
+		e.uvarint(uint64(code))
+		e.string(op.Path)
+		switch op.Op {
+		case "move", "copy":
+			e.string(op.From)
+		case "add", "replace", "test":
+			if err := e.value(op.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return e.finish(encodedPatch), nil
+}
+
+// DecodePatch returns the JSON Patch operations encoded in data by
+// EncodePatch. It returns an error if data isn't such an encoding, or is
+// of a version it doesn't know.
+func DecodePatch(data []byte) ([]Operation, error) {
+	d, err := newDecoder(data, encodedPatch)
+	if err != nil {
+		return nil, err
+	}
+	count, err := d.count()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, d.finish()
+	}
+	patch := make([]Operation, count)
+	for i := range patch {
+		op := &patch[i]
+		code, err := d.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if code >= uint64(len(patchOps)) {
+			return nil, fmt.Errorf("invalid patch operation %d", code)
+		}
+		op.Op = patchOps[code]
+		if op.Path, err = d.string(); err != nil {
+			return nil, err
+		}
+		switch op.Op {
+		case "move", "copy":
+			op.From, err = d.string()
+		case "add", "replace", "test":
+			op.Value, err = d.value()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return patch, d.finish()
+}
+
+// encoder writes the body of an encoding, adding the strings found to its
+// table as they're first seen.
+type encoder struct {
+	body    []byte
+	strings []string
+	index   map[string]int
+}
+
+func newEncoder() *encoder {
+	return &encoder{index: make(map[string]int)}
+}
+
+// finish returns the whole encoding of a list of the given kind.
+func (e *encoder) finish(kind byte) []byte {
+	size := 2 + binary.MaxVarintLen64 + len(e.body)
+	for _, s := range e.strings {
+		size += binary.MaxVarintLen64 + len(s)
+	}
+	data := make([]byte, 0, size)
+	data = append(data, encodingVersion, kind)
+	data = binary.AppendUvarint(data, uint64(len(e.strings)))
+	for _, s := range e.strings {
+		data = binary.AppendUvarint(data, uint64(len(s)))
+		data = append(data, s...)
+	}
+	return append(data, e.body...)
+}
+
+func (e *encoder) uvarint(u uint64) {
+	e.body = binary.AppendUvarint(e.body, u)
+}
+
+func (e *encoder) string(s string) {
+	e.uvarint(uint64(e.stringIndex(s)))
+}
+
+// stringIndex returns the position of s in the table, adding it if needed.
+func (e *encoder) stringIndex(s string) int {
+	i, ok := e.index[s]
+	if !ok {
+		i = len(e.strings)
+		e.index[s] = i
+		e.strings = append(e.strings, s)
+	}
+	return i
+}
+
+// path writes the length of path followed by its elements, each as twice
+// the position of a key in the table, or twice an index plus one.
+func (e *encoder) path(path Path) error {
+	e.uvarint(uint64(len(path)))
+	for _, elem := range path {
+		switch elem := elem.(type) {
+		case string:
+			e.uvarint(uint64(e.stringIndex(elem)) << 1)
+		case int:
+			if elem < 0 {
+				return fmt.Errorf("cannot encode path %v: negative index", path)
+			}
+			e.uvarint(uint64(elem)<<1 | 1)
+		default:
+			return fmt.Errorf("cannot encode path element %#v", elem)
+		}
+	}
+	return nil
+}
+
+// maxExactInt is the highest integer up to which every integer is held
+// exactly by a float64.
+const maxExactInt = 1 << 53
+
+func (e *encoder) value(value any) error {
+	switch value := value.(type) {
+	case nil:
+		e.body = append(e.body, tagNull)
+	case bool:
+		if value {
+			e.body = append(e.body, tagTrue)
+		} else {
+			e.body = append(e.body, tagFalse)
+		}
+	case float64:
+		if value == math.Trunc(value) && math.Abs(value) <= maxExactInt && !(value == 0 && math.Signbit(value)) {
+			e.body = append(e.body, tagInt)
+			e.body = binary.AppendVarint(e.body, int64(value))
+		} else {
+			e.body = append(e.body, tagFloat)
+			e.body = binary.LittleEndian.AppendUint64(e.body, math.Float64bits(value))
+		}
+	case json.Number:
+		e.body = append(e.body, tagNumber)
+		e.string(string(value))
+	case string:
+		e.body = append(e.body, tagString)
+		e.string(value)
+	case []any:
+		e.body = append(e.body, tagArray)
+		e.uvarint(uint64(len(value)))
+		for _, elem := range value {
+			if err := e.value(elem); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		e.body = append(e.body, tagObject)
+		e.uvarint(uint64(len(value)))
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			e.string(k)
+			if err := e.value(value[k]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("cannot encode value of type %T", value)
+	}
+	return nil
+}
+
+var errTruncated = errors.New("truncated encoding")
+
+// decoder reads an encoding written by encoder.
+type decoder struct {
+	data    []byte
+	strings []string
+}
+
+// newDecoder returns a decoder for the body of data, which must be an
+// encoding of a list of the given kind.
+func newDecoder(data []byte, kind byte) (*decoder, error) {
+	if len(data) < 2 {
+		return nil, errTruncated
+	}
+	if data[0] != encodingVersion {
+		return nil, fmt.Errorf("unsupported encoding version %d", data[0])
+	}
+	if data[1] != kind {
+		names := map[byte]string{encodedChanges: "changes", encodedPatch: "patch"}
+		if name, ok := names[data[1]]; ok {
+			return nil, fmt.Errorf("encoding holds a %s rather than %s", name, names[kind])
+		}
+		return nil, fmt.Errorf("invalid encoding kind %d", data[1])
+	}
+	d := &decoder{data: data[2:]}
+	count, err := d.count()
+	if err != nil {
+		return nil, err
+	}
+	d.strings = make([]string, count)
+	for i := range d.strings {
+		size, err := d.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if size > uint64(len(d.data)) {
+			return nil, errTruncated
+		}
+		d.strings[i] = string(d.data[:size])
+		d.data = d.data[size:]
+	}
+	return d, nil
+}
+
+// finish returns an error if there's anything left to decode.
+func (d *decoder) finish() error {
+	if len(d.data) > 0 {
+		return fmt.Errorf("%d bytes left after encoding", len(d.data))
+	}
+	return nil
+}
+
+func (d *decoder) uvarint() (uint64, error) {
+	u, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	d.data = d.data[n:]
+	return u, nil
+}
+
+// count reads the number of items in a list, each of which takes at least
+// one byte, so that lists longer than the data left aren't allocated.
+func (d *decoder) count() (int, error) {
+	count, err := d.uvarint()
+	if err != nil {
+		return 0, err
+	}
+	if count > uint64(len(d.data)) {
+		return 0, errTruncated
+	}
+	return int(count), nil
+}
+
+func (d *decoder) string() (string, error) {
+	i, err := d.uvarint()
+	if err != nil {
+		return "", err
+	}
+	if i >= uint64(len(d.strings)) {
+		return "", fmt.Errorf("invalid string %d in encoding with %d strings", i, len(d.strings))
+	}
+	return d.strings[i], nil
+}
+
+func (d *decoder) path() (Path, error) {
+	count, err := d.count()
+	if err != nil {
+		return nil, err
+	}
+	path := make(Path, count)
+	for i := range path {
+		u, err := d.uvarint()
+		if err != nil {
+			return nil, err
+		}
+		if u&1 == 1 {
+			if u>>1 > math.MaxInt32 {
+				return nil, fmt.Errorf("invalid index %d in path", u>>1)
+			}
+			path[i] = int(u >> 1)
+			continue
+		}
+		if u>>1 >= uint64(len(d.strings)) {
+			return nil, fmt.Errorf("invalid string %d in encoding with %d strings", u>>1, len(d.strings))
+		}
+		path[i] = d.strings[u>>1]
+	}
+	return path, nil
+}
+
+func (d *decoder) value() (any, error) {
+	if len(d.data) == 0 {
+		return nil, errTruncated
+	}
+	tag := d.data[0]
+	d.data = d.data[1:]
+	switch tag {
+	case tagNull:
+		return nil, nil
+	case tagFalse:
+		return false, nil
+	case tagTrue:
+		return true, nil
+	case tagInt:
+		i, n := binary.Varint(d.data)
+		if n <= 0 {
+			return nil, errTruncated
+		}
+		d.data = d.data[n:]
+		return float64(i), nil
+	case tagFloat:
+		if len(d.data) < 8 {
+			return nil, errTruncated
+		}
+		f := math.Float64frombits(binary.LittleEndian.Uint64(d.data))
+		d.data = d.data[8:]
+		return f, nil
+	case tagNumber:
+		s, err := d.string()
+		return json.Number(s), err
+	case tagString:
+		return d.string()
+	case tagArray:
+		count, err := d.count()
+		if err != nil {
+			return nil, err
+		}
+		array := make([]any, count)
+		for i := range array {
+			if array[i], err = d.value(); err != nil {
+				return nil, err
+			}
+		}
+		return array, nil
+	case tagObject:
+		count, err := d.count()
+		if err != nil {
+			return nil, err
+		}
+		object := make(map[string]any, count)
+		for i := 0; i < count; i++ {
+			key, err := d.string()
+			if err != nil {
+				return nil, err
+			}
+			if object[key], err = d.value(); err != nil {
+				return nil, err
+			}
+		}
+		return object, nil
+	}
+	return nil, fmt.Errorf("invalid value tag %d", tag)
+}
@@ -0,0 +1,153 @@
+package jsondiff_test
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/jsondiff"
+)
+
+func (*S) TestEncodeChanges(c *C) {
+	changes := []jsondiff.Change{
+		{Op: jsondiff.Set, Path: jsondiff.Path{"a", 1}, Old: 1.0, New: "x"},
+		{Op: jsondiff.Move, Path: jsondiff.Path{"x"}, From: jsondiff.Path{"a", 0}, Old: "a", New: "a"},
+	}
+	data, err := jsondiff.EncodeChanges(changes)
+	c.Assert(err, IsNil)
+	c.Assert(data, DeepEquals, []byte{
+		1, 'c', // Version and kind.
+		2, 1, 'a', 1, 'x', // String table.
+		2,                      // Number of changes.
+		3, 2, 0, 3, 3, 2, 6, 1, // set .a[1] 1 => "x"
+		4, 1, 2, 2, 0, 1, 6, 0, 6, 0, // move .a[0] => .x "a" => "a"
+	})
+	decoded, err := jsondiff.DecodeChanges(data)
+	c.Assert(err, IsNil)
+	c.Assert(decoded, DeepEquals, changes)
+
+	decoded, err = jsondiff.DecodeChanges([]byte{1, 'c', 0, 0})
+	c.Assert(err, IsNil)
+	c.Assert(decoded, HasLen, 0)
+}
+
+func (*S) TestEncodeValues(c *C) {
+	values := []any{
+		nil, true, false, 0.0, math.Copysign(0, -1), -3.0, 0.5, 1e300, float64(1 << 60),
+		json.Number("1.50"), "", "é", []any{}, []any{1.0, []any{}},
+		map[string]any{}, map[string]any{"a": map[string]any{"b": nil}, "b": "a"},
+	}
+	for _, value := range values {
+		c.Logf("Value: %#v", value)
+		changes := []jsondiff.Change{{Op: jsondiff.Set, Path: jsondiff.Path{}, New: value}}
+		data, err := jsondiff.EncodeChanges(changes)
+		c.Assert(err, IsNil)
+		decoded, err := jsondiff.DecodeChanges(data)
+		c.Assert(err, IsNil)
+		c.Assert(decoded, DeepEquals, changes)
+		if f, ok := value.(float64); ok {
+			c.Assert(math.Signbit(decoded[0].New.(float64)), Equals, math.Signbit(f))
+		}
+	}
+
+	// Strings are only held once.
+	key := strings.Repeat("k", 100)
+	var changes []jsondiff.Change
+	for i := 0; i < 10; i++ {
+		changes = append(changes, jsondiff.Change{Op: jsondiff.Add, Path: jsondiff.Path{key, i}, New: map[string]any{key: key}})
+	}
+	data, err := jsondiff.EncodeChanges(changes)
+	c.Assert(err, IsNil)
+	c.Assert(len(data) < 2*len(key)+10, Equals, true, Commentf("%d bytes", len(data)))
+
+	_, err = jsondiff.EncodeChanges([]jsondiff.Change{{Op: jsondiff.Add, Path: jsondiff.Path{}, New: 1}})
+	c.Assert(err, ErrorMatches, `cannot encode value of type int`)
+	_, err = jsondiff.EncodeChanges([]jsondiff.Change{{Op: jsondiff.Remove, Path: jsondiff.Path{-1}}})
+	c.Assert(err, ErrorMatches, `cannot encode path \.\[-1\]: negative index`)
+	_, err = jsondiff.EncodeChanges([]jsondiff.Change{{Op: jsondiff.Remove, Path: jsondiff.Path{1.5}}})
+	c.Assert(err, ErrorMatches, `cannot encode path element 1.5`)
+	_, err = jsondiff.EncodeChanges([]jsondiff.Change{{Op: 0}})
+	c.Assert(err, ErrorMatches, `cannot encode change with invalid operation Op\(0\)`)
+}
+
+func (*S) TestEncodePatch(c *C) {
+	patch := []jsondiff.Operation{
+		{Op: "add", Path: "/a/1", Value: map[string]any{"a": 1.0}},
+		{Op: "remove", Path: "/b"},
+		{Op: "move", Path: "/c", From: "/a/0"},
+		{Op: "copy", Path: "/d", From: "/c"},
+		{Op: "test", Path: "/d", Value: nil},
+		{Op: "replace", Path: "", Value: []any{}},
+	}
+	data, err := jsondiff.EncodePatch(patch)
+	c.Assert(err, IsNil)
+	decoded, err := jsondiff.DecodePatch(data)
+	c.Assert(err, IsNil)
+	c.Assert(decoded, DeepEquals, patch)
+
+	_, err = jsondiff.EncodePatch([]jsondiff.Operation{{Op: "merge"}})
+	c.Assert(err, ErrorMatches, `cannot encode patch with invalid operation "merge"`)
+	_, err = jsondiff.DecodeChanges(data)
+	c.Assert(err, ErrorMatches, `encoding holds a patch rather than changes`)
+}
+
+var decodeErrors = []struct {
+	data []byte
+	err  string
+}{
+	{nil, "truncated encoding"},
+	{[]byte{2, 'c'}, "unsupported encoding version 2"},
+	{[]byte{1, 'x'}, "invalid encoding kind 120"},
+	{[]byte{1, 'c'}, "truncated encoding"},
+	{[]byte{1, 'c', 1, 5, 'a'}, "truncated encoding"},
+	{[]byte{1, 'c', 0, 0, 0}, "1 bytes left after encoding"},
+	{[]byte{1, 'c', 0, 5, 1}, "truncated encoding"},
+	{[]byte{1, 'c', 0, 1, 7, 0, 0, 0}, "invalid change operation 7"},
+	{[]byte{1, 'c', 0, 1, 1, 1, 0, 0, 0}, "invalid string 0 in encoding with 0 strings"},
+	{[]byte{1, 'c', 0, 1, 1, 0, 0, 9}, "invalid value tag 9"},
+	{[]byte{1, 'c', 0, 1, 1, 0, 0, 4, 0}, "truncated encoding"},
+	{[]byte{1, 'c', 0, 1, 1, 0, 0, 6, 3}, "invalid string 3 in encoding with 0 strings"},
+	{[]byte{1, 'c', 0, 1, 1, 0, 0, 7, 0xff, 0x0f}, "truncated encoding"},
+	{[]byte{1, 'p', 0, 1, 9}, "invalid patch operation 9"},
+}
+
+func (*S) TestDecodeErrors(c *C) {
+	for _, test := range decodeErrors {
+		c.Logf("Data: %v", test.data)
+		var err error
+		if len(test.data) > 1 && test.data[1] == 'p' {
+			_, err = jsondiff.DecodePatch(test.data)
+		} else {
+			_, err = jsondiff.DecodeChanges(test.data)
+		}
+		c.Assert(err, ErrorMatches, test.err)
+	}
+}
+
+func (*S) TestEncodeRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		a := randomValue(rnd, 3)
+		b := mutate(rnd, decode(c, encode(a)), 3)
+		c.Logf("Test %d: %s => %s", i, encode(a), encode(b))
+		changes := jsondiff.Diff(a, b, nil)
+		data, err := jsondiff.EncodeChanges(changes)
+		c.Assert(err, IsNil)
+		decoded, err := jsondiff.DecodeChanges(data)
+		c.Assert(err, IsNil)
+		c.Assert(encode(decoded), Equals, encode(changes))
+		result, err := jsondiff.Apply(a, decoded)
+		c.Assert(err, IsNil)
+		c.Assert(encode(result), Equals, encode(b))
+
+		patch := jsondiff.Patch(a, b, changes)
+		data, err = jsondiff.EncodePatch(patch)
+		c.Assert(err, IsNil)
+		decodedPatch, err := jsondiff.DecodePatch(data)
+		c.Assert(err, IsNil)
+		c.Assert(encode(decodedPatch), Equals, encode(patch))
+	}
+}
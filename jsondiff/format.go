@@ -0,0 +1,249 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FormatOptions holds the options for Format.
+type FormatOptions struct {
+	// Color marks the lines added in green, those removed in red, and
+	// those moved in cyan, with ANSI escape sequences.
+	Color bool
+}
+
+const (
+	colorReset = "\x1b[0m"
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorCyan  = "\x1b[36m"
+)
+
+// Format renders the changes turning document a into document b, as
+// returned by Diff, in the style of a unified diff of the documents.
+//
+// Document b is printed with one value per line, and every line starts
+// with a mark: "+" for values added, "-" for values removed, "~" for values
+// moved, and a space for context. Values set are printed twice, removed
+// with their old value and added with their new one. Only objects and
+// arrays holding changes are expanded, and the other values around them
+// are printed as context, with objects and arrays collapsed. Values
+// removed are printed at the end of the object or array they were removed
+// from, wherever it is found in b. The options may be nil.
+func Format(a, b any, changes []Change, options *FormatOptions) string {
+	if options == nil {
+		options = &FormatOptions{}
+	}
+	f := newFormatter(changes)
+	f.options = options
+	f.value(Path{}, "", b, "", false)
+	return f.sb.String()
+}
+
+// newFormatter returns a formatter with the changes indexed by where they
+// are rendered in the new document.
+func newFormatter(changes []Change) *formatter {
+	f := &formatter{
+		marks:    make(map[string]*Change),
+		touched:  make(map[string]bool),
+		removals: make(map[string][]*Change),
+	}
+	moves := make(map[string]*Change)
+	for i := range changes {
+		if changes[i].Op == Move {
+			moves[changes[i].From.String()] = &changes[i]
+		}
+	}
+	for i := range changes {
+		change := &changes[i]
+		if change.Op != Remove {
+			f.marks[change.Path.String()] = change
+			f.touch(change.Path[:max(len(change.Path)-1, 0)])
+			continue
+		}
+		parent := location(change.Path[:len(change.Path)-1], moves)
+		f.removals[parent.String()] = append(f.removals[parent.String()], change)
+		f.touch(parent)
+	}
+	return f
+}
+
+// location returns the location in the new document of the value at path
+// in the old one, which isn't removed, given the changes moving values by
+// their origin.
+func location(path Path, moves map[string]*Change) Path {
+	if len(path) == 0 {
+		return path
+	}
+	if move := moves[path.String()]; move != nil {
+		return move.Path
+	}
+	parent := location(path[:len(path)-1], moves)
+	return append(parent[:len(parent):len(parent)], path[len(path)-1])
+}
+
+type formatter struct {
+	sb      strings.Builder
+	options *FormatOptions
+
+	// marks holds the changes adding, setting, or moving a value by the
+	// string form of their path, touched holds the paths of the values
+	// holding changes, and removals holds the changes removing a value
+	// by the path in the new document of the value it was removed from.
+	marks    map[string]*Change
+	touched  map[string]bool
+	removals map[string][]*Change
+}
+
+// touch records that the value at path and every value above it hold
+// changes.
+func (f *formatter) touch(path Path) {
+	for i := len(path); i >= 0; i-- {
+		key := path[:i].String()
+		if f.touched[key] {
+			return
+		}
+		f.touched[key] = true
+	}
+}
+
+// value renders value at path in the new document, with the given label
+// and indentation. The value is within a value added if added is set.
+func (f *formatter) value(path Path, label string, value any, indent string, added bool) {
+	key := path.String()
+	change := f.marks[key]
+	switch {
+	case (added && change == nil) || (change != nil && change.Op == Add):
+		// Values added may hold values moved from elsewhere.
+		if isContainer(value) && f.touched[key] {
+			f.children('+', colorGreen, path, label, value, indent, "", true)
+			return
+		}
+		f.whole('+', colorGreen, indent, label, value, "")
+		return
+	case change != nil && change.Op == Set:
+		f.whole('-', colorRed, indent, label, change.Old, "")
+		f.whole('+', colorGreen, indent, label, change.New, "")
+		return
+	case change != nil && change.Op == Move:
+		note := fmt.Sprintf("  (moved from %s)", change.From)
+		if !isContainer(value) || !f.touched[key] {
+			if !isContainer(value) && encodeValue(change.Old) != encodeValue(value) {
+				note = fmt.Sprintf("  (moved from %s, was %s)", change.From, encodeValue(change.Old))
+			}
+			f.whole('~', colorCyan, indent, label, value, note)
+			return
+		}
+		f.children('~', colorCyan, path, label, value, indent, note, false)
+		return
+	case f.touched[key] && isContainer(value):
+		f.children(' ', "", path, label, value, indent, "", false)
+		return
+	}
+	switch value := value.(type) {
+	case map[string]any:
+		if len(value) > 0 {
+			f.line(' ', "", indent+label+"{...}")
+			return
+		}
+	case []any:
+		if len(value) > 0 {
+			f.line(' ', "", indent+label+"[...]")
+			return
+		}
+	}
+	f.whole(' ', "", indent, label, value, "")
+}
+
+// children renders the object or array value at path in the new document
+// with each of its values on their own lines, followed by those removed
+// from it. The values are within a value added if added is set.
+func (f *formatter) children(mark byte, color string, path Path, label string, value any, indent, note string, added bool) {
+	inner := indent + "  "
+	switch value := value.(type) {
+	case map[string]any:
+		f.line(mark, color, indent+label+"{"+note)
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			f.value(append(path[:len(path):len(path)], key), encodeValue(key)+": ", value[key], inner, added)
+		}
+		f.removed(path, inner)
+		f.line(mark, color, indent+"}")
+	case []any:
+		f.line(mark, color, indent+label+"["+note)
+		for i, elem := range value {
+			f.value(append(path[:len(path):len(path)], i), "", elem, inner, added)
+		}
+		f.removed(path, inner)
+		f.line(mark, color, indent+"]")
+	}
+}
+
+// removed renders the values removed from the value at path in the new
+// document.
+func (f *formatter) removed(path Path, indent string) {
+	for _, change := range f.removals[path.String()] {
+		var label string
+		if key, ok := change.Path[len(change.Path)-1].(string); ok {
+			label = encodeValue(key) + ": "
+		}
+		f.whole('-', colorRed, indent, label, change.Old, "")
+	}
+}
+
+// whole renders value in full, with its first line labeled and followed
+// by note.
+func (f *formatter) whole(mark byte, color, indent, label string, value any, note string) {
+	data, err := json.MarshalIndent(value, indent, "  ")
+	if err != nil {
+		panic(fmt.Sprintf("jsondiff: cannot encode %#v: %v", value, err))
+	}
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if i == 0 {
+			line = indent + label + line
+		}
+		if i == len(lines)-1 {
+			line += note
+		}
+		f.line(mark, color, line)
+	}
+}
+
+func (f *formatter) line(mark byte, color, line string) {
+	if f.options.Color && color != "" {
+		f.sb.WriteString(color)
+	}
+	f.sb.WriteByte(mark)
+	f.sb.WriteByte(' ')
+	f.sb.WriteString(line)
+	if f.options.Color && color != "" {
+		f.sb.WriteString(colorReset)
+	}
+	f.sb.WriteByte('\n')
+}
+
+func encodeValue(value any) string {
+	return encodeAll([]any{value})[0]
+}
@@ -0,0 +1,88 @@
+package jsondiff_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/jsondiff"
+)
+
+var formatTests = []struct {
+	summary string
+	a, b    string
+	color   bool
+	output  string
+}{{
+	summary: "Equal documents",
+	a:       `{"a": {"b": 1}}`,
+	b:       `{"a": {"b": 1}}`,
+	output:  "  {...}\n",
+}, {
+	summary: "Documents of different types",
+	a:       `[1]`,
+	b:       `"x"`,
+	output: "" +
+		"- [\n" +
+		"-   1\n" +
+		"- ]\n" +
+		"+ \"x\"\n",
+}, {
+	summary: "Changes in context",
+	a:       `{"a": {"x": 1, "y": 2, "z": "z"}, "k": [1, 2, 3], "o": {"p": 1}, "s": "s"}`,
+	b:       `{"b": {"x": 1, "y": 3, "z": "z"}, "k": [1, 3], "o": {"p": 1}, "s": "t", "n": {"q": [1]}}`,
+	output: "" +
+		"  {\n" +
+		"~   \"b\": {  (moved from .a)\n" +
+		"+     \"x\": 1\n" +
+		"~     \"y\": 3  (moved from .k[2])\n" +
+		"      \"z\": \"z\"\n" +
+		"-     \"y\": 2\n" +
+		"~   }\n" +
+		"    \"k\": [\n" +
+		"      1\n" +
+		"-     2\n" +
+		"+     3\n" +
+		"    ]\n" +
+		"+   \"n\": {\n" +
+		"+     \"q\": [\n" +
+		"~       1  (moved from .a.x)\n" +
+		"+     ]\n" +
+		"+   }\n" +
+		"    \"o\": {...}\n" +
+		"-   \"s\": \"s\"\n" +
+		"+   \"s\": \"t\"\n" +
+		"  }\n",
+}, {
+	summary: "Moved value changed",
+	a:       `{"a": {"x": 1, "y": 2}, "b": {}}`,
+	b:       `{"a": {"x": 1}, "b": {"y": 3}}`,
+	output: "" +
+		"  {\n" +
+		"    \"a\": {...}\n" +
+		"    \"b\": {\n" +
+		"~     \"y\": 3  (moved from .a.y, was 2)\n" +
+		"    }\n" +
+		"  }\n",
+}, {
+	summary: "Colors",
+	a:       `{"a": 1, "b": [2], "c": 3}`,
+	b:       `{"a": 1, "b": [3], "d": 3}`,
+	color:   true,
+	output: "" +
+		"  {\n" +
+		"    \"a\": 1\n" +
+		"    \"b\": [\n" +
+		"\x1b[31m-     2\x1b[0m\n" +
+		"\x1b[32m+     3\x1b[0m\n" +
+		"    ]\n" +
+		"\x1b[36m~   \"d\": 3  (moved from .c)\x1b[0m\n" +
+		"  }\n",
+}}
+
+func (*S) TestFormat(c *C) {
+	for _, test := range formatTests {
+		c.Logf("Summary: %s", test.summary)
+		a, b := decode(c, test.a), decode(c, test.b)
+		output := jsondiff.Format(a, b, jsondiff.Diff(a, b, nil), &jsondiff.FormatOptions{Color: test.color})
+		c.Assert(output, Equals, test.output)
+	}
+}
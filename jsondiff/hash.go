@@ -0,0 +1,175 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondiff
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"math"
+
+	"github.com/canonical/go-algo/assign"
+)
+
+// digest is a Merkle-style hash of a value and the values under it.
+type digest [sha256.Size]byte
+
+// hasher computes the hash of a value from the hashes of its members or
+// elements, or from its encoding for scalars.
+type hasher struct {
+	h hash.Hash
+}
+
+func newHasher(data any) *hasher {
+	h := &hasher{h: sha256.New()}
+	switch data.(type) {
+	case map[string]any:
+		h.h.Write([]byte("{"))
+	case []any:
+		h.h.Write([]byte("["))
+	default:
+		// The type tells apart scalars with the same encoding, such as
+		// json.Number and float64 values, which aren't equal unless
+		// normalized into the same form first.
+		fmt.Fprintf(h.h, "%T:%s", data, encodeValue(data))
+	}
+	return h
+}
+
+// member adds to the hash of an object its member n, under key.
+func (h *hasher) member(key string, n *node) {
+	fmt.Fprintf(h.h, "%q:", key)
+	h.h.Write(n.hash[:])
+}
+
+// elem adds to the hash of an array its element n.
+func (h *hasher) elem(n *node) {
+	h.h.Write(n.hash[:])
+}
+
+func (h *hasher) sum() (sum digest) {
+	h.h.Sum(sum[:0])
+	return sum
+}
+
+// prune pairs values that are equal in both documents without going
+// through the assignment, and leaves them out of it along with every value
+// under them, which stay where they are. Equal values found at the same
+// path are paired first. Then, among the objects and arrays left that
+// don't hold values already paired, those that are the only ones with
+// their hash in both documents are paired with each other, as they moved.
+//
+// The sources and targets left are returned, along with the pairs found.
+func (d *differ) prune(sources, targets []any) (restSources, restTargets []any, pairs []assign.Pair) {
+	sourcePruned := make([]bool, len(sources))
+	targetPruned := make([]bool, len(targets))
+	pair := func(s, t *node) {
+		pairs = append(pairs, assign.Pair{Source: s, Target: t, Cost: assign.IntCost(0)})
+		for i := s.index; i < s.index+s.size; i++ {
+			sourcePruned[i] = true
+		}
+		for i := t.index; i < t.index+t.size; i++ {
+			targetPruned[i] = true
+		}
+	}
+
+	byKey := make(map[string]*node, len(targets))
+	for _, target := range targets {
+		byKey[target.(*node).key] = target.(*node)
+	}
+	for i := 1; i < len(sources); {
+		s := sources[i].(*node)
+		if t := byKey[s.key]; t != nil && t.hash == s.hash && compatible(s, t) {
+			pair(s, t)
+			i += s.size
+			continue
+		}
+		i++
+	}
+
+	// Values holding values paired already can't be paired as a whole.
+	sourceHeld := holding(sources, sourcePruned)
+	targetHeld := holding(targets, targetPruned)
+	sourceCount := make(map[digest]int)
+	targetCount := make(map[digest]int)
+	byHash := make(map[digest]*node)
+	for i, source := range sources {
+		if s := source.(*node); !sourcePruned[i] && !sourceHeld[i] && s.size > 1 {
+			sourceCount[s.hash]++
+		}
+	}
+	for i, target := range targets {
+		if t := target.(*node); !targetPruned[i] && !targetHeld[i] && t.size > 1 {
+			targetCount[t.hash]++
+			byHash[t.hash] = t
+		}
+	}
+	for i := 1; i < len(sources); {
+		s := sources[i].(*node)
+		if !sourcePruned[i] && !sourceHeld[i] && s.size > 1 && sourceCount[s.hash] == 1 && targetCount[s.hash] == 1 {
+			if t := byHash[s.hash]; !targetPruned[t.index] && t.index > 0 && compatible(s, t) && d.nearDepth(s, t) {
+				pair(s, t)
+				i += s.size
+				continue
+			}
+		}
+		i++
+	}
+
+	for i, source := range sources {
+		if !sourcePruned[i] {
+			restSources = append(restSources, source)
+		}
+	}
+	for i, target := range targets {
+		if !targetPruned[i] {
+			restTargets = append(restTargets, target)
+		}
+	}
+	return restSources, restTargets, pairs
+}
+
+// holding returns which of nodes hold a node that is pruned.
+func holding(nodes []any, pruned []bool) []bool {
+	held := make([]bool, len(nodes))
+	// Nodes are in preorder, so the nodes holding a node are those before
+	// it whose range covers it.
+	var stack []*node
+	for i, elem := range nodes {
+		n := elem.(*node)
+		for len(stack) > 0 && stack[len(stack)-1].index+stack[len(stack)-1].size <= i {
+			stack = stack[:len(stack)-1]
+		}
+		if pruned[i] {
+			for _, above := range stack {
+				if above.index != i {
+					held[above.index] = true
+				}
+			}
+		}
+		stack = append(stack, n)
+	}
+	return held
+}
+
+// pairOrder returns the position of the pair in the changes, which follow
+// the order of the targets, with deletions last.
+func pairOrder(pair assign.Pair) int {
+	if pair.Target == nil {
+		return math.MaxInt
+	}
+	return pair.Target.(*node).index
+}
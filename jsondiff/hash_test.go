@@ -0,0 +1,93 @@
+package jsondiff_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/jsondiff"
+)
+
+var pruneTests = []struct {
+	summary string
+	a, b    string
+	changes []string
+}{{
+	summary: "Equal subtree moved as a whole",
+	a:       `{"a": {"x": [1, 2, {"y": 3}], "z": "z"}, "b": 1}`,
+	b:       `{"c": {"x": [1, 2, {"y": 3}], "z": "z"}, "b": 1}`,
+	changes: []string{`move .a => .c {"x":[1,2,{"y":3}],"z":"z"} => {"x":[1,2,{"y":3}],"z":"z"}`},
+}, {
+	summary: "Equal subtrees swapped",
+	a:       `[{"id": 1, "tags": ["a", "b"]}, {"id": 2, "tags": ["c"]}]`,
+	b:       `[{"id": 2, "tags": ["c"]}, {"id": 1, "tags": ["a", "b"]}]`,
+	changes: []string{
+		`move .[1] => .[0] {"id":2,"tags":["c"]} => {"id":2,"tags":["c"]}`,
+		`move .[0] => .[1] {"id":1,"tags":["a","b"]} => {"id":1,"tags":["a","b"]}`,
+	},
+}, {
+	summary: "Duplicated subtrees",
+	a:       `{"a": {"x": 1}, "b": {"x": 1}}`,
+	b:       `{"a": {"x": 1}, "c": {"x": 1}}`,
+	changes: []string{`move .b => .c {"x":1} => {"x":1}`},
+}, {
+	summary: "Change next to equal subtrees",
+	a:       `{"a": {"x": [1, 2]}, "b": {"y": [3, 4]}, "c": 1}`,
+	b:       `{"a": {"x": [1, 2]}, "b": {"y": [3, 4]}, "c": 2}`,
+	changes: []string{`set .c 1 => 2`},
+}}
+
+func (*S) TestDiffPrune(c *C) {
+	for _, test := range pruneTests {
+		c.Logf("Summary: %s", test.summary)
+		a, b := decode(c, test.a), decode(c, test.b)
+		diff := jsondiff.Diff(a, b, nil)
+		var changes []string
+		for _, change := range diff {
+			changes = append(changes, changeString(change))
+		}
+		c.Assert(changes, DeepEquals, test.changes)
+
+		result, err := jsondiff.Apply(a, diff)
+		c.Assert(err, IsNil)
+		c.Assert(encode(result), Equals, encode(b))
+	}
+}
+
+func (*S) TestDiffPruneNumbers(c *C) {
+	// Numbers decoded as json.Number aren't equal to float64 values, so
+	// their hashes must differ.
+	dec := json.NewDecoder(strings.NewReader(`{"a": [1, 2]}`))
+	dec.UseNumber()
+	var a any
+	c.Assert(dec.Decode(&a), IsNil)
+	b := decode(c, `{"a": [1, 2]}`)
+	var changes []string
+	for _, change := range jsondiff.Diff(a, b, nil) {
+		changes = append(changes, changeString(change))
+	}
+	c.Assert(changes, DeepEquals, []string{`add .a[0] 1`, `add .a[1] 2`, `remove .a[0] 1`, `remove .a[1] 2`})
+}
+
+func (*S) TestDiffPruneLarge(c *C) {
+	// A small change in a large document is found without comparing every
+	// pair of values in it.
+	var sb strings.Builder
+	sb.WriteString("[")
+	for i := 0; i < 2000; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `{"id": %d, "name": "item%d", "tags": ["a", "b"]}`, i, i)
+	}
+	sb.WriteString("]")
+	a := decode(c, sb.String())
+	b := decode(c, strings.Replace(sb.String(), `"item1000"`, `"changed"`, 1))
+	var changes []string
+	for _, change := range jsondiff.Diff(a, b, nil) {
+		changes = append(changes, changeString(change))
+	}
+	c.Assert(changes, DeepEquals, []string{`set .[1000].name "item1000" => "changed"`})
+}
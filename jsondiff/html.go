@@ -0,0 +1,239 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// HTMLOptions holds the options for FormatHTML.
+type HTMLOptions struct {
+	// Title is the title of the page. It defaults to "JSON diff".
+	Title string
+
+	// AName and BName head the columns of documents a and b. They
+	// default to "a" and "b".
+	AName, BName string
+}
+
+// FormatHTML renders the changes turning document a into document b, as
+// returned by Diff, as a self-contained HTML page showing the documents
+// side by side, with no scripts or external resources.
+//
+// The values are laid out as in document b, as done by Format, with the
+// value each of them had in a on the left and the one it has in b on the
+// right. Values added, removed, set, and moved are highlighted, and values
+// removed are shown at the end of the object or array they were removed
+// from. Objects and arrays holding changes are expanded, and the others
+// are collapsed, but may be expanded as well. The options may be nil.
+func FormatHTML(a, b any, changes []Change, options *HTMLOptions) string {
+	if options == nil {
+		options = &HTMLOptions{}
+	}
+	title := options.Title
+	if title == "" {
+		title = "JSON diff"
+	}
+	aName, bName := options.AName, options.BName
+	if aName == "" {
+		aName = "a"
+	}
+	if bName == "" {
+		bName = "b"
+	}
+
+	h := &htmlFormatter{formatter: newFormatter(changes)}
+	fmt.Fprintf(&h.sb, htmlHeader, html.EscapeString(title))
+	fmt.Fprintf(&h.sb, "<h1>%s</h1>\n", html.EscapeString(title))
+	fmt.Fprintf(&h.sb, "<div class=\"row head\"><div class=\"cell\">%s</div><div class=\"cell\">%s</div></div>\n",
+		html.EscapeString(aName), html.EscapeString(bName))
+	h.value(Path{}, "", b, 0, false)
+	h.sb.WriteString("</body>\n</html>\n")
+	return h.sb.String()
+}
+
+const htmlHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>%s</title>
+<style>
+body { font-family: sans-serif; margin: 1em; }
+.row { display: grid; grid-template-columns: 1fr 1fr; }
+.head { font-weight: bold; border-bottom: 1px solid #999; }
+.cell { padding: 0 0.5em; overflow-x: auto; border-right: 1px solid #ddd; }
+.cell pre { margin: 0; font-family: monospace; white-space: pre-wrap; }
+.add { background: #dfd; }
+.del { background: #fdd; }
+.move { background: #dff; }
+.note { color: #666; font-style: italic; }
+summary { list-style: none; cursor: pointer; }
+summary::-webkit-details-marker { display: none; }
+details > summary .cell:last-child pre::before { content: "\25be  "; color: #999; }
+details:not([open]) > summary .cell:last-child pre::before { content: "\25b8  "; }
+details:not([open]) > summary .cell pre::after { content: " \2026"; color: #999; }
+</style>
+</head>
+<body>
+`
+
+type htmlFormatter struct {
+	*formatter
+	sb strings.Builder
+}
+
+// value renders value at path in the new document, with the given label
+// and depth. The value is within a value added if added is set.
+func (h *htmlFormatter) value(path Path, label string, value any, depth int, added bool) {
+	key := path.String()
+	change := h.marks[key]
+	switch {
+	case (added && change == nil) || (change != nil && change.Op == Add):
+		// Values added may hold values moved from elsewhere.
+		if isContainer(value) && h.touched[key] {
+			h.children("", "add", path, "", label, value, depth, "", true)
+			return
+		}
+		h.row("", "", "add", whole(label, value), depth, "")
+		return
+	case change != nil && change.Op == Set:
+		h.row("del", whole(label, change.Old), "add", whole(label, change.New), depth, "")
+		return
+	case change != nil && change.Op == Move:
+		note := fmt.Sprintf("moved from %s", change.From)
+		aLabel := pathLabel(change.From)
+		if !isContainer(value) || !h.touched[key] {
+			h.row("move", whole(aLabel, change.Old), "move", whole(label, value), depth, note)
+			return
+		}
+		h.children("move", "move", path, aLabel, label, value, depth, note, false)
+		return
+	case isContainer(value) && !isEmpty(value):
+		h.children("", "", path, label, label, value, depth, "", false)
+		return
+	}
+	text := whole(label, value)
+	h.row("", text, "", text, depth, "")
+}
+
+// children renders the object or array value at path in the new document
+// with each of its values on their own rows, followed by those removed
+// from it, within an element that is expanded if it holds changes. The
+// value is labeled as aLabel on the left and label on the right. The
+// values are within a value added if added is set, and aren't shown as
+// present in the old document.
+func (h *htmlFormatter) children(aClass, bClass string, path Path, aLabel, label string, value any, depth int, note string, added bool) {
+	opening, closing := "{", "}"
+	if _, ok := value.([]any); ok {
+		opening, closing = "[", "]"
+	}
+	aOpening, aClosing := aLabel+opening, closing
+	if added {
+		aOpening, aClosing = "", ""
+	}
+	if h.touched[path.String()] {
+		h.sb.WriteString("<details open>\n")
+	} else {
+		h.sb.WriteString("<details>\n")
+	}
+	h.sb.WriteString("<summary>")
+	h.row(aClass, aOpening, bClass, label+opening, depth, note)
+	h.sb.WriteString("</summary>\n")
+	switch value := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			h.value(append(path[:len(path):len(path)], key), encodeValue(key)+": ", value[key], depth+1, added)
+		}
+	case []any:
+		for i, elem := range value {
+			h.value(append(path[:len(path):len(path)], i), "", elem, depth+1, added)
+		}
+	}
+	for _, change := range h.removals[path.String()] {
+		h.row("del", whole(pathLabel(change.Path), change.Old), "", "", depth+1, "")
+	}
+	h.row(aClass, aClosing, bClass, closing, depth, "")
+	h.sb.WriteString("</details>\n")
+}
+
+// pathLabel returns the label of the value at path, which is its key if
+// it's an object member, and nothing otherwise.
+func pathLabel(path Path) string {
+	if len(path) > 0 {
+		if key, ok := path[len(path)-1].(string); ok {
+			return encodeValue(key) + ": "
+		}
+	}
+	return ""
+}
+
+func isEmpty(value any) bool {
+	switch value := value.(type) {
+	case map[string]any:
+		return len(value) == 0
+	case []any:
+		return len(value) == 0
+	}
+	return false
+}
+
+// row renders a row with the text of a value in the old document on the
+// left and in the new document on the right, either of which may be
+// empty, indented by depth and followed on the right by note.
+func (h *htmlFormatter) row(aClass, aText, bClass, bText string, depth int, note string) {
+	h.sb.WriteString(`<div class="row">`)
+	h.cell(aClass, aText, depth, "")
+	h.cell(bClass, bText, depth, note)
+	h.sb.WriteString("</div>\n")
+}
+
+func (h *htmlFormatter) cell(class, text string, depth int, note string) {
+	if text == "" {
+		h.sb.WriteString(`<div class="cell"></div>`)
+		return
+	}
+	if class != "" {
+		class = " " + class
+	}
+	fmt.Fprintf(&h.sb, `<div class="cell%s" style="padding-left: %.1fem"><pre>%s`, class, 0.5+1.5*float64(depth), html.EscapeString(text))
+	if note != "" {
+		fmt.Fprintf(&h.sb, `  <span class="note">(%s)</span>`, html.EscapeString(note))
+	}
+	h.sb.WriteString("</pre></div>")
+}
+
+// whole returns value encoded in full, preceded by label. Characters
+// special to HTML are left as they are, to be escaped with the rest of
+// the text.
+func whole(label string, value any) string {
+	var sb strings.Builder
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(value); err != nil {
+		panic(fmt.Sprintf("jsondiff: cannot encode %#v: %v", value, err))
+	}
+	return label + strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,45 @@
+package jsondiff_test
+
+import (
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/jsondiff"
+)
+
+func (*S) TestFormatHTML(c *C) {
+	a := decode(c, `{"name": "web", "ports": [80, 443], "env": {"A": "1", "B": "2"}, "old": true, "meta": {"x": 1}}`)
+	b := decode(c, `{"name": "<web>", "ports": [80, 443, 8080], "env": {"A": "1", "B": "2"}, "meta": {"x": 1}, "new": {"y": []}}`)
+	changes := jsondiff.Diff(a, b, nil)
+	page := jsondiff.FormatHTML(a, b, changes, &jsondiff.HTMLOptions{AName: "old.json", BName: "new.json"})
+
+	c.Assert(strings.HasPrefix(page, "<!DOCTYPE html>\n"), Equals, true)
+	c.Assert(page, Matches, `(?s).*<title>JSON diff</title>.*`)
+	c.Assert(page, Matches, `(?s).*<div class="cell">old.json</div><div class="cell">new.json</div>.*`)
+
+	// Values set are shown with their old value on the left and the new
+	// one on the right, escaped.
+	c.Assert(page, Matches, `(?s).*<div class="cell del" [^>]*><pre>&#34;name&#34;: &#34;web&#34;</pre></div><div class="cell add" [^>]*><pre>&#34;name&#34;: &#34;&lt;web&gt;&#34;</pre></div>.*`)
+	// Values added are only on the right, and those removed on the left.
+	c.Assert(page, Matches, `(?s).*<div class="cell"></div><div class="cell add" [^>]*><pre>8080</pre></div>.*`)
+	c.Assert(page, Matches, `(?s).*<div class="cell del" [^>]*><pre>&#34;old&#34;: true</pre></div><div class="cell"></div>.*`)
+	c.Assert(page, Matches, `(?s).*<div class="cell add" [^>]*><pre>&#34;new&#34;: \{\n  &#34;y&#34;: \[\]\n\}</pre></div>.*`)
+
+	// Only the objects and arrays holding changes are expanded.
+	c.Assert(strings.Count(page, "<details open>"), Equals, 2)
+	c.Assert(strings.Count(page, "<details>"), Equals, 2)
+	c.Assert(strings.Count(page, "</details>"), Equals, 4)
+	c.Assert(strings.Count(page, "<div"), Equals, strings.Count(page, "</div>"))
+	c.Assert(strings.HasSuffix(page, "</body>\n</html>\n"), Equals, true)
+}
+
+func (*S) TestFormatHTMLMove(c *C) {
+	a := decode(c, `{"a": {"x": 1, "y": 2}}`)
+	b := decode(c, `{"b": {"x": 1, "y": 3}}`)
+	changes := jsondiff.Diff(a, b, nil)
+	page := jsondiff.FormatHTML(a, b, changes, &jsondiff.HTMLOptions{Title: "Move & set"})
+	c.Assert(page, Matches, `(?s).*<title>Move &amp; set</title>.*`)
+	c.Assert(page, Matches, `(?s).*<div class="cell move" [^>]*><pre>&#34;a&#34;: \{</pre></div><div class="cell move" [^>]*><pre>&#34;b&#34;: \{  <span class="note">\(moved from .a\)</span></pre></div>.*`)
+	c.Assert(page, Matches, `(?s).*<div class="cell del" [^>]*><pre>&#34;y&#34;: 2</pre></div><div class="cell add" [^>]*><pre>&#34;y&#34;: 3</pre></div>.*`)
+}
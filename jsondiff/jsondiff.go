@@ -0,0 +1,896 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsondiff computes the changes between JSON documents, as decoded
+// by encoding/json into maps, slices, and scalar values.
+//
+// Every value in both documents is a node, and nodes are paired across the
+// documents with the assign package, so values that moved elsewhere in the
+// document are reported as moves rather than as removals and additions.
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/go-algo/assign"
+	"github.com/canonical/go-algo/listdist"
+)
+
+// Op is the kind of a Change.
+type Op int
+
+const (
+	// Add adds New at Path.
+	Add Op = iota + 1
+	// Remove removes Old from Path.
+	Remove
+	// Set replaces Old with New at Path.
+	Set
+	// Move moves Old from From to Path, where it is found as New. Moved
+	// scalars may have changed on the way, in which case Old and New
+	// differ.
+	Move
+)
+
+var opNames = []string{"", "add", "remove", "set", "move"}
+
+func (op Op) String() string {
+	if op > 0 && int(op) < len(opNames) {
+		return opNames[op]
+	}
+	return fmt.Sprintf("Op(%d)", int(op))
+}
+
+// Path locates a value in a document, with a string for every object key
+// and an int for every array index on the way from the root.
+type Path []any
+
+// String returns the path in the form ".key[index].key", or "." for the
+// root of the document.
+func (p Path) String() string {
+	if len(p) == 0 {
+		return "."
+	}
+	var sb strings.Builder
+	for i, elem := range p {
+		switch elem := elem.(type) {
+		case string:
+			sb.WriteString("." + elem)
+		case int:
+			if i == 0 {
+				sb.WriteString(".")
+			}
+			fmt.Fprintf(&sb, "[%d]", elem)
+		default:
+			panic(fmt.Sprintf("jsondiff: invalid path element %#v", elem))
+		}
+	}
+	return sb.String()
+}
+
+// ParsePath parses a path in the form returned by Path.String.
+func ParsePath(text string) (Path, error) {
+	if !strings.HasPrefix(text, ".") {
+		return nil, fmt.Errorf("invalid path %q: must start with .", text)
+	}
+	path := Path{}
+	rest := text
+	if rest == "." {
+		rest = ""
+	} else if strings.HasPrefix(rest, ".[") {
+		rest = rest[1:]
+	}
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[") + 1
+			if end == 0 {
+				end = len(rest)
+			}
+			if end == 1 {
+				return nil, fmt.Errorf("invalid path %q: empty key", text)
+			}
+			path = append(path, rest[1:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: missing ]", text)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid path %q: invalid index %q", text, rest[1:end])
+			}
+			path = append(path, index)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid path %q: unexpected %q", text, rest[0])
+		}
+	}
+	return path, nil
+}
+
+// Change is a change turning one document into another.
+type Change struct {
+	Op Op
+
+	// Path is the location of the value changed in the new document, or
+	// in the old document for removals.
+	Path Path
+
+	// From is the location in the old document of a value moved.
+	From Path
+
+	// Old and New are the value before and after the change.
+	Old any
+	New any
+}
+
+// Options holds the options for Diff.
+type Options struct {
+	// ArrayKeys maps the path of arrays of objects, in the form returned
+	// by Path.String, to the name of the member identifying their
+	// elements. Elements holding the same identifier in both documents
+	// are paired wherever they are in the array, so their members are
+	// compared with each other, and elements with different identifiers
+	// are never paired. Elements missing the member, or sharing their
+	// identifier with another element of the same array, are compared by
+	// position as usual.
+	ArrayKeys map[string]string
+
+	// Ignore holds patterns matching the paths of values left out of the
+	// comparison, in either document, along with the values under them.
+	// Changes aren't reported for values ignored, so applying the changes
+	// leaves those of a in place.
+	Ignore []*Pattern
+
+	// Tolerance is the largest difference between numbers that are
+	// considered equal.
+	Tolerance float64
+
+	// RelativeTolerance is the largest difference between numbers that
+	// are considered equal, as a fraction of the larger of their absolute
+	// values. Numbers within either tolerance are equal.
+	RelativeTolerance float64
+
+	// NumericEqual compares numbers by their value rather than by their
+	// representation, so that json.Number values such as 1 and 1.0 are
+	// equal, and so are json.Number and float64 values of the same number.
+	// DiffStream decodes numbers as json.Number values with this option,
+	// so that large numbers are compared exactly.
+	NumericEqual bool
+
+	// IgnoreCase compares strings regardless of the case of their letters.
+	IgnoreCase bool
+
+	// TrimSpace compares strings without their leading and trailing white
+	// space.
+	TrimSpace bool
+
+	// NullAbsent considers object members holding null the same as missing
+	// members, so that they are only added or removed when the member holds
+	// another value in the other document.
+	NullAbsent bool
+
+	// IgnoreOrder compares arrays regardless of the order of their
+	// elements. The elements of the arrays of b are first reordered to
+	// follow the order of the elements of a they are equal to, or else of
+	// those at the same place among the elements that aren't equal to any,
+	// and the changes are those turning a into b as reordered.
+	IgnoreOrder bool
+
+	// MaxDepth is the depth below which values aren't compared on their
+	// own, which is one for the members or elements of the root, two for
+	// those under them, and so on. Objects and arrays found at that depth
+	// are compared as a whole, exactly but for the values ignored and the
+	// normalization of numbers and strings requested, and are set if they
+	// differ in any way. Zero compares values at any depth.
+	MaxDepth int
+
+	// NoMoves reports values found elsewhere in b as removed from their
+	// place in a and added at their place in b, rather than as moved, so
+	// changes to the values under them aren't reported on their own.
+	NoMoves bool
+
+	// MoveSimilarity is the lowest similarity between a value and the one
+	// it moved to, from 0 to 1, for the move to be reported, as done for
+	// renames by git diff -M. Less similar values are removed and added
+	// instead, as with NoMoves. The similarity is twice the number of
+	// values under both that are paired without changes, counting the
+	// values themselves, over the total number of values under both.
+	// Values moved without changes are always reported as moved, and
+	// scalars moved with changes never are if this is set.
+	MoveSimilarity float64
+
+	// SameDepth only pairs values found at the same depth in both
+	// documents, so that values aren't reported as moved across levels of
+	// the documents, such as from an object member into an array nested
+	// several levels below it. Values found at another depth are removed
+	// and added instead.
+	SameDepth bool
+
+	// MaxDepthChange is the largest difference in depth between values
+	// paired, as with SameDepth but allowing values to move up or down a
+	// few levels. Zero allows any difference.
+	MaxDepthChange int
+}
+
+// Diff returns the changes turning document a into document b. Values
+// under an added or removed value aren't reported separately, and neither
+// are values that stay in the same place within a moved value, unless
+// they changed.
+//
+// Values equal in both documents are found by their hashes and paired
+// before the rest, so that documents with few changes are compared in time
+// proportional to their size rather than its square.
+//
+// The changes are ordered by the position of the value in b, followed by
+// removals in the order of their position in a. The options may be nil.
+func Diff(a, b any, options *Options) []Change {
+	if options == nil {
+		options = &Options{}
+	}
+	d := &differ{options: options}
+	return d.diff(a, b, Path{})
+}
+
+// DiffAt is like Diff but only compares the values at path in a and b,
+// leaving alone the rest of the documents. The changes are reported with
+// paths from the root of the documents, which the options refer to as
+// well. The value is added or removed as a whole if it is only found in
+// one of the documents, and DiffAt returns an error if it is found in
+// neither.
+func DiffAt(a, b any, path Path, options *Options) ([]Change, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	va, errA := lookup(a, path)
+	vb, errB := lookup(b, path)
+	switch {
+	case errA != nil && errB != nil:
+		return nil, errA
+	case errA != nil:
+		return []Change{{Op: Add, Path: path, New: vb}}, nil
+	case errB != nil:
+		return []Change{{Op: Remove, Path: path, Old: va}}, nil
+	}
+	d := &differ{options: options}
+	return d.diff(va, vb, path), nil
+}
+
+// diff returns the changes turning a into b, which are found at path in
+// the documents compared.
+func (d *differ) diff(a, b any, path Path) []Change {
+	if d.ignored(path) {
+		return nil
+	}
+	if d.options.IgnoreOrder {
+		b = reorder(a, b)
+	}
+	if d.options.NullAbsent && len(path) > 0 && (a == nil) != (b == nil) {
+		if _, member := path[len(path)-1].(string); member {
+			if a == nil {
+				return []Change{{Op: Add, Path: path, New: b}}
+			}
+			return []Change{{Op: Remove, Path: path, Old: a}}
+		}
+	}
+	if !isContainer(a) || !isContainer(b) || reflect.TypeOf(a) != reflect.TypeOf(b) {
+		if d.equal(a, b) {
+			return nil
+		}
+		return []Change{{Op: Set, Path: path, Old: a, New: b}}
+	}
+
+	sources := d.flatten(nil, a, path, "", "", "")
+	targets := d.flatten(nil, b, path, "", "", "")
+	if sources[0].(*node).hash == targets[0].(*node).hash {
+		return nil
+	}
+	if sources[0].(*node).whole {
+		return []Change{{Op: Set, Path: path, Old: a, New: b}}
+	}
+	nodes := sources
+	sources, targets, pairs := d.prune(sources, targets)
+	pruned := len(pairs)
+	pairs = append(pairs, assign.Assign(sources, targets, &assign.AssignOptions{
+		Algorithm: assign.Rectangular,
+		EditCost:  d.editCost,
+	})...)
+	m := d.newMoves(nodes, pairs, pruned)
+	sort.SliceStable(pairs, func(i, j int) bool { return pairOrder(pairs[i]) < pairOrder(pairs[j]) })
+
+	// Paths are unique within each document, so they identify the nodes.
+	paired := make(map[string]string)
+	removed := make(map[string]bool)
+	added := make(map[string]bool)
+	for _, pair := range pairs {
+		switch {
+		case pair.Target == nil:
+			removed[pair.Source.(*node).key] = true
+		case pair.Source == nil:
+			added[pair.Target.(*node).key] = true
+		default:
+			paired[pair.Source.(*node).key] = pair.Target.(*node).key
+		}
+	}
+	split := m.split(pairs, paired, removed, added)
+
+	var changes, removals []Change
+	for _, pair := range pairs {
+		switch {
+		case pair.Target == nil:
+			source := pair.Source.(*node)
+			if !removed[source.parent] {
+				removals = append(removals, Change{Op: Remove, Path: source.path, Old: source.data})
+			}
+		case pair.Source == nil:
+			target := pair.Target.(*node)
+			if !added[target.parent] {
+				changes = append(changes, Change{Op: Add, Path: target.path, New: target.data})
+			}
+		default:
+			source, target := pair.Source.(*node), pair.Target.(*node)
+			if source.ident == "" {
+				// The roots are always paired.
+				continue
+			}
+			// Moves take along the values under them from where they were,
+			// so objects and arrays compared as a whole that changed and
+			// moved are removed and added instead, as the values under them
+			// that changed aren't reported on their own.
+			if split[source] || source.whole && source.hash != target.hash && !stays(source, target, paired) {
+				if !removed[source.parent] {
+					removals = append(removals, Change{Op: Remove, Path: source.path, Old: source.data})
+				}
+				if !added[target.parent] {
+					changes = append(changes, Change{Op: Add, Path: target.path, New: target.data})
+				}
+				continue
+			}
+			switch {
+			case !stays(source, target, paired):
+				changes = append(changes, Change{Op: Move, Path: target.path, From: source.path, Old: source.data, New: target.data})
+			case !isContainer(source.data) && !d.equal(source.data, target.data),
+				source.whole && source.hash != target.hash:
+				changes = append(changes, Change{Op: Set, Path: target.path, Old: source.data, New: target.data})
+			}
+		}
+	}
+	return append(changes, removals...)
+}
+
+// stays reports whether source is at the same place in the new document,
+// under the value its parent is paired with, and with the same key or index.
+func stays(source, target *node, paired map[string]string) bool {
+	return paired[source.parent] == target.parent && source.path[len(source.path)-1] == target.path[len(target.path)-1]
+}
+
+// node is a value in a document, at the given path.
+type node struct {
+	path Path
+	data any
+
+	// key and parent are the string forms of path and of the path of
+	// the parent value.
+	key    string
+	parent string
+
+	// ident is the identity of the value within the document, which is
+	// the string form of its path, except that array elements with an
+	// identifier are found by identifier rather than by index. The last
+	// step of ident is in step, and id holds the encoded identifier of
+	// array elements that have one. The identity of the closest of those
+	// elements holding the value, including itself, is in scope.
+	ident string
+	step  string
+	id    string
+	scope string
+
+	// skip holds the keys of the object members that are ignored, or
+	// that hold null with the NullAbsent option.
+	skip map[string]bool
+
+	// whole is set for objects and arrays at the maximum depth, which are
+	// compared as a whole, and whose values aren't in the list of nodes.
+	whole bool
+
+	// index is the position of the node in the list of nodes of its
+	// document, which are in preorder, and size is the number of nodes
+	// from there that are the value itself and the values under it.
+	index int
+	size  int
+
+	// hash identifies the value and every value under it, so that values
+	// with the same hash are equal.
+	hash digest
+}
+
+type differ struct {
+	options *Options
+}
+
+// ignored reports whether the value at path is left out of the comparison.
+func (d *differ) ignored(path Path) bool {
+	for _, pattern := range d.options.Ignore {
+		if pattern.Match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal reports whether the scalars x and y are considered equal.
+func (d *differ) equal(x, y any) bool {
+	if d.options.Tolerance > 0 || d.options.RelativeTolerance > 0 {
+		fx, xok := number(x)
+		fy, yok := number(y)
+		if xok && yok {
+			diff := math.Abs(fx - fy)
+			return diff <= d.options.Tolerance || diff <= d.options.RelativeTolerance*math.Max(math.Abs(fx), math.Abs(fy))
+		}
+	}
+	return reflect.DeepEqual(d.normalize(x), d.normalize(y))
+}
+
+// sameType reports whether x and y are values of the same type, taking
+// numbers of either type as the same when they're compared by value.
+func (d *differ) sameType(x, y any) bool {
+	if reflect.TypeOf(x) == reflect.TypeOf(y) {
+		return true
+	}
+	if d.options.NumericEqual || d.options.Tolerance > 0 || d.options.RelativeTolerance > 0 {
+		_, xok := number(x)
+		_, yok := number(y)
+		return xok && yok
+	}
+	return false
+}
+
+// normalValue is the normalized form of a number compared by value.
+type normalValue string
+
+// normalize returns the scalar value in the form it is compared and hashed
+// in, with the normalization of numbers and strings requested in the
+// options, so that values are equal when their normalized forms are.
+func (d *differ) normalize(value any) any {
+	switch value := value.(type) {
+	case string:
+		if d.options.TrimSpace {
+			value = strings.TrimSpace(value)
+		}
+		if d.options.IgnoreCase {
+			value = strings.ToLower(value)
+		}
+		return value
+	case float64:
+		if d.options.NumericEqual && !math.IsInf(value, 0) && !math.IsNaN(value) {
+			// The shortest decimal form of a float64 value is the
+			// number it was most likely decoded from.
+			return d.normalize(json.Number(strconv.FormatFloat(value, 'g', -1, 64)))
+		}
+	case json.Number:
+		if d.options.NumericEqual {
+			f, _, err := big.ParseFloat(string(value), 10, 256, big.ToNearestEven)
+			if err == nil {
+				if f.Sign() == 0 {
+					return normalValue("0")
+				}
+				return normalValue(f.Text('p', 0))
+			}
+		}
+	}
+	return value
+}
+
+func number(value any) (float64, bool) {
+	switch value := value.(type) {
+	case float64:
+		return value, true
+	case json.Number:
+		f, err := value.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// flatten appends to nodes the value data at path and every value under
+// it, in preorder and with object keys sorted. The value has the identity
+// ident, of which step is the last step.
+func (d *differ) flatten(nodes []any, data any, path Path, ident, step, scope string) []any {
+	n := &node{path: path, data: data, key: path.String(), ident: ident, step: step, scope: scope, index: len(nodes)}
+	if len(path) > 0 {
+		n.parent = path[:len(path)-1].String()
+	}
+	nodes = append(nodes, n)
+	// The values under objects and arrays compared as a whole are only
+	// flattened for their hashes.
+	n.whole = d.options.MaxDepth > 0 && len(path) >= d.options.MaxDepth && isContainer(data)
+	h := newHasher(d.normalize(data))
+	switch data := data.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(data))
+		for k := range data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			childPath := append(path[:len(path):len(path)], k)
+			if d.ignored(childPath) || d.options.NullAbsent && data[k] == nil {
+				if n.skip == nil {
+					n.skip = make(map[string]bool)
+				}
+				n.skip[k] = true
+				continue
+			}
+			step := "." + k
+			first := len(nodes)
+			nodes = d.flatten(nodes, data[k], childPath, ident+step, step, scope)
+			h.member(k, nodes[first].(*node))
+		}
+	case []any:
+		ids := elementIDs(data, d.options.ArrayKeys[n.key])
+		for i, elem := range data {
+			childPath := append(path[:len(path):len(path)], i)
+			if d.ignored(childPath) {
+				continue
+			}
+			step, scope := fmt.Sprintf("[%d]", i), scope
+			if ids[i] != "" {
+				step = fmt.Sprintf("[%s=%s]", d.options.ArrayKeys[n.key], ids[i])
+				scope = ident + step
+			}
+			first := len(nodes)
+			nodes = d.flatten(nodes, elem, childPath, ident+step, step, scope)
+			nodes[first].(*node).id = ids[i]
+			h.elem(nodes[first].(*node))
+		}
+	}
+	if n.whole {
+		nodes = nodes[:n.index+1]
+	}
+	n.size = len(nodes) - n.index
+	n.hash = h.sum()
+	return nodes
+}
+
+// elementIDs returns the encoded value of the member named key of every
+// element of array, or an empty string for elements that are missing it or
+// whose identifier isn't unique in the array.
+func elementIDs(array []any, key string) []string {
+	ids := make([]string, len(array))
+	if key == "" {
+		return ids
+	}
+	seen := make(map[string]int)
+	for i, elem := range array {
+		obj, ok := elem.(map[string]any)
+		if !ok {
+			continue
+		}
+		value, ok := obj[key]
+		if !ok {
+			continue
+		}
+		ids[i] = encodeAll([]any{value})[0]
+		seen[ids[i]]++
+	}
+	for i, id := range ids {
+		if seen[id] > 1 {
+			ids[i] = ""
+		}
+	}
+	return ids
+}
+
+func encodeAll(values []any) []string {
+	encoded := make([]string, len(values))
+	for i, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			panic(fmt.Sprintf("jsondiff: cannot encode %#v: %v", value, err))
+		}
+		encoded[i] = string(data)
+	}
+	return encoded
+}
+
+func isContainer(data any) bool {
+	switch data.(type) {
+	case map[string]any, []any:
+		return true
+	}
+	return false
+}
+
+// compatible reports whether the identifiers of the array elements s and
+// t, or of those holding them, allow pairing them.
+func compatible(s, t *node) bool {
+	if s.id != "" && t.id != "" && s.id != t.id {
+		return false
+	}
+	return s.scope == "" || t.scope == "" || s.scope == t.scope
+}
+
+// nearDepth reports whether s and t are close enough in depth to be
+// paired, following the SameDepth and MaxDepthChange options.
+func (d *differ) nearDepth(s, t *node) bool {
+	change := len(s.path) - len(t.path)
+	if change < 0 {
+		change = -change
+	}
+	switch {
+	case d.options.SameDepth:
+		return change == 0
+	case d.options.MaxDepthChange > 0:
+		return change <= d.options.MaxDepthChange
+	}
+	return true
+}
+
+// editCost returns the cost of pairing source with target. Roots are only
+// paired with each other, and values of different types, scalars that
+// changed under a different key or index, or values too far apart in
+// depth aren't paired at all, so they are removed and added instead.
+// Values that moved cost one more than those staying in place, so that
+// equal values are only reported as moved if they did move. Array
+// elements with the same identifier are always paired, and those with
+// different identifiers never are, nor are the values under them.
+func (d *differ) editCost(source, target any) assign.Cost {
+	if source == nil || target == nil {
+		return assign.MaxIntCost
+	}
+	s, t := source.(*node), target.(*node)
+	if s.ident == "" || t.ident == "" {
+		if s.ident == t.ident {
+			return assign.IntCost(0)
+		}
+		return assign.MaxIntCost
+	}
+	if !d.sameType(s.data, t.data) || s.whole != t.whole || !compatible(s, t) || !d.nearDepth(s, t) {
+		return assign.MaxIntCost
+	}
+	if s.id != "" && s.id == t.id && s.ident == t.ident {
+		return assign.IntCost(0)
+	}
+
+	var cost assign.IntCost
+	if s.ident != t.ident {
+		cost = 1
+	}
+	if s.whole {
+		if s.hash != t.hash {
+			if s.step != t.step {
+				return assign.MaxIntCost
+			}
+			cost++
+		}
+		return cost
+	}
+	switch sdata := s.data.(type) {
+	case map[string]any:
+		tdata := t.data.(map[string]any)
+		for k := range sdata {
+			if _, ok := tdata[k]; !ok && !s.skip[k] && !t.skip[k] {
+				cost++
+			}
+		}
+		for k := range tdata {
+			if _, ok := sdata[k]; !ok && !s.skip[k] && !t.skip[k] {
+				cost++
+			}
+		}
+	case []any:
+		// Elements may be maps or slices, which can't be compared with ==,
+		// so the arrays are compared through their encoded elements.
+		cost += assign.IntCost(listdist.DistanceOf(encodeAll(sdata), encodeAll(t.data.([]any)), listdist.StandardCostOf[string], 0))
+	default:
+		if !d.equal(s.data, t.data) {
+			if s.step != t.step {
+				return assign.MaxIntCost
+			}
+			cost++
+		}
+	}
+	return cost
+}
+
+// reorder returns b with the elements of its arrays reordered to follow the
+// order of the elements of the arrays at the same place in a, as described
+// for Options.IgnoreOrder.
+func reorder(a, b any) any {
+	switch b := b.(type) {
+	case map[string]any:
+		a, _ := a.(map[string]any)
+		result := make(map[string]any, len(b))
+		for k, elem := range b {
+			result[k] = reorder(a[k], elem)
+		}
+		return result
+	case []any:
+		a, _ := a.([]any)
+		partners := reorderPartners(a, b)
+		used := make([]bool, len(b))
+		result := make([]any, 0, len(b))
+		for i, j := range partners {
+			if j >= 0 {
+				result = append(result, reorder(a[i], b[j]))
+				used[j] = true
+			}
+		}
+		for j, elem := range b {
+			if !used[j] {
+				result = append(result, reorder(nil, elem))
+			}
+		}
+		return result
+	}
+	return b
+}
+
+// orderElem is an array element considered by reorderPartners.
+type orderElem struct {
+	index int
+	value any
+	canon string
+}
+
+// reorderPartners returns the index of the element of b paired with every
+// element of a, or -1 for those paired with none. Elements that are equal
+// regardless of the order of their arrays are paired first, and the others
+// are paired with assign by how many of their members or elements differ.
+func reorderPartners(a, b []any) []int {
+	partners := make([]int, len(a))
+	byCanon := make(map[string][]int)
+	for j, elem := range b {
+		canon := canonical(elem)
+		byCanon[canon] = append(byCanon[canon], j)
+	}
+	var sources []any
+	for i, elem := range a {
+		canon := canonical(elem)
+		if js := byCanon[canon]; len(js) > 0 {
+			partners[i] = js[0]
+			byCanon[canon] = js[1:]
+			continue
+		}
+		partners[i] = -1
+		sources = append(sources, &orderElem{i, elem, canon})
+	}
+	var targets []any
+	for j, elem := range b {
+		canon := canonical(elem)
+		if js := byCanon[canon]; len(js) > 0 && js[0] == j {
+			byCanon[canon] = js[1:]
+			targets = append(targets, &orderElem{j, elem, canon})
+		}
+	}
+	if len(sources) == 0 || len(targets) == 0 {
+		return partners
+	}
+	pairs := assign.Assign(sources, targets, &assign.AssignOptions{
+		Algorithm: assign.Rectangular,
+		EditCost: func(source, target any) assign.Cost {
+			switch {
+			case source == nil:
+				return assign.IntCost(orderSize(target.(*orderElem).value))
+			case target == nil:
+				return assign.IntCost(orderSize(source.(*orderElem).value))
+			}
+			return orderCost(source.(*orderElem).value, target.(*orderElem).value)
+		},
+	})
+	for _, pair := range pairs {
+		if pair.Source != nil && pair.Target != nil {
+			partners[pair.Source.(*orderElem).index] = pair.Target.(*orderElem).index
+		}
+	}
+	return partners
+}
+
+// orderSize returns the cost of leaving value unpaired when reordering,
+// which is the number of its members or elements.
+func orderSize(value any) int {
+	switch value := value.(type) {
+	case map[string]any:
+		return max(len(value), 1)
+	case []any:
+		return max(len(value), 1)
+	}
+	return 1
+}
+
+// orderCost returns the cost of pairing x with y when reordering, which is
+// the number of members or elements they don't have in common.
+func orderCost(x, y any) assign.Cost {
+	switch x := x.(type) {
+	case map[string]any:
+		y, ok := y.(map[string]any)
+		if !ok {
+			return assign.MaxIntCost
+		}
+		var cost assign.IntCost
+		for k, xv := range x {
+			if yv, ok := y[k]; !ok || canonical(xv) != canonical(yv) {
+				cost++
+			}
+		}
+		for k := range y {
+			if _, ok := x[k]; !ok {
+				cost++
+			}
+		}
+		return cost
+	case []any:
+		y, ok := y.([]any)
+		if !ok {
+			return assign.MaxIntCost
+		}
+		count := make(map[string]int)
+		for _, elem := range x {
+			count[canonical(elem)]++
+		}
+		for _, elem := range y {
+			count[canonical(elem)]--
+		}
+		var cost assign.IntCost
+		for _, n := range count {
+			cost += assign.IntCost(max(n, -n))
+		}
+		return cost
+	}
+	if isContainer(y) {
+		return assign.MaxIntCost
+	}
+	return assign.IntCost(1)
+}
+
+// canonical returns the encoded value, with the elements of its arrays
+// sorted by their own encoding.
+func canonical(value any) string {
+	switch value := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var sb strings.Builder
+		sb.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString(encodeAll([]any{k})[0] + ":" + canonical(value[k]))
+		}
+		sb.WriteByte('}')
+		return sb.String()
+	case []any:
+		elems := make([]string, len(value))
+		for i, elem := range value {
+			elems[i] = canonical(elem)
+		}
+		sort.Strings(elems)
+		return "[" + strings.Join(elems, ",") + "]"
+	}
+	return encodeAll([]any{value})[0]
+}
@@ -0,0 +1,479 @@
+package jsondiff_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/jsondiff"
+)
+
+func decode(c *C, text string) any {
+	var data any
+	err := json.Unmarshal([]byte(text), &data)
+	c.Assert(err, IsNil)
+	return data
+}
+
+func encode(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+// changeString renders change compactly, for comparing them in tests.
+func changeString(change jsondiff.Change) string {
+	switch change.Op {
+	case jsondiff.Add:
+		return fmt.Sprintf("add %s %s", change.Path, encode(change.New))
+	case jsondiff.Remove:
+		return fmt.Sprintf("remove %s %s", change.Path, encode(change.Old))
+	case jsondiff.Set:
+		return fmt.Sprintf("set %s %s => %s", change.Path, encode(change.Old), encode(change.New))
+	case jsondiff.Move:
+		return fmt.Sprintf("move %s => %s %s => %s", change.From, change.Path, encode(change.Old), encode(change.New))
+	}
+	return change.Op.String()
+}
+
+var diffTests = []struct {
+	summary string
+	a, b    string
+	changes []string
+}{{
+	summary: "Equal documents",
+	a:       `{"a": 1, "b": [1, 2, {"c": true}]}`,
+	b:       `{"b": [1, 2, {"c": true}], "a": 1}`,
+}, {
+	summary: "Scalar documents",
+	a:       `1`,
+	b:       `"x"`,
+	changes: []string{`set . 1 => "x"`},
+}, {
+	summary: "Document of another type",
+	a:       `{"a": 1}`,
+	b:       `[1]`,
+	changes: []string{`set . {"a":1} => [1]`},
+}, {
+	summary: "Value set",
+	a:       `{"a": 1, "b": 2}`,
+	b:       `{"a": 1, "b": 3}`,
+	changes: []string{`set .b 2 => 3`},
+}, {
+	summary: "Object added and removed",
+	a:       `{"a": {"x": 1, "y": 2}}`,
+	b:       `{"b": [true, false]}`,
+	changes: []string{`add .b [true,false]`, `remove .a {"x":1,"y":2}`},
+}, {
+	summary: "Value moved",
+	a:       `{"a": {"x": 1, "y": 2}, "b": {}}`,
+	b:       `{"a": {"x": 1}, "b": {"y": 2}}`,
+	changes: []string{`move .a.y => .b.y 2 => 2`},
+}, {
+	summary: "Object moved with changes",
+	a:       `{"a": {"x": 1, "y": 2, "z": "z"}}`,
+	b:       `{"b": {"x": 1, "y": 3, "z": "z"}}`,
+	changes: []string{`move .a => .b {"x":1,"y":2,"z":"z"} => {"x":1,"y":3,"z":"z"}`, `set .b.y 2 => 3`},
+}, {
+	summary: "Array element removed",
+	a:       `["a", "b", "c"]`,
+	b:       `["a", "c"]`,
+	changes: []string{`move .[2] => .[1] "c" => "c"`, `remove .[1] "b"`},
+}, {
+	summary: "Equal values stay in place",
+	a:       `{"a": true, "b": true}`,
+	b:       `{"a": true, "b": true, "c": true}`,
+	changes: []string{`add .c true`},
+}}
+
+func (*S) TestDiff(c *C) {
+	for _, test := range diffTests {
+		c.Logf("Summary: %s", test.summary)
+		var changes []string
+		for _, change := range jsondiff.Diff(decode(c, test.a), decode(c, test.b), nil) {
+			changes = append(changes, changeString(change))
+		}
+		c.Assert(changes, DeepEquals, test.changes)
+	}
+	c.Assert(jsondiff.Move.String(), Equals, "move")
+	c.Assert(jsondiff.Op(42).String(), Equals, "Op(42)")
+}
+
+func (*S) TestPathString(c *C) {
+	c.Assert(jsondiff.Path{}.String(), Equals, ".")
+	c.Assert(jsondiff.Path{"a", 1, "b"}.String(), Equals, ".a[1].b")
+	c.Assert(jsondiff.Path{0, 1}.String(), Equals, ".[0][1]")
+	c.Assert(func() { _ = jsondiff.Path{1.5}.String() }, PanicMatches, `jsondiff: invalid path element 1.5`)
+}
+
+var arrayKeyTests = []struct {
+	summary string
+	keys    map[string]string
+	a, b    string
+	changes []string
+}{{
+	summary: "Reordered elements with changes",
+	keys:    map[string]string{".items": "id"},
+	a:       `{"items": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}]}`,
+	b:       `{"items": [{"id": 2, "name": "c"}, {"id": 1, "name": "a"}]}`,
+	changes: []string{
+		`move .items[1] => .items[0] {"id":2,"name":"b"} => {"id":2,"name":"c"}`,
+		`set .items[0].name "b" => "c"`,
+		`move .items[0] => .items[1] {"id":1,"name":"a"} => {"id":1,"name":"a"}`,
+	},
+}, {
+	summary: "Without keys",
+	a:       `{"items": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}]}`,
+	b:       `{"items": [{"id": 2, "name": "c"}, {"id": 1, "name": "a"}]}`,
+	changes: []string{
+		`move .items[1] => .items[0] {"id":2,"name":"b"} => {"id":2,"name":"c"}`,
+		`set .items[0].name "b" => "c"`,
+		`move .items[0] => .items[1] {"id":1,"name":"a"} => {"id":1,"name":"a"}`,
+	},
+}, {
+	summary: "Elements with different identifiers",
+	keys:    map[string]string{".items": "id"},
+	a:       `{"items": [{"id": 1, "name": "a"}]}`,
+	b:       `{"items": [{"id": 2, "name": "a"}]}`,
+	changes: []string{
+		`add .items[0] {"id":2,"name":"a"}`,
+		`remove .items[0] {"id":1,"name":"a"}`,
+	},
+}, {
+	summary: "Elements with changed members",
+	keys:    map[string]string{".": "name"},
+	a:       `[{"name": "x", "a": 1, "b": 2}, {"name": "y", "c": 3}]`,
+	b:       `[{"name": "y", "d": 4}, {"name": "z"}, {"name": "x", "a": 1, "c": 3}]`,
+	changes: []string{
+		`move .[1] => .[0] {"c":3,"name":"y"} => {"d":4,"name":"y"}`,
+		`add .[0].d 4`,
+		`add .[1] {"name":"z"}`,
+		`move .[0] => .[2] {"a":1,"b":2,"name":"x"} => {"a":1,"c":3,"name":"x"}`,
+		`add .[2].c 3`,
+		`remove .[0].b 2`,
+		`remove .[1].c 3`,
+	},
+}, {
+	summary: "Elements with duplicated or missing identifiers",
+	keys:    map[string]string{".a": "id"},
+	a:       `{"a": [{"id": 1, "v": 1}, {"id": 1, "v": 2}, {"v": 3}, 4]}`,
+	b:       `{"a": [{"v": 3}, {"id": 1, "v": 1}, {"id": 1, "v": 2}, 4]}`,
+	changes: []string{
+		`move .a[2] => .a[0] {"v":3} => {"v":3}`,
+		`move .a[0].v => .a[1].v 1 => 1`,
+		`move .a[0] => .a[2] {"id":1,"v":1} => {"id":1,"v":2}`,
+		`move .a[1].v => .a[2].v 2 => 2`,
+	},
+}, {
+	summary: "Nested arrays with keys",
+	keys:    map[string]string{".": "id", ".[0].sub": "k", ".[1].sub": "k"},
+	a:       `[{"id": "p", "sub": [{"k": 1, "v": "a"}, {"k": 2, "v": "b"}]}, {"id": "q", "sub": []}]`,
+	b:       `[{"id": "q", "sub": []}, {"id": "p", "sub": [{"k": 2, "v": "b"}, {"k": 1, "v": "c"}]}]`,
+	changes: []string{
+		`move .[1] => .[0] {"id":"q","sub":[]} => {"id":"q","sub":[]}`,
+		`move .[0] => .[1] {"id":"p","sub":[{"k":1,"v":"a"},{"k":2,"v":"b"}]} => {"id":"p","sub":[{"k":2,"v":"b"},{"k":1,"v":"c"}]}`,
+		`move .[0].sub[1] => .[1].sub[0] {"k":2,"v":"b"} => {"k":2,"v":"b"}`,
+		`move .[0].sub[0] => .[1].sub[1] {"k":1,"v":"a"} => {"k":1,"v":"c"}`,
+		`set .[1].sub[1].v "a" => "c"`,
+	},
+}}
+
+func (*S) TestDiffArrayKeys(c *C) {
+	for _, test := range arrayKeyTests {
+		c.Logf("Summary: %s", test.summary)
+		a, b := decode(c, test.a), decode(c, test.b)
+		diff := jsondiff.Diff(a, b, &jsondiff.Options{ArrayKeys: test.keys})
+		var changes []string
+		for _, change := range diff {
+			changes = append(changes, changeString(change))
+		}
+		c.Assert(changes, DeepEquals, test.changes)
+
+		result, err := jsondiff.Apply(a, diff)
+		c.Assert(err, IsNil)
+		c.Assert(encode(result), Equals, encode(b))
+	}
+}
+
+var optionTests = []struct {
+	summary string
+	options *jsondiff.Options
+	a, b    string
+	changes []string
+}{{
+	summary: "Ignored paths",
+	options: &jsondiff.Options{Ignore: []*jsondiff.Pattern{
+		jsondiff.MustParsePattern(".metadata.timestamp"),
+		jsondiff.MustParsePattern(".nodes[*].lastSeen"),
+	}},
+	a:       `{"metadata": {"timestamp": 1, "name": "a"}, "nodes": [{"id": 1, "lastSeen": 5}]}`,
+	b:       `{"metadata": {"timestamp": 2, "name": "b"}, "nodes": [{"id": 1, "lastSeen": 6}, {"id": 2}]}`,
+	changes: []string{`set .metadata.name "a" => "b"`, `add .nodes[1] {"id":2}`},
+}, {
+	summary: "Ignored values only in one document",
+	options: &jsondiff.Options{Ignore: []*jsondiff.Pattern{jsondiff.MustParsePattern("/.*\\.extra/")}},
+	a:       `{"a": {"extra": 1}, "b": 1}`,
+	b:       `{"a": {}, "b": 2, "c": {"extra": true}}`,
+	changes: []string{`set .b 1 => 2`, `add .c {"extra":true}`},
+}, {
+	summary: "Ignored root",
+	options: &jsondiff.Options{Ignore: []*jsondiff.Pattern{jsondiff.MustParsePattern(".")}},
+	a:       `{"a": 1}`,
+	b:       `[2]`,
+}, {
+	summary: "Numbers within tolerance",
+	options: &jsondiff.Options{Tolerance: 0.01},
+	a:       `{"a": 1.0, "b": 2.0, "c": [3.0]}`,
+	b:       `{"a": 1.005, "b": 2.5, "c": [2.995]}`,
+	changes: []string{`set .b 2 => 2.5`},
+}, {
+	summary: "Scalar documents within tolerance",
+	options: &jsondiff.Options{Tolerance: 1},
+	a:       `1`,
+	b:       `2`,
+}, {
+	summary: "Array order ignored",
+	options: &jsondiff.Options{IgnoreOrder: true},
+	a:       `{"a": [1, 2, 3], "b": [{"x": [1, 2]}, {"y": 1}]}`,
+	b:       `{"a": [3, 1, 2], "b": [{"y": 2}, {"x": [2, 1]}]}`,
+	changes: []string{`set .b[1].y 1 => 2`},
+}, {
+	summary: "Array order ignored with elements added and removed",
+	options: &jsondiff.Options{IgnoreOrder: true},
+	a:       `[1, 2, 3, 4]`,
+	b:       `[5, 4, 1, 6, 7]`,
+	changes: []string{`set .[1] 2 => 7`, `set .[2] 3 => 6`, `add .[4] 5`},
+}, {
+	summary: "Values compared as a whole below the maximum depth",
+	options: &jsondiff.Options{MaxDepth: 1},
+	a:       `{"a": {"x": 1, "y": 2}, "b": [1, 2], "c": {"z": 1}, "d": 1}`,
+	b:       `{"a": {"x": 1, "y": 3}, "b": [1, 2], "e": {"z": 1}, "d": 2}`,
+	changes: []string{`set .a {"x":1,"y":2} => {"x":1,"y":3}`, `set .d 1 => 2`, `move .c => .e {"z":1} => {"z":1}`},
+}, {
+	summary: "Values compared as a whole that changed under a moved parent",
+	options: &jsondiff.Options{MaxDepth: 3},
+	a:       `{"a": {"a": {"b": {}, "c": 2, "d": {}}}, "b": {}, "c": {}}`,
+	b:       `{"a": {"a": {"b": {"e": 0}, "e": {"a": null, "b": 0}}}, "b": {}, "e": 0}`,
+	changes: []string{`move .c => .a.a {} => {"b":{"e":0},"e":{"a":null,"b":0}}`, `add .a.a.b {"e":0}`, `add .a.a.e {"a":null,"b":0}`, `add .e 0`, `remove .a.a {"b":{},"c":2,"d":{}}`},
+}, {
+	summary: "Maximum depth with values ignored",
+	options: &jsondiff.Options{MaxDepth: 1, Ignore: []*jsondiff.Pattern{jsondiff.MustParsePattern(".a.t")}},
+	a:       `{"a": {"x": 1, "t": 1}}`,
+	b:       `{"a": {"x": 1, "t": 2}}`,
+}, {
+	summary: "Numbers within relative tolerance",
+	options: &jsondiff.Options{RelativeTolerance: 0.01},
+	a:       `{"a": 1000, "b": 0.1, "c": 0}`,
+	b:       `{"a": 1009, "b": 0.11, "c": 0}`,
+	changes: []string{`set .b 0.1 => 0.11`},
+}, {
+	summary: "Numbers within either tolerance",
+	options: &jsondiff.Options{Tolerance: 0.05, RelativeTolerance: 0.01},
+	a:       `[1000, 0.1, 5]`,
+	b:       `[1009, 0.14, 5.1]`,
+	changes: []string{`set .[2] 5 => 5.1`},
+}, {
+	summary: "Strings compared regardless of case",
+	options: &jsondiff.Options{IgnoreCase: true},
+	a:       `{"a": "Hello", "b": ["X", "y"], "c": "a "}`,
+	b:       `{"a": "hELLO", "b": ["x", "Y"], "c": "a"}`,
+	changes: []string{`set .c "a " => "a"`},
+}, {
+	summary: "Strings compared without surrounding space",
+	options: &jsondiff.Options{TrimSpace: true},
+	a:       `{"a": " x\n", "b": "Y"}`,
+	b:       `{"a": "x", "b": "y"}`,
+	changes: []string{`set .b "Y" => "y"`},
+}, {
+	summary: "Strings normalized in values compared as a whole",
+	options: &jsondiff.Options{MaxDepth: 1, IgnoreCase: true, TrimSpace: true},
+	a:       `{"a": {"x": "A "}}`,
+	b:       `{"a": {"x": "a"}}`,
+}, {
+	summary: "Null members are the same as missing members",
+	options: &jsondiff.Options{NullAbsent: true},
+	a:       `{"a": null, "b": 1, "c": null, "d": {"e": null}, "f": [null]}`,
+	b:       `{"b": null, "c": 2, "d": {}, "f": []}`,
+	changes: []string{`add .c 2`, `remove .f[0] null`, `remove .b 1`},
+}, {
+	summary: "Null members are removed without the option",
+	a:       `{"a": null, "b": 1}`,
+	b:       `{"b": 2}`,
+	changes: []string{`set .b 1 => 2`, `remove .a null`},
+}}
+
+func (*S) TestDiffOptions(c *C) {
+	for _, test := range optionTests {
+		c.Logf("Summary: %s", test.summary)
+		var changes []string
+		for _, change := range jsondiff.Diff(decode(c, test.a), decode(c, test.b), test.options) {
+			changes = append(changes, changeString(change))
+		}
+		c.Assert(changes, DeepEquals, test.changes)
+	}
+}
+
+func (*S) TestDiffNumericEqual(c *C) {
+	decodeNumbers := func(text string) any {
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.UseNumber()
+		var value any
+		c.Assert(dec.Decode(&value), IsNil)
+		return value
+	}
+	a := decodeNumbers(`{"a": 1, "b": [1e2, 0.10], "c": {"d": -0}, "e": 12345678901234567890, "f": 1}`)
+	b := decodeNumbers(`{"a": 1.0, "b": [100, 0.1], "c": {"d": 0.0}, "e": 12345678901234567891, "f": 2}`)
+	options := &jsondiff.Options{NumericEqual: true}
+	var changes []string
+	for _, change := range jsondiff.Diff(a, b, options) {
+		changes = append(changes, changeString(change))
+	}
+	c.Assert(changes, DeepEquals, []string{`set .e 12345678901234567890 => 12345678901234567891`, `set .f 1 => 2`})
+
+	// Numbers decoded as float64 values are equal to json.Number ones.
+	changes = nil
+	for _, change := range jsondiff.Diff(decodeNumbers(`{"a": 1, "b": [1e2, 0.10], "c": {"d": -0}}`), decode(c, `{"a": 1.0, "b": [100, 0.1], "c": {"d": 0}}`), options) {
+		changes = append(changes, changeString(change))
+	}
+	c.Assert(changes, HasLen, 0)
+
+	options.MaxDepth = 1
+	c.Assert(jsondiff.Diff(a, b, options), HasLen, 2)
+	c.Assert(jsondiff.Diff(a, decodeNumbers(`{"a": 1, "b": [100, 0.1], "c": {"d": 0}, "e": 12345678901234567890, "f": 1}`), options), HasLen, 0)
+}
+
+var diffAtTests = []struct {
+	summary string
+	path    string
+	a, b    string
+	changes []string
+	err     string
+}{{
+	summary: "Changes outside of the path",
+	path:    ".spec.containers",
+	a:       `{"spec": {"containers": [{"name": "a", "image": "x"}], "replicas": 1}}`,
+	b:       `{"spec": {"containers": [{"name": "a", "image": "y"}], "replicas": 2}}`,
+	changes: []string{`set .spec.containers[0].image "x" => "y"`},
+}, {
+	summary: "Value added",
+	path:    ".a[1]",
+	a:       `{"a": [1]}`,
+	b:       `{"a": [1, {"b": 2}]}`,
+	changes: []string{`add .a[1] {"b":2}`},
+}, {
+	summary: "Value removed",
+	path:    ".a",
+	a:       `{"a": 1}`,
+	b:       `{}`,
+	changes: []string{`remove .a 1`},
+}, {
+	summary: "Value missing",
+	path:    ".a.b",
+	a:       `{"a": 1}`,
+	b:       `{}`,
+	err:     `no value at .a.b`,
+}}
+
+func (*S) TestDiffAt(c *C) {
+	for _, test := range diffAtTests {
+		c.Logf("Summary: %s", test.summary)
+		path, err := jsondiff.ParsePath(test.path)
+		c.Assert(err, IsNil)
+		a, b := decode(c, test.a), decode(c, test.b)
+		diff, err := jsondiff.DiffAt(a, b, path, nil)
+		if test.err != "" {
+			c.Assert(err, ErrorMatches, test.err)
+			continue
+		}
+		c.Assert(err, IsNil)
+		var changes []string
+		for _, change := range diff {
+			changes = append(changes, changeString(change))
+		}
+		c.Assert(changes, DeepEquals, test.changes)
+		_, err = jsondiff.Apply(a, diff)
+		c.Assert(err, IsNil)
+	}
+
+	// Values at the path may be below the maximum depth.
+	a, b := decode(c, `{"a": {"b": {"x": 1}}}`), decode(c, `{"a": {"b": {"x": 2}}}`)
+	diff, err := jsondiff.DiffAt(a, b, jsondiff.Path{"a", "b"}, &jsondiff.Options{MaxDepth: 1})
+	c.Assert(err, IsNil)
+	c.Assert(diff, HasLen, 1)
+	c.Assert(changeString(diff[0]), Equals, `set .a.b {"x":1} => {"x":2}`)
+}
+
+var parsePathTests = []struct {
+	text string
+	path jsondiff.Path
+	err  string
+}{
+	{text: ".", path: jsondiff.Path{}},
+	{text: ".a.b", path: jsondiff.Path{"a", "b"}},
+	{text: ".[0][1]", path: jsondiff.Path{0, 1}},
+	{text: ".a[2].b", path: jsondiff.Path{"a", 2, "b"}},
+	{text: "a", err: `invalid path "a": must start with .`},
+	{text: ".a..b", err: `invalid path ".a..b": empty key`},
+	{text: ".a[1", err: `invalid path ".a\[1": missing \]`},
+	{text: ".a[x]", err: `invalid path ".a\[x\]": invalid index "x"`},
+	{text: ".a[-1]", err: `invalid path ".a\[-1\]": invalid index "-1"`},
+	{text: ".[0]x", err: `invalid path ".\[0\]x": unexpected 'x'`},
+}
+
+func (*S) TestParsePath(c *C) {
+	for _, test := range parsePathTests {
+		c.Logf("Test: %s", test.text)
+		path, err := jsondiff.ParsePath(test.text)
+		if test.err != "" {
+			c.Assert(err, ErrorMatches, test.err)
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(path, DeepEquals, test.path)
+		c.Assert(path.String(), Equals, test.text)
+	}
+}
+
+func (*S) TestDiffIgnoreOrderRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	options := &jsondiff.Options{IgnoreOrder: true}
+	for i := 0; i < 300; i++ {
+		a := randomValue(rnd, 3)
+		b := mutate(rnd, decode(c, encode(a)), 3)
+		c.Logf("Test: %s => %s", encode(a), encode(b))
+		result, err := jsondiff.Apply(a, jsondiff.Diff(a, b, options))
+		c.Assert(err, IsNil)
+		c.Assert(jsondiff.Diff(result, b, options), HasLen, 0)
+	}
+}
+
+func (*S) TestDiffMaxDepthRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		a := randomValue(rnd, 3)
+		b := mutate(rnd, decode(c, encode(a)), 3)
+		options := &jsondiff.Options{MaxDepth: 1 + i%3}
+		c.Logf("Test: %s => %s (depth %d)", encode(a), encode(b), options.MaxDepth)
+		result, err := jsondiff.Apply(a, jsondiff.Diff(a, b, options))
+		c.Assert(err, IsNil)
+		c.Assert(encode(result), Equals, encode(b))
+	}
+}
+
+func (*S) TestDiffMaxDepthDeepRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 1000; i++ {
+		a := randomValue(rnd, 4)
+		b := mutate(rnd, decode(c, encode(a)), 4)
+		options := &jsondiff.Options{MaxDepth: 1 + i%4}
+		c.Logf("Test %d: %s => %s (depth %d)", i, encode(a), encode(b), options.MaxDepth)
+		result, err := jsondiff.Apply(a, jsondiff.Diff(a, b, options))
+		c.Assert(err, IsNil)
+		c.Assert(encode(result), Equals, encode(b))
+	}
+}
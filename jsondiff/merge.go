@@ -0,0 +1,121 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondiff
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MergePatch returns a JSON Merge Patch document turning document a into
+// document b, as defined by RFC 7386.
+//
+// Object members removed are set to null in the patch, members that
+// changed within objects on both sides are patched recursively, and any
+// other value that changed is replaced as a whole. Members that are equal
+// on both sides are omitted, so the patch for equal objects is an empty
+// object.
+//
+// Merge patches use null to remove members, so they cannot set a member to
+// null, nor add objects holding null members. MergePatch returns an error
+// if b has such a null value where a differs.
+func MergePatch(a, b any) (any, error) {
+	if b == nil {
+		// A null patch replaces the whole document.
+		return nil, nil
+	}
+	return mergePatch(a, b, Path{})
+}
+
+func mergePatch(a, b any, path Path) (any, error) {
+	bobj, ok := b.(map[string]any)
+	if !ok {
+		if b == nil {
+			return nil, fmt.Errorf("cannot set %s to null in a merge patch", path)
+		}
+		return b, checkNulls(b, path)
+	}
+	aobj, ok := a.(map[string]any)
+	if !ok {
+		// Merging into a value that isn't an object starts from an empty
+		// object, so b is reproduced as long as it holds no nulls.
+		return b, checkNulls(b, path)
+	}
+	patch := make(map[string]any)
+	for key := range aobj {
+		if _, ok := bobj[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	for key, bvalue := range bobj {
+		avalue, ok := aobj[key]
+		if ok && reflect.DeepEqual(avalue, bvalue) {
+			continue
+		}
+		if !ok {
+			avalue = nil
+		}
+		value, err := mergePatch(avalue, bvalue, append(path[:len(path):len(path)], key))
+		if err != nil {
+			return nil, err
+		}
+		patch[key] = value
+	}
+	return patch, nil
+}
+
+// checkNulls returns an error if value holds an object member that is null,
+// which a merge patch would remove rather than set.
+func checkNulls(value any, path Path) error {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, elem := range value {
+			elemPath := append(path[:len(path):len(path)], key)
+			if elem == nil {
+				return fmt.Errorf("cannot set %s to null in a merge patch", elemPath)
+			}
+			if err := checkNulls(elem, elemPath); err != nil {
+				return err
+			}
+		}
+	case []any:
+		// Arrays are replaced as a whole, including any null in them.
+	}
+	return nil
+}
+
+// ApplyMergePatch returns the document resulting from applying the JSON
+// Merge Patch document patch to doc, as defined by RFC 7386. Neither doc
+// nor patch are changed, and values from patch are shared with the result.
+func ApplyMergePatch(doc, patch any) any {
+	pobj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	dobj, _ := doc.(map[string]any)
+	result := make(map[string]any, len(dobj)+len(pobj))
+	for key, value := range dobj {
+		result[key] = value
+	}
+	for key, value := range pobj {
+		if value == nil {
+			delete(result, key)
+		} else {
+			result[key] = ApplyMergePatch(result[key], value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+package jsondiff_test
+
+import (
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/jsondiff"
+)
+
+var mergePatchTests = []struct {
+	summary string
+	a, b    string
+	patch   string
+	error   string
+}{{
+	summary: "Equal objects",
+	a:       `{"a": 1, "b": [1, 2]}`,
+	b:       `{"a": 1, "b": [1, 2]}`,
+	patch:   `{}`,
+}, {
+	summary: "Members added, removed, and changed",
+	a:       `{"a": 1, "b": {"c": 2, "d": 3}, "e": [1, 2]}`,
+	b:       `{"a": 1, "b": {"c": 4}, "e": [2], "f": {"g": true}}`,
+	patch:   `{"b":{"c":4,"d":null},"e":[2],"f":{"g":true}}`,
+}, {
+	summary: "Object replacing a scalar",
+	a:       `{"a": 1}`,
+	b:       `{"a": {"b": [null]}}`,
+	patch:   `{"a":{"b":[null]}}`,
+}, {
+	summary: "Scalar documents",
+	a:       `1`,
+	b:       `"x"`,
+	patch:   `"x"`,
+}, {
+	summary: "Null document",
+	a:       `{"a": 1}`,
+	b:       `null`,
+	patch:   `null`,
+}, {
+	summary: "Member set to null",
+	a:       `{"a": {"b": 1}}`,
+	b:       `{"a": {"b": null}}`,
+	error:   `cannot set .a.b to null in a merge patch`,
+}, {
+	summary: "Member added as null",
+	a:       `{}`,
+	b:       `{"a": null}`,
+	error:   `cannot set .a to null in a merge patch`,
+}, {
+	summary: "Object added with a null member",
+	a:       `{"a": 1}`,
+	b:       `{"a": {"b": {"c": null}}}`,
+	error:   `cannot set .a.b.c to null in a merge patch`,
+}}
+
+func (*S) TestMergePatch(c *C) {
+	for _, test := range mergePatchTests {
+		c.Logf("Summary: %s", test.summary)
+		a, b := decode(c, test.a), decode(c, test.b)
+		patch, err := jsondiff.MergePatch(a, b)
+		if test.error != "" {
+			c.Assert(err, ErrorMatches, test.error)
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(encode(patch), Equals, test.patch)
+		before := encode(a)
+		c.Assert(encode(jsondiff.ApplyMergePatch(a, patch)), Equals, encode(b))
+		c.Assert(encode(a), Equals, before)
+	}
+}
+
+func (*S) TestApplyMergePatch(c *C) {
+	// Test cases from appendix A of RFC 7386.
+	tests := [][3]string{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, test := range tests {
+		c.Logf("Test: %v", test)
+		c.Assert(encode(jsondiff.ApplyMergePatch(decode(c, test[0]), decode(c, test[1]))), Equals, test[2])
+	}
+}
+
+func (*S) TestMergePatchRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		a := randomValue(rnd, 3)
+		b := mutate(rnd, decode(c, encode(a)), 3)
+		patch, err := jsondiff.MergePatch(a, b)
+		if err != nil {
+			continue
+		}
+		c.Logf("Test: %s => %s", encode(a), encode(b))
+		c.Assert(encode(jsondiff.ApplyMergePatch(a, patch)), Equals, encode(b))
+	}
+}
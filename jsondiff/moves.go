@@ -0,0 +1,119 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondiff
+
+import (
+	"github.com/canonical/go-algo/assign"
+)
+
+// moves decides which of the values paired in different places are
+// removed and added rather than moved, according to the NoMoves and
+// MoveSimilarity options.
+type moves struct {
+	options *Options
+
+	// nodes holds the values of the old document in preorder, and partner
+	// holds the value paired with each of them. Values paired as a whole
+	// with an equal value, without the values under them, are in whole.
+	nodes   []any
+	partner map[*node]*node
+	whole   map[*node]bool
+}
+
+// newMoves returns the moves among pairs, which are for the given nodes,
+// the first pruned of them holding equal values paired as a whole.
+func (d *differ) newMoves(nodes []any, pairs []assign.Pair, pruned int) *moves {
+	m := &moves{options: d.options, nodes: nodes}
+	if d.options.MoveSimilarity <= 0 {
+		return m
+	}
+	m.partner = make(map[*node]*node)
+	m.whole = make(map[*node]bool)
+	for i, pair := range pairs {
+		if pair.Source != nil && pair.Target != nil {
+			m.partner[pair.Source.(*node)] = pair.Target.(*node)
+			if i < pruned {
+				m.whole[pair.Source.(*node)] = true
+			}
+		}
+	}
+	return m
+}
+
+// split returns the values of the old document that are paired in another
+// place but reported as removed and added. They're taken off paired, and
+// their paths are marked as removed and added in the respective documents,
+// so that the values under them are left out as well.
+//
+// The values under a value added this way must be added along with it, so
+// they're split as well, which may only be known after the value holding
+// them is considered. Pairs are thus considered again until none is split.
+func (m *moves) split(pairs []assign.Pair, paired map[string]string, removed, added map[string]bool) map[*node]bool {
+	if !m.options.NoMoves && m.options.MoveSimilarity <= 0 {
+		return nil
+	}
+	split := make(map[*node]bool)
+	splitTargets := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, pair := range pairs {
+			if pair.Source == nil || pair.Target == nil {
+				continue
+			}
+			source, target := pair.Source.(*node), pair.Target.(*node)
+			if source.ident == "" || split[source] || stays(source, target, paired) {
+				continue
+			}
+			if !m.options.NoMoves && !splitTargets[target.parent] && m.similarity(source, target) >= m.options.MoveSimilarity {
+				continue
+			}
+			split[source] = true
+			splitTargets[target.key] = true
+			delete(paired, source.key)
+			removed[source.key] = true
+			added[target.key] = true
+			changed = true
+		}
+	}
+	return split
+}
+
+// similarity returns how similar source is to target, as described for
+// the MoveSimilarity option.
+func (m *moves) similarity(source, target *node) float64 {
+	if source.hash == target.hash {
+		return 1
+	}
+	within := func(n *node) bool {
+		return n.index >= target.index && n.index < target.index+target.size
+	}
+	common := 0
+	for i := source.index; i < source.index+source.size; {
+		n := m.nodes[i].(*node)
+		partner := m.partner[n]
+		switch {
+		case partner == nil || !within(partner):
+		case m.whole[n]:
+			common += n.size
+			i += n.size
+			continue
+		case isContainer(n.data) && !n.whole || n.hash == partner.hash:
+			common++
+		}
+		i++
+	}
+	return 2 * float64(common) / float64(source.size+target.size)
+}
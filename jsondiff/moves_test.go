@@ -0,0 +1,130 @@
+package jsondiff_test
+
+import (
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/jsondiff"
+)
+
+var moveOptionTests = []struct {
+	summary string
+	options jsondiff.Options
+	a, b    string
+	changes []string
+}{{
+	summary: "Moves reported by default",
+	a:       `{"a": {"x": 1, "y": 2, "z": "z"}}`,
+	b:       `{"b": {"x": 1, "y": 3, "z": "z"}}`,
+	changes: []string{`move .a => .b {"x":1,"y":2,"z":"z"} => {"x":1,"y":3,"z":"z"}`, `set .b.y 2 => 3`},
+}, {
+	summary: "No moves",
+	options: jsondiff.Options{NoMoves: true},
+	a:       `{"a": {"x": 1, "y": 2, "z": "z"}}`,
+	b:       `{"b": {"x": 1, "y": 3, "z": "z"}}`,
+	changes: []string{`add .b {"x":1,"y":3,"z":"z"}`, `remove .a {"x":1,"y":2,"z":"z"}`},
+}, {
+	summary: "No moves within arrays",
+	options: jsondiff.Options{NoMoves: true},
+	a:       `["a", "b", "c"]`,
+	b:       `["a", "c"]`,
+	changes: []string{`add .[1] "c"`, `remove .[2] "c"`, `remove .[1] "b"`},
+}, {
+	summary: "Similar enough to move",
+	options: jsondiff.Options{MoveSimilarity: 0.5},
+	a:       `{"a": {"x": 1, "y": 2, "z": "z"}}`,
+	b:       `{"b": {"x": 1, "y": 3, "z": "z"}}`,
+	changes: []string{`move .a => .b {"x":1,"y":2,"z":"z"} => {"x":1,"y":3,"z":"z"}`, `set .b.y 2 => 3`},
+}, {
+	summary: "Not similar enough to move",
+	options: jsondiff.Options{MoveSimilarity: 0.9},
+	a:       `{"a": {"x": 1, "y": 2, "z": "z"}}`,
+	b:       `{"b": {"x": 1, "y": 3, "z": "z"}}`,
+	changes: []string{`add .b {"x":1,"y":3,"z":"z"}`, `remove .a {"x":1,"y":2,"z":"z"}`},
+}, {
+	summary: "Equal values always move",
+	options: jsondiff.Options{MoveSimilarity: 1},
+	a:       `{"a": {"x": 1, "y": 2}, "b": {}}`,
+	b:       `{"a": {"x": 1}, "b": {"y": 2}}`,
+	changes: []string{`move .a.y => .b.y 2 => 2`},
+}, {
+	summary: "Values moved out of a value split",
+	options: jsondiff.Options{MoveSimilarity: 0.6},
+	a:       `{"a": {"k": {"p": 1, "q": 2}, "x": 1, "y": 2}}`,
+	b:       `{"b": {"x": 1, "y": 3, "z": 4}, "k": {"p": 1, "q": 2}}`,
+	changes: []string{`add .b {"x":1,"y":3,"z":4}`, `move .a.k => .k {"p":1,"q":2} => {"p":1,"q":2}`, `remove .a {"k":{"p":1,"q":2},"x":1,"y":2}`},
+}, {
+	summary: "Moves across levels by default",
+	a:       `{"a": {"x": 1, "y": 2}, "b": {"c": [{"d": {}}]}}`,
+	b:       `{"b": {"c": [{"d": {"x": 1, "y": 2}}]}}`,
+	changes: []string{`move .a => .b.c[0].d {"x":1,"y":2} => {"x":1,"y":2}`, `remove .b.c[0].d {}`},
+}, {
+	summary: "Same depth",
+	options: jsondiff.Options{SameDepth: true},
+	a:       `{"a": {"x": 1, "y": 2}, "b": {"c": [{"d": {}}]}}`,
+	b:       `{"b": {"c": [{"d": {"x": 1, "y": 2}}]}}`,
+	changes: []string{`add .b.c[0].d.x 1`, `add .b.c[0].d.y 2`, `remove .a {"x":1,"y":2}`},
+}, {
+	summary: "Same depth moves",
+	options: jsondiff.Options{SameDepth: true},
+	a:       `{"a": {"x": 1, "y": 2}, "b": {}}`,
+	b:       `{"b": {"x": 1, "y": 2}}`,
+	changes: []string{`move .a => .b {"x":1,"y":2} => {"x":1,"y":2}`, `remove .b {}`},
+}, {
+	summary: "Depth change too large",
+	options: jsondiff.Options{MaxDepthChange: 2},
+	a:       `{"a": {"x": 1, "y": 2}, "b": {"c": [{"d": {}}]}}`,
+	b:       `{"b": {"c": [{"d": {"x": 1, "y": 2}}]}}`,
+	changes: []string{`add .b.c[0].d.x 1`, `add .b.c[0].d.y 2`, `remove .a {"x":1,"y":2}`},
+}, {
+	summary: "Depth change small enough",
+	options: jsondiff.Options{MaxDepthChange: 3},
+	a:       `{"a": {"x": 1, "y": 2}, "b": {"c": [{"d": {}}]}}`,
+	b:       `{"b": {"c": [{"d": {"x": 1, "y": 2}}]}}`,
+	changes: []string{`move .a => .b.c[0].d {"x":1,"y":2} => {"x":1,"y":2}`, `remove .b.c[0].d {}`},
+}}
+
+func (*S) TestDiffMoveOptions(c *C) {
+	for _, test := range moveOptionTests {
+		c.Logf("Summary: %s", test.summary)
+		a, b := decode(c, test.a), decode(c, test.b)
+		diff := jsondiff.Diff(a, b, &test.options)
+		var changes []string
+		for _, change := range diff {
+			changes = append(changes, changeString(change))
+		}
+		c.Assert(changes, DeepEquals, test.changes)
+
+		result, err := jsondiff.Apply(a, diff)
+		c.Assert(err, IsNil)
+		c.Assert(encode(result), Equals, encode(b))
+	}
+}
+
+func (*S) TestDiffMoveOptionsRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		a := randomValue(rnd, 3)
+		b := mutate(rnd, decode(c, encode(a)), 3)
+		options := &jsondiff.Options{NoMoves: i%2 == 0, MoveSimilarity: float64(i%5) / 4, SameDepth: i%7 == 0, MaxDepthChange: i % 3}
+		c.Logf("Test: %s => %s (no moves %v, similarity %v, same depth %v, depth change %d)", encode(a), encode(b),
+			options.NoMoves, options.MoveSimilarity, options.SameDepth, options.MaxDepthChange)
+		diff := jsondiff.Diff(a, b, options)
+		for _, change := range diff {
+			if change.Op != jsondiff.Move {
+				continue
+			}
+			c.Assert(options.NoMoves, Equals, false)
+			depthChange := len(change.Path) - len(change.From)
+			if options.SameDepth {
+				c.Assert(depthChange, Equals, 0)
+			} else if options.MaxDepthChange > 0 {
+				c.Assert(depthChange <= options.MaxDepthChange && -depthChange <= options.MaxDepthChange, Equals, true)
+			}
+		}
+		result, err := jsondiff.Apply(a, diff)
+		c.Assert(err, IsNil)
+		c.Assert(encode(result), Equals, encode(b))
+	}
+}
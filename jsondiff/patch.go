@@ -0,0 +1,433 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Pointer returns the path as a JSON Pointer, as defined by RFC 6901.
+func (p Path) Pointer() string {
+	var sb strings.Builder
+	for _, elem := range p {
+		sb.WriteByte('/')
+		switch elem := elem.(type) {
+		case string:
+			sb.WriteString(pointerEscaper.Replace(elem))
+		case int:
+			sb.WriteString(strconv.Itoa(elem))
+		default:
+			// Let String panic with a helpful message.
+			_ = Path{elem}.String()
+		}
+	}
+	return sb.String()
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// ParsePointer parses a JSON Pointer, as defined by RFC 6901, into the path
+// it refers to in doc. Pointers don't tell array indexes apart from object
+// keys made of digits, so tokens are taken as indexes when the value they
+// refer into is an array in doc, and as keys when it's an object. Past the
+// values found in doc, tokens that are an index written as Pointer writes
+// it are taken as indexes, and any others as keys. A nil doc thus parses
+// any pointer that way, which round-trips the paths without keys made of
+// digits.
+func ParsePointer(pointer string, doc any) (Path, error) {
+	path := Path{}
+	if pointer == "" {
+		return path, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid pointer %q: must start with /", pointer)
+	}
+	value, found := doc, doc != nil
+	for _, token := range strings.Split(pointer[1:], "/") {
+		key, err := unescapePointer(token)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pointer %q: %v", pointer, err)
+		}
+		index, indexErr := pointerIndex(key)
+		var elem any = key
+		switch container := value.(type) {
+		case map[string]any:
+			value, found = container[key]
+		case []any:
+			if indexErr != nil {
+				return nil, fmt.Errorf("invalid pointer %q: %v", pointer, indexErr)
+			}
+			elem = index
+			if found = index < len(container); found {
+				value = container[index]
+			}
+		default:
+			if indexErr == nil {
+				elem = index
+			}
+			found = false
+		}
+		if !found {
+			value = nil
+		}
+		path = append(path, elem)
+	}
+	return path, nil
+}
+
+// unescapePointer returns the key encoded by a token of a JSON Pointer.
+func unescapePointer(token string) (string, error) {
+	if !strings.Contains(token, "~") {
+		return token, nil
+	}
+	for i := 0; i < len(token); i++ {
+		if token[i] == '~' && (i+1 == len(token) || token[i+1] != '0' && token[i+1] != '1') {
+			return "", fmt.Errorf("invalid escape in %q", token)
+		}
+	}
+	return pointerUnescaper.Replace(token), nil
+}
+
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// pointerIndex returns the array index in key, which must be written in
+// decimal without leading zeros.
+func pointerIndex(key string) (int, error) {
+	if key == "" || len(key) > 1 && key[0] == '0' || strings.TrimLeft(key, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid index %q", key)
+	}
+	index, err := strconv.Atoi(key)
+	if err != nil {
+		return 0, fmt.Errorf("invalid index %q", key)
+	}
+	return index, nil
+}
+
+// Operation is an operation in a JSON Patch document, as defined by
+// RFC 6902.
+type Operation struct {
+	// Op is one of "add", "remove", "replace", or "move".
+	Op    string
+	Path  string
+	From  string
+	Value any
+}
+
+// MarshalJSON encodes the operation with the members it needs, which
+// for additions and replacements includes the value even if it's null.
+func (op Operation) MarshalJSON() ([]byte, error) {
+	type encoded struct {
+		Op    string `json:"op"`
+		From  string `json:"from,omitempty"`
+		Path  string `json:"path"`
+		Value *any   `json:"value,omitempty"`
+	}
+	e := encoded{Op: op.Op, Path: op.Path}
+	switch op.Op {
+	case "move", "copy":
+		e.From = op.From
+	case "add", "replace", "test":
+		e.Value = &op.Value
+	}
+	return json.Marshal(e)
+}
+
+// Patch returns a JSON Patch document turning document a into document b,
+// with the changes returned by Diff for them. Applying the operations in
+// order to a produces b.
+//
+// Values moved are moved by the patch as well, unless their original place
+// was taken by another value before they could be moved, in which case they
+// are added instead. The patch may also move values that stay in place
+// according to the changes, when other values are added or moved before
+// them in the same array.
+func Patch(a, b any, changes []Change) []Operation {
+	for _, change := range changes {
+		if change.Op == Set && len(change.Path) == 0 {
+			return []Operation{{Op: "replace", Path: "", Value: change.New}}
+		}
+	}
+
+	p := &patcher{
+		sources: make(map[string]*wnode),
+		moved:   make(map[string]Path),
+		added:   make(map[string]bool),
+	}
+	for _, change := range changes {
+		switch change.Op {
+		case Move:
+			p.moved[change.Path.String()] = change.From
+		case Add:
+			p.added[change.Path.String()] = true
+		}
+	}
+	root := p.wrap(nil, a, Path{})
+	root.placed = true
+	p.visit(root, b, Path{}, Path{})
+
+	// Remove what is left from a, starting from the end so that removing
+	// array elements doesn't affect the position of the following ones.
+	var leftover []*wnode
+	root.walk(func(n *wnode) bool {
+		if !n.placed {
+			leftover = append(leftover, n)
+			return false
+		}
+		return true
+	})
+	for i := len(leftover) - 1; i >= 0; i-- {
+		n := leftover[i]
+		p.ops = append(p.ops, Operation{Op: "remove", Path: n.path().Pointer()})
+		n.detach()
+	}
+	return p.ops
+}
+
+// wnode is a value in the document being patched, which starts as a copy
+// of the old document and is changed as the operations are emitted, so
+// that the location of values is known at every step.
+type wnode struct {
+	parent *wnode
+	key    string
+	object map[string]*wnode
+	array  []*wnode
+	data   any
+
+	// placed is set once the value is known to be where it must be in
+	// the new document.
+	placed bool
+}
+
+// path returns the location of n in the document.
+func (n *wnode) path() Path {
+	var path Path
+	for ; n.parent != nil; n = n.parent {
+		if n.parent.array != nil {
+			for i, elem := range n.parent.array {
+				if elem == n {
+					path = append(path, i)
+					break
+				}
+			}
+		} else {
+			path = append(path, n.key)
+		}
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// detach removes n from its parent.
+func (n *wnode) detach() {
+	parent := n.parent
+	n.parent = nil
+	if parent == nil {
+		return
+	}
+	if parent.array != nil {
+		for i, elem := range parent.array {
+			if elem == n {
+				parent.array = append(parent.array[:i], parent.array[i+1:]...)
+				break
+			}
+		}
+		return
+	}
+	delete(parent.object, n.key)
+}
+
+// attach inserts n under parent at pos, which is a key for objects and an
+// index for arrays, detaching any value that was found under that key.
+func (n *wnode) attach(parent *wnode, pos any) {
+	n.parent = parent
+	if key, ok := pos.(string); ok {
+		if old := parent.object[key]; old != nil {
+			old.parent = nil
+		}
+		n.key = key
+		parent.object[key] = n
+		return
+	}
+	i := pos.(int)
+	parent.array = append(parent.array, nil)
+	copy(parent.array[i+1:], parent.array[i:])
+	parent.array[i] = n
+}
+
+// walk calls f for n and the values under it, in preorder, skipping the
+// values under those for which f returns false.
+func (n *wnode) walk(f func(n *wnode) bool) {
+	if !f(n) {
+		return
+	}
+	keys := make([]string, 0, len(n.object))
+	for key := range n.object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		n.object[key].walk(f)
+	}
+	for _, elem := range append([]*wnode(nil), n.array...) {
+		elem.walk(f)
+	}
+}
+
+type patcher struct {
+	ops []Operation
+
+	// sources holds the values of the old document by the string form
+	// of their original path.
+	sources map[string]*wnode
+
+	// moved and added hold the paths in the new document of the values
+	// moved and added, with the origin of the moved ones.
+	moved map[string]Path
+	added map[string]bool
+
+	root *wnode
+}
+
+// wrap returns the wnode for data at path in the old document.
+func (p *patcher) wrap(parent *wnode, data any, path Path) *wnode {
+	n := &wnode{parent: parent, data: data}
+	if parent == nil {
+		p.root = n
+	}
+	p.sources[path.String()] = n
+	switch data := data.(type) {
+	case map[string]any:
+		n.object = make(map[string]*wnode, len(data))
+		for key, value := range data {
+			child := p.wrap(n, value, append(path[:len(path):len(path)], key))
+			child.key = key
+			n.object[key] = child
+		}
+	case []any:
+		n.array = make([]*wnode, len(data))
+		for i, value := range data {
+			n.array[i] = p.wrap(n, value, append(path[:len(path):len(path)], i))
+		}
+	}
+	return n
+}
+
+// visit emits the operations turning the children of n, which came from
+// the value at source in the old document, into those of data, at path in
+// the new document.
+func (p *patcher) visit(n *wnode, data any, path, source Path) {
+	var positions []any
+	switch data := data.(type) {
+	case map[string]any:
+		for key := range data {
+			positions = append(positions, key)
+		}
+		sort.Slice(positions, func(i, j int) bool { return positions[i].(string) < positions[j].(string) })
+	case []any:
+		for i := range data {
+			positions = append(positions, i)
+		}
+	}
+	for _, pos := range positions {
+		var value any
+		if key, ok := pos.(string); ok {
+			value = data.(map[string]any)[key]
+		} else {
+			value = data.([]any)[pos.(int)]
+		}
+		childPath := append(path[:len(path):len(path)], pos)
+		key := childPath.String()
+
+		var childSource Path
+		switch {
+		case p.added[key]:
+		case p.moved[key] != nil:
+			childSource = p.moved[key]
+		case source != nil:
+			childSource = append(source[:len(source):len(source)], pos)
+		}
+		child := p.sources[childSource.String()]
+		if childSource == nil || child == nil || child.placed || !p.isAttached(child) {
+			// The value is new, or was replaced by another one.
+			fresh := &wnode{placed: true}
+			p.ops = append(p.ops, Operation{Op: "add", Path: childPath.Pointer(), Value: value})
+			fresh.attach(n, pos)
+			p.freeze(fresh, value)
+			continue
+		}
+
+		if child.parent != n || !samePosition(child, pos) {
+			from := child.path().Pointer()
+			child.detach()
+			child.attach(n, pos)
+			p.ops = append(p.ops, Operation{Op: "move", From: from, Path: childPath.Pointer()})
+		}
+		child.placed = true
+		if isContainer(value) && reflect.TypeOf(value) == reflect.TypeOf(child.data) {
+			p.visit(child, value, childPath, childSource)
+		} else if !reflect.DeepEqual(value, child.data) {
+			p.ops = append(p.ops, Operation{Op: "replace", Path: childPath.Pointer(), Value: value})
+			child.object, child.array = nil, nil
+			p.freeze(child, value)
+		}
+	}
+}
+
+// freeze sets n to hold value, with every value under it placed.
+func (p *patcher) freeze(n *wnode, value any) {
+	n.data = value
+	n.placed = true
+	switch value := value.(type) {
+	case map[string]any:
+		n.object = make(map[string]*wnode, len(value))
+		for key, elem := range value {
+			child := &wnode{parent: n, key: key}
+			n.object[key] = child
+			p.freeze(child, elem)
+		}
+	case []any:
+		n.array = make([]*wnode, len(value))
+		for i, elem := range value {
+			child := &wnode{parent: n}
+			n.array[i] = child
+			p.freeze(child, elem)
+		}
+	}
+}
+
+// isAttached reports whether n is still part of the document.
+func (p *patcher) isAttached(n *wnode) bool {
+	for n.parent != nil {
+		n = n.parent
+	}
+	return n == p.root
+}
+
+// samePosition reports whether n is found at pos in its parent.
+func samePosition(n *wnode, pos any) bool {
+	if key, ok := pos.(string); ok {
+		return n.parent.array == nil && n.key == key
+	}
+	i := pos.(int)
+	return i < len(n.parent.array) && n.parent.array[i] == n
+}
@@ -0,0 +1,340 @@
+package jsondiff_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/jsondiff"
+)
+
+func (*S) TestPointer(c *C) {
+	c.Assert(jsondiff.Path{}.Pointer(), Equals, "")
+	c.Assert(jsondiff.Path{"a", 1, "b"}.Pointer(), Equals, "/a/1/b")
+	c.Assert(jsondiff.Path{"a/b", "c~d", ""}.Pointer(), Equals, "/a~1b/c~0d/")
+}
+
+func (*S) TestParsePointer(c *C) {
+	doc := decode(c, `{"a": [{"1": {"x.y": true}}], "b/c": {"d~e": [1, 2]}, "": 0}`)
+	tests := []struct {
+		pointer string
+		path    jsondiff.Path
+		err     string
+	}{
+		{pointer: "", path: jsondiff.Path{}},
+		{pointer: "/a/0/1/x.y", path: jsondiff.Path{"a", 0, "1", "x.y"}},
+		{pointer: "/b~1c/d~0e/1", path: jsondiff.Path{"b/c", "d~e", 1}},
+		{pointer: "/", path: jsondiff.Path{""}},
+		{pointer: "/a/1/2/k", path: jsondiff.Path{"a", 1, 2, "k"}},
+		{pointer: "/new/3", path: jsondiff.Path{"new", 3}},
+		{pointer: "/a/01", err: `invalid pointer "/a/01": invalid index "01"`},
+		{pointer: "/a/-", err: `invalid pointer "/a/-": invalid index "-"`},
+		{pointer: "/b~2", err: `invalid pointer "/b~2": invalid escape in "b~2"`},
+		{pointer: "a", err: `invalid pointer "a": must start with /`},
+	}
+	for _, test := range tests {
+		c.Logf("Pointer: %q", test.pointer)
+		path, err := jsondiff.ParsePointer(test.pointer, doc)
+		if test.err != "" {
+			c.Assert(err, ErrorMatches, regexp.QuoteMeta(test.err))
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(path, DeepEquals, test.path)
+		c.Assert(path.Pointer(), Equals, test.pointer)
+	}
+
+	// Keys made of digits need the document to tell them from indexes.
+	path, err := jsondiff.ParsePointer("/a/0/1", nil)
+	c.Assert(err, IsNil)
+	c.Assert(path, DeepEquals, jsondiff.Path{"a", 0, 1})
+}
+
+func (*S) TestParsePointerRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		a := randomValue(rnd, 3)
+		b := mutate(rnd, decode(c, encode(a)), 3)
+		c.Logf("Test: %s => %s", encode(a), encode(b))
+		for _, change := range jsondiff.Diff(a, b, nil) {
+			doc, path := b, change.Path
+			if change.Op == jsondiff.Remove {
+				doc = a
+			}
+			parsed, err := jsondiff.ParsePointer(path.Pointer(), doc)
+			c.Assert(err, IsNil)
+			c.Assert(parsed, DeepEquals, path)
+		}
+	}
+}
+
+func (*S) TestOperationJSON(c *C) {
+	ops := []jsondiff.Operation{
+		{Op: "add", Path: "/a", Value: nil},
+		{Op: "remove", Path: "/b"},
+		{Op: "replace", Path: "/c", Value: 1},
+		{Op: "move", From: "/d", Path: "/e"},
+	}
+	data, err := json.Marshal(ops)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, `[{"op":"add","path":"/a","value":null},{"op":"remove","path":"/b"},{"op":"replace","path":"/c","value":1},{"op":"move","from":"/d","path":"/e"}]`)
+}
+
+var patchTests = []struct {
+	summary string
+	a, b    string
+	patch   string
+}{{
+	summary: "Equal documents",
+	a:       `{"a": [1, 2]}`,
+	b:       `{"a": [1, 2]}`,
+	patch:   `null`,
+}, {
+	summary: "Root replaced",
+	a:       `{"a": 1}`,
+	b:       `[1]`,
+	patch:   `[{"op":"replace","path":"","value":[1]}]`,
+}, {
+	summary: "Values set, added and removed",
+	a:       `{"a": 1, "b": {"c": 2}, "d/e": 3}`,
+	b:       `{"a": 2, "b": {"c": 2, "x": null}}`,
+	patch:   `[{"op":"replace","path":"/a","value":2},{"op":"add","path":"/b/x","value":null},{"op":"remove","path":"/d~1e"}]`,
+}, {
+	summary: "Value moved",
+	a:       `{"a": {"x": 1, "y": 2}, "b": {}}`,
+	b:       `{"a": {"x": 1}, "b": {"y": 2}}`,
+	patch:   `[{"op":"move","from":"/a/y","path":"/b/y"}]`,
+}, {
+	summary: "Array element removed",
+	a:       `["a", "b", "c"]`,
+	b:       `["a", "c"]`,
+	patch:   `[{"op":"move","from":"/2","path":"/1"},{"op":"remove","path":"/2"}]`,
+}}
+
+func (*S) TestPatch(c *C) {
+	for _, test := range patchTests {
+		c.Logf("Summary: %s", test.summary)
+		a, b := decode(c, test.a), decode(c, test.b)
+		patch := jsondiff.Patch(a, b, jsondiff.Diff(a, b, nil))
+		c.Assert(encode(patch), Equals, test.patch)
+		c.Assert(encode(applyPatch(c, decode(c, test.a), patch)), Equals, encode(b))
+	}
+}
+
+func (*S) TestPatchRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		a := randomValue(rnd, 3)
+		b := mutate(rnd, decode(c, encode(a)), 3)
+		c.Logf("Test: %s => %s", encode(a), encode(b))
+		patch := jsondiff.Patch(a, b, jsondiff.Diff(a, b, nil))
+		c.Logf("Patch: %s", encode(patch))
+		c.Assert(encode(applyPatch(c, decode(c, encode(a)), patch)), Equals, encode(b))
+	}
+}
+
+// randomValue returns a random document with values nested up to depth.
+func randomValue(rnd *rand.Rand, depth int) any {
+	kind := rnd.Intn(6)
+	if depth == 0 {
+		kind = rnd.Intn(3)
+	}
+	switch kind {
+	case 0:
+		return float64(rnd.Intn(4))
+	case 1:
+		return "abc"[rnd.Intn(3):]
+	case 2:
+		return nil
+	case 3, 4:
+		m := make(map[string]any)
+		for i := rnd.Intn(4); i > 0; i-- {
+			m["abcd"[rnd.Intn(4):][:1]] = randomValue(rnd, depth-1)
+		}
+		return m
+	}
+	var l []any
+	for i := rnd.Intn(4); i > 0; i-- {
+		l = append(l, randomValue(rnd, depth-1))
+	}
+	if l == nil {
+		l = []any{}
+	}
+	return l
+}
+
+// mutate returns value with random changes, moving some of its values
+// elsewhere within it.
+func mutate(rnd *rand.Rand, value any, depth int) any {
+	switch value := value.(type) {
+	case map[string]any:
+		var keys []string
+		for key := range value {
+			keys = append(keys, key)
+		}
+		for _, key := range keys {
+			switch rnd.Intn(6) {
+			case 0:
+				delete(value, key)
+			case 1:
+				value["abcde"[rnd.Intn(5):][:1]] = value[key]
+				delete(value, key)
+			case 2:
+				value[key] = randomValue(rnd, depth-1)
+			default:
+				value[key] = mutate(rnd, value[key], depth-1)
+			}
+		}
+		if rnd.Intn(3) == 0 {
+			value["e"] = randomValue(rnd, depth-1)
+		}
+		return value
+	case []any:
+		var result []any
+		for _, elem := range value {
+			switch rnd.Intn(6) {
+			case 0:
+			case 1:
+				result = append([]any{elem}, result...)
+			case 2:
+				result = append(result, randomValue(rnd, depth-1), elem)
+			default:
+				result = append(result, mutate(rnd, elem, depth-1))
+			}
+		}
+		if result == nil {
+			result = []any{}
+		}
+		return result
+	}
+	if rnd.Intn(3) == 0 {
+		return randomValue(rnd, depth)
+	}
+	return value
+}
+
+// applyPatch applies a JSON Patch document to doc, as defined by RFC 6902.
+func applyPatch(c *C, doc any, patch []jsondiff.Operation) any {
+	for _, op := range patch {
+		switch op.Op {
+		case "add", "replace":
+			if op.Op == "replace" {
+				doc = pointerRemove(c, doc, op.Path)
+			}
+			doc = pointerAdd(c, doc, op.Path, decode(c, encode(op.Value)))
+		case "remove":
+			doc = pointerRemove(c, doc, op.Path)
+		case "move":
+			value := pointerGet(c, doc, op.From)
+			doc = pointerRemove(c, doc, op.From)
+			doc = pointerAdd(c, doc, op.Path, value)
+		default:
+			c.Fatalf("unsupported operation %q", op.Op)
+		}
+	}
+	return doc
+}
+
+func pointerParent(c *C, doc any, pointer string) (parent any, last string) {
+	c.Assert(strings.HasPrefix(pointer, "/"), Equals, true, Commentf("pointer %q", pointer))
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	parent = doc
+	for _, token := range tokens[:len(tokens)-1] {
+		parent = child(c, parent, token)
+	}
+	return parent, tokens[len(tokens)-1]
+}
+
+func child(c *C, value any, token string) any {
+	switch value := value.(type) {
+	case map[string]any:
+		child, ok := value[token]
+		c.Assert(ok, Equals, true, Commentf("missing key %q", token))
+		return child
+	case []any:
+		i, err := strconv.Atoi(token)
+		c.Assert(err, IsNil)
+		c.Assert(i < len(value), Equals, true, Commentf("index %d out of range", i))
+		return value[i]
+	}
+	c.Fatalf("cannot index %v with %q", value, token)
+	return nil
+}
+
+func pointerGet(c *C, doc any, pointer string) any {
+	if pointer == "" {
+		return doc
+	}
+	parent, last := pointerParent(c, doc, pointer)
+	return child(c, parent, last)
+}
+
+// pointerAdd and pointerRemove return the modified document, and change
+// arrays in place through their parent so that the change is visible.
+func pointerAdd(c *C, doc any, pointer string, value any) any {
+	if pointer == "" {
+		return value
+	}
+	parent, last := pointerParent(c, doc, pointer)
+	switch p := parent.(type) {
+	case map[string]any:
+		p[last] = value
+		return doc
+	case []any:
+		i, err := strconv.Atoi(last)
+		c.Assert(err, IsNil)
+		c.Assert(i <= len(p), Equals, true)
+		p = append(p[:i:i], append([]any{value}, p[i:]...)...)
+		return replaceAt(c, doc, pointer[:strings.LastIndex(pointer, "/")], p)
+	}
+	c.Fatalf("cannot add to %v", parent)
+	return nil
+}
+
+func pointerRemove(c *C, doc any, pointer string) any {
+	if pointer == "" {
+		return nil
+	}
+	parent, last := pointerParent(c, doc, pointer)
+	switch p := parent.(type) {
+	case map[string]any:
+		_, ok := p[last]
+		c.Assert(ok, Equals, true, Commentf("missing key %q", last))
+		delete(p, last)
+		return doc
+	case []any:
+		i, err := strconv.Atoi(last)
+		c.Assert(err, IsNil)
+		c.Assert(i < len(p), Equals, true)
+		p = append(p[:i:i], p[i+1:]...)
+		return replaceAt(c, doc, pointer[:strings.LastIndex(pointer, "/")], p)
+	}
+	c.Fatalf("cannot remove from %v", parent)
+	return nil
+}
+
+func replaceAt(c *C, doc any, pointer string, value any) any {
+	if pointer == "" {
+		return value
+	}
+	parent, last := pointerParent(c, doc, pointer)
+	switch p := parent.(type) {
+	case map[string]any:
+		p[last] = value
+	case []any:
+		i, err := strconv.Atoi(last)
+		c.Assert(err, IsNil)
+		p[i] = value
+	default:
+		panic(fmt.Sprintf("cannot set %q", pointer))
+	}
+	return doc
+}
@@ -0,0 +1,155 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondiff
+
+import (
+	"fmt"
+	pathpkg "path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Pattern matches paths.
+type Pattern struct {
+	text  string
+	regex *regexp.Regexp
+	elems []patternElem
+}
+
+// patternElem matches a key, or an index if index is set, with a glob as
+// supported by path.Match. The elem "**" matches any number of elements.
+type patternElem struct {
+	glob  string
+	index bool
+}
+
+// ParsePattern parses a pattern matching paths.
+//
+// Patterns in the form "/regexp/" match the paths whose string form, as
+// returned by Path.String, fully matches the regular expression. Any other
+// pattern is written as the paths it matches, such as ".a.b[0]" or "." for
+// the root, except that keys and indexes may be globs as supported by the
+// path package, such as ".*" for any key or "[*]" for any index, and that
+// ".**" matches any number of keys and indexes.
+func ParsePattern(text string) (*Pattern, error) {
+	p := &Pattern{text: text}
+	if len(text) >= 2 && strings.HasPrefix(text, "/") && strings.HasSuffix(text, "/") {
+		regex, err := regexp.Compile("^(?:" + text[1:len(text)-1] + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", text, err)
+		}
+		p.regex = regex
+		return p, nil
+	}
+	if !strings.HasPrefix(text, ".") {
+		return nil, fmt.Errorf("invalid pattern %q: must start with . or /", text)
+	}
+	rest := text
+	if rest == "." {
+		rest = ""
+	} else if strings.HasPrefix(rest, ".[") {
+		rest = rest[1:]
+	}
+	for rest != "" {
+		var elem patternElem
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[") + 1
+			if end == 0 {
+				end = len(rest)
+			}
+			elem.glob = rest[1:end]
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid pattern %q: missing ]", text)
+			}
+			elem.glob, elem.index = rest[1:end], true
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid pattern %q: unexpected %q", text, rest[0])
+		}
+		if elem.glob == "" {
+			return nil, fmt.Errorf("invalid pattern %q: empty key or index", text)
+		}
+		if _, err := pathpkg.Match(elem.glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", text, err)
+		}
+		p.elems = append(p.elems, elem)
+	}
+	return p, nil
+}
+
+// MustParsePattern is like ParsePattern but panics if the pattern is
+// invalid.
+func MustParsePattern(text string) *Pattern {
+	p, err := ParsePattern(text)
+	if err != nil {
+		panic("jsondiff: " + err.Error())
+	}
+	return p
+}
+
+// String returns the pattern as parsed.
+func (p *Pattern) String() string {
+	return p.text
+}
+
+// Match reports whether the pattern matches path.
+func (p *Pattern) Match(path Path) bool {
+	if p.regex != nil {
+		return p.regex.MatchString(path.String())
+	}
+	return matchElems(p.elems, path)
+}
+
+func matchElems(elems []patternElem, path Path) bool {
+	if len(elems) == 0 {
+		return len(path) == 0
+	}
+	if !elems[0].index && elems[0].glob == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchElems(elems[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	var name string
+	switch elem := path[0].(type) {
+	case string:
+		if elems[0].index {
+			return false
+		}
+		name = elem
+	case int:
+		if !elems[0].index {
+			return false
+		}
+		name = strconv.Itoa(elem)
+	default:
+		return false
+	}
+	if ok, _ := pathpkg.Match(elems[0].glob, name); !ok {
+		return false
+	}
+	return matchElems(elems[1:], path[1:])
+}
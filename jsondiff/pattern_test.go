@@ -0,0 +1,77 @@
+package jsondiff_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/jsondiff"
+)
+
+var patternTests = []struct {
+	pattern string
+	matches []jsondiff.Path
+	misses  []jsondiff.Path
+	error   string
+}{{
+	pattern: ".",
+	matches: []jsondiff.Path{{}},
+	misses:  []jsondiff.Path{{"a"}, {0}},
+}, {
+	pattern: ".metadata.timestamp",
+	matches: []jsondiff.Path{{"metadata", "timestamp"}},
+	misses:  []jsondiff.Path{{"metadata"}, {"metadata", "timestamp", "x"}, {"timestamp"}},
+}, {
+	pattern: ".*.lastSeen",
+	matches: []jsondiff.Path{{"a", "lastSeen"}, {"b", "lastSeen"}},
+	misses:  []jsondiff.Path{{0, "lastSeen"}, {"lastSeen"}, {"a", "b", "lastSeen"}},
+}, {
+	pattern: ".items[*].id",
+	matches: []jsondiff.Path{{"items", 0, "id"}, {"items", 12, "id"}},
+	misses:  []jsondiff.Path{{"items", "x", "id"}, {"items", "id"}},
+}, {
+	pattern: ".[1?]",
+	matches: []jsondiff.Path{{10}, {19}},
+	misses:  []jsondiff.Path{{1}, {100}, {"10"}},
+}, {
+	pattern: ".**.time*",
+	matches: []jsondiff.Path{{"time"}, {"a", 1, "timestamp"}},
+	misses:  []jsondiff.Path{{"a"}, {"time", "a"}},
+}, {
+	pattern: "/\\.status(\\..*)?/",
+	matches: []jsondiff.Path{{"status"}, {"status", "a", 1}},
+	misses:  []jsondiff.Path{{"statusCode"}, {"a", "status"}},
+}, {
+	pattern: "a.b",
+	error:   `invalid pattern "a.b": must start with . or /`,
+}, {
+	pattern: ".a[0",
+	error:   `invalid pattern ".a\[0": missing \]`,
+}, {
+	pattern: ".a..b",
+	error:   `invalid pattern ".a..b": empty key or index`,
+}, {
+	pattern: ".a[[]",
+	error:   `invalid pattern ".a\[\[\]": syntax error in pattern`,
+}, {
+	pattern: "/(/",
+	error:   `invalid pattern "/\(/": error parsing regexp: .*`,
+}}
+
+func (*S) TestPattern(c *C) {
+	for _, test := range patternTests {
+		c.Logf("Test: %s", test.pattern)
+		pattern, err := jsondiff.ParsePattern(test.pattern)
+		if test.error != "" {
+			c.Assert(err, ErrorMatches, test.error)
+			c.Assert(func() { jsondiff.MustParsePattern(test.pattern) }, PanicMatches, "jsondiff: "+test.error)
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(pattern.String(), Equals, test.pattern)
+		for _, path := range test.matches {
+			c.Assert(pattern.Match(path), Equals, true, Commentf("path %s", path))
+		}
+		for _, path := range test.misses {
+			c.Assert(pattern.Match(path), Equals, false, Commentf("path %s", path))
+		}
+	}
+}
@@ -0,0 +1,99 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondiff
+
+import (
+	"fmt"
+)
+
+// Stats summarizes the changes between two documents.
+type Stats struct {
+	// Added, Removed, Changed, and Moved are the number of changes of
+	// each kind, with values set counted as changed.
+	Added   int
+	Removed int
+	Changed int
+	Moved   int
+
+	// Nodes is the number of values in both documents, counting objects
+	// and arrays as well as the values in them, but not values ignored.
+	Nodes int
+
+	// Similarity is the fraction of those values left alone by the
+	// changes, from 0 to 1. Values added, removed, or set count as changed
+	// along with the values in them, while values moved count as changed
+	// on their own, in both documents.
+	Similarity float64
+}
+
+// String returns the stats in the form "1 added, 2 removed, 0 changed,
+// 0 moved in 40 values (92.5% similar)".
+func (s Stats) String() string {
+	return fmt.Sprintf("%d added, %d removed, %d changed, %d moved in %d values (%.1f%% similar)",
+		s.Added, s.Removed, s.Changed, s.Moved, s.Nodes, s.Similarity*100)
+}
+
+// Summarize returns the stats of the changes returned by Diff for
+// documents a and b with the given options, which may be nil.
+func Summarize(a, b any, changes []Change, options *Options) Stats {
+	if options == nil {
+		options = &Options{}
+	}
+	d := &differ{options: options}
+	stats := Stats{Nodes: d.count(a, Path{}) + d.count(b, Path{})}
+	changed := 0
+	for _, change := range changes {
+		switch change.Op {
+		case Add:
+			stats.Added++
+			changed += d.count(change.New, change.Path)
+		case Remove:
+			stats.Removed++
+			changed += d.count(change.Old, change.Path)
+		case Set:
+			stats.Changed++
+			changed += d.count(change.Old, change.Path) + d.count(change.New, change.Path)
+		case Move:
+			stats.Moved++
+			changed += 2
+		}
+	}
+	stats.Similarity = 1
+	if stats.Nodes > 0 {
+		stats.Similarity = max(0, 1-float64(changed)/float64(stats.Nodes))
+	}
+	return stats
+}
+
+// count returns the number of values in value, which is found at path,
+// including itself but not the values ignored.
+func (d *differ) count(value any, path Path) int {
+	if d.ignored(path) {
+		return 0
+	}
+	n := 1
+	switch value := value.(type) {
+	case map[string]any:
+		for key, member := range value {
+			n += d.count(member, append(path[:len(path):len(path)], key))
+		}
+	case []any:
+		for i, elem := range value {
+			n += d.count(elem, append(path[:len(path):len(path)], i))
+		}
+	}
+	return n
+}
@@ -0,0 +1,64 @@
+package jsondiff_test
+
+import (
+	"math"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/jsondiff"
+)
+
+var summarizeTests = []struct {
+	summary string
+	a, b    string
+	options *jsondiff.Options
+	stats   jsondiff.Stats
+}{{
+	summary: "Equal documents",
+	a:       `{"a": [1, 2]}`,
+	b:       `{"a": [1, 2]}`,
+	stats:   jsondiff.Stats{Nodes: 8, Similarity: 1},
+}, {
+	summary: "Scalar set",
+	a:       `{"a": 1, "b": 2}`,
+	b:       `{"a": 1, "b": 3}`,
+	stats:   jsondiff.Stats{Changed: 1, Nodes: 6, Similarity: 4.0 / 6},
+}, {
+	summary: "Object added with its members",
+	a:       `{"a": 1}`,
+	b:       `{"a": 1, "b": {"c": 2, "d": 3}}`,
+	stats:   jsondiff.Stats{Added: 1, Nodes: 7, Similarity: 4.0 / 7},
+}, {
+	summary: "Members removed and moved",
+	a:       `{"a": [1, 2, 3], "b": 4, "c": "moved"}`,
+	b:       `{"a": [1, 2], "d": "moved"}`,
+	stats:   jsondiff.Stats{Removed: 2, Moved: 1, Nodes: 12, Similarity: 8.0 / 12},
+}, {
+	summary: "Ignored values are not counted",
+	a:       `{"a": 1, "meta": {"x": 1, "y": 2}}`,
+	b:       `{"a": 2, "meta": {"x": 3}}`,
+	options: &jsondiff.Options{Ignore: []*jsondiff.Pattern{jsondiff.MustParsePattern(".meta")}},
+	stats:   jsondiff.Stats{Changed: 1, Nodes: 4, Similarity: 2.0 / 4},
+}, {
+	summary: "Different roots",
+	a:       `[1, 2]`,
+	b:       `"x"`,
+	stats:   jsondiff.Stats{Changed: 1, Nodes: 4, Similarity: 0},
+}}
+
+func (*S) TestSummarize(c *C) {
+	for _, test := range summarizeTests {
+		c.Logf("Summary: %s", test.summary)
+		a, b := decode(c, test.a), decode(c, test.b)
+		changes := jsondiff.Diff(a, b, test.options)
+		stats := jsondiff.Summarize(a, b, changes, test.options)
+		c.Assert(math.Abs(stats.Similarity-test.stats.Similarity) < 1e-9, Equals, true, Commentf("similarity %v", stats.Similarity))
+		stats.Similarity = test.stats.Similarity
+		c.Assert(stats, DeepEquals, test.stats)
+	}
+}
+
+func (*S) TestStatsString(c *C) {
+	stats := jsondiff.Stats{Added: 1, Removed: 2, Nodes: 40, Similarity: 0.925}
+	c.Assert(stats.String(), Equals, "1 added, 2 removed, 0 changed, 0 moved in 40 values (92.5% similar)")
+}
@@ -0,0 +1,340 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondiff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// StreamOptions holds the options for DiffStream.
+type StreamOptions struct {
+	Options
+
+	// Depth is the depth of the values diffed independently of each other,
+	// which is one for the members or elements of the root, two for those
+	// under them, and so on. It defaults to one.
+	Depth int
+}
+
+// DiffStream calls emit with the changes turning the JSON document read
+// from a into the one read from b, reading both as they are compared
+// rather than decoding them first, so that documents much larger than
+// those Diff can handle may be compared.
+//
+// Objects and arrays above the given depth are read member by member and
+// element by element, and members are paired by key and elements by index.
+// The values at the given depth are decoded and diffed independently of
+// each other with Diff, so values moved from one of them to another are
+// reported as removed and added, and only one pair of them is held in
+// memory at a time, along with the members found in a different order in
+// both documents until their counterpart is found. Arrays are decoded and
+// diffed as a whole wherever they are if the options compare them
+// regardless of order, and so are those with ArrayKeys.
+//
+// The changes are the same as those Diff would report at the given depth,
+// except for their order: the changes found under each pair of values are
+// emitted together, in the order of the values in b, followed by the values
+// added and removed from objects, sorted by key. DiffStream stops and
+// returns the error if emit returns one, and returns an error if either
+// document can't be decoded. The options may be nil.
+func DiffStream(a, b io.Reader, options *StreamOptions, emit func(change Change) error) error {
+	if options == nil {
+		options = &StreamOptions{}
+	}
+	s := &streamer{
+		a:     json.NewDecoder(a),
+		b:     json.NewDecoder(b),
+		d:     &differ{options: &options.Options},
+		depth: max(options.Depth, 1),
+		emit:  emit,
+	}
+	if options.NumericEqual {
+		s.a.UseNumber()
+		s.b.UseNumber()
+	}
+	if err := s.value(Path{}, 0); err != nil {
+		return err
+	}
+	for _, dec := range []*json.Decoder{s.a, s.b} {
+		if _, err := dec.Token(); err != io.EOF {
+			if err != nil {
+				return err
+			}
+			return errors.New("unexpected data after the document")
+		}
+	}
+	return nil
+}
+
+type streamer struct {
+	a, b  *json.Decoder
+	d     *differ
+	depth int
+	emit  func(change Change) error
+}
+
+// value compares the next values of both documents, at path and depth.
+func (s *streamer) value(path Path, depth int) error {
+	if depth >= s.depth || s.d.ignored(path) {
+		var va, vb any
+		if err := s.a.Decode(&va); err != nil {
+			return err
+		}
+		if err := s.b.Decode(&vb); err != nil {
+			return err
+		}
+		return s.diff(va, vb, path)
+	}
+	ta, err := s.a.Token()
+	if err != nil {
+		return err
+	}
+	tb, err := s.b.Token()
+	if err != nil {
+		return err
+	}
+	_, keyed := s.d.options.ArrayKeys[path.String()]
+	switch {
+	case ta == json.Delim('{') && tb == json.Delim('{'):
+		return s.object(path, depth)
+	case ta == json.Delim('[') && tb == json.Delim('[') && !s.d.options.IgnoreOrder && !keyed:
+		return s.array(path, depth)
+	}
+	va, err := readRest(s.a, ta)
+	if err != nil {
+		return err
+	}
+	vb, err := readRest(s.b, tb)
+	if err != nil {
+		return err
+	}
+	return s.diff(va, vb, path)
+}
+
+// object compares the members of the next objects of both documents, at
+// path and depth, after their opening delimiters were read.
+func (s *streamer) object(path Path, depth int) error {
+	pendingA := make(map[string]any)
+	pendingB := make(map[string]any)
+	for {
+		moreA, moreB := s.a.More(), s.b.More()
+		if !moreA && !moreB {
+			break
+		}
+		var ka, kb string
+		var err error
+		if moreA {
+			if ka, err = readKey(s.a); err != nil {
+				return err
+			}
+		}
+		if moreB {
+			if kb, err = readKey(s.b); err != nil {
+				return err
+			}
+		}
+		if moreA && moreB && ka == kb {
+			if err := s.value(append(path[:len(path):len(path)], ka), depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+		// The members are in a different order, so they are held until
+		// their counterpart is found.
+		if moreA {
+			if err := s.pending(path, ka, s.a, pendingA, pendingB, false); err != nil {
+				return err
+			}
+		}
+		if moreB {
+			if err := s.pending(path, kb, s.b, pendingB, pendingA, true); err != nil {
+				return err
+			}
+		}
+	}
+	for _, dec := range []*json.Decoder{s.a, s.b} {
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range sortedKeys(pendingB) {
+		if s.d.options.NullAbsent && pendingB[key] == nil {
+			continue
+		}
+		if err := s.added(append(path[:len(path):len(path)], key), pendingB[key]); err != nil {
+			return err
+		}
+	}
+	for _, key := range sortedKeys(pendingA) {
+		if s.d.options.NullAbsent && pendingA[key] == nil {
+			continue
+		}
+		if err := s.removed(append(path[:len(path):len(path)], key), pendingA[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pending reads the value of the member with the given key from dec, and
+// diffs it with its counterpart in others if it was found already, or adds
+// it to values otherwise. The value is from b if fromB is set.
+func (s *streamer) pending(path Path, key string, dec *json.Decoder, values, others map[string]any, fromB bool) error {
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return err
+	}
+	other, ok := others[key]
+	if !ok {
+		values[key] = value
+		return nil
+	}
+	delete(others, key)
+	if fromB {
+		return s.diff(other, value, append(path[:len(path):len(path)], key))
+	}
+	return s.diff(value, other, append(path[:len(path):len(path)], key))
+}
+
+// array compares the elements of the next arrays of both documents, at
+// path and depth, after their opening delimiters were read.
+func (s *streamer) array(path Path, depth int) error {
+	var removals []Change
+	for i := 0; ; i++ {
+		moreA, moreB := s.a.More(), s.b.More()
+		elemPath := append(path[:len(path):len(path)], i)
+		switch {
+		case moreA && moreB:
+			if err := s.value(elemPath, depth+1); err != nil {
+				return err
+			}
+			continue
+		case moreA:
+			var value any
+			if err := s.a.Decode(&value); err != nil {
+				return err
+			}
+			if !s.d.ignored(elemPath) {
+				removals = append(removals, Change{Op: Remove, Path: elemPath, Old: value})
+			}
+			continue
+		case moreB:
+			var value any
+			if err := s.b.Decode(&value); err != nil {
+				return err
+			}
+			if err := s.added(elemPath, value); err != nil {
+				return err
+			}
+			continue
+		}
+		break
+	}
+	for _, dec := range []*json.Decoder{s.a, s.b} {
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+	for _, change := range removals {
+		if err := s.emit(change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *streamer) diff(a, b any, path Path) error {
+	for _, change := range s.d.diff(a, b, path) {
+		if err := s.emit(change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *streamer) added(path Path, value any) error {
+	if s.d.ignored(path) {
+		return nil
+	}
+	return s.emit(Change{Op: Add, Path: path, New: value})
+}
+
+func (s *streamer) removed(path Path, value any) error {
+	if s.d.ignored(path) {
+		return nil
+	}
+	return s.emit(Change{Op: Remove, Path: path, Old: value})
+}
+
+func readKey(dec *json.Decoder) (string, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return "", err
+	}
+	key, ok := token.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid object key %v", token)
+	}
+	return key, nil
+}
+
+// readRest returns the value starting with token, reading the rest of it
+// from dec.
+func readRest(dec *json.Decoder, token json.Token) (any, error) {
+	switch token {
+	case json.Delim('{'):
+		obj := make(map[string]any)
+		for dec.More() {
+			key, err := readKey(dec)
+			if err != nil {
+				return nil, err
+			}
+			var value any
+			if err := dec.Decode(&value); err != nil {
+				return nil, err
+			}
+			obj[key] = value
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []any{}
+		for dec.More() {
+			var value any
+			if err := dec.Decode(&value); err != nil {
+				return nil, err
+			}
+			arr = append(arr, value)
+		}
+		_, err := dec.Token()
+		return arr, err
+	}
+	return token, nil
+}
+
+func sortedKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,149 @@
+package jsondiff_test
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/jsondiff"
+)
+
+func diffStream(a, b string, options *jsondiff.StreamOptions) ([]jsondiff.Change, error) {
+	var changes []jsondiff.Change
+	err := jsondiff.DiffStream(strings.NewReader(a), strings.NewReader(b), options, func(change jsondiff.Change) error {
+		changes = append(changes, change)
+		return nil
+	})
+	return changes, err
+}
+
+var streamTests = []struct {
+	summary string
+	options *jsondiff.StreamOptions
+	a, b    string
+	changes []string
+}{{
+	summary: "Equal documents",
+	a:       `{"a": 1, "b": [1, 2, {"c": true}]}`,
+	b:       `{"a": 1, "b": [1, 2, {"c": true}]}`,
+}, {
+	summary: "Members in the same order",
+	a:       `{"a": 1, "b": {"x": 1, "y": 2}, "c": [1, 2]}`,
+	b:       `{"a": 2, "b": {"x": 1, "z": 2}, "c": [1, 2]}`,
+	changes: []string{`set .a 1 => 2`, `move .b.y => .b.z 2 => 2`},
+}, {
+	summary: "Members in a different order",
+	a:       `{"a": 1, "b": 2, "c": 3, "d": 4}`,
+	b:       `{"d": 4, "b": 3, "e": 5, "a": 1}`,
+	changes: []string{`set .b 2 => 3`, `add .e 5`, `remove .c 3`},
+}, {
+	summary: "Values moved across subtrees",
+	a:       `{"a": {"x": 1}, "b": {}}`,
+	b:       `{"a": {}, "b": {"x": 1}}`,
+	changes: []string{`remove .a.x 1`, `add .b.x 1`},
+}, {
+	summary: "Arrays at the root",
+	a:       `[1, {"a": 1}, 3, 4]`,
+	b:       `[1, {"a": 2}]`,
+	changes: []string{`set .[1].a 1 => 2`, `remove .[2] 3`, `remove .[3] 4`},
+}, {
+	summary: "Deeper values",
+	options: &jsondiff.StreamOptions{Depth: 2},
+	a:       `{"a": {"x": [1, 2], "y": {"z": 1}}, "b": [[1], [2]]}`,
+	b:       `{"a": {"y": {"z": 2}, "x": [2]}, "b": [[1], [3], [4]]}`,
+	changes: []string{`set .a.y.z 1 => 2`, `move .a.x[1] => .a.x[0] 2 => 2`, `remove .a.x[0] 1`, `set .b[1][0] 2 => 3`, `add .b[2] [4]`},
+}, {
+	summary: "Documents of different types",
+	a:       `{"a": 1}`,
+	b:       `[1]`,
+	changes: []string{`set . {"a":1} => [1]`},
+}, {
+	summary: "Options",
+	options: &jsondiff.StreamOptions{Options: jsondiff.Options{
+		Ignore:    []*jsondiff.Pattern{jsondiff.MustParsePattern(".*.ts"), jsondiff.MustParsePattern(".skip")},
+		Tolerance: 0.1,
+	}},
+	a:       `{"a": {"ts": 1, "v": 1.0}, "skip": 1, "c": {"ts": 1}}`,
+	b:       `{"c": {"ts": 2}, "a": {"ts": 2, "v": 1.05}, "d": 1}`,
+	changes: []string{`add .d 1`},
+}, {
+	summary: "Null members are the same as missing members",
+	options: &jsondiff.StreamOptions{Options: jsondiff.Options{NullAbsent: true, IgnoreCase: true}},
+	a:       `{"a": null, "b": 1, "c": null, "d": "X"}`,
+	b:       `{"b": null, "e": null, "c": 2, "d": "x"}`,
+	changes: []string{`remove .b 1`, `add .c 2`},
+}, {
+	summary: "Numbers compared by value",
+	options: &jsondiff.StreamOptions{Options: jsondiff.Options{NumericEqual: true}},
+	a:       `{"a": 1.0, "b": [12345678901234567890]}`,
+	b:       `{"a": 1, "b": [12345678901234567891]}`,
+	changes: []string{`set .b[0] 12345678901234567890 => 12345678901234567891`},
+}}
+
+func (*S) TestDiffStream(c *C) {
+	for _, test := range streamTests {
+		c.Logf("Summary: %s", test.summary)
+		changes, err := diffStream(test.a, test.b, test.options)
+		c.Assert(err, IsNil)
+		var strs []string
+		for _, change := range changes {
+			strs = append(strs, changeString(change))
+		}
+		c.Assert(strs, DeepEquals, test.changes)
+	}
+}
+
+func (*S) TestDiffStreamRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		a := randomValue(rnd, 3)
+		b := mutate(rnd, decode(c, encode(a)), 3)
+		depth := rnd.Intn(4)
+		c.Logf("Test: %s => %s at depth %d", encode(a), encode(b), depth)
+		changes, err := diffStream(encode(a), encode(b), &jsondiff.StreamOptions{Depth: depth})
+		c.Assert(err, IsNil)
+		result, err := jsondiff.Apply(a, changes)
+		c.Assert(err, IsNil)
+		c.Assert(encode(result), Equals, encode(b))
+	}
+}
+
+var streamErrorTests = []struct {
+	summary string
+	a, b    string
+	error   string
+}{{
+	summary: "Invalid document",
+	a:       `{"a": 1}`,
+	b:       `{"a": }`,
+	error:   `invalid character '}' looking for beginning of value`,
+}, {
+	summary: "Truncated document",
+	a:       `{"a": [1, 2`,
+	b:       `{"a": [1, 2]}`,
+	error:   `unexpected EOF`,
+}, {
+	summary: "Data after the document",
+	a:       `{"a": 1} {}`,
+	b:       `{"a": 1}`,
+	error:   `unexpected data after the document`,
+}}
+
+func (*S) TestDiffStreamErrors(c *C) {
+	for _, test := range streamErrorTests {
+		c.Logf("Summary: %s", test.summary)
+		_, err := diffStream(test.a, test.b, nil)
+		c.Assert(err, ErrorMatches, test.error)
+	}
+
+	emitErr := errors.New("stop")
+	calls := 0
+	err := jsondiff.DiffStream(strings.NewReader(`[1, 2]`), strings.NewReader(`[3, 4]`), nil, func(jsondiff.Change) error {
+		calls++
+		return emitErr
+	})
+	c.Assert(err, Equals, emitErr)
+	c.Assert(calls, Equals, 1)
+}
@@ -0,0 +1,228 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+// Conflict is a value changed in incompatible ways by both sides of a
+// three-way merge.
+type Conflict struct {
+	// Path is the location of the value in the merged document.
+	Path Path
+
+	// Base, Ours, and Theirs are the value in each of the documents.
+	Base   any
+	Ours   any
+	Theirs any
+
+	// HasBase, HasOurs, and HasTheirs report whether the documents hold
+	// the value at all, which tells values removed apart from null.
+	HasBase   bool
+	HasOurs   bool
+	HasTheirs bool
+}
+
+// Merge returns the document holding the changes made to document base by
+// both ours and theirs, along with the conflicts between those changes.
+//
+// Object members are merged by key, and array elements are paired across
+// the documents with the listdist package, so that elements inserted,
+// removed, or changed on one side are merged with the changes made to the
+// other elements on the other side. Values changed on both sides are
+// merged recursively if they are objects or arrays on all sides, and are
+// equal or in conflict otherwise. Elements inserted by both sides at the
+// same place of an array are in conflict unless they are equal, and the
+// conflict holds arrays with the elements inserted by each side.
+//
+// The merged document holds the value from ours wherever there is a
+// conflict. Merge returns an error if the documents hold values of types
+// that aren't produced by encoding/json.
+func Merge(base, ours, theirs any) (merged any, conflicts []Conflict, err error) {
+	for _, doc := range []any{base, ours, theirs} {
+		if err := checkTypes(doc, Path{}); err != nil {
+			return nil, nil, err
+		}
+	}
+	m := &merger{}
+	merged, _ = m.merge(Path{}, base, ours, theirs, true, true, true)
+	return merged, m.conflicts, nil
+}
+
+// checkTypes returns an error if value or a value under it has a type
+// that isn't produced by encoding/json.
+func checkTypes(value any, path Path) error {
+	switch value := value.(type) {
+	case nil, bool, float64, json.Number, string:
+	case map[string]any:
+		for key, elem := range value {
+			if err := checkTypes(elem, append(path[:len(path):len(path)], key)); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, elem := range value {
+			if err := checkTypes(elem, append(path[:len(path):len(path)], i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported value of type %T at %s", value, path)
+	}
+	return nil
+}
+
+type merger struct {
+	conflicts []Conflict
+}
+
+// merge returns the merged value at path, and whether there is a value
+// there at all, given the values in each document and whether they hold
+// one.
+func (m *merger) merge(path Path, base, ours, theirs any, hasBase, hasOurs, hasTheirs bool) (any, bool) {
+	switch {
+	case hasOurs == hasTheirs && (!hasOurs || reflect.DeepEqual(ours, theirs)):
+		return ours, hasOurs
+	case hasBase == hasOurs && (!hasBase || reflect.DeepEqual(base, ours)):
+		return theirs, hasTheirs
+	case hasBase == hasTheirs && (!hasBase || reflect.DeepEqual(base, theirs)):
+		return ours, hasOurs
+	}
+	if hasOurs && hasTheirs {
+		switch ours := ours.(type) {
+		case map[string]any:
+			theirs, ok := theirs.(map[string]any)
+			base, baseOK := base.(map[string]any)
+			if ok && (baseOK || !hasBase) {
+				return m.mergeObjects(path, base, ours, theirs), true
+			}
+		case []any:
+			theirs, ok := theirs.([]any)
+			base, baseOK := base.([]any)
+			if ok && baseOK {
+				return m.mergeArrays(path, base, ours, theirs), true
+			}
+		}
+	}
+	m.conflicts = append(m.conflicts, Conflict{
+		Path:      path,
+		Base:      base,
+		Ours:      ours,
+		Theirs:    theirs,
+		HasBase:   hasBase,
+		HasOurs:   hasOurs,
+		HasTheirs: hasTheirs,
+	})
+	return ours, hasOurs
+}
+
+func (m *merger) mergeObjects(path Path, base, ours, theirs map[string]any) map[string]any {
+	var keys []string
+	for _, obj := range []map[string]any{base, ours, theirs} {
+		for key := range obj {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	merged := make(map[string]any)
+	for i, key := range keys {
+		if i > 0 && key == keys[i-1] {
+			continue
+		}
+		b, hasBase := base[key]
+		o, hasOurs := ours[key]
+		t, hasTheirs := theirs[key]
+		value, ok := m.merge(append(path[:len(path):len(path)], key), b, o, t, hasBase, hasOurs, hasTheirs)
+		if ok {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// arrayEdit is what happened to the elements of an array in a side of a
+// merge, relative to the array in the base document.
+type arrayEdit struct {
+	// elems holds for every element of the base array the element it
+	// became, if it wasn't deleted.
+	elems   []any
+	deleted []bool
+
+	// inserted holds for every position of the base array, including its
+	// end, the elements inserted before the element at that position.
+	inserted [][]any
+}
+
+func editArray(base, side []any) *arrayEdit {
+	e := &arrayEdit{
+		elems:    make([]any, len(base)),
+		deleted:  make([]bool, len(base)),
+		inserted: make([][]any, len(base)+1),
+	}
+	pos := 0
+	for _, op := range listdist.DiffOf(encodeAll(base), encodeAll(side), listdist.StandardCostOf[string]) {
+		switch op.Kind {
+		case listdist.Insert:
+			e.inserted[pos] = append(e.inserted[pos], side[op.B])
+			continue
+		case listdist.Delete:
+			e.deleted[op.A] = true
+		default:
+			e.elems[op.A] = side[op.B]
+		}
+		pos = op.A + 1
+	}
+	return e
+}
+
+func (m *merger) mergeArrays(path Path, base, ours, theirs []any) []any {
+	o, t := editArray(base, ours), editArray(base, theirs)
+	merged := []any{}
+	for i := 0; i <= len(base); i++ {
+		oi, ti := o.inserted[i], t.inserted[i]
+		switch {
+		case len(oi) == 0 || reflect.DeepEqual(oi, ti):
+			merged = append(merged, ti...)
+		case len(ti) == 0:
+			merged = append(merged, oi...)
+		default:
+			m.conflicts = append(m.conflicts, Conflict{
+				Path:      append(path[:len(path):len(path)], len(merged)),
+				Ours:      oi,
+				Theirs:    ti,
+				HasOurs:   true,
+				HasTheirs: true,
+			})
+			merged = append(merged, oi...)
+		}
+		if i == len(base) {
+			break
+		}
+		elemPath := append(path[:len(path):len(path)], len(merged))
+		value, ok := m.merge(elemPath, base[i], o.elems[i], t.elems[i], true, !o.deleted[i], !t.deleted[i])
+		if ok {
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,141 @@
+package jsondiff_test
+
+import (
+	"fmt"
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/jsondiff"
+)
+
+// conflictString renders conflict compactly, with missing values as "-".
+func conflictString(conflict jsondiff.Conflict) string {
+	value := func(value any, ok bool) string {
+		if !ok {
+			return "-"
+		}
+		return encode(value)
+	}
+	return fmt.Sprintf("%s %s: %s | %s", conflict.Path, value(conflict.Base, conflict.HasBase),
+		value(conflict.Ours, conflict.HasOurs), value(conflict.Theirs, conflict.HasTheirs))
+}
+
+var mergeTests = []struct {
+	summary   string
+	base      string
+	ours      string
+	theirs    string
+	merged    string
+	conflicts []string
+}{{
+	summary: "Nothing changed",
+	base:    `{"a": 1}`,
+	ours:    `{"a": 1}`,
+	theirs:  `{"a": 1}`,
+	merged:  `{"a": 1}`,
+}, {
+	summary: "Changes on one side",
+	base:    `{"a": 1, "b": [1, 2]}`,
+	ours:    `{"a": 1, "b": [1, 2]}`,
+	theirs:  `{"a": 2, "c": true}`,
+	merged:  `{"a": 2, "c": true}`,
+}, {
+	summary: "Changes to different members",
+	base:    `{"a": 1, "b": {"x": 1, "y": 2}, "c": 3}`,
+	ours:    `{"a": 2, "b": {"x": 1, "y": 3}, "c": 3}`,
+	theirs:  `{"a": 1, "b": {"x": 0, "y": 2, "z": 4}}`,
+	merged:  `{"a": 2, "b": {"x": 0, "y": 3, "z": 4}}`,
+}, {
+	summary: "Same change on both sides",
+	base:    `{"a": 1, "b": 2}`,
+	ours:    `{"a": 3}`,
+	theirs:  `{"a": 3}`,
+	merged:  `{"a": 3}`,
+}, {
+	summary: "Conflicting changes",
+	base:    `{"a": 1, "b": 2, "c": {"x": 1}}`,
+	ours:    `{"a": 2, "b": 3, "c": {"x": 2}}`,
+	theirs:  `{"a": 3, "c": null}`,
+	merged:  `{"a": 2, "b": 3, "c": {"x": 2}}`,
+	conflicts: []string{
+		`.a 1: 2 | 3`,
+		`.b 2: 3 | -`,
+		`.c {"x":1}: {"x":2} | null`,
+	},
+}, {
+	summary: "Members added on both sides",
+	base:    `{}`,
+	ours:    `{"a": {"x": 1, "y": 2}, "b": 1}`,
+	theirs:  `{"a": {"x": 1, "z": 3}, "b": 2}`,
+	merged:  `{"a": {"x": 1, "y": 2, "z": 3}, "b": 1}`,
+	conflicts: []string{
+		`.b -: 1 | 2`,
+	},
+}, {
+	summary: "Array changes on both sides",
+	base:    `[1, 2, 3, 4, 5]`,
+	ours:    `[0, 1, 2, 4, 5]`,
+	theirs:  `[1, 2, 3, 4, 6, 7]`,
+	merged:  `[0, 1, 2, 4, 6, 7]`,
+}, {
+	summary: "Array elements merged recursively",
+	base:    `[{"id": 1, "v": "a"}, {"id": 2, "v": "b"}]`,
+	ours:    `[{"id": 1, "v": "c"}, {"id": 2, "v": "b"}, {"id": 3}]`,
+	theirs:  `[{"id": 1, "v": "a", "w": true}, {"id": 2, "v": "b"}]`,
+	merged:  `[{"id": 1, "v": "c", "w": true}, {"id": 2, "v": "b"}, {"id": 3}]`,
+}, {
+	summary: "Conflicting array changes",
+	base:    `[1, 2, 3, 4]`,
+	ours:    `[1, "x", 3, 4, 5]`,
+	theirs:  `[1, 3, 4, 6]`,
+	merged:  `[1, "x", 3, 4, 5]`,
+	conflicts: []string{
+		`.[1] 2: "x" | -`,
+		`.[4] -: [5] | [6]`,
+	},
+}, {
+	summary: "Values of different types",
+	base:    `{"a": [1]}`,
+	ours:    `{"a": {"x": 1}}`,
+	theirs:  `{"a": [2]}`,
+	merged:  `{"a": {"x": 1}}`,
+	conflicts: []string{
+		`.a [1]: {"x":1} | [2]`,
+	},
+}}
+
+func (*S) TestMerge(c *C) {
+	for _, test := range mergeTests {
+		c.Logf("Summary: %s", test.summary)
+		base, ours, theirs := decode(c, test.base), decode(c, test.ours), decode(c, test.theirs)
+		merged, conflicts, err := jsondiff.Merge(base, ours, theirs)
+		c.Assert(err, IsNil)
+		c.Assert(encode(merged), Equals, encode(decode(c, test.merged)))
+		var strs []string
+		for _, conflict := range conflicts {
+			strs = append(strs, conflictString(conflict))
+		}
+		c.Assert(strs, DeepEquals, test.conflicts)
+	}
+}
+
+func (*S) TestMergeRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		base := randomValue(rnd, 3)
+		changed := mutate(rnd, decode(c, encode(base)), 3)
+		c.Logf("Test: %s => %s", encode(base), encode(changed))
+		for _, sides := range [][2]any{{changed, base}, {base, changed}, {changed, changed}} {
+			merged, conflicts, err := jsondiff.Merge(base, sides[0], sides[1])
+			c.Assert(err, IsNil)
+			c.Assert(conflicts, HasLen, 0)
+			c.Assert(encode(merged), Equals, encode(changed))
+		}
+	}
+}
+
+func (*S) TestMergeError(c *C) {
+	_, _, err := jsondiff.Merge(map[string]any{"a": []any{1}}, nil, nil)
+	c.Assert(err, ErrorMatches, `unsupported value of type int at .a\[0\]`)
+}
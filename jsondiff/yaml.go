@@ -0,0 +1,221 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DecodeYAML decodes the YAML document in data into the values encoding/json
+// would decode from the equivalent JSON document, so that it can be compared
+// with other documents, whether they come from YAML or JSON. Empty data
+// holds a null document.
+//
+// Numbers become float64 values, and other scalars that have no JSON
+// equivalent, such as timestamps, become strings holding them as written.
+// Mapping keys must be scalars, and are used as written as well. Aliases
+// and merge keys are resolved. DecodeYAML returns an error if data holds
+// more than one document, or values that can't be represented in JSON,
+// such as infinite numbers.
+func DecodeYAML(data []byte) (any, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var doc yaml.Node
+	if err := dec.Decode(&doc); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var extra yaml.Node
+	if err := dec.Decode(&extra); err != io.EOF {
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("yaml: line %d: unexpected document after the first one", extra.Line)
+	}
+	return fromYAML(&doc)
+}
+
+// DecodeYAMLDocuments is like DecodeYAML but decodes every document in
+// data, which may hold any number of them, including none.
+func DecodeYAMLDocuments(data []byte) ([]any, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var docs []any
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if err == io.EOF {
+				return docs, nil
+			}
+			return nil, err
+		}
+		value, err := fromYAML(&doc)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, value)
+	}
+}
+
+// fromYAML returns the value held by node.
+func fromYAML(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return fromYAML(node.Content[0])
+	case yaml.AliasNode:
+		return fromYAML(node.Alias)
+	case yaml.SequenceNode:
+		seq := make([]any, len(node.Content))
+		for i, elem := range node.Content {
+			value, err := fromYAML(elem)
+			if err != nil {
+				return nil, err
+			}
+			seq[i] = value
+		}
+		return seq, nil
+	case yaml.MappingNode:
+		obj := make(map[string]any)
+		if err := mergeYAML(obj, node, false); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	}
+
+	switch node.ShortTag() {
+	case "!!null":
+		return nil, nil
+	case "!!bool":
+		var value bool
+		err := node.Decode(&value)
+		return value, err
+	case "!!int", "!!float":
+		var value float64
+		if err := node.Decode(&value); err != nil {
+			return nil, err
+		}
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return nil, fmt.Errorf("yaml: line %d: cannot represent %s in JSON", node.Line, node.Value)
+		}
+		return value, nil
+	}
+	return node.Value, nil
+}
+
+// mergeYAML adds to obj the members of the mapping node, leaving alone the
+// members obj already holds if merging, as done for merge keys.
+func mergeYAML(obj map[string]any, node *yaml.Node, merging bool) error {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("yaml: line %d: cannot merge a value that is not a mapping", node.Line)
+	}
+	// Explicit members take precedence over merged ones wherever they are
+	// found in the mapping, so they are added first.
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Kind == yaml.AliasNode {
+			key = key.Alias
+		}
+		if key.Kind != yaml.ScalarNode {
+			return fmt.Errorf("yaml: line %d: mapping key is not a scalar", key.Line)
+		}
+		if key.ShortTag() == "!!merge" {
+			merges = append(merges, value)
+			continue
+		}
+		if _, ok := obj[key.Value]; ok && merging {
+			continue
+		}
+		data, err := fromYAML(value)
+		if err != nil {
+			return err
+		}
+		obj[key.Value] = data
+	}
+	for _, value := range merges {
+		if value.Kind == yaml.AliasNode {
+			value = value.Alias
+		}
+		if value.Kind != yaml.SequenceNode {
+			if err := mergeYAML(obj, value, true); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, elem := range value.Content {
+			if err := mergeYAML(obj, elem, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// EncodeYAML returns the YAML document holding value, which is made of the
+// values encoding/json decodes documents into.
+func EncodeYAML(value any) ([]byte, error) {
+	if err := checkTypes(value, Path{}); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(toYAML(value)); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// toYAML returns value with its json.Number values replaced by YAML nodes
+// holding them as written, which the YAML encoder would quote otherwise.
+func toYAML(value any) any {
+	switch value := value.(type) {
+	case json.Number:
+		tag := "!!float"
+		if _, err := value.Int64(); err == nil {
+			tag = "!!int"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value.String()}
+	case map[string]any:
+		obj := make(map[string]any, len(value))
+		for key, elem := range value {
+			obj[key] = toYAML(elem)
+		}
+		return obj
+	case []any:
+		seq := make([]any, len(value))
+		for i, elem := range value {
+			seq[i] = toYAML(elem)
+		}
+		return seq
+	}
+	return value
+}
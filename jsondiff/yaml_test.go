@@ -0,0 +1,99 @@
+package jsondiff_test
+
+import (
+	"encoding/json"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/jsondiff"
+)
+
+var decodeYAMLTests = []struct {
+	summary string
+	yaml    string
+	json    string
+	error   string
+}{{
+	summary: "Empty document",
+	yaml:    ``,
+	json:    `null`,
+}, {
+	summary: "Scalars",
+	yaml:    "a: 1\nb: 1.5\nc: 0x10\nd: true\ne: null\nf: text\ng: '1'\nh: 2001-12-14\n",
+	json:    `{"a": 1, "b": 1.5, "c": 16, "d": true, "e": null, "f": "text", "g": "1", "h": "2001-12-14"}`,
+}, {
+	summary: "Sequences and mappings",
+	yaml:    "items:\n  - id: 1\n    tags: [x, y]\n  - {id: 2}\n1: one\n",
+	json:    `{"items": [{"id": 1, "tags": ["x", "y"]}, {"id": 2}], "1": "one"}`,
+}, {
+	summary: "Aliases and merge keys",
+	yaml:    "base: &base {a: 1, b: 2}\nother: &other {c: 3}\nderived:\n  <<: [*base, *other]\n  b: 4\ncopy: *base\n",
+	json:    `{"base": {"a": 1, "b": 2}, "other": {"c": 3}, "derived": {"a": 1, "b": 4, "c": 3}, "copy": {"a": 1, "b": 2}}`,
+}, {
+	summary: "Key that is not a scalar",
+	yaml:    "? [a]\n: 1\n",
+	error:   `yaml: line 1: mapping key is not a scalar`,
+}, {
+	summary: "Infinite number",
+	yaml:    "a: .inf\n",
+	error:   `yaml: line 1: cannot represent .inf in JSON`,
+}, {
+	summary: "Several documents",
+	yaml:    "a: 1\n---\nb: 2\n",
+	error:   `yaml: line 2: unexpected document after the first one`,
+}, {
+	summary: "Invalid syntax",
+	yaml:    "a: [1\n",
+	error:   `yaml: line 1: .*`,
+}}
+
+func (*S) TestDecodeYAML(c *C) {
+	for _, test := range decodeYAMLTests {
+		c.Logf("Summary: %s", test.summary)
+		value, err := jsondiff.DecodeYAML([]byte(test.yaml))
+		if test.error != "" {
+			c.Assert(err, ErrorMatches, test.error)
+			continue
+		}
+		c.Assert(err, IsNil)
+		c.Assert(value, DeepEquals, decode(c, test.json))
+	}
+}
+
+func (*S) TestDecodeYAMLDocuments(c *C) {
+	docs, err := jsondiff.DecodeYAMLDocuments([]byte("a: 1\n---\n- b\n---\n"))
+	c.Assert(err, IsNil)
+	c.Assert(docs, DeepEquals, []any{decode(c, `{"a": 1}`), decode(c, `["b"]`), nil})
+
+	docs, err = jsondiff.DecodeYAMLDocuments(nil)
+	c.Assert(err, IsNil)
+	c.Assert(docs, HasLen, 0)
+
+	_, err = jsondiff.DecodeYAMLDocuments([]byte("a: 1\n---\nb: .inf\n"))
+	c.Assert(err, ErrorMatches, `yaml: line 3: cannot represent .inf in JSON`)
+}
+
+func (*S) TestEncodeYAML(c *C) {
+	data, err := jsondiff.EncodeYAML(decode(c, `{"b": [1, 2.5, {"c": null}], "a": "x"}`))
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "a: x\nb:\n  - 1\n  - 2.5\n  - c: null\n")
+
+	data, err = jsondiff.EncodeYAML([]any{json.Number("12"), json.Number("1.50")})
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "- 12\n- 1.50\n")
+
+	_, err = jsondiff.EncodeYAML(map[string]any{"a": 1})
+	c.Assert(err, ErrorMatches, `unsupported value of type int at .a`)
+}
+
+func (*S) TestYAMLRoundTrip(c *C) {
+	for i, doc := range []string{`{"a": [1, {"b": "2001-12-14"}], "c": "yes", "d": "1e3", "e": ""}`, `[null, "null", "~", true, "true"]`} {
+		c.Logf("Test: %d", i)
+		value := decode(c, doc)
+		data, err := jsondiff.EncodeYAML(value)
+		c.Assert(err, IsNil)
+		back, err := jsondiff.DecodeYAML(data)
+		c.Assert(err, IsNil)
+		c.Assert(back, DeepEquals, value)
+	}
+}
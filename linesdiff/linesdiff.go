@@ -0,0 +1,281 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package linesdiff compares texts line by line, as done by the diff tool,
+// grouping the changes into hunks that may be rendered as a unified diff.
+//
+// Lines are compared as a whole, with Myers' algorithm from the listdist
+// package, so that texts of many lines which are mostly equal are compared
+// quickly. Lines replaced by others may then be compared character by
+// character, marking the parts that changed.
+package linesdiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+// Options holds the options for Compare.
+type Options struct {
+	// Context is the number of unchanged lines shown around changes. It
+	// defaults to 3, and negative values show none.
+	Context int
+
+	// Refine pairs the lines deleted and inserted in a run of changes, in
+	// order, and marks the characters that differ between them.
+	Refine bool
+}
+
+// Range is a range of bytes in the text of a line, from Start up to but
+// not including End.
+type Range struct {
+	Start, End int
+}
+
+// Line is a line in a hunk.
+type Line struct {
+	// Kind is listdist.Equal for unchanged lines, listdist.Delete for
+	// lines of a that were removed, and listdist.Insert for lines of b
+	// that were added.
+	Kind listdist.OpKind
+
+	// Text is the line without its line terminator.
+	Text string
+
+	// NoNewline is set for the last line of a text not ending in one.
+	NoNewline bool
+
+	// Changed holds the ranges of Text that differ from the line it was
+	// paired with, if the Refine option was set.
+	Changed []Range
+}
+
+// Hunk is a run of changes with the unchanged lines around them.
+type Hunk struct {
+	// A is the index of the first line of a in the hunk, counting from
+	// zero, and ALen is the number of lines of a in it.
+	A, ALen int
+
+	// B is the index of the first line of b in the hunk, counting from
+	// zero, and BLen is the number of lines of b in it.
+	B, BLen int
+
+	Lines []Line
+}
+
+// Compare returns the hunks of changes turning text a into text b, which
+// are empty if the texts are equal. The options may be nil.
+func Compare(a, b string, options *Options) []Hunk {
+	if options == nil {
+		options = &Options{}
+	}
+	context := 3
+	if options.Context != 0 {
+		context = max(options.Context, 0)
+	}
+
+	alines, blines := split(a), split(b)
+	ids := make(map[string]int)
+	intern := func(lines []string) []int {
+		result := make([]int, len(lines))
+		for i, line := range lines {
+			id, ok := ids[line]
+			if !ok {
+				id = len(ids)
+				ids[line] = id
+			}
+			result[i] = id
+		}
+		return result
+	}
+	ops := listdist.DiffMyersOf(intern(alines), intern(blines), nil)
+
+	// Positions of the lines of a and b before each op.
+	apos := make([]int, len(ops)+1)
+	bpos := make([]int, len(ops)+1)
+	for i, op := range ops {
+		apos[i+1], bpos[i+1] = apos[i], bpos[i]
+		if op.A >= 0 {
+			apos[i+1]++
+		}
+		if op.B >= 0 {
+			bpos[i+1]++
+		}
+	}
+
+	line := func(kind listdist.OpKind, text string) Line {
+		trimmed, ok := strings.CutSuffix(text, "\n")
+		return Line{Kind: kind, Text: trimmed, NoNewline: !ok}
+	}
+
+	var hunks []Hunk
+	for i := 0; i < len(ops); {
+		if ops[i].Kind == listdist.Equal {
+			i++
+			continue
+		}
+		// Merge changes that are close enough for their context to overlap.
+		start := max(i-context, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].Kind != listdist.Equal {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].Kind == listdist.Equal {
+				next++
+			}
+			if next == len(ops) || next-end > 2*context {
+				break
+			}
+			end = next
+		}
+		i = end
+		end = min(end+context, len(ops))
+
+		hunk := Hunk{
+			A:    apos[start],
+			ALen: apos[end] - apos[start],
+			B:    bpos[start],
+			BLen: bpos[end] - bpos[start],
+		}
+		for j := start; j < end; {
+			if ops[j].Kind == listdist.Equal {
+				hunk.Lines = append(hunk.Lines, line(listdist.Equal, alines[ops[j].A]))
+				j++
+				continue
+			}
+			k := j
+			for k < end && ops[k].Kind != listdist.Equal {
+				k++
+			}
+			var deleted, inserted []Line
+			for _, op := range ops[j:k] {
+				if op.A >= 0 {
+					deleted = append(deleted, line(listdist.Delete, alines[op.A]))
+				} else {
+					inserted = append(inserted, line(listdist.Insert, blines[op.B]))
+				}
+			}
+			if options.Refine {
+				for n := range min(len(deleted), len(inserted)) {
+					deleted[n].Changed, inserted[n].Changed = changedRanges(deleted[n].Text, inserted[n].Text)
+				}
+			}
+			hunk.Lines = append(hunk.Lines, deleted...)
+			hunk.Lines = append(hunk.Lines, inserted...)
+			j = k
+		}
+		hunks = append(hunks, hunk)
+	}
+	return hunks
+}
+
+// split splits text into lines, keeping their line terminators so that a
+// last line without one differs from the same line with it.
+func split(text string) []string {
+	var lines []string
+	for text != "" {
+		i := strings.IndexByte(text, '\n') + 1
+		if i == 0 {
+			i = len(text)
+		}
+		lines = append(lines, text[:i])
+		text = text[i:]
+	}
+	return lines
+}
+
+// changedRanges returns the ranges of a and b holding the runes deleted
+// from a and inserted into b, respectively, by a script turning a into b.
+func changedRanges(a, b string) (achanged, bchanged []Range) {
+	arunes, aoffsets := runes(a)
+	brunes, boffsets := runes(b)
+	for _, op := range listdist.DiffMyersOf(arunes, brunes, nil) {
+		switch op.Kind {
+		case listdist.Delete:
+			achanged = addRange(achanged, aoffsets[op.A], aoffsets[op.A+1])
+		case listdist.Insert:
+			bchanged = addRange(bchanged, boffsets[op.B], boffsets[op.B+1])
+		}
+	}
+	return achanged, bchanged
+}
+
+// runes returns the runes of s, and the offset in bytes where each of
+// them starts, followed by the length of s.
+func runes(s string) ([]rune, []int) {
+	result := make([]rune, 0, len(s))
+	offsets := make([]int, 0, len(s)+1)
+	for i, r := range s {
+		result = append(result, r)
+		offsets = append(offsets, i)
+	}
+	return result, append(offsets, len(s))
+}
+
+// addRange adds the range from start to end to ranges, extending the last
+// range instead if it ends at start.
+func addRange(ranges []Range, start, end int) []Range {
+	if n := len(ranges); n > 0 && ranges[n-1].End == start {
+		ranges[n-1].End = end
+		return ranges
+	}
+	return append(ranges, Range{start, end})
+}
+
+// Unified renders hunks as a unified diff between files named aName and
+// bName, or returns an empty string if there are no hunks.
+func Unified(aName, bName string, hunks []Hunk) string {
+	if len(hunks) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", aName, bName)
+	for _, hunk := range hunks {
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(hunk.A, hunk.ALen), hunkRange(hunk.B, hunk.BLen))
+		for _, line := range hunk.Lines {
+			switch line.Kind {
+			case listdist.Delete:
+				sb.WriteByte('-')
+			case listdist.Insert:
+				sb.WriteByte('+')
+			default:
+				sb.WriteByte(' ')
+			}
+			sb.WriteString(line.Text)
+			sb.WriteByte('\n')
+			if line.NoNewline {
+				sb.WriteString("\\ No newline at end of file\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// hunkRange formats a range in a hunk header, with lines counted from one
+// as in diff, where an empty range refers to the line before it.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
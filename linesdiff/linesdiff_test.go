@@ -0,0 +1,162 @@
+package linesdiff_test
+
+import (
+	"math/rand"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/linesdiff"
+	"github.com/canonical/go-algo/listdist"
+)
+
+var unifiedTests = []struct {
+	summary string
+	a, b    string
+	context int
+	diff    string
+}{{
+	summary: "Equal",
+	a:       "a\nb\n",
+	b:       "a\nb\n",
+	diff:    "",
+}, {
+	summary: "Both empty",
+	diff:    "",
+}, {
+	summary: "From empty",
+	b:       "a\nb\n",
+	diff:    "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+}, {
+	summary: "To empty",
+	a:       "a\n",
+	diff:    "--- a\n+++ b\n@@ -1 +0,0 @@\n-a\n",
+}, {
+	summary: "Change with context",
+	a:       "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+	b:       "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+	diff:    "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+}, {
+	summary: "Smaller context",
+	a:       "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+	b:       "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+	context: 1,
+	diff:    "--- a\n+++ b\n@@ -4,3 +4,3 @@\n 4\n-5\n+five\n 6\n",
+}, {
+	summary: "No context",
+	a:       "1\n2\n3\n",
+	b:       "1\n3\n4\n",
+	context: -1,
+	diff:    "--- a\n+++ b\n@@ -2 +1,0 @@\n-2\n@@ -3,0 +3 @@\n+4\n",
+}, {
+	summary: "Separate hunks",
+	a:       "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+	b:       "one\n2\n3\n4\n5\n6\n7\n8\nnine\n",
+	context: 2,
+	diff:    "--- a\n+++ b\n@@ -1,3 +1,3 @@\n-1\n+one\n 2\n 3\n@@ -7,3 +7,3 @@\n 7\n 8\n-9\n+nine\n",
+}, {
+	summary: "Merged hunks",
+	a:       "1\n2\n3\n4\n5\n6\n",
+	b:       "one\n2\n3\n4\n5\nsix\n",
+	context: 2,
+	diff:    "--- a\n+++ b\n@@ -1,6 +1,6 @@\n-1\n+one\n 2\n 3\n 4\n 5\n-6\n+six\n",
+}, {
+	summary: "Missing newline",
+	a:       "a\nb",
+	b:       "a\nb\n",
+	diff:    "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+}, {
+	summary: "Unchanged line without newline",
+	a:       "a\nb",
+	b:       "x\nb",
+	diff:    "--- a\n+++ b\n@@ -1,2 +1,2 @@\n-a\n+x\n b\n\\ No newline at end of file\n",
+}}
+
+func (*S) TestUnified(c *C) {
+	for _, test := range unifiedTests {
+		c.Logf("Summary: %s", test.summary)
+		hunks := linesdiff.Compare(test.a, test.b, &linesdiff.Options{Context: test.context})
+		c.Assert(linesdiff.Unified("a", "b", hunks), Equals, test.diff)
+	}
+}
+
+func (*S) TestRefine(c *C) {
+	hunks := linesdiff.Compare("same\nthe quick fox\nold\n", "same\nthe quick brown fox\nnew line\nextra\n", &linesdiff.Options{Refine: true})
+	c.Assert(hunks, HasLen, 1)
+	c.Assert(hunks[0].Lines, DeepEquals, []linesdiff.Line{
+		{Kind: listdist.Equal, Text: "same"},
+		{Kind: listdist.Delete, Text: "the quick fox"},
+		{Kind: listdist.Delete, Text: "old", Changed: []linesdiff.Range{{0, 1}, {2, 3}}},
+		{Kind: listdist.Insert, Text: "the quick brown fox", Changed: []linesdiff.Range{{10, 16}}},
+		{Kind: listdist.Insert, Text: "new line", Changed: []linesdiff.Range{{0, 4}, {5, 8}}},
+		{Kind: listdist.Insert, Text: "extra"},
+	})
+
+	hunks = linesdiff.Compare("añb\n", "aéb\n", &linesdiff.Options{Refine: true})
+	c.Assert(hunks[0].Lines[0].Changed, DeepEquals, []linesdiff.Range{{1, 3}})
+	c.Assert(hunks[0].Lines[1].Changed, DeepEquals, []linesdiff.Range{{1, 3}})
+
+	hunks = linesdiff.Compare("a\n", "b\n", nil)
+	c.Assert(hunks[0].Lines[0].Changed, IsNil)
+}
+
+func (*S) TestCompareRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	randomText := func() string {
+		lines := make([]string, rnd.Intn(30))
+		for i := range lines {
+			lines[i] = "abcd"[rnd.Intn(4):][:1]
+		}
+		text := strings.Join(lines, "\n")
+		if text != "" && rnd.Intn(4) > 0 {
+			text += "\n"
+		}
+		return text
+	}
+	for i := 0; i < 300; i++ {
+		a, b := randomText(), randomText()
+		context := rnd.Intn(4) - 1
+		c.Logf("Test %d: %q, %q, context %d", i, a, b, context)
+		hunks := linesdiff.Compare(a, b, &linesdiff.Options{Context: context})
+		c.Assert(apply(c, a, hunks), Equals, b)
+	}
+}
+
+// apply returns text a with hunks applied, checking that the lines they
+// expect from a are there.
+func apply(c *C, a string, hunks []linesdiff.Hunk) string {
+	var lines []string
+	for _, line := range strings.SplitAfter(a, "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	text := func(line linesdiff.Line) string {
+		if line.NoNewline {
+			return line.Text
+		}
+		return line.Text + "\n"
+	}
+	var result []string
+	next := 0
+	for _, hunk := range hunks {
+		c.Assert(hunk.A >= next, Equals, true)
+		result = append(result, lines[next:hunk.A]...)
+		next = hunk.A
+		alen, blen := 0, 0
+		for _, line := range hunk.Lines {
+			if line.Kind != listdist.Insert {
+				c.Assert(lines[next], Equals, text(line))
+				next++
+				alen++
+			}
+			if line.Kind != listdist.Delete {
+				result = append(result, text(line))
+				blen++
+			}
+		}
+		c.Assert([]int{alen, blen}, DeepEquals, []int{hunk.ALen, hunk.BLen})
+	}
+	result = append(result, lines[next:]...)
+	return strings.Join(result, "")
+}
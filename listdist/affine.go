@@ -0,0 +1,211 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+// DistanceAffine returns the lowest total cost of the edits turning list a
+// into list b with affine gap penalties, or Inhibit if every sequence of
+// edits is inhibited.
+//
+// Edits are charged by f as in Distance, and in addition open is charged
+// once for every gap, which is a run of consecutive deletions or a run of
+// consecutive insertions. The DeleteA and InsertB costs in f are then the
+// costs of extending a gap by one element, so a gap of n deletions costs
+// open plus the n DeleteA costs.
+func DistanceAffine(a, b []any, f CostFunc, open CostInt) int64 {
+	return DistanceAffineOf(a, b, boxedCost(f), open)
+}
+
+// DistanceAffineOf is the generic form of DistanceAffine.
+//
+// It's computed with Gotoh's algorithm, which keeps the cost of the best
+// edits ending in a swap, in a deletion, and in an insertion for every
+// entry of the cost matrix. It takes about three times as long as Distance,
+// and linear space.
+func DistanceAffineOf[T comparable](a, b []T, f CostFuncOf[T], open CostInt) int64 {
+	g := gotoh[T]{differ: differ[T]{a: a, b: b, f: indexedCost(f)}, open: open}
+	cols := len(b) + 1
+	last := make([]gotohCell, cols)
+	row := make([]gotohCell, cols)
+	g.first(row)
+	for i := 1; i <= len(a); i++ {
+		last, row = row, last
+		g.next(i, last, row)
+	}
+	return int64(row[len(b)].best())
+}
+
+// DiffAffine returns a script of minimum cost turning a into b, with costs
+// as computed by DistanceAffine. Operations are ordered as in Diff.
+//
+// If every script is inhibited, the script returned is one of them, and
+// includes inhibited operations.
+func DiffAffine(a, b []any, f CostFunc, open CostInt) []Op {
+	return DiffAffineOf(a, b, boxedCost(f), open)
+}
+
+// DiffAffineOf is the generic form of DiffAffine.
+//
+// The script is traced back through the full cost matrix, so it takes
+// space proportional to the product of the list lengths.
+func DiffAffineOf[T comparable](a, b []T, f CostFuncOf[T], open CostInt) []Op {
+	g := gotoh[T]{differ: differ[T]{a: a, b: b, f: indexedCost(f)}, open: open}
+	cols := len(b) + 1
+	cells := make([]gotohCell, (len(a)+1)*cols)
+	g.first(cells[:cols])
+	for i := 1; i <= len(a); i++ {
+		g.next(i, cells[(i-1)*cols:i*cols], cells[i*cols:(i+1)*cols])
+	}
+	cell := func(i, j int) *gotohCell { return &cells[i*cols+j] }
+
+	// Walk back from the end, preferring to keep or swap elements, and
+	// then insertions so that they end up after deletions in the script.
+	// Within a gap, extending it is preferred over opening another one.
+	ops := make([]Op, 0, max(len(a), len(b)))
+	i, j := len(a), len(b)
+	state := g.pick(i, j, cell(i, j).best(), [3]gotohState{swapState, insertState, deleteState}, func(s gotohState) CostInt {
+		return cell(i, j)[s]
+	})
+	for i > 0 || j > 0 {
+		current := cell(i, j)[state]
+		switch state {
+		case swapState:
+			kind := Swap
+			if a[i-1] == b[j-1] {
+				kind = Equal
+			}
+			ops = append(ops, Op{Kind: kind, A: i - 1, B: j - 1})
+			edge := g.swapCost(i-1, j-1)
+			state = g.pick(i-1, j-1, current, [3]gotohState{swapState, insertState, deleteState}, func(s gotohState) CostInt {
+				return addCost(cell(i-1, j-1)[s], edge)
+			})
+			i--
+			j--
+		case deleteState:
+			ops = append(ops, Op{Kind: Delete, A: i - 1, B: -1})
+			edge := g.deleteCost(i-1, j)
+			state = g.pick(i-1, j, current, [3]gotohState{deleteState, swapState, insertState}, func(s gotohState) CostInt {
+				return addCost(g.gap(s, deleteState, cell(i-1, j)[s]), edge)
+			})
+			i--
+		case insertState:
+			ops = append(ops, Op{Kind: Insert, A: -1, B: j - 1})
+			edge := g.insertCost(i, j-1)
+			state = g.pick(i, j-1, current, [3]gotohState{insertState, swapState, deleteState}, func(s gotohState) CostInt {
+				return addCost(g.gap(s, insertState, cell(i, j-1)[s]), edge)
+			})
+			j--
+		}
+	}
+	reverse(ops)
+	return ops
+}
+
+type gotohState int
+
+const (
+	swapState gotohState = iota
+	deleteState
+	insertState
+)
+
+// gotohCell holds the lowest cost of reaching an entry of the cost matrix
+// with the last edit being a swap, a deletion, or an insertion. The start
+// of the matrix counts as a swap, so that the first gap is opened as well.
+type gotohCell [3]CostInt
+
+func (c *gotohCell) best() CostInt {
+	return min(c[swapState], c[deleteState], c[insertState])
+}
+
+type gotoh[T comparable] struct {
+	differ[T]
+	open CostInt
+}
+
+// gap returns cost plus the cost of opening a gap if moving from state
+// from into the gap state to requires it.
+func (g *gotoh[T]) gap(from, to gotohState, cost CostInt) CostInt {
+	if from == to {
+		return cost
+	}
+	return addCost(cost, g.open)
+}
+
+// first fills in the row of the cost matrix for no elements of a.
+func (g *gotoh[T]) first(row []gotohCell) {
+	row[0] = gotohCell{0, Inhibit, Inhibit}
+	for j := 1; j < len(row); j++ {
+		prev := &row[j-1]
+		row[j] = gotohCell{
+			Inhibit,
+			Inhibit,
+			addCost(min(g.gap(swapState, insertState, prev[swapState]), prev[insertState]), g.insertCost(0, j-1)),
+		}
+	}
+}
+
+// next fills in row i of the cost matrix from row i-1 in last.
+func (g *gotoh[T]) next(i int, last, row []gotohCell) {
+	for j := range row {
+		up := &last[j]
+		cell := &row[j]
+		cell[deleteState] = addCost(min(
+			g.gap(swapState, deleteState, up[swapState]),
+			up[deleteState],
+			g.gap(insertState, deleteState, up[insertState]),
+		), g.deleteCost(i-1, j))
+		if j == 0 {
+			cell[swapState] = Inhibit
+			cell[insertState] = Inhibit
+			continue
+		}
+		cell[swapState] = addCost(last[j-1].best(), g.swapCost(i-1, j-1))
+		left := &row[j-1]
+		cell[insertState] = addCost(min(
+			g.gap(swapState, insertState, left[swapState]),
+			g.gap(deleteState, insertState, left[deleteState]),
+			left[insertState],
+		), g.insertCost(i, j-1))
+	}
+}
+
+// pick returns the first of the states in order that may end at (i, j)
+// and reaches the current cost there, or the first one that may end at
+// (i, j) if none does, which only happens when every script is inhibited.
+func (g *gotoh[T]) pick(i, j int, current CostInt, order [3]gotohState, cost func(gotohState) CostInt) gotohState {
+	valid := func(s gotohState) bool {
+		switch s {
+		case swapState:
+			return i > 0 && j > 0 || i == 0 && j == 0
+		case deleteState:
+			return i > 0
+		}
+		return j > 0
+	}
+	fallback := gotohState(-1)
+	for _, s := range order {
+		if !valid(s) {
+			continue
+		}
+		if cost(s) == current {
+			return s
+		}
+		if fallback < 0 {
+			fallback = s
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,122 @@
+package listdist_test
+
+import (
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+type affineTest struct {
+	a, b string
+	open listdist.CostInt
+	dist int64
+	ops  string
+}
+
+var affineTests = []affineTest{
+	{a: "", b: "", open: 5, dist: 0, ops: ""},
+	{a: "abc", b: "abc", open: 5, dist: 0, ops: "==="},
+	{a: "abc", b: "", open: 5, dist: 8, ops: "---"},
+	{a: "", b: "abc", open: 5, dist: 8, ops: "+++"},
+	{a: "abc", b: "abd", open: 5, dist: 1, ops: "==~"},
+	{a: "aaxxxxbb", b: "aabb", open: 5, dist: 9, ops: "==----=="},
+	{a: "axbxc", b: "abc", open: 0, dist: 2, ops: "=-=-="},
+	{a: "axbxc", b: "abc", open: 5, dist: 8, ops: "=--~="},
+	{a: "abc", b: "xyz", open: 5, dist: 3, ops: "~~~"},
+	{a: "abcdef", b: "xabcdy", open: 1, dist: 5, ops: "+====-~"},
+}
+
+func (s *S) TestDiffAffine(c *C) {
+	for _, test := range affineTests {
+		c.Logf("Test: %v", test)
+		a, b := splitString(test.a), splitString(test.b)
+		c.Assert(listdist.DistanceAffine(a, b, listdist.StandardCost, test.open), Equals, test.dist)
+		ops := listdist.DiffAffine(a, b, listdist.StandardCost, test.open)
+		c.Assert(opString(ops), Equals, test.ops)
+	}
+}
+
+// affineCost returns the cost of applying ops to turn a into b with
+// affine gap penalties.
+func affineCost(c *C, a, b []byte, ops []listdist.Op, f listdist.CostFuncOf[byte], open listdist.CostInt) int64 {
+	cost := scriptCost(c, a, b, ops, f)
+	for i, op := range ops {
+		if (op.Kind == listdist.Delete || op.Kind == listdist.Insert) && (i == 0 || ops[i-1].Kind != op.Kind) {
+			cost += int64(open)
+		}
+	}
+	return cost
+}
+
+// bruteAffine returns the lowest affine cost of turning a[i:] into b[j:]
+// after an edit of the given kind, trying every script.
+func bruteAffine(a, b []byte, i, j int, last listdist.OpKind, f listdist.CostFuncOf[byte], open int64) int64 {
+	if i == len(a) && j == len(b) {
+		return 0
+	}
+	best := int64(1 << 62)
+	if i < len(a) && j < len(b) {
+		cost := int64(0)
+		if a[i] != b[j] {
+			cost = int64(f(&a[i], &b[j]).SwapAB)
+		}
+		best = min(best, cost+bruteAffine(a, b, i+1, j+1, listdist.Swap, f, open))
+	}
+	if i < len(a) {
+		var cost int64
+		if j == 0 {
+			cost = int64(f(&a[i], nil).DeleteA)
+		} else {
+			cost = int64(f(&a[i], &b[j-1]).DeleteA)
+		}
+		if last != listdist.Delete {
+			cost += open
+		}
+		best = min(best, cost+bruteAffine(a, b, i+1, j, listdist.Delete, f, open))
+	}
+	if j < len(b) {
+		var cost int64
+		if i == 0 {
+			cost = int64(f(nil, &b[j]).InsertB)
+		} else {
+			cost = int64(f(&a[i-1], &b[j]).InsertB)
+		}
+		if last != listdist.Insert {
+			cost += open
+		}
+		best = min(best, cost+bruteAffine(a, b, i, j+1, listdist.Insert, f, open))
+	}
+	return best
+}
+
+func (s *S) TestDiffAffineRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		a := randomBytes(rnd, rnd.Intn(7))
+		b := randomBytes(rnd, rnd.Intn(7))
+		open := listdist.CostInt(rnd.Intn(4))
+		for _, f := range []listdist.CostFuncOf[byte]{listdist.StandardCostOf[byte], contextCost} {
+			dist := listdist.DistanceAffineOf(a, b, f, open)
+			c.Assert(dist, Equals, bruteAffine(a, b, 0, 0, listdist.Swap, f, int64(open)))
+			ops := listdist.DiffAffineOf(a, b, f, open)
+			c.Assert(affineCost(c, a, b, ops, f, open), Equals, dist)
+			if open == 0 {
+				c.Assert(dist, Equals, listdist.DistanceOf(a, b, f, 0))
+			}
+		}
+	}
+}
+
+func (s *S) TestDiffAffineInhibit(c *C) {
+	inhibit := func(ar, br *byte) listdist.Cost {
+		return listdist.Cost{SwapAB: listdist.Inhibit, DeleteA: listdist.Inhibit, InsertB: 1}
+	}
+	a, b := []byte("ab"), []byte("c")
+	c.Assert(listdist.DistanceAffineOf(a, b, inhibit, 1), Equals, int64(listdist.Inhibit))
+	ops := listdist.DiffAffineOf(a, b, inhibit, 1)
+	c.Assert(opString(ops), Equals, "-~")
+	c.Assert(listdist.DistanceAffineOf(a, a, inhibit, listdist.Inhibit), Equals, int64(0))
+	c.Assert(listdist.DistanceAffineOf([]byte(nil), b, inhibit, listdist.Inhibit), Equals, int64(listdist.Inhibit))
+}
@@ -0,0 +1,125 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+)
+
+// standardCostName is the name of StandardCostOf, which is the same for
+// every type of element. Instances of StandardCostOf taken from different
+// places may have different addresses, so they are told by name instead.
+var standardCostName = funcName(StandardCostOf[byte])
+
+func funcName(f any) string {
+	return runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+}
+
+// bitParallel returns the Levenshtein distance between a and b with the
+// algorithm by Myers, "A fast bit-vector algorithm for approximate string
+// matching based on dynamic programming" (1999), in the form extended to
+// patterns of any length by Hyyrö (2003). Each column of the cost matrix
+// is kept as the bits of the vertical differences between its entries,
+// 64 of them per word, so a whole column is computed with a handful of
+// word operations. The context, if not nil, is checked once for every
+// element of a.
+func (c *Calculator[T]) bitParallel(ctx context.Context, a, b []T) (int64, error) {
+	if len(b) == 0 {
+		return int64(len(a)), nil
+	}
+	// The bits of the elements of b equal to each element are found in
+	// eqs at the offset held in peq, after a first block of zeros for the
+	// elements not in b.
+	words := (len(b) + 63) / 64
+	if c.peq == nil {
+		c.peq = make(map[T]int)
+	}
+	clear(c.peq)
+	eqs := grow(&c.eqs, words)
+	clear(eqs)
+	for i, elem := range b {
+		offset, ok := c.peq[elem]
+		if !ok {
+			offset = len(eqs)
+			for range words {
+				eqs = append(eqs, 0)
+			}
+			c.peq[elem] = offset
+		}
+		eqs[offset+i/64] |= 1 << (i % 64)
+	}
+	c.eqs = eqs
+
+	// Pv and Mv have the bits set for the entries of the column that are
+	// one more and one less than the entry above them, respectively.
+	pv := grow(&c.pv, words)
+	mv := grow(&c.mv, words)
+	for w := range pv {
+		pv[w], mv[w] = ^uint64(0), 0
+	}
+	last := uint64(1) << ((len(b) - 1) % 64)
+	score := int64(len(b))
+	for ai := range a {
+		if ctx != nil {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+		eq := eqs[c.peq[a[ai]]:]
+		// The top entry of every column is one more than the previous one.
+		hin := 1
+		for w := 0; w < words; w++ {
+			high := uint64(1) << 63
+			if w == words-1 {
+				high = last
+			}
+			pv[w], mv[w], hin = advanceBlock(pv[w], mv[w], eq[w], hin, high)
+		}
+		score += int64(hin)
+	}
+	return score, nil
+}
+
+// advanceBlock computes the vertical differences pv and mv of a block of
+// 64 entries in the next column, given the bits of the block's elements of
+// b equal to the next element of a in eq, and the horizontal difference
+// above the block in hin. It also returns the horizontal difference at the
+// entry of bit high.
+func advanceBlock(pv, mv, eq uint64, hin int, high uint64) (uint64, uint64, int) {
+	xv := eq | mv
+	if hin < 0 {
+		eq |= 1
+	}
+	xh := (((eq & pv) + pv) ^ pv) | eq
+	ph := mv | ^(xh | pv)
+	mh := pv & xh
+	hout := 0
+	if ph&high != 0 {
+		hout = 1
+	} else if mh&high != 0 {
+		hout = -1
+	}
+	ph <<= 1
+	mh <<= 1
+	if hin < 0 {
+		mh |= 1
+	} else if hin > 0 {
+		ph |= 1
+	}
+	return mh | ^(xv | ph), ph & xv, hout
+}
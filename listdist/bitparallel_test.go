@@ -0,0 +1,58 @@
+package listdist_test
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+// uniformCost is the same as StandardCostOf, but is not recognized as it
+// so that the distance is computed entry by entry.
+func uniformCost(ar, br *byte) listdist.Cost {
+	return listdist.Cost{SwapAB: 1, DeleteA: 1, InsertB: 1}
+}
+
+func (s *S) TestDistanceBitParallel(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		// Lengths cross the boundaries of 64 bit words.
+		a := randomBytes(rnd, rnd.Intn(200))
+		b := randomBytes(rnd, rnd.Intn(200))
+		if i%3 == 0 {
+			b = append(append([]byte{}, a...), randomBytes(rnd, rnd.Intn(70))...)
+		}
+		c.Logf("Test %d: %q, %q", i, a, b)
+		expected := listdist.DistanceOf(a, b, uniformCost, 0)
+		c.Assert(listdist.DistanceOf(a, b, listdist.StandardCostOf[byte], 0), Equals, expected)
+		c.Assert(listdist.DistanceOf(b, a, listdist.StandardCostOf[byte], 0), Equals, expected)
+	}
+
+	c.Assert(listdist.Distance(splitString("kitten"), splitString("sitting"), listdist.StandardCost, 0), Equals, int64(3))
+	c.Assert(listdist.StringDistance("kitten", "sitting", nil), Equals, int64(3))
+	c.Assert(listdist.StringDistance("", "abc", nil), Equals, int64(3))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := listdist.DistanceContextOf(ctx, []byte("a"), []byte("b"), listdist.StandardCostOf[byte], 0)
+	c.Assert(err, Equals, context.Canceled)
+}
+
+var longA = []byte(strings.Repeat("the quick brown fox jumps over the lazy dog ", 25))
+var longB = []byte(strings.Repeat("the quick brown cat jumped over a lazy dog ", 25))
+
+func BenchmarkDistanceLong(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		listdist.DistanceOf(longA, longB, listdist.StandardCostOf[byte], 0)
+	}
+}
+
+func BenchmarkDistanceLongUniform(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		listdist.DistanceOf(longA, longB, uniformCost, 0)
+	}
+}
@@ -0,0 +1,110 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+// DistanceBounded returns the distance between a and b as computed by
+// Distance and true if that distance is at most max, or a value above max
+// and false otherwise. Costs returned by f must not be negative.
+//
+// Only the entries of the cost matrix that are within max are computed,
+// which is the cutoff described by Ukkonen in "Algorithms for approximate
+// string matching" (1985). Entries above max can't lead to a distance within
+// it, so each row is limited to the band of columns reachable from entries
+// within max in the previous row, and the computation stops as soon as
+// that band is empty. With similar lists and a small max, that takes time
+// proportional to the length of the lists times max over the lowest cost
+// of insertions and deletions.
+func DistanceBounded(a, b []any, f CostFunc, max int64) (int64, bool) {
+	return DistanceBoundedOf(a, b, boxedCost(f), max)
+}
+
+// DistanceBoundedOf is the generic form of DistanceBounded.
+func DistanceBoundedOf[T comparable](a, b []T, f CostFuncOf[T], max int64) (int64, bool) {
+	if max < 0 {
+		return 0, false
+	}
+	if costKindOf(f).trimmable() {
+		a, b = trimCommon(a, b)
+	}
+	limit := CostInt(max)
+	exceeded := func() (int64, bool) {
+		if limit == Inhibit {
+			return int64(Inhibit), false
+		}
+		return max + 1, false
+	}
+
+	// prev and cur hold the entries of the previous and current rows for
+	// columns lo to hi, with every other entry taken to be above max.
+	prev := make([]CostInt, len(b)+1)
+	cur := make([]CostInt, len(b)+1)
+	lo, hi := 0, 0
+	for j := range b {
+		cost := addCost(prev[j], f(nil, &b[j]).InsertB)
+		if cost > limit {
+			break
+		}
+		prev[j+1] = cost
+		hi = j + 1
+	}
+
+	for i := range a {
+		ar := &a[i]
+		nlo, nhi := -1, -1
+		var left CostInt = Inhibit
+		for j := lo; j <= len(b); j++ {
+			if j > hi+1 && left > limit {
+				break
+			}
+			var cost Cost
+			var best CostInt = Inhibit
+			if j == 0 {
+				cost = f(ar, nil)
+			} else {
+				br := &b[j-1]
+				cost = f(ar, br)
+				if j-1 >= lo && j-1 <= hi {
+					if *ar == *br {
+						best = prev[j-1]
+					} else {
+						best = addCost(prev[j-1], cost.SwapAB)
+					}
+				}
+				best = min(best, addCost(left, cost.InsertB))
+			}
+			if j <= hi {
+				best = min(best, addCost(prev[j], cost.DeleteA))
+			}
+			cur[j] = best
+			left = best
+			if best <= limit {
+				if nlo < 0 {
+					nlo = j
+				}
+				nhi = j
+			}
+		}
+		if nlo < 0 {
+			return exceeded()
+		}
+		prev, cur = cur, prev
+		lo, hi = nlo, nhi
+	}
+	if hi < len(b) || prev[len(b)] > limit {
+		return exceeded()
+	}
+	return int64(prev[len(b)]), true
+}
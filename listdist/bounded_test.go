@@ -0,0 +1,59 @@
+package listdist_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+func (s *S) TestDistanceBounded(c *C) {
+	for _, test := range distanceTests {
+		if test.cut != 0 {
+			continue
+		}
+		c.Logf("Test: %v", test)
+		a, b := splitString(test.a), splitString(test.b)
+		for max := int64(0); max <= test.r+1; max++ {
+			r, ok := listdist.DistanceBounded(a, b, test.f, max)
+			if max < test.r {
+				c.Assert(ok, Equals, false)
+				c.Assert(r > max, Equals, true)
+			} else {
+				c.Assert(ok, Equals, true)
+				c.Assert(r, Equals, test.r)
+			}
+		}
+	}
+	_, ok := listdist.DistanceBounded(nil, nil, listdist.StandardCost, -1)
+	c.Assert(ok, Equals, false)
+	r, ok := listdist.DistanceBounded(splitString("ab"), splitString("ba"), listdist.StandardCost, listdist.Inhibit)
+	c.Assert([]any{r, ok}, DeepEquals, []any{int64(2), true})
+}
+
+func (s *S) TestDistanceBoundedRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 500; i++ {
+		a := randomBytes(rnd, rnd.Intn(20))
+		b := randomBytes(rnd, rnd.Intn(20))
+		for _, f := range []listdist.CostFuncOf[byte]{listdist.StandardCostOf[byte], contextCost, insertDeleteCost} {
+			expected := listdist.DistanceOf(a, b, f, 0)
+			max := rnd.Int63n(expected + 2)
+			r, ok := listdist.DistanceBoundedOf(a, b, f, max)
+			c.Assert(ok, Equals, expected <= max)
+			if ok {
+				c.Assert(r, Equals, expected)
+			}
+		}
+	}
+}
+
+func BenchmarkDistanceBounded(b *testing.B) {
+	one := splitString("abdefghijklmnopqrstuvwxyz")
+	two := splitString("a.d.f.h.j.l.n.p.r.t.v.x.z")
+	for i := 0; i < b.N; i++ {
+		listdist.DistanceBounded(one, two, listdist.StandardCost, 1)
+	}
+}
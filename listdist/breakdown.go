@@ -0,0 +1,107 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+import (
+	"fmt"
+)
+
+// Breakdown splits the cost of a script turning list a into list b by the
+// kind of its operations, for explaining where a distance comes from.
+// Costs are Inhibit if any operation of their kind is inhibited.
+type Breakdown struct {
+	SwapCost   CostInt
+	DeleteCost CostInt
+	InsertCost CostInt
+
+	// Equals, Swaps, Deletes, and Inserts count the operations of each
+	// kind in the script.
+	Equals  int
+	Swaps   int
+	Deletes int
+	Inserts int
+}
+
+// Total returns the total cost of the script, which is Inhibit if any of
+// its operations is inhibited.
+func (bd *Breakdown) Total() CostInt {
+	return addCost(addCost(bd.SwapCost, bd.DeleteCost), bd.InsertCost)
+}
+
+// DistanceBreakdown returns the distance between a and b as computed by
+// Distance, along with its breakdown for the script returned by Diff.
+func DistanceBreakdown(a, b []any, f CostFunc) (int64, *Breakdown) {
+	return DistanceBreakdownOf(a, b, boxedCost(f))
+}
+
+// DistanceBreakdownOf is the generic form of DistanceBreakdown.
+func DistanceBreakdownOf[T comparable](a, b []T, f CostFuncOf[T]) (int64, *Breakdown) {
+	bd := ScriptCostOf(a, b, DiffOf(a, b, f), f)
+	return int64(bd.Total()), bd
+}
+
+// ScriptCost returns the breakdown of the cost of script ops turning a
+// into b, with the cost of each operation computed by f as done by
+// Distance. The cost of deleting an element of a and of inserting an
+// element of b depend on the element of the other list edited last, if
+// any, as f is called with both.
+//
+// ScriptCost panics if ops isn't a script turning a into b in order, as
+// returned by Diff, which excludes scripts with Move operations.
+func ScriptCost(a, b []any, ops []Op, f CostFunc) *Breakdown {
+	return ScriptCostOf(a, b, ops, boxedCost(f))
+}
+
+// ScriptCostOf is the generic form of ScriptCost.
+func ScriptCostOf[T comparable](a, b []T, ops []Op, f CostFuncOf[T]) *Breakdown {
+	bd := &Breakdown{}
+	i, j := 0, 0
+	for _, op := range ops {
+		switch {
+		case (op.Kind == Equal || op.Kind == Swap) && op.A == i && op.B == j && i < len(a) && j < len(b):
+			if op.Kind == Equal {
+				bd.Equals++
+			} else {
+				bd.SwapCost = addCost(bd.SwapCost, f(&a[i], &b[j]).SwapAB)
+				bd.Swaps++
+			}
+			i++
+			j++
+		case op.Kind == Delete && op.A == i && op.B == -1 && i < len(a):
+			var br *T
+			if j > 0 {
+				br = &b[j-1]
+			}
+			bd.DeleteCost = addCost(bd.DeleteCost, f(&a[i], br).DeleteA)
+			bd.Deletes++
+			i++
+		case op.Kind == Insert && op.A == -1 && op.B == j && j < len(b):
+			var ar *T
+			if i > 0 {
+				ar = &a[i-1]
+			}
+			bd.InsertCost = addCost(bd.InsertCost, f(ar, &b[j]).InsertB)
+			bd.Inserts++
+			j++
+		default:
+			panic(fmt.Sprintf("listdist: %s operation at %d, %d doesn't follow the script at %d, %d", op.Kind, op.A, op.B, i, j))
+		}
+	}
+	if i != len(a) || j != len(b) {
+		panic(fmt.Sprintf("listdist: script ends at %d, %d rather than at %d, %d", i, j, len(a), len(b)))
+	}
+	return bd
+}
@@ -0,0 +1,66 @@
+package listdist_test
+
+import (
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+func (s *S) TestDistanceBreakdown(c *C) {
+	cost := func(ar, br *byte) listdist.Cost {
+		return listdist.Cost{SwapAB: 3, DeleteA: 2, InsertB: 1}
+	}
+	dist, bd := listdist.DistanceBreakdownOf([]byte("abcd"), []byte("xbd"), cost)
+	c.Assert(dist, Equals, int64(5))
+	c.Assert(bd, DeepEquals, &listdist.Breakdown{
+		SwapCost:   3,
+		DeleteCost: 2,
+		Equals:     2,
+		Swaps:      1,
+		Deletes:    1,
+	})
+
+	dist, bd = listdist.DistanceBreakdown([]any{"a"}, []any{"b", "c"}, listdist.InsertDeleteCost)
+	c.Assert(dist, Equals, int64(3))
+	c.Assert(bd, DeepEquals, &listdist.Breakdown{DeleteCost: 1, InsertCost: 2, Deletes: 1, Inserts: 2})
+
+	inhibit := func(ar, br *byte) listdist.Cost {
+		return listdist.Cost{SwapAB: listdist.Inhibit, DeleteA: listdist.Inhibit, InsertB: 1}
+	}
+	dist, bd = listdist.DistanceBreakdownOf([]byte("a"), []byte("b"), inhibit)
+	c.Assert(dist, Equals, int64(listdist.Inhibit))
+	c.Assert(bd.Total(), Equals, listdist.CostInt(listdist.Inhibit))
+}
+
+func (s *S) TestScriptCostInvalid(c *C) {
+	a, b := []byte("ab"), []byte("b")
+	ops := []listdist.Op{{Kind: listdist.Equal, A: 1, B: 0}}
+	c.Assert(func() { listdist.ScriptCostOf(a, b, ops, listdist.StandardCostOf[byte]) }, PanicMatches,
+		`listdist: equal operation at 1, 0 doesn't follow the script at 0, 0`)
+	ops = []listdist.Op{{Kind: listdist.Delete, A: 0, B: -1}}
+	c.Assert(func() { listdist.ScriptCostOf(a, b, ops, listdist.StandardCostOf[byte]) }, PanicMatches,
+		`listdist: script ends at 1, 0 rather than at 2, 1`)
+}
+
+func (s *S) TestDistanceBreakdownRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		a := randomBytes(rnd, rnd.Intn(12))
+		b := randomBytes(rnd, rnd.Intn(12))
+		f := randomCost(rnd)
+		if i%2 == 0 {
+			f = contextCost
+		}
+		c.Logf("Test %d: %q %q", i, a, b)
+		dist, bd := listdist.DistanceBreakdownOf(a, b, f)
+		c.Assert(dist, Equals, listdist.DistanceOf(a, b, f, 0))
+		if dist != listdist.Inhibit {
+			ops := listdist.DiffOf(a, b, f)
+			c.Assert(dist, Equals, scriptCost(c, a, b, ops, f))
+		}
+		c.Assert(bd.Equals+bd.Swaps+bd.Deletes, Equals, len(a))
+		c.Assert(bd.Equals+bd.Swaps+bd.Inserts, Equals, len(b))
+	}
+}
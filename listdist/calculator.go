@@ -0,0 +1,60 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+// Calculator computes distances and scripts between lists of elements of
+// type T, keeping the memory used for that from one call to the next, so
+// that comparing many pairs of lists in a loop does not allocate it every
+// time. The zero value is ready to use. A Calculator must not be used by
+// several goroutines at once.
+type Calculator[T comparable] struct {
+	// row is the row of the cost matrix computed by distance.
+	row []CostInt
+
+	// kindPC and kind are the address and kind of the last cost function.
+	kindPC uintptr
+	kind   costKind
+
+	// peq, eqs, pv, and mv are used by bitParallel.
+	peq         map[T]int
+	eqs, pv, mv []uint64
+
+	differ differ[T]
+}
+
+// Distance is the same as DistanceOf.
+func (c *Calculator[T]) Distance(a, b []T, f CostFuncOf[T], cut int64) int64 {
+	dist, _ := c.distance(nil, a, b, f, nil, cut)
+	return dist
+}
+
+// Diff is the same as DiffOf. The script returned is not reused by later
+// calls.
+func (c *Calculator[T]) Diff(a, b []T, f CostFuncOf[T]) []Op {
+	return c.diff(a, b, indexedCost(f))
+}
+
+// diff implements Diff and DiffIndexedOf.
+func (c *Calculator[T]) diff(a, b []T, f IndexCostFuncOf[T]) []Op {
+	d := &c.differ
+	d.a, d.b, d.f = a, b, f
+	d.ops = make([]Op, 0, max(len(a), len(b)))
+	d.diff(0, len(a), 0, len(b))
+	ops := d.ops
+	// Don't hold on to the lists until the next call.
+	d.a, d.b, d.f, d.ops = nil, nil, nil, nil
+	return ops
+}
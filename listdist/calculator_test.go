@@ -0,0 +1,47 @@
+package listdist_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+func (s *S) TestCalculator(c *C) {
+	defer listdist.SetDiffMatrixLimit(16)()
+
+	var calc listdist.Calculator[byte]
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		// Lists of varying sizes, so buffers are both grown and reused.
+		a := randomBytes(rnd, rnd.Intn(100))
+		b := randomBytes(rnd, rnd.Intn(100))
+		for j, f := range []listdist.CostFuncOf[byte]{listdist.StandardCostOf[byte], uniformCost, insertDeleteCost, contextCost} {
+			c.Logf("Test %d: %q, %q, cost %d", i, a, b, j)
+			c.Assert(calc.Distance(a, b, f, 0), Equals, listdist.DistanceOf(a, b, f, 0))
+			c.Assert(calc.Diff(a, b, f), DeepEquals, listdist.DiffOf(a, b, f))
+		}
+	}
+}
+
+func (s *S) TestCalculatorAllocs(c *C) {
+	var calc listdist.Calculator[byte]
+	a := []byte("the quick brown fox jumps over the lazy dog")
+	b := []byte("the quick brown cat jumped over a lazy dog")
+	for _, f := range []listdist.CostFuncOf[byte]{listdist.StandardCostOf[byte], uniformCost} {
+		calc.Distance(a, b, f, 0)
+		allocs := testing.AllocsPerRun(10, func() {
+			calc.Distance(b, a, f, 0)
+		})
+		c.Assert(allocs, Equals, 0.0)
+	}
+}
+
+func BenchmarkCalculatorDistance(b *testing.B) {
+	var calc listdist.Calculator[byte]
+	for i := 0; i < b.N; i++ {
+		calc.Distance(longA, longB, uniformCost, 0)
+	}
+}
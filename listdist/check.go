@@ -0,0 +1,69 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+import (
+	"fmt"
+)
+
+// CheckMetric verifies that the distance computed with f behaves as a metric
+// over the provided samples: the distance between a list and itself is zero,
+// the distance from a to b is the same as from b to a, and the distance from
+// a to c is never above the distance from a to b plus the distance from b to c.
+// Inhibited distances are considered infinite, so they never violate the
+// triangle inequality.
+//
+// CheckMetric is meant to be used in tests, and returns an error describing
+// the first violation found.
+func CheckMetric(f CostFunc, samples [][]any) error {
+	n := len(samples)
+	dists := make([][]int64, n)
+	for i, a := range samples {
+		dists[i] = make([]int64, n)
+		for j, b := range samples {
+			dists[i][j] = Distance(a, b, f, 0)
+		}
+	}
+	for i, a := range samples {
+		if dist := dists[i][i]; dist != 0 {
+			return fmt.Errorf("distance between %v and itself is %v", a, CostInt(dist))
+		}
+	}
+	for i, a := range samples {
+		for j := i + 1; j < n; j++ {
+			if dists[i][j] != dists[j][i] {
+				b := samples[j]
+				return fmt.Errorf("distance from %v to %v is %v but from %v to %v is %v", a, b, CostInt(dists[i][j]), b, a, CostInt(dists[j][i]))
+			}
+		}
+	}
+	for i, a := range samples {
+		for j, b := range samples {
+			if dists[i][j] == Inhibit {
+				continue
+			}
+			for k, c := range samples {
+				if dists[j][k] == Inhibit {
+					continue
+				}
+				if through := dists[i][j] + dists[j][k]; through < dists[i][k] {
+					return fmt.Errorf("distance from %v to %v is %v, above %v via %v", a, c, CostInt(dists[i][k]), through, b)
+				}
+			}
+		}
+	}
+	return nil
+}
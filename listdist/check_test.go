@@ -0,0 +1,22 @@
+package listdist_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+func (s *S) TestCheckMetric(c *C) {
+	samples := [][]any{splitString(""), splitString("ab"), splitString("abc"), splitString("xbc")}
+	c.Assert(listdist.CheckMetric(listdist.StandardCost, samples), IsNil)
+
+	c.Assert(listdist.CheckMetric(uniqueCost, samples), ErrorMatches, `distance from \[\] to \[a b\] is 10 but from \[a b\] to \[\] is 6`)
+
+	expensiveX := func(ar, br any) listdist.Cost {
+		if ar == "x" || br == "x" {
+			return listdist.Cost{SwapAB: 1, DeleteA: 10, InsertB: 10}
+		}
+		return listdist.Cost{SwapAB: 10, DeleteA: 1, InsertB: 1}
+	}
+	c.Assert(listdist.CheckMetric(expensiveX, samples), ErrorMatches, `distance from \[\] to \[x b c\] is 12, above 4 via \[a b\]`)
+}
@@ -0,0 +1,216 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+// CircularDistance returns the lowest distance between any rotation of
+// list a and list b, along with the lowest shift for which a[shift:]
+// followed by a[:shift] is at that distance from b. This compares lists
+// holding elements in a ring, with no particular start, such as the nodes
+// of a cycle or the contents of a circular buffer.
+//
+// As the elements of a are in a ring, f is given the last element of a
+// rotation as the one before its first, when computing the cost of
+// inserting elements of b at the start, rather than nil as done by
+// Distance. The distance is otherwise the same as computed by Distance
+// for each rotation.
+//
+// This is the algorithm by Maes, "On a cyclic string-to-string correction
+// problem" (1990), which relies on the paths through the cost matrix for
+// different rotations not crossing each other to only look for the path
+// of each rotation between those of two others. It runs in O(nm log n)
+// time rather than O(n²m) for computing the distance of every rotation,
+// where n and m are the lengths of a and b, and needs O(nm) bytes.
+func CircularDistance(a, b []any, f CostFunc) (dist int64, shift int) {
+	return CircularDistanceOf(a, b, boxedCost(f))
+}
+
+// CircularDistanceOf is the generic form of CircularDistance.
+func CircularDistanceOf[T comparable](a, b []T, f CostFuncOf[T]) (dist int64, shift int) {
+	if len(a) == 0 || len(b) == 0 {
+		return DistanceOf(a, b, f, 0), 0
+	}
+	c := &circular[T]{a: a, b: b, f: f}
+	first := c.solve(0, nil, nil)
+	var last *ringPath
+	if first != nil {
+		last = &ringPath{start: len(a), first: first.first, last: first.last}
+	}
+	c.between(first, last, 0, len(a))
+	return int64(c.dist), c.shift
+}
+
+// circular finds the distance between the rotations of a and b, as paths
+// through the grid of nodes (i, j) for i elements of a followed by a again
+// and j elements of b. The path for the rotation by k goes from (k, 0) to
+// (k+n, m), and the edge costs of the grid are the same for all of them.
+type circular[T comparable] struct {
+	a, b []T
+	f    CostFuncOf[T]
+
+	// dist and shift hold the best rotation found so far.
+	dist  CostInt
+	shift int
+	found bool
+
+	// lo and hi hold the range of columns within the region searched for
+	// each row of a path, and off the position of the first one in moves,
+	// which holds the last edge of the best path to every node. The costs
+	// of the nodes in the previous and current rows are in prev and row.
+	lo, hi, off []int
+	moves       []uint8
+	prev, row   []CostInt
+}
+
+// ringPath is the path through the grid for the rotation by start, with
+// the range of columns it goes through in each row from start.
+type ringPath struct {
+	start       int
+	first, last []int
+}
+
+const (
+	ringSwap uint8 = iota + 1
+	ringDelete
+	ringInsert
+)
+
+// between finds the paths for the rotations after from and before to,
+// which they are known not to cross those of prev and next. The paths of
+// rotations at an inhibited distance aren't the lowest cost paths between
+// their nodes, so they don't bound the others, and the bounds of the
+// rotations around them are the paths of prev and next instead, or none.
+func (c *circular[T]) between(prev, next *ringPath, from, to int) {
+	if to-from < 2 {
+		return
+	}
+	mid := (from + to) / 2
+	path := c.solve(mid, prev, next)
+	if path == nil {
+		c.between(prev, next, from, mid)
+		c.between(prev, next, mid, to)
+		return
+	}
+	c.between(prev, path, from, mid)
+	c.between(path, next, mid, to)
+}
+
+// solve returns the lowest cost path for the rotation by start, only
+// going through nodes between the paths prev and next if they're set, and
+// records its cost. It returns nil if the distance is inhibited.
+func (c *circular[T]) solve(start int, prev, next *ringPath) *ringPath {
+	n, m := len(c.a), len(c.b)
+	grow(&c.lo, n+1)
+	grow(&c.hi, n+1)
+	grow(&c.off, n+1)
+	size := 0
+	for r := 0; r <= n; r++ {
+		i := start + r
+		c.lo[r], c.hi[r] = 0, m
+		if next != nil && i >= next.start {
+			c.lo[r] = next.first[i-next.start]
+		}
+		if prev != nil && i <= prev.start+n {
+			c.hi[r] = prev.last[i-prev.start]
+		}
+		c.off[r] = size
+		size += c.hi[r] - c.lo[r] + 1
+	}
+	moves := grow(&c.moves, size)
+	prevRow := grow(&c.prev, m+1)
+	row := grow(&c.row, m+1)
+
+	for r := 0; r <= n; r++ {
+		i := start + r
+		for j := c.lo[r]; j <= c.hi[r]; j++ {
+			if r == 0 && j == 0 {
+				row[j] = 0
+				continue
+			}
+			best, move := CostInt(Inhibit), uint8(0)
+			if r > 0 && j > 0 && c.lo[r-1] <= j-1 && j-1 <= c.hi[r-1] {
+				best, move = addCost(prevRow[j-1], c.swapCost(i-1, j-1)), ringSwap
+			}
+			if r > 0 && c.lo[r-1] <= j && j <= c.hi[r-1] {
+				if cost := addCost(prevRow[j], c.deleteCost(i-1, j)); move == 0 || cost < best {
+					best, move = cost, ringDelete
+				}
+			}
+			if j > c.lo[r] {
+				if cost := addCost(row[j-1], c.insertCost(i, j-1)); move == 0 || cost < best {
+					best, move = cost, ringInsert
+				}
+			}
+			row[j] = best
+			moves[c.off[r]+j-c.lo[r]] = move
+		}
+		prevRow, row = row, prevRow
+	}
+	if dist := prevRow[m]; !c.found || dist < c.dist || dist == c.dist && start < c.shift {
+		c.dist, c.shift, c.found = dist, start, true
+	}
+	if prevRow[m] == Inhibit {
+		return nil
+	}
+
+	path := &ringPath{start: start, first: make([]int, n+1), last: make([]int, n+1)}
+	r, j := n, m
+	path.last[r] = j
+	for {
+		path.first[r] = j
+		if r == 0 && j == 0 {
+			break
+		}
+		switch moves[c.off[r]+j-c.lo[r]] {
+		case ringSwap:
+			r, j = r-1, j-1
+			path.last[r] = j
+		case ringDelete:
+			r--
+			path.last[r] = j
+		case ringInsert:
+			j--
+		}
+	}
+	return path
+}
+
+// elem returns the element of a at row i of the grid.
+func (c *circular[T]) elem(i int) *T {
+	return &c.a[i%len(c.a)]
+}
+
+// swapCost returns the cost of the edge from (i, j) to (i+1, j+1).
+func (c *circular[T]) swapCost(i, j int) CostInt {
+	ar := c.elem(i)
+	if *ar == c.b[j] {
+		return 0
+	}
+	return c.f(ar, &c.b[j]).SwapAB
+}
+
+// deleteCost returns the cost of the edge from (i, j) to (i+1, j).
+func (c *circular[T]) deleteCost(i, j int) CostInt {
+	if j == 0 {
+		return c.f(c.elem(i), nil).DeleteA
+	}
+	return c.f(c.elem(i), &c.b[j-1]).DeleteA
+}
+
+// insertCost returns the cost of the edge from (i, j) to (i, j+1), which
+// follows the element before row i, wrapping around the ring.
+func (c *circular[T]) insertCost(i, j int) CostInt {
+	return c.f(c.elem(i+len(c.a)-1), &c.b[j]).InsertB
+}
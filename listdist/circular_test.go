@@ -0,0 +1,95 @@
+package listdist_test
+
+import (
+	"math/bits"
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+var circularTests = []struct {
+	a, b  string
+	dist  int64
+	shift int
+}{
+	{a: "", b: "", dist: 0, shift: 0},
+	{a: "abc", b: "", dist: 3, shift: 0},
+	{a: "", b: "abc", dist: 3, shift: 0},
+	{a: "abc", b: "abc", dist: 0, shift: 0},
+	{a: "abc", b: "bca", dist: 0, shift: 1},
+	{a: "abc", b: "cab", dist: 0, shift: 2},
+	{a: "abcd", b: "cdxab", dist: 1, shift: 2},
+	{a: "abcd", b: "dab", dist: 1, shift: 2},
+	{a: "aaaa", b: "aa", dist: 2, shift: 0},
+	{a: "abcabc", b: "cabcab", dist: 0, shift: 2},
+	{a: "xyz", b: "abc", dist: 3, shift: 0},
+}
+
+func (s *S) TestCircularDistance(c *C) {
+	for _, test := range circularTests {
+		c.Logf("Test: %v", test)
+		dist, shift := listdist.CircularDistance(splitString(test.a), splitString(test.b), listdist.StandardCost)
+		c.Assert(dist, Equals, test.dist)
+		c.Assert(shift, Equals, test.shift)
+		dist, shift = listdist.CircularDistanceOf([]byte(test.a), []byte(test.b), listdist.StandardCostOf[byte])
+		c.Assert(dist, Equals, test.dist)
+		c.Assert(shift, Equals, test.shift)
+	}
+}
+
+// ringDistance returns the distance between a rotated by shift and b as
+// defined by CircularDistance, with the last element of the rotation as
+// context for inserting elements at its start.
+func ringDistance(a, b []byte, shift int, f listdist.CostFuncOf[byte]) listdist.CostInt {
+	rotated := append(append([]byte(nil), a[shift:]...), a[:shift]...)
+	before := &rotated[len(rotated)-1]
+	return referenceDistance(rotated, b, func(ar, br *byte) listdist.Cost {
+		cost := f(ar, br)
+		if ar == nil && br != nil {
+			cost.InsertB = f(before, br).InsertB
+		}
+		return cost
+	})
+}
+
+func (s *S) TestCircularDistanceRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		a := randomBytes(rnd, 1+rnd.Intn(7))
+		b := randomBytes(rnd, 1+rnd.Intn(7))
+		for j, f := range []listdist.CostFuncOf[byte]{listdist.StandardCostOf[byte], contextCost, insertDeleteCost, randomCost(rnd)} {
+			c.Logf("Test %d: %q, %q, cost %d", i, a, b, j)
+			want, wantShift := ringDistance(a, b, 0, f), 0
+			for shift := 1; shift < len(a); shift++ {
+				if dist := ringDistance(a, b, shift, f); dist < want {
+					want, wantShift = dist, shift
+				}
+			}
+			dist, shift := listdist.CircularDistanceOf(a, b, f)
+			c.Assert(dist, Equals, int64(want))
+			c.Assert(shift, Equals, wantShift)
+		}
+	}
+}
+
+func (s *S) TestCircularDistanceLong(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	a := randomBytes(rnd, 300)
+	b := append(append([]byte(nil), a[120:]...), a[:120]...)
+	b[10], b[200] = 'y', 'z'
+	calls := 0
+	f := func(ar, br *byte) listdist.Cost {
+		calls++
+		return listdist.Cost{SwapAB: 1, DeleteA: 1, InsertB: 1}
+	}
+	dist, shift := listdist.CircularDistanceOf(a, b, f)
+	c.Assert(dist, Equals, int64(2))
+	c.Assert(shift, Equals, 120)
+
+	// Computing every rotation would take about 3n³ calls.
+	n := len(a)
+	c.Logf("%d calls", calls)
+	c.Assert(calls < 3*(n+1)*(n+1)*(bits.Len(uint(n))+1), Equals, true)
+}
@@ -0,0 +1,69 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+import (
+	"fmt"
+)
+
+// Costs returns a CostFunc with the same costs for every element: swap
+// for swapping an element of a with one of b, del for deleting an element
+// of a, and ins for inserting an element of b. Any of them may be Inhibit
+// to disallow that kind of edit. Costs(1, 1, 1) is StandardCost and
+// Costs(Inhibit, 1, 1) is InsertDeleteCost, so the shortcuts taken with
+// those are still taken. Costs panics if a cost is negative.
+func Costs(swap, del, ins int64) CostFunc {
+	switch cost := newCost(swap, del, ins); cost {
+	case StandardCost(nil, nil):
+		return StandardCost
+	case InsertDeleteCost(nil, nil):
+		return InsertDeleteCost
+	default:
+		return func(ar, br any) Cost { return cost }
+	}
+}
+
+// CostsOf is the generic form of Costs.
+func CostsOf[T any](swap, del, ins int64) CostFuncOf[T] {
+	switch cost := newCost(swap, del, ins); cost {
+	case StandardCostOf[T](nil, nil):
+		return markCost(StandardCostOf[T], standardCost)
+	case InsertDeleteCostOf[T](nil, nil):
+		return markCost(InsertDeleteCostOf[T], insertDeleteCost)
+	default:
+		return func(ar, br *T) Cost { return cost }
+	}
+}
+
+// InhibitSwaps returns a CostFunc allowing only deletions at del and
+// insertions at ins, so that the distance is the cost of the elements not
+// in the longest common subsequence. InhibitSwaps(1, 1) is
+// InsertDeleteCost.
+func InhibitSwaps(del, ins int64) CostFunc {
+	return Costs(Inhibit, del, ins)
+}
+
+// InhibitSwapsOf is the generic form of InhibitSwaps.
+func InhibitSwapsOf[T any](del, ins int64) CostFuncOf[T] {
+	return CostsOf[T](Inhibit, del, ins)
+}
+
+func newCost(swap, del, ins int64) Cost {
+	if swap < 0 || del < 0 || ins < 0 {
+		panic(fmt.Sprintf("listdist: negative cost in %d, %d, %d", swap, del, ins))
+	}
+	return Cost{SwapAB: CostInt(swap), DeleteA: CostInt(del), InsertB: CostInt(ins)}
+}
@@ -0,0 +1,68 @@
+package listdist_test
+
+import (
+	"math/rand"
+	"reflect"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+var costsTests = []struct {
+	a, b           string
+	swap, del, ins int64
+	dist           int64
+}{
+	{a: "abc", b: "abd", swap: 1, del: 1, ins: 1, dist: 1},
+	{a: "abc", b: "abd", swap: 3, del: 1, ins: 1, dist: 2},
+	{a: "abc", b: "abd", swap: 3, del: 1, ins: 5, dist: 3},
+	{a: "abc", b: "abd", swap: listdist.Inhibit, del: 1, ins: 1, dist: 2},
+	{a: "abc", b: "ab", swap: 1, del: listdist.Inhibit, ins: 1, dist: listdist.Inhibit},
+	{a: "ab", b: "abc", swap: 1, del: 1, ins: listdist.Inhibit, dist: listdist.Inhibit},
+	{a: "abc", b: "abc", swap: listdist.Inhibit, del: listdist.Inhibit, ins: listdist.Inhibit, dist: 0},
+	{a: "abc", b: "xyz", swap: 0, del: 1, ins: 1, dist: 0},
+}
+
+func (s *S) TestCosts(c *C) {
+	for _, test := range costsTests {
+		c.Logf("Test: %v", test)
+		a, b := splitString(test.a), splitString(test.b)
+		f := listdist.Costs(test.swap, test.del, test.ins)
+		c.Assert(f("a", "b"), Equals, listdist.Cost{SwapAB: listdist.CostInt(test.swap), DeleteA: listdist.CostInt(test.del), InsertB: listdist.CostInt(test.ins)})
+		c.Assert(listdist.Distance(a, b, f, 0), Equals, test.dist)
+		c.Assert(listdist.DistanceOf([]byte(test.a), []byte(test.b), listdist.CostsOf[byte](test.swap, test.del, test.ins), 0), Equals, test.dist)
+		if test.swap == listdist.Inhibit {
+			c.Assert(listdist.Distance(a, b, listdist.InhibitSwaps(test.del, test.ins), 0), Equals, test.dist)
+			c.Assert(listdist.DistanceOf([]byte(test.a), []byte(test.b), listdist.InhibitSwapsOf[byte](test.del, test.ins), 0), Equals, test.dist)
+		}
+	}
+
+	// The same shortcuts are taken as with the predefined functions.
+	pointer := func(f any) uintptr { return reflect.ValueOf(f).Pointer() }
+	c.Assert(pointer(listdist.Costs(1, 1, 1)), Equals, pointer(listdist.StandardCost))
+	c.Assert(pointer(listdist.InhibitSwaps(1, 1)), Equals, pointer(listdist.InsertDeleteCost))
+	c.Assert(listdist.CostKind(listdist.CostsOf[byte](1, 1, 1)), Equals, "standard")
+	c.Assert(listdist.CostKind(listdist.CostsOf[string](1, 1, 1)), Equals, "standard")
+	c.Assert(listdist.CostKind(listdist.InhibitSwapsOf[byte](1, 1)), Equals, "insert-delete")
+	c.Assert(listdist.CostKind(listdist.InsertDeleteCostOf[byte]), Equals, "insert-delete")
+	c.Assert(listdist.CostKind(listdist.CostsOf[byte](2, 1, 1)), Equals, "other")
+	c.Assert(listdist.CostKind(listdist.InhibitSwapsOf[byte](2, 1)), Equals, "other")
+
+	c.Assert(func() { listdist.Costs(1, -1, 1) }, PanicMatches, `listdist: negative cost in 1, -1, 1`)
+}
+
+func (s *S) TestCostsRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	costs := []int64{0, 1, 2, 5, listdist.Inhibit}
+	for i := 0; i < 300; i++ {
+		a := randomBytes(rnd, rnd.Intn(12))
+		b := randomBytes(rnd, rnd.Intn(12))
+		swap, del, ins := costs[rnd.Intn(len(costs))], costs[rnd.Intn(len(costs))], costs[rnd.Intn(len(costs))]
+		c.Logf("Test %d: %q => %q (%d, %d, %d)", i, a, b, swap, del, ins)
+		want := listdist.DistanceOf(a, b, func(ar, br *byte) listdist.Cost {
+			return listdist.Cost{SwapAB: listdist.CostInt(swap), DeleteA: listdist.CostInt(del), InsertB: listdist.CostInt(ins)}
+		}, 0)
+		c.Assert(listdist.DistanceOf(a, b, listdist.CostsOf[byte](swap, del, ins), 0), Equals, want)
+	}
+}
@@ -0,0 +1,288 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+import (
+	"fmt"
+)
+
+// OpKind is the kind of an edit operation in a script returned by Diff.
+type OpKind int
+
+const (
+	// Equal keeps an element of a that is equal to an element of b.
+	Equal OpKind = iota + 1
+	// Swap replaces an element of a with an element of b.
+	Swap
+	// Delete removes an element of a.
+	Delete
+	// Insert adds an element of b.
+	Insert
+	// Move takes an element of a that is equal to an element of b from
+	// elsewhere in the list. It's only found in scripts returned by
+	// DetectMoves.
+	Move
+)
+
+func (k OpKind) String() string {
+	switch k {
+	case Equal:
+		return "equal"
+	case Swap:
+		return "swap"
+	case Delete:
+		return "delete"
+	case Insert:
+		return "insert"
+	case Move:
+		return "move"
+	}
+	return fmt.Sprintf("OpKind(%d)", int(k))
+}
+
+// Op is an edit operation in a script turning list a into list b.
+type Op struct {
+	Kind OpKind
+
+	// A is the index of the element of a affected by the operation,
+	// or -1 for insertions.
+	A int
+
+	// B is the index of the element of b affected by the operation,
+	// or -1 for deletions.
+	B int
+}
+
+// Diff returns a script of minimum cost turning a into b, with costs as
+// computed by Distance. Operations are ordered by their position in both
+// lists, and deletions come before insertions at the same position.
+//
+// If every script is inhibited by f, the script returned is one of them,
+// and includes inhibited operations.
+func Diff(a, b []any, f CostFunc) []Op {
+	return DiffOf(a, b, boxedCost(f))
+}
+
+// DiffOf is the generic form of Diff.
+//
+// Small inputs are aligned by tracing back the full cost matrix. Larger
+// ones use Hirschberg's divide and conquer algorithm, which finds where the
+// optimal script crosses the middle element of a by computing the cost of
+// the first half of a forwards and of the second half backwards, and then
+// recurses on both halves. It takes a few times longer than Distance, but
+// only linear space, so it works with lists of millions of elements.
+func DiffOf[T comparable](a, b []T, f CostFuncOf[T]) []Op {
+	return DiffIndexedOf(a, b, indexedCost(f))
+}
+
+// DiffIndexed is the same as Diff, except that f is given the position
+// of the elements in their lists, as done by DistanceIndexed.
+func DiffIndexed(a, b []any, f IndexCostFunc) []Op {
+	return DiffIndexedOf(a, b, boxedIndexCost(f))
+}
+
+// DiffIndexedOf is the generic form of DiffIndexed.
+func DiffIndexedOf[T comparable](a, b []T, f IndexCostFuncOf[T]) []Op {
+	return new(Calculator[T]).diff(a, b, f)
+}
+
+// diffMatrixLimit is the largest subproblem, in number of cost matrix
+// cells, that is aligned by full traceback rather than split further.
+var diffMatrixLimit = 4096
+
+type differ[T comparable] struct {
+	a, b []T
+	f    IndexCostFuncOf[T]
+	ops  []Op
+
+	// fwd and bwd are reused across the passes of Hirschberg's algorithm,
+	// and cells across the subproblems aligned by traceback.
+	fwd, bwd, cells []CostInt
+}
+
+// addCost returns x + y, or Inhibit if either of them is Inhibit.
+func addCost(x, y CostInt) CostInt {
+	if x == Inhibit || y == Inhibit {
+		return Inhibit
+	}
+	return x + y
+}
+
+// The script is a path through the grid of nodes (i, j), for i elements
+// of a and j elements of b edited so far, and the cost of each edge in the
+// grid matches the cost used for it by Distance.
+
+// insertCost returns the cost of the edge from (i, j) to (i, j+1).
+func (d *differ[T]) insertCost(i, j int) CostInt {
+	if i == 0 {
+		return d.f(-1, j, nil, &d.b[j]).InsertB
+	}
+	return d.f(i-1, j, &d.a[i-1], &d.b[j]).InsertB
+}
+
+// deleteCost returns the cost of the edge from (i, j) to (i+1, j).
+func (d *differ[T]) deleteCost(i, j int) CostInt {
+	if j == 0 {
+		return d.f(i, -1, &d.a[i], nil).DeleteA
+	}
+	return d.f(i, j-1, &d.a[i], &d.b[j-1]).DeleteA
+}
+
+// swapCost returns the cost of the edge from (i, j) to (i+1, j+1).
+func (d *differ[T]) swapCost(i, j int) CostInt {
+	if d.a[i] == d.b[j] {
+		return 0
+	}
+	return d.f(i, j, &d.a[i], &d.b[j]).SwapAB
+}
+
+// diff appends the script turning a[a0:a1] into b[b0:b1] to d.ops.
+func (d *differ[T]) diff(a0, a1, b0, b1 int) {
+	switch {
+	case a0 == a1:
+		for j := b0; j < b1; j++ {
+			d.ops = append(d.ops, Op{Kind: Insert, A: -1, B: j})
+		}
+		return
+	case b0 == b1:
+		for i := a0; i < a1; i++ {
+			d.ops = append(d.ops, Op{Kind: Delete, A: i, B: -1})
+		}
+		return
+	case a1-a0 == 1 || (a1-a0)*(b1-b0) <= diffMatrixLimit:
+		d.traceback(a0, a1, b0, b1)
+		return
+	}
+
+	mid := (a0 + a1) / 2
+	d.forward(a0, mid, b0, b1)
+	d.backward(mid, a1, b0, b1)
+	split := -1
+	best := CostInt(Inhibit)
+	for j := b0; j <= b1; j++ {
+		if cost := addCost(d.fwd[j-b0], d.bwd[j-b0]); split == -1 || cost < best {
+			split, best = j, cost
+		}
+	}
+	d.diff(a0, mid, b0, split)
+	d.diff(mid, a1, split, b1)
+}
+
+// forward sets d.fwd[j-b0] to the cost of the path from (a0, b0) to (a1, j).
+func (d *differ[T]) forward(a0, a1, b0, b1 int) {
+	row := grow(&d.fwd, b1-b0+1)
+	row[0] = 0
+	for j := b0; j < b1; j++ {
+		row[j-b0+1] = addCost(row[j-b0], d.insertCost(a0, j))
+	}
+	for i := a0; i < a1; i++ {
+		last := row[0]
+		row[0] = addCost(last, d.deleteCost(i, b0))
+		for j := b0; j < b1; j++ {
+			// The edges into (i+1, j+1) all come from the same call.
+			ar, br := &d.a[i], &d.b[j]
+			cost := d.f(i, j, ar, br)
+			if *ar == *br {
+				cost.SwapAB = 0
+			}
+			k := j - b0 + 1
+			best := addCost(last, cost.SwapAB)
+			best = min(best, addCost(row[k-1], cost.InsertB))
+			best = min(best, addCost(row[k], cost.DeleteA))
+			last, row[k] = row[k], best
+		}
+	}
+}
+
+// backward sets d.bwd[j-b0] to the cost of the path from (a0, j) to (a1, b1).
+func (d *differ[T]) backward(a0, a1, b0, b1 int) {
+	n := b1 - b0
+	row := grow(&d.bwd, n+1)
+	row[n] = 0
+	for j := b1 - 1; j >= b0; j-- {
+		row[j-b0] = addCost(row[j-b0+1], d.insertCost(a1, j))
+	}
+	for i := a1 - 1; i >= a0; i-- {
+		last := row[n]
+		row[n] = addCost(last, d.deleteCost(i, b1))
+		for j := b1 - 1; j >= b0; j-- {
+			k := j - b0
+			best := addCost(last, d.swapCost(i, j))
+			best = min(best, addCost(row[k+1], d.insertCost(i, j)))
+			best = min(best, addCost(row[k], d.deleteCost(i, j)))
+			last, row[k] = row[k], best
+		}
+	}
+}
+
+// traceback appends the script turning a[a0:a1] into b[b0:b1] to d.ops,
+// computing the full cost matrix for them.
+func (d *differ[T]) traceback(a0, a1, b0, b1 int) {
+	rows, cols := a1-a0+1, b1-b0+1
+	cells := grow(&d.cells, rows*cols)
+	cell := func(i, j int) *CostInt { return &cells[(i-a0)*cols+j-b0] }
+	*cell(a0, b0) = 0
+	for j := b0; j < b1; j++ {
+		*cell(a0, j+1) = addCost(*cell(a0, j), d.insertCost(a0, j))
+	}
+	for i := a0; i < a1; i++ {
+		*cell(i+1, b0) = addCost(*cell(i, b0), d.deleteCost(i, b0))
+		for j := b0; j < b1; j++ {
+			best := addCost(*cell(i, j), d.swapCost(i, j))
+			best = min(best, addCost(*cell(i+1, j), d.insertCost(i+1, j)))
+			best = min(best, addCost(*cell(i, j+1), d.deleteCost(i, j+1)))
+			*cell(i+1, j+1) = best
+		}
+	}
+
+	// Walk back from the end, preferring to keep or swap elements, and
+	// then insertions so that they end up after deletions in the script.
+	start := len(d.ops)
+	i, j := a1, b1
+	for i > a0 || j > b0 {
+		current := *cell(i, j)
+		if i > a0 && j > b0 && addCost(*cell(i-1, j-1), d.swapCost(i-1, j-1)) == current {
+			kind := Swap
+			if d.a[i-1] == d.b[j-1] {
+				kind = Equal
+			}
+			d.ops = append(d.ops, Op{Kind: kind, A: i - 1, B: j - 1})
+			i--
+			j--
+		} else if j > b0 && (i == a0 || addCost(*cell(i, j-1), d.insertCost(i, j-1)) == current) {
+			d.ops = append(d.ops, Op{Kind: Insert, A: -1, B: j - 1})
+			j--
+		} else {
+			d.ops = append(d.ops, Op{Kind: Delete, A: i - 1, B: -1})
+			i--
+		}
+	}
+	reverse(d.ops[start:])
+}
+
+func grow[E any](s *[]E, n int) []E {
+	if cap(*s) < n {
+		*s = make([]E, n)
+	}
+	*s = (*s)[:n]
+	return *s
+}
+
+func reverse[E any](s []E) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
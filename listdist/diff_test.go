@@ -0,0 +1,173 @@
+package listdist_test
+
+import (
+	"math/rand"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+type diffTest struct {
+	a, b string
+	ops  string
+}
+
+var diffTests = []diffTest{
+	{a: "", b: "", ops: ""},
+	{a: "abc", b: "abc", ops: "==="},
+	{a: "abc", b: "", ops: "---"},
+	{a: "", b: "abc", ops: "+++"},
+	{a: "abc", b: "abd", ops: "==~"},
+	{a: "abc", b: "ac", ops: "=-="},
+	{a: "ac", b: "abc", ops: "=+="},
+	{a: "abcdefg", b: "axcdfgh", ops: "=~==-==+"},
+}
+
+// opString renders ops compactly, for comparing them in tests.
+func opString(ops []listdist.Op) string {
+	var sb strings.Builder
+	for _, op := range ops {
+		sb.WriteByte(" =~-+>"[op.Kind])
+	}
+	return sb.String()
+}
+
+func (s *S) TestDiff(c *C) {
+	for _, test := range diffTests {
+		c.Logf("Test: %v", test)
+		ops := listdist.Diff(splitString(test.a), splitString(test.b), listdist.StandardCost)
+		c.Assert(opString(ops), Equals, test.ops)
+	}
+	c.Assert(listdist.Equal.String(), Equals, "equal")
+	c.Assert(listdist.OpKind(42).String(), Equals, "OpKind(42)")
+}
+
+// scriptCost returns the cost of applying ops to turn a into b, failing
+// if the script doesn't do that.
+func scriptCost(c *C, a, b []byte, ops []listdist.Op, f listdist.CostFuncOf[byte]) int64 {
+	return indexScriptCost(c, a, b, ops, func(ai, bi int, ar, br *byte) listdist.Cost {
+		return f(ar, br)
+	})
+}
+
+// indexScriptCost is the same as scriptCost, for position-aware costs.
+func indexScriptCost(c *C, a, b []byte, ops []listdist.Op, f listdist.IndexCostFuncOf[byte]) int64 {
+	var cost int64
+	i, j := 0, 0
+	for _, op := range ops {
+		switch op.Kind {
+		case listdist.Equal, listdist.Swap:
+			c.Assert([]int{op.A, op.B}, DeepEquals, []int{i, j})
+			if op.Kind == listdist.Equal {
+				c.Assert(a[i], Equals, b[j])
+			} else {
+				c.Assert(a[i], Not(Equals), b[j])
+				cost += int64(f(i, j, &a[i], &b[j]).SwapAB)
+			}
+			i++
+			j++
+		case listdist.Delete:
+			c.Assert([]int{op.A, op.B}, DeepEquals, []int{i, -1})
+			if j == 0 {
+				cost += int64(f(i, -1, &a[i], nil).DeleteA)
+			} else {
+				cost += int64(f(i, j-1, &a[i], &b[j-1]).DeleteA)
+			}
+			i++
+		case listdist.Insert:
+			c.Assert([]int{op.A, op.B}, DeepEquals, []int{-1, j})
+			if i == 0 {
+				cost += int64(f(-1, j, nil, &b[j]).InsertB)
+			} else {
+				cost += int64(f(i-1, j, &a[i-1], &b[j]).InsertB)
+			}
+			j++
+		}
+	}
+	c.Assert([]int{i, j}, DeepEquals, []int{len(a), len(b)})
+	return cost
+}
+
+// contextCost makes edits cheaper next to an 'x', so that costs depend on
+// the position of insertions and deletions.
+func contextCost(ar, br *byte) listdist.Cost {
+	cost := listdist.Cost{SwapAB: 3, DeleteA: 2, InsertB: 2}
+	if ar != nil && *ar == 'x' {
+		cost.InsertB = 1
+	}
+	if br != nil && *br == 'x' {
+		cost.DeleteA = 1
+	}
+	return cost
+}
+
+func randomBytes(rnd *rand.Rand, n int) []byte {
+	s := make([]byte, n)
+	for i := range s {
+		s[i] = "abcx"[rnd.Intn(4)]
+	}
+	return s
+}
+
+func (s *S) TestDiffRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for _, limit := range []int{0, 16, 1 << 30} {
+		restore := listdist.SetDiffMatrixLimit(limit)
+		for i := 0; i < 100; i++ {
+			a := randomBytes(rnd, rnd.Intn(40))
+			b := randomBytes(rnd, rnd.Intn(40))
+			for _, f := range []listdist.CostFuncOf[byte]{listdist.StandardCostOf[byte], contextCost} {
+				ops := listdist.DiffOf(a, b, f)
+				c.Assert(scriptCost(c, a, b, ops, f), Equals, listdist.DistanceOf(a, b, f, 0))
+			}
+		}
+		restore()
+	}
+}
+
+// positionCost makes edits more expensive near the start of the lists.
+func positionCost(ai, bi int, ar, br *byte) listdist.Cost {
+	weight := listdist.CostInt(1)
+	if max(ai, bi) < 3 {
+		weight = listdist.CostInt(4 - max(ai, bi))
+	}
+	return listdist.Cost{SwapAB: 2 * weight, DeleteA: weight, InsertB: weight}
+}
+
+func (s *S) TestDiffIndexed(c *C) {
+	a, b := splitString("aab"), splitString("ab")
+	c.Assert(opString(listdist.Diff(a, b, listdist.StandardCost)), Equals, "-==")
+	costAt := func(ai, bi int, ar, br any) listdist.Cost {
+		if ai == 0 {
+			return listdist.Cost{SwapAB: 10, DeleteA: 10, InsertB: 10}
+		}
+		return listdist.StandardCost(ar, br)
+	}
+	c.Assert(listdist.DistanceIndexed(a, b, costAt, 0), Equals, int64(1))
+	c.Assert(opString(listdist.DiffIndexed(a, b, costAt)), Equals, "=-=")
+
+	rnd := rand.New(rand.NewSource(42))
+	for _, limit := range []int{0, 16, 1 << 30} {
+		restore := listdist.SetDiffMatrixLimit(limit)
+		for i := 0; i < 100; i++ {
+			a := randomBytes(rnd, rnd.Intn(40))
+			b := randomBytes(rnd, rnd.Intn(40))
+			ops := listdist.DiffIndexedOf(a, b, positionCost)
+			c.Assert(indexScriptCost(c, a, b, ops, positionCost), Equals, listdist.DistanceIndexedOf(a, b, positionCost, 0))
+		}
+		restore()
+	}
+}
+
+func (s *S) TestDiffLarge(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	a := randomBytes(rnd, 3000)
+	b := append([]byte(nil), a...)
+	for i := 0; i < 100; i++ {
+		b[rnd.Intn(len(b))] = 'z'
+	}
+	ops := listdist.DiffOf(a, b, listdist.StandardCostOf[byte])
+	c.Assert(scriptCost(c, a, b, ops, listdist.StandardCostOf[byte]), Equals, listdist.DistanceOf(a, b, listdist.StandardCostOf[byte], 0))
+}
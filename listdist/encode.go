@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// scriptVersion is the version of the encoding written by EncodeScript.
+const scriptVersion = 1
+
+// EncodeScript returns a compact binary encoding of the script ops, which
+// DecodeScript turns back into the same script, so that scripts may be
+// stored or sent elsewhere.
+//
+// The encoding starts with a version byte, followed by the number of
+// operations and every operation in turn as varints: its kind, and each
+// of its indexes as zero for -1, or else as one more than the zigzag
+// encoded difference from the last index other than -1 in the same list.
+// Scripts returned by Diff go through both lists in order, so most of
+// their operations take three bytes.
+func EncodeScript(ops []Op) []byte {
+	data := []byte{scriptVersion}
+	data = binary.AppendUvarint(data, uint64(len(ops)))
+	lastA, lastB := -1, -1
+	for _, op := range ops {
+		data = binary.AppendUvarint(data, uint64(op.Kind))
+		data = appendIndex(data, op.A, &lastA)
+		data = appendIndex(data, op.B, &lastB)
+	}
+	return data
+}
+
+func appendIndex(data []byte, index int, last *int) []byte {
+	if index == -1 {
+		return append(data, 0)
+	}
+	delta := int64(index - *last)
+	*last = index
+	return binary.AppendUvarint(data, uint64(delta<<1^delta>>63)+1)
+}
+
+// readIndex returns the index encoded by appendIndex at the start of data,
+// and the number of bytes it took.
+func readIndex(data []byte, last *int) (index, n int, err error) {
+	u, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, 0, errTruncated
+	}
+	if u == 0 {
+		return -1, n, nil
+	}
+	u--
+	index = *last + int(int64(u>>1)^-int64(u&1))
+	if index < 0 {
+		return 0, 0, fmt.Errorf("negative index in script")
+	}
+	*last = index
+	return index, n, nil
+}
+
+var errTruncated = errors.New("truncated script")
+
+// DecodeScript returns the script encoded in data by EncodeScript. It
+// returns an error if data isn't such an encoding, or is of a version
+// it doesn't know.
+func DecodeScript(data []byte) ([]Op, error) {
+	if len(data) == 0 {
+		return nil, errTruncated
+	}
+	if data[0] != scriptVersion {
+		return nil, fmt.Errorf("unsupported script encoding version %d", data[0])
+	}
+	data = data[1:]
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, errTruncated
+	}
+	data = data[n:]
+	// Every operation takes at least three bytes.
+	if count > uint64(len(data)/3) {
+		return nil, errTruncated
+	}
+	if count == 0 {
+		if len(data) > 0 {
+			return nil, fmt.Errorf("%d bytes left after script", len(data))
+		}
+		return nil, nil
+	}
+	ops := make([]Op, count)
+	lastA, lastB := -1, -1
+	for i := range ops {
+		kind, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errTruncated
+		}
+		data = data[n:]
+		if kind < uint64(Equal) || kind > uint64(Move) {
+			return nil, fmt.Errorf("invalid operation kind %d in script", kind)
+		}
+		a, n, err := readIndex(data, &lastA)
+		if err != nil {
+			return nil, err
+		}
+		data = data[n:]
+		b, n, err := readIndex(data, &lastB)
+		if err != nil {
+			return nil, err
+		}
+		data = data[n:]
+		ops[i] = Op{Kind: OpKind(kind), A: a, B: b}
+	}
+	if len(data) > 0 {
+		return nil, fmt.Errorf("%d bytes left after script", len(data))
+	}
+	return ops, nil
+}
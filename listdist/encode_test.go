@@ -0,0 +1,61 @@
+package listdist_test
+
+import (
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+func (s *S) TestEncodeScript(c *C) {
+	ops := listdist.DiffOf([]byte("abcd"), []byte("axcdy"), listdist.StandardCostOf[byte])
+	data := listdist.EncodeScript(ops)
+	c.Assert(data, DeepEquals, []byte{1, 5, 1, 3, 3, 2, 3, 3, 1, 3, 3, 1, 3, 3, 4, 0, 3})
+	decoded, err := listdist.DecodeScript(data)
+	c.Assert(err, IsNil)
+	c.Assert(decoded, DeepEquals, ops)
+
+	decoded, err = listdist.DecodeScript(listdist.EncodeScript(nil))
+	c.Assert(err, IsNil)
+	c.Assert(decoded, HasLen, 0)
+}
+
+var decodeScriptErrors = []struct {
+	data []byte
+	err  string
+}{
+	{nil, "truncated script"},
+	{[]byte{2}, "unsupported script encoding version 2"},
+	{[]byte{1}, "truncated script"},
+	{[]byte{1, 2, 1, 2, 2}, "truncated script"},
+	{[]byte{1, 1, 1, 2}, "truncated script"},
+	{[]byte{1, 1, 9, 2, 2}, "invalid operation kind 9 in script"},
+	{[]byte{1, 1, 1, 2, 1}, "negative index in script"},
+	{[]byte{1, 1, 1, 3, 3, 0}, "1 bytes left after script"},
+	{[]byte{1, 0xff, 0xff, 0xff, 0xff, 0x0f}, "truncated script"},
+}
+
+func (s *S) TestDecodeScriptErrors(c *C) {
+	for _, test := range decodeScriptErrors {
+		c.Logf("Data: %v", test.data)
+		_, err := listdist.DecodeScript(test.data)
+		c.Assert(err, ErrorMatches, test.err)
+	}
+}
+
+func (s *S) TestEncodeScriptRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		a := randomBytes(rnd, rnd.Intn(30))
+		b := randomBytes(rnd, rnd.Intn(30))
+		c.Logf("Test %d: %q => %q", i, a, b)
+		ops := listdist.DetectMovesOf(a, b, listdist.DiffOf(a, b, listdist.StandardCostOf[byte]), 1)
+		decoded, err := listdist.DecodeScript(listdist.EncodeScript(ops))
+		c.Assert(err, IsNil)
+		c.Assert(decoded, HasLen, len(ops))
+		if len(ops) > 0 {
+			c.Assert(decoded, DeepEquals, ops)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package listdist
+
+// SetDiffMatrixLimit changes the size of the subproblems aligned by full
+// traceback in Diff, and returns a function restoring the previous value.
+func SetDiffMatrixLimit(limit int) (restore func()) {
+	old := diffMatrixLimit
+	diffMatrixLimit = limit
+	return func() { diffMatrixLimit = old }
+}
+
+// CostKind returns the kind of f, naming the cost functions with which
+// distances are computed faster.
+func CostKind[T any](f CostFuncOf[T]) string {
+	switch costKindOf(f) {
+	case standardCost:
+		return "standard"
+	case insertDeleteCost:
+		return "insert-delete"
+	}
+	return "other"
+}
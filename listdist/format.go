@@ -0,0 +1,185 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// FormatOptions holds the options for FormatUnified and FormatSideBySide.
+type FormatOptions[T any] struct {
+	// Format returns the text for an element. It defaults to fmt.Sprint.
+	Format func(elem T) string
+
+	// Context is the number of unchanged elements shown around changes by
+	// FormatUnified. It defaults to 3, and negative values show none.
+	Context int
+
+	// Width is the number of characters in the column for a used by
+	// FormatSideBySide. It defaults to 40.
+	Width int
+}
+
+func (o *FormatOptions[T]) format(elem T) string {
+	if o != nil && o.Format != nil {
+		return o.Format(elem)
+	}
+	return fmt.Sprint(elem)
+}
+
+// FormatUnified renders the script ops turning a into b, as returned by
+// Diff, in the style of a unified diff: one element per line, prefixed by
+// "-" for elements of a that were removed, "+" for elements of b that were
+// added, and a space for unchanged elements, grouped into hunks with a
+// "@@ -start,count +start,count @@" header. Swaps show as a removal and an
+// addition, and the removals in a run of changes come before the additions.
+// The options may be nil.
+func FormatUnified[T any](a, b []T, ops []Op, options *FormatOptions[T]) string {
+	context := 3
+	if options != nil && options.Context != 0 {
+		context = max(options.Context, 0)
+	}
+
+	// Find the ranges of ops in each hunk, merging changes that are
+	// close enough for their context to overlap.
+	type hunk struct{ start, end int }
+	var hunks []hunk
+	for i := 0; i < len(ops); i++ {
+		if ops[i].Kind == Equal {
+			continue
+		}
+		start := max(i-context, 0)
+		end := i + 1
+		for end < len(ops) {
+			if ops[end].Kind != Equal {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].Kind == Equal {
+				next++
+			}
+			if next == len(ops) || next-end > 2*context {
+				break
+			}
+			end = next
+		}
+		i = end
+		end = min(end+context, len(ops))
+		if len(hunks) > 0 && hunks[len(hunks)-1].end >= start {
+			hunks[len(hunks)-1].end = end
+		} else {
+			hunks = append(hunks, hunk{start, end})
+		}
+	}
+
+	var sb strings.Builder
+	for _, h := range hunks {
+		aStart, bStart := position(ops, h.start)
+		aEnd, bEnd := position(ops, h.end)
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aStart, aEnd-aStart), hunkRange(bStart, bEnd-bStart))
+		for i := h.start; i < h.end; {
+			if ops[i].Kind == Equal {
+				sb.WriteString(" " + options.format(a[ops[i].A]) + "\n")
+				i++
+				continue
+			}
+			j := i
+			for j < h.end && ops[j].Kind != Equal {
+				j++
+			}
+			for _, op := range ops[i:j] {
+				if op.A >= 0 {
+					sb.WriteString("-" + options.format(a[op.A]) + "\n")
+				}
+			}
+			for _, op := range ops[i:j] {
+				if op.B >= 0 {
+					sb.WriteString("+" + options.format(b[op.B]) + "\n")
+				}
+			}
+			i = j
+		}
+	}
+	return sb.String()
+}
+
+// position returns the number of elements of a and b before ops[i].
+func position(ops []Op, i int) (ai, bi int) {
+	for _, op := range ops[:i] {
+		if op.A >= 0 {
+			ai++
+		}
+		if op.B >= 0 {
+			bi++
+		}
+	}
+	return ai, bi
+}
+
+// hunkRange formats a range in a hunk header, with lines counted from one
+// as in diff, where an empty range refers to the line before it.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// FormatSideBySide renders the script ops turning a into b, as returned by
+// Diff, with elements of a on the left and of b on the right, one pair per
+// line, in the style of sdiff. The marker between them is "|" for swaps,
+// "<" for removals, ">" for additions, and a space for unchanged elements.
+// Elements of a longer than the column width are truncated. The options
+// may be nil.
+func FormatSideBySide[T any](a, b []T, ops []Op, options *FormatOptions[T]) string {
+	width := 40
+	if options != nil && options.Width > 0 {
+		width = options.Width
+	}
+	var sb strings.Builder
+	for _, op := range ops {
+		var left, right string
+		marker := " "
+		switch op.Kind {
+		case Swap:
+			marker = "|"
+		case Delete:
+			marker = "<"
+		case Insert:
+			marker = ">"
+		}
+		if op.A >= 0 {
+			left = options.format(a[op.A])
+		}
+		if op.B >= 0 {
+			right = options.format(b[op.B])
+		}
+		if n := utf8.RuneCountInString(left); n > width {
+			left = string([]rune(left)[:width])
+		} else {
+			left += strings.Repeat(" ", width-n)
+		}
+		line := left + " " + marker + " " + right
+		sb.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	return sb.String()
+}
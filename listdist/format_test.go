@@ -0,0 +1,81 @@
+package listdist_test
+
+import (
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+func (s *S) TestFormatUnified(c *C) {
+	a := strings.Split("a b c d e f g h i j k l m", " ")
+	b := strings.Split("a B c d e f g h i j k m n", " ")
+	ops := listdist.DiffMyersOf(a, b, nil)
+	c.Assert(listdist.FormatUnified(a, b, ops, nil), Equals, ""+
+		"@@ -1,5 +1,5 @@\n"+
+		" a\n"+
+		"-b\n"+
+		"+B\n"+
+		" c\n"+
+		" d\n"+
+		" e\n"+
+		"@@ -9,5 +9,5 @@\n"+
+		" i\n"+
+		" j\n"+
+		" k\n"+
+		"-l\n"+
+		" m\n"+
+		"+n\n")
+
+	options := &listdist.FormatOptions[string]{Context: 5, Format: strings.ToUpper}
+	c.Assert(listdist.FormatUnified(a, b, ops, options), Equals, ""+
+		"@@ -1,13 +1,13 @@\n"+
+		" A\n"+
+		"-B\n"+
+		"+B\n"+
+		" C\n"+
+		" D\n"+
+		" E\n"+
+		" F\n"+
+		" G\n"+
+		" H\n"+
+		" I\n"+
+		" J\n"+
+		" K\n"+
+		"-L\n"+
+		" M\n"+
+		"+N\n")
+
+	options = &listdist.FormatOptions[string]{Context: -1}
+	c.Assert(listdist.FormatUnified(a, b, ops, options), Equals, ""+
+		"@@ -2 +2 @@\n"+
+		"-b\n"+
+		"+B\n"+
+		"@@ -12 +11,0 @@\n"+
+		"-l\n"+
+		"@@ -13,0 +13 @@\n"+
+		"+n\n")
+
+	c.Assert(listdist.FormatUnified(a, a, listdist.DiffMyersOf(a, a, nil), nil), Equals, "")
+	c.Assert(listdist.FormatUnified([]int{}, []int{1, 2}, listdist.DiffMyersOf([]int{}, []int{1, 2}, nil), nil), Equals,
+		"@@ -0,0 +1,2 @@\n+1\n+2\n")
+}
+
+func (s *S) TestFormatSideBySide(c *C) {
+	a := []any{"one", "two", "three", "four"}
+	b := []any{"one", "2", "four", "five"}
+	ops := []listdist.Op{
+		{Kind: listdist.Equal, A: 0, B: 0},
+		{Kind: listdist.Swap, A: 1, B: 1},
+		{Kind: listdist.Delete, A: 2, B: -1},
+		{Kind: listdist.Equal, A: 3, B: 2},
+		{Kind: listdist.Insert, A: -1, B: 3},
+	}
+	c.Assert(listdist.FormatSideBySide(a, b, ops, &listdist.FormatOptions[any]{Width: 4}), Equals, ""+
+		"one    one\n"+
+		"two  | 2\n"+
+		"thre <\n"+
+		"four   four\n"+
+		"     > five\n")
+}
@@ -0,0 +1,41 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+// LCS returns a longest common subsequence of a and b, with the elements
+// taken from a. It's found with Myers' algorithm, as in DiffMyersOf, so it
+// is fast when the lists are mostly equal.
+func LCS[T comparable](a, b []T) []T {
+	var result []T
+	for _, op := range DiffMyersOf(a, b, nil) {
+		if op.Kind == Equal {
+			result = append(result, a[op.A])
+		}
+	}
+	return result
+}
+
+// LCSLength returns the length of the longest common subsequences of a and b.
+func LCSLength[T comparable](a, b []T) int {
+	// Elements of a not deleted are in the subsequence.
+	n := len(a)
+	for _, op := range DiffMyersOf(a, b, nil) {
+		if op.Kind == Delete {
+			n--
+		}
+	}
+	return n
+}
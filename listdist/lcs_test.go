@@ -0,0 +1,57 @@
+package listdist_test
+
+import (
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+type lcsTest struct {
+	a, b string
+	lcs  string
+}
+
+var lcsTests = []lcsTest{
+	{a: "", b: "", lcs: ""},
+	{a: "abc", b: "", lcs: ""},
+	{a: "abc", b: "abc", lcs: "abc"},
+	{a: "abc", b: "xyz", lcs: ""},
+	{a: "abcbdab", b: "bdcaba", lcs: "bcba"},
+	{a: "kitten", b: "sitting", lcs: "ittn"},
+}
+
+func (s *S) TestLCS(c *C) {
+	for _, test := range lcsTests {
+		c.Logf("Test: %v", test)
+		lcs := listdist.LCS([]rune(test.a), []rune(test.b))
+		c.Assert(string(lcs), Equals, test.lcs)
+		c.Assert(listdist.LCSLength([]rune(test.a), []rune(test.b)), Equals, len(test.lcs))
+	}
+}
+
+// isSubsequence reports whether sub is a subsequence of s.
+func isSubsequence(sub, s []byte) bool {
+	i := 0
+	for _, e := range s {
+		if i < len(sub) && sub[i] == e {
+			i++
+		}
+	}
+	return i == len(sub)
+}
+
+func (s *S) TestLCSRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		a := randomBytes(rnd, rnd.Intn(30))
+		b := randomBytes(rnd, rnd.Intn(30))
+		lcs := listdist.LCS(a, b)
+		c.Assert(isSubsequence(lcs, a), Equals, true)
+		c.Assert(isSubsequence(lcs, b), Equals, true)
+		dist := listdist.DistanceOf(a, b, insertDeleteCost, 0)
+		c.Assert(int64(len(a)+len(b)-2*len(lcs)), Equals, dist)
+		c.Assert(listdist.LCSLength(a, b), Equals, len(lcs))
+	}
+}
@@ -18,6 +18,8 @@
 package listdist
 
 import (
+	"context"
+	"reflect"
 	"strconv"
 )
 
@@ -44,57 +46,202 @@ func StandardCost(ar, br any) Cost {
 	return Cost{SwapAB: 1, DeleteA: 1, InsertB: 1}
 }
 
+// Distance returns the lowest total cost of the edits turning list a into
+// list b, with the cost of each edit computed by f, or Inhibit if every
+// sequence of edits is inhibited.
+//
+// If cut is not zero, Distance gives up once every entry in a row of the
+// cost matrix beyond the first one is at or above cut, returning the last
+// entry of that row rather than the distance. DistanceBounded offers the
+// same optimization with well defined results.
 func Distance(a, b []any, f CostFunc, cut int64) int64 {
-	lst := make([]CostInt, len(b)+1)
-	bl := 0
-	for bi, br := range b {
-		bl++
-		cost := f(nil, br)
+	return DistanceOf(a, b, boxedCost(f), cut)
+}
+
+// CostFuncOf is the generic form of CostFunc, for lists with elements of
+// type T. The element being inserted or deleted is nil, and the elements
+// are passed by reference so that large values are not copied.
+type CostFuncOf[T any] func(ar, br *T) Cost
+
+// StandardCostOf is the generic form of StandardCost.
+func StandardCostOf[T any](ar, br *T) Cost {
+	return Cost{SwapAB: 1, DeleteA: 1, InsertB: 1}
+}
+
+// boxedCost adapts f to the generic form used by DistanceOf. StandardCost
+// and InsertDeleteCost become their generic forms, so that the shortcuts
+// taken with them are still taken.
+func boxedCost(f CostFunc) CostFuncOf[any] {
+	if f != nil {
+		switch reflect.ValueOf(f).Pointer() {
+		case reflect.ValueOf(StandardCost).Pointer():
+			return StandardCostOf[any]
+		case reflect.ValueOf(InsertDeleteCost).Pointer():
+			return InsertDeleteCostOf[any]
+		}
+	}
+	return func(ar, br *any) Cost {
+		var a, b any
+		if ar != nil {
+			a = *ar
+		}
+		if br != nil {
+			b = *br
+		}
+		return f(a, b)
+	}
+}
+
+// DistanceOf is the generic form of Distance, which works directly on
+// lists such as []byte, []rune, or []string without boxing every element.
+// Elements equal to each other are swapped at no cost.
+func DistanceOf[T comparable](a, b []T, f CostFuncOf[T], cut int64) int64 {
+	dist, _ := new(Calculator[T]).distance(nil, a, b, f, nil, cut)
+	return dist
+}
+
+// IndexCostFunc is a CostFunc that is also given the index of ar in list a
+// and of br in list b, so that costs may depend on where the edit happens.
+// The index of an element being inserted or deleted is -1.
+type IndexCostFunc func(ai, bi int, ar, br any) Cost
+
+// IndexCostFuncOf is the generic form of IndexCostFunc.
+type IndexCostFuncOf[T any] func(ai, bi int, ar, br *T) Cost
+
+// DistanceIndexed is the same as Distance, except that f is given the
+// position of the elements in their lists.
+func DistanceIndexed(a, b []any, f IndexCostFunc, cut int64) int64 {
+	dist, _ := new(Calculator[any]).distance(nil, a, b, nil, boxedIndexCost(f), cut)
+	return dist
+}
+
+// DistanceIndexedOf is the generic form of DistanceIndexed.
+func DistanceIndexedOf[T comparable](a, b []T, f IndexCostFuncOf[T], cut int64) int64 {
+	dist, _ := new(Calculator[T]).distance(nil, a, b, nil, f, cut)
+	return dist
+}
+
+// indexedCost adapts f to the form used internally, ignoring the indexes.
+func indexedCost[T any](f CostFuncOf[T]) IndexCostFuncOf[T] {
+	return func(ai, bi int, ar, br *T) Cost {
+		return f(ar, br)
+	}
+}
+
+// boxedIndexCost adapts f to the generic form used by DistanceIndexedOf.
+func boxedIndexCost(f IndexCostFunc) IndexCostFuncOf[any] {
+	return func(ai, bi int, ar, br *any) Cost {
+		var a, b any
+		if ar != nil {
+			a = *ar
+		}
+		if br != nil {
+			b = *br
+		}
+		return f(ai, bi, a, b)
+	}
+}
+
+// DistanceContext is the same as Distance, except that it stops early and
+// returns the context error if ctx is done before the distance is known.
+// The context is checked once for every element of a.
+func DistanceContext(ctx context.Context, a, b []any, f CostFunc, cut int64) (int64, error) {
+	return new(Calculator[any]).distance(ctx, a, b, boxedCost(f), nil, cut)
+}
+
+// DistanceContextOf is the generic form of DistanceContext.
+func DistanceContextOf[T comparable](ctx context.Context, a, b []T, f CostFuncOf[T], cut int64) (int64, error) {
+	return new(Calculator[T]).distance(ctx, a, b, f, nil, cut)
+}
+
+// distance implements DistanceOf and DistanceIndexedOf, checking ctx for
+// every row if not nil, and keeping the row of the cost matrix in c. Costs
+// come from fi if set, and from f otherwise, which is faster than adapting
+// f with indexedCost. Without a cut, the ends common to both lists are
+// trimmed if the kind of f allows, and with StandardCostOf the distance is
+// computed many elements at a time by bitParallel.
+func (c *Calculator[T]) distance(ctx context.Context, a, b []T, f CostFuncOf[T], fi IndexCostFuncOf[T], cut int64) (int64, error) {
+	if kind := c.costKind(f); fi == nil && cut == 0 && kind.trimmable() {
+		a, b = trimCommon(a, b)
+		if kind == standardCost {
+			return c.bitParallel(ctx, a, b)
+		}
+	}
+	lst := c.firstRow(b, f, fi)
+	for ai := range a {
+		if ctx != nil {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+		}
+		if !c.nextRow(lst, ai, &a[ai], b, f, fi, cut) && cut != 0 {
+			break
+		}
+	}
+	return int64(lst[len(lst)-1]), nil
+}
+
+// firstRow returns the first row of the cost matrix, holding the cost of
+// inserting the elements of b, in the memory kept by c.
+func (c *Calculator[T]) firstRow(b []T, f CostFuncOf[T], fi IndexCostFuncOf[T]) []CostInt {
+	lst := grow(&c.row, len(b)+1)
+	lst[0] = 0
+	for bi := range b {
+		cost := callCost(f, fi, -1, bi, nil, &b[bi])
 		if cost.InsertB == Inhibit || lst[bi] == Inhibit {
 			lst[bi+1] = Inhibit
 		} else {
 			lst[bi+1] = lst[bi] + cost.InsertB
 		}
 	}
-	lst = lst[:bl+1]
-	for _, ar := range a {
-		last := lst[0]
-		cost := f(ar, nil)
-		if cost.DeleteA == Inhibit || last == Inhibit {
-			lst[0] = Inhibit
-		} else {
-			lst[0] = last + cost.DeleteA
+	return lst
+}
+
+// nextRow turns lst from the row of the cost matrix for the elements of a
+// before ar, which is at index ai, into the row including it. It reports
+// whether any entry beyond the first one is below cut.
+func (c *Calculator[T]) nextRow(lst []CostInt, ai int, ar *T, b []T, f CostFuncOf[T], fi IndexCostFuncOf[T], cut int64) bool {
+	last := lst[0]
+	cost := callCost(f, fi, ai, -1, ar, nil)
+	if cost.DeleteA == Inhibit || last == Inhibit {
+		lst[0] = Inhibit
+	} else {
+		lst[0] = last + cost.DeleteA
+	}
+	below := false
+	i := 0
+	for bi := range b {
+		br := &b[bi]
+		i++
+		cost := callCost(f, fi, ai, bi, ar, br)
+		min := CostInt(Inhibit)
+		if *ar == *br {
+			min = last
+		} else if cost.SwapAB != Inhibit && last != Inhibit {
+			min = last + cost.SwapAB
 		}
-		stop := true
-		i := 0
-		for _, br := range b {
-			i++
-			cost := f(ar, br)
-			min := CostInt(Inhibit)
-			if ar == br {
-				min = last
-			} else if cost.SwapAB != Inhibit && last != Inhibit {
-				min = last + cost.SwapAB
-			}
-			if cost.InsertB != Inhibit && lst[i-1] != Inhibit {
-				if n := lst[i-1] + cost.InsertB; n < min {
-					min = n
-				}
+		if cost.InsertB != Inhibit && lst[i-1] != Inhibit {
+			if n := lst[i-1] + cost.InsertB; n < min {
+				min = n
 			}
-			if cost.DeleteA != Inhibit && lst[i] != Inhibit {
-				if n := lst[i] + cost.DeleteA; n < min {
-					min = n
-				}
-			}
-			last, lst[i] = lst[i], min
-			if min < CostInt(cut) {
-				stop = false
+		}
+		if cost.DeleteA != Inhibit && lst[i] != Inhibit {
+			if n := lst[i] + cost.DeleteA; n < min {
+				min = n
 			}
 		}
-		_ = stop
-		if cut != 0 && stop {
-			break
+		last, lst[i] = lst[i], min
+		if min < CostInt(cut) {
+			below = true
 		}
 	}
-	return int64(lst[len(lst)-1])
+	return below
+}
+
+// callCost returns the cost computed by fi if set, or by f otherwise.
+func callCost[T any](f CostFuncOf[T], fi IndexCostFuncOf[T], ai, bi int, ar, br *T) Cost {
+	if fi != nil {
+		return fi(ai, bi, ar, br)
+	}
+	return f(ar, br)
 }
@@ -1,6 +1,9 @@
 package listdist_test
 
 import (
+	"context"
+	"math/rand"
+
 	. "gopkg.in/check.v1"
 
 	"testing"
@@ -59,6 +62,129 @@ func (s *S) TestDistance(c *C) {
 	}
 }
 
+func (s *S) TestDistanceOf(c *C) {
+	for _, test := range distanceTests {
+		c.Logf("Test: %v", test)
+		f := test.f
+		if f == nil {
+			f = listdist.StandardCost
+		}
+		runeCost := func(ar, br *rune) listdist.Cost {
+			var a, b any
+			if ar != nil {
+				a = string(*ar)
+			}
+			if br != nil {
+				b = string(*br)
+			}
+			return f(a, b)
+		}
+		r := listdist.DistanceOf([]rune(test.a), []rune(test.b), runeCost, test.cut)
+		c.Assert(r, Equals, test.r)
+	}
+
+	type point struct{ x, y int }
+	a := []point{{1, 2}, {3, 4}, {5, 6}}
+	b := []point{{1, 2}, {5, 6}, {7, 8}}
+	c.Assert(listdist.DistanceOf(a, b, listdist.StandardCostOf[point], 0), Equals, int64(2))
+	c.Assert(listdist.DistanceOf([]string{"foo", "bar"}, []string{"bar"}, listdist.StandardCostOf[string], 0), Equals, int64(1))
+}
+
+func (s *S) TestDistanceContext(c *C) {
+	for _, test := range distanceTests {
+		c.Logf("Test: %v", test)
+		f := test.f
+		if f == nil {
+			f = listdist.StandardCost
+		}
+		r, err := listdist.DistanceContext(context.Background(), splitString(test.a), splitString(test.b), f, test.cut)
+		c.Assert(err, IsNil)
+		c.Assert(r, Equals, test.r)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	f := func(ar, br *byte) listdist.Cost {
+		calls++
+		if calls == 100 {
+			cancel()
+		}
+		return listdist.Cost{SwapAB: 1, DeleteA: 1, InsertB: 1}
+	}
+	long := make([]byte, 1000)
+	_, err := listdist.DistanceContextOf(ctx, long, long, f, 0)
+	c.Assert(err, Equals, context.Canceled)
+	c.Assert(calls < 2*len(long), Equals, true)
+}
+
+// referenceDistance is a direct recursive definition of the distance
+// computed by Distance, too slow for anything but short lists.
+func referenceDistance(a, b []byte, f listdist.CostFuncOf[byte]) listdist.CostInt {
+	plus := func(x, y listdist.CostInt) listdist.CostInt {
+		if x == listdist.Inhibit || y == listdist.Inhibit {
+			return listdist.Inhibit
+		}
+		return x + y
+	}
+	var d func(i, j int) listdist.CostInt
+	d = func(i, j int) listdist.CostInt {
+		switch {
+		case i == 0 && j == 0:
+			return 0
+		case j == 0:
+			return plus(d(i-1, 0), f(&a[i-1], nil).DeleteA)
+		case i == 0:
+			return plus(d(0, j-1), f(nil, &b[j-1]).InsertB)
+		}
+		cost := f(&a[i-1], &b[j-1])
+		best := plus(d(i-1, j-1), cost.SwapAB)
+		if a[i-1] == b[j-1] {
+			best = d(i-1, j-1)
+		}
+		return min(best, plus(d(i, j-1), cost.InsertB), plus(d(i-1, j), cost.DeleteA))
+	}
+	return d(len(a), len(b))
+}
+
+// randomCost returns a cost function with random costs for every pair of
+// elements that randomBytes may produce, some of them inhibited.
+func randomCost(rnd *rand.Rand) listdist.CostFuncOf[byte] {
+	costs := make(map[[2]byte]listdist.Cost)
+	random := func() listdist.CostInt {
+		if rnd.Intn(6) == 0 {
+			return listdist.Inhibit
+		}
+		return listdist.CostInt(rnd.Intn(5))
+	}
+	for _, ab := range "\x00abcx" {
+		for _, bb := range "\x00abcx" {
+			costs[[2]byte{byte(ab), byte(bb)}] = listdist.Cost{SwapAB: random(), DeleteA: random(), InsertB: random()}
+		}
+	}
+	return func(ar, br *byte) listdist.Cost {
+		var key [2]byte
+		if ar != nil {
+			key[0] = *ar
+		}
+		if br != nil {
+			key[1] = *br
+		}
+		return costs[key]
+	}
+}
+
+func (s *S) TestDistanceRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		a := randomBytes(rnd, rnd.Intn(6))
+		b := randomBytes(rnd, rnd.Intn(6))
+		for j, f := range []listdist.CostFuncOf[byte]{listdist.StandardCostOf[byte], contextCost, insertDeleteCost, randomCost(rnd)} {
+			c.Logf("Test %d: %q, %q, cost %d", i, a, b, j)
+			c.Assert(listdist.DistanceOf(a, b, f, 0), Equals, int64(referenceDistance(a, b, f)))
+		}
+	}
+}
+
 func splitString(s string) []any {
 	r := make([]any, len(s))
 	for i, c := range s {
@@ -82,3 +208,11 @@ func BenchmarkDistanceCut(b *testing.B) {
 		listdist.Distance(one, two, listdist.StandardCost, 1)
 	}
 }
+
+func BenchmarkDistanceOf(b *testing.B) {
+	one := []rune("abdefghijklmnopqrstuvwxyz")
+	two := []rune("a.d.f.h.j.l.n.p.r.t.v.x.z")
+	for i := 0; i < b.N; i++ {
+		listdist.DistanceOf(one, two, listdist.StandardCostOf[rune], 0)
+	}
+}
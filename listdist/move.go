@@ -0,0 +1,156 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+import (
+	"container/heap"
+)
+
+// DetectMoves returns the script ops turning a into b, as returned by Diff,
+// with the blocks of at least minLen consecutive elements that are deleted
+// from a and inserted unchanged elsewhere in b reported as moves.
+//
+// The Delete operation of every element moved is dropped from the script,
+// and its Insert operation is replaced by a Move operation with the index
+// of the element in both lists. The script is then still ordered by the
+// position of the operations in b, but no longer in a.
+//
+// Longer blocks are picked first, and blocks partially taken by a longer
+// one are reconsidered with the elements left. Ties are broken by the
+// position of the block in a and then in b. A minLen below one is taken
+// as one.
+func DetectMoves(a, b []any, ops []Op, minLen int) []Op {
+	return DetectMovesOf(a, b, ops, minLen)
+}
+
+// DetectMovesOf is the generic form of DetectMoves.
+func DetectMovesOf[T comparable](a, b []T, ops []Op, minLen int) []Op {
+	minLen = max(minLen, 1)
+
+	// from[i] is the index in b where a[i] is moved to, and to[j] the index
+	// in a where b[j] is moved from. Both are -1 for elements not deleted
+	// or inserted, and -2 for those deleted or inserted but not moved yet.
+	from := make([]int, len(a))
+	to := make([]int, len(b))
+	for i := range from {
+		from[i] = -1
+	}
+	for j := range to {
+		to[j] = -1
+	}
+	inserted := make(map[T][]int)
+	for _, op := range ops {
+		switch op.Kind {
+		case Delete:
+			from[op.A] = -2
+		case Insert:
+			to[op.B] = -2
+			inserted[b[op.B]] = append(inserted[b[op.B]], op.B)
+		}
+	}
+
+	// Find the longest block of deleted and inserted elements starting at
+	// every pair of equal ones, skipping pairs in the middle of a block.
+	var moves moveHeap
+	for i := range a {
+		if from[i] != -2 {
+			continue
+		}
+		for _, j := range inserted[a[i]] {
+			if i > 0 && j > 0 && from[i-1] == -2 && to[j-1] == -2 && a[i-1] == b[j-1] {
+				continue
+			}
+			n := 1
+			for i+n < len(a) && j+n < len(b) && from[i+n] == -2 && to[j+n] == -2 && a[i+n] == b[j+n] {
+				n++
+			}
+			if n >= minLen {
+				moves = append(moves, move{i, j, n})
+			}
+		}
+	}
+	heap.Init(&moves)
+
+	moved := false
+	for len(moves) > 0 {
+		m := heap.Pop(&moves).(move)
+		free := true
+		for k := 0; k < m.n && free; k++ {
+			free = from[m.a+k] == -2 && to[m.b+k] == -2
+		}
+		if free {
+			for k := 0; k < m.n; k++ {
+				from[m.a+k] = m.b + k
+				to[m.b+k] = m.a + k
+			}
+			moved = true
+			continue
+		}
+		// Reconsider the runs of the block not taken by longer ones.
+		for k := 0; k < m.n; {
+			start := k
+			for k < m.n && from[m.a+k] == -2 && to[m.b+k] == -2 {
+				k++
+			}
+			if k-start >= minLen {
+				heap.Push(&moves, move{m.a + start, m.b + start, k - start})
+			}
+			k++
+		}
+	}
+	if !moved {
+		return ops
+	}
+
+	result := make([]Op, 0, len(ops))
+	for _, op := range ops {
+		switch {
+		case op.Kind == Delete && from[op.A] >= 0:
+			continue
+		case op.Kind == Insert && to[op.B] >= 0:
+			op = Op{Kind: Move, A: to[op.B], B: op.B}
+		}
+		result = append(result, op)
+	}
+	return result
+}
+
+// move is a block of n elements that may be moved from a[a:] to b[b:].
+type move struct{ a, b, n int }
+
+// moveHeap holds the blocks that may be moved, with the best one at the top.
+type moveHeap []move
+
+func (h moveHeap) Len() int { return len(h) }
+func (h moveHeap) Less(i, j int) bool {
+	x, y := h[i], h[j]
+	if x.n != y.n {
+		return x.n > y.n
+	}
+	if x.a != y.a {
+		return x.a < y.a
+	}
+	return x.b < y.b
+}
+func (h moveHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *moveHeap) Push(x any)   { *h = append(*h, x.(move)) }
+
+func (h *moveHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,75 @@
+package listdist_test
+
+import (
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+type moveTest struct {
+	a, b   string
+	minLen int
+	ops    string
+	moves  [][2]int
+}
+
+var moveTests = []moveTest{
+	{a: "", b: "", minLen: 1, ops: ""},
+	{a: "abc", b: "abc", minLen: 1, ops: "==="},
+	{a: "abcdefgh", b: "efabcdgh", minLen: 2, ops: ">>======", moves: [][2]int{{4, 0}, {5, 1}}},
+	{a: "abcdefgh", b: "efabcdgh", minLen: 3, ops: "++====--=="},
+	{a: "xab", b: "abx", minLen: 1, ops: "==>", moves: [][2]int{{0, 2}}},
+	{a: "abcxy", b: "xyabc", minLen: 1, ops: ">>===", moves: [][2]int{{3, 0}, {4, 1}}},
+	{a: "abcxy", b: "xyabc", minLen: 3, ops: "++===--"},
+	{a: "aaxbb", b: "bbyaa", minLen: 2, ops: "-==+>>", moves: [][2]int{{0, 3}, {1, 4}}},
+}
+
+func (s *S) TestDetectMoves(c *C) {
+	for _, test := range moveTests {
+		c.Logf("Test: %v", test)
+		a, b := splitString(test.a), splitString(test.b)
+		ops := listdist.DetectMoves(a, b, listdist.DiffMyers(a, b, nil), test.minLen)
+		c.Assert(opString(ops), Equals, test.ops)
+		var moves [][2]int
+		for _, op := range ops {
+			if op.Kind == listdist.Move {
+				moves = append(moves, [2]int{op.A, op.B})
+			}
+		}
+		c.Assert(moves, DeepEquals, test.moves)
+	}
+	c.Assert(listdist.Move.String(), Equals, "move")
+}
+
+func (s *S) TestDetectMovesRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		a := randomBytes(rnd, rnd.Intn(30))
+		b := randomBytes(rnd, rnd.Intn(30))
+		minLen := 1 + rnd.Intn(3)
+		ops := listdist.DetectMovesOf(a, b, listdist.DiffMyersOf(a, b, nil), minLen)
+
+		// Every element must be used once, and moved ones unchanged.
+		usedA := make([]int, len(a))
+		usedB := make([]int, len(b))
+		lastB := -1
+		for _, op := range ops {
+			if op.A >= 0 {
+				usedA[op.A]++
+			}
+			if op.B >= 0 {
+				usedB[op.B]++
+				c.Assert(op.B > lastB, Equals, true)
+				lastB = op.B
+			}
+			if op.Kind == listdist.Move {
+				c.Assert(a[op.A], Equals, b[op.B])
+			}
+		}
+		for _, n := range append(usedA, usedB...) {
+			c.Assert(n, Equals, 1)
+		}
+	}
+}
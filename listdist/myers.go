@@ -0,0 +1,187 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+// InsertDeleteCost is a CostFunc allowing only insertions and deletions,
+// at a cost of one each, as done by line based diff tools. The distance
+// with it is the number of elements not in the longest common subsequence.
+func InsertDeleteCost(ar, br any) Cost {
+	return Cost{SwapAB: Inhibit, DeleteA: 1, InsertB: 1}
+}
+
+// InsertDeleteCostOf is the generic form of InsertDeleteCost.
+func InsertDeleteCostOf[T any](ar, br *T) Cost {
+	return Cost{SwapAB: Inhibit, DeleteA: 1, InsertB: 1}
+}
+
+// DiffMyers is the same as Diff, except that it uses Myers' algorithm when
+// f is nil, finding the script with the fewest insertions and deletions as
+// with InsertDeleteCost. With a non-nil f it falls back to Diff.
+func DiffMyers(a, b []any, f CostFunc) []Op {
+	if f != nil {
+		return Diff(a, b, f)
+	}
+	return DiffMyersOf[any](a, b, nil)
+}
+
+// DiffMyersOf is the generic form of DiffMyers.
+//
+// Myers' algorithm, described in "An O(ND) difference algorithm and its
+// variations" (1986), explores the edit graph one diagonal at a time from
+// both ends, following runs of equal elements for free, until the paths
+// meet. It runs in O((n+m)·D) time, where D is the number of insertions
+// and deletions in the script, so it's much faster than DiffOf when the
+// lists are mostly equal, and uses linear space by recursing on both sides
+// of the point where the paths meet.
+func DiffMyersOf[T comparable](a, b []T, f CostFuncOf[T]) []Op {
+	if f != nil {
+		return DiffOf(a, b, f)
+	}
+	d := myers[T]{a: a, b: b}
+	d.ops = make([]Op, 0, max(len(a), len(b)))
+	d.diff(0, len(a), 0, len(b))
+	return d.ops
+}
+
+type myers[T comparable] struct {
+	a, b []T
+	ops  []Op
+
+	// fwd and bwd hold the furthest x reached on each diagonal
+	// from the start and from the end, respectively.
+	fwd, bwd []int
+}
+
+func (d *myers[T]) equal(a0, a1, b0 int) {
+	for i := a0; i < a1; i++ {
+		d.ops = append(d.ops, Op{Kind: Equal, A: i, B: b0 + i - a0})
+	}
+}
+
+// replace appends the deletion of a[a0:a1] and the insertion of b[b0:b1].
+func (d *myers[T]) replace(a0, a1, b0, b1 int) {
+	for i := a0; i < a1; i++ {
+		d.ops = append(d.ops, Op{Kind: Delete, A: i, B: -1})
+	}
+	for j := b0; j < b1; j++ {
+		d.ops = append(d.ops, Op{Kind: Insert, A: -1, B: j})
+	}
+}
+
+// diff appends the script turning a[a0:a1] into b[b0:b1] to d.ops.
+func (d *myers[T]) diff(a0, a1, b0, b1 int) {
+	prefix := 0
+	for a0+prefix < a1 && b0+prefix < b1 && d.a[a0+prefix] == d.b[b0+prefix] {
+		prefix++
+	}
+	d.equal(a0, a0+prefix, b0)
+	a0 += prefix
+	b0 += prefix
+
+	suffix := 0
+	for a0 < a1-suffix && b0 < b1-suffix && d.a[a1-suffix-1] == d.b[b1-suffix-1] {
+		suffix++
+	}
+	a1 -= suffix
+	b1 -= suffix
+
+	if a0 == a1 || b0 == b1 || a1-a0 == 1 && b1-b0 == 1 {
+		// With the common ends removed, single elements can't be equal.
+		d.replace(a0, a1, b0, b1)
+	} else if x, y, ok := d.middle(a0, a1, b0, b1); ok {
+		d.diff(a0, x, b0, y)
+		d.diff(x, a1, y, b1)
+	} else {
+		d.replace(a0, a1, b0, b1)
+	}
+	d.equal(a1, a1+suffix, b1)
+}
+
+// middle returns a point on a shortest path through the edit graph of
+// a[a0:a1] and b[b0:b1], where the paths from both ends meet, or false if
+// the lists have nothing in common.
+func (d *myers[T]) middle(a0, a1, b0, b1 int) (x, y int, ok bool) {
+	n, m := a1-a0, b1-b0
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+	size := 2*maxD + 3
+	fwd := grow(&d.fwd, size)
+	bwd := grow(&d.bwd, size)
+	for i := range fwd {
+		fwd[i] = -1
+		bwd[i] = -1
+	}
+	fwd[offset+1] = 0
+	bwd[offset+1] = 0
+
+	// Diagonals k = x - y beyond the edges of the graph are skipped by
+	// narrowing the range of k explored on later rounds.
+	delta := n - m
+	front := delta%2 != 0
+	var fwdStart, fwdEnd, bwdStart, bwdEnd int
+	for step := 0; step < maxD; step++ {
+		for k := -step + fwdStart; k <= step-fwdEnd; k += 2 {
+			i := offset + k
+			var x int
+			if k == -step || k != step && fwd[i-1] < fwd[i+1] {
+				x = fwd[i+1]
+			} else {
+				x = fwd[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[a0+x] == d.b[b0+y] {
+				x++
+				y++
+			}
+			fwd[i] = x
+			if x > n {
+				fwdEnd += 2
+			} else if y > m {
+				fwdStart += 2
+			} else if front {
+				if j := offset + delta - k; j >= 0 && j < size && bwd[j] != -1 && x >= n-bwd[j] {
+					return a0 + x, b0 + y, true
+				}
+			}
+		}
+		for k := -step + bwdStart; k <= step-bwdEnd; k += 2 {
+			i := offset + k
+			var x int
+			if k == -step || k != step && bwd[i-1] < bwd[i+1] {
+				x = bwd[i+1]
+			} else {
+				x = bwd[i-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && d.a[a1-x-1] == d.b[b1-y-1] {
+				x++
+				y++
+			}
+			bwd[i] = x
+			if x > n {
+				bwdEnd += 2
+			} else if y > m {
+				bwdStart += 2
+			} else if !front {
+				if j := offset + delta - k; j >= 0 && j < size && fwd[j] != -1 && fwd[j] >= n-x {
+					fx := fwd[j]
+					return a0 + fx, b0 + fx - (j - offset), true
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
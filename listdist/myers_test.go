@@ -0,0 +1,84 @@
+package listdist_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+var myersTests = []diffTest{
+	{a: "", b: "", ops: ""},
+	{a: "abc", b: "abc", ops: "==="},
+	{a: "abc", b: "", ops: "---"},
+	{a: "", b: "abc", ops: "+++"},
+	{a: "abc", b: "abd", ops: "==-+"},
+	{a: "abc", b: "ac", ops: "=-="},
+	{a: "ac", b: "abc", ops: "=+="},
+	{a: "abc", b: "xyz", ops: "---+++"},
+	{a: "abcabba", b: "cbabac", ops: "-+=-==-=+"},
+}
+
+func insertDeleteCost(ar, br *byte) listdist.Cost {
+	return listdist.Cost{SwapAB: listdist.Inhibit, DeleteA: 1, InsertB: 1}
+}
+
+func (s *S) TestDiffMyers(c *C) {
+	for _, test := range myersTests {
+		c.Logf("Test: %v", test)
+		a, b := splitString(test.a), splitString(test.b)
+		ops := listdist.DiffMyers(a, b, nil)
+		c.Assert(opString(ops), Equals, test.ops)
+		c.Assert(listdist.Distance(a, b, listdist.InsertDeleteCost, 0), Equals, int64(len(ops)-countEqual(ops)))
+	}
+
+	// Custom costs fall back to Diff.
+	ops := listdist.DiffMyers(splitString("abc"), splitString("abd"), listdist.StandardCost)
+	c.Assert(opString(ops), Equals, "==~")
+}
+
+func countEqual(ops []listdist.Op) int {
+	n := 0
+	for _, op := range ops {
+		if op.Kind == listdist.Equal {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *S) TestDiffMyersRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 1000; i++ {
+		a := randomBytes(rnd, rnd.Intn(30))
+		b := randomBytes(rnd, rnd.Intn(30))
+		if i%2 == 0 {
+			// Mostly equal lists.
+			b = append([]byte(nil), a...)
+			for j := rnd.Intn(4); j > 0 && len(b) > 0; j-- {
+				k := rnd.Intn(len(b))
+				b = append(b[:k], b[k+1:]...)
+			}
+			for j := rnd.Intn(4); j > 0; j-- {
+				k := rnd.Intn(len(b) + 1)
+				b = append(b[:k], append([]byte{'z'}, b[k:]...)...)
+			}
+		}
+		ops := listdist.DiffMyersOf(a, b, nil)
+		c.Assert(scriptCost(c, a, b, ops, insertDeleteCost), Equals, listdist.DistanceOf(a, b, insertDeleteCost, 0))
+	}
+}
+
+func BenchmarkDiffMyers(b *testing.B) {
+	rnd := rand.New(rand.NewSource(42))
+	one := randomBytes(rnd, 10000)
+	two := append([]byte(nil), one...)
+	for i := 0; i < 10; i++ {
+		two[rnd.Intn(len(two))] = 'z'
+	}
+	for i := 0; i < b.N; i++ {
+		listdist.DiffMyersOf(one, two, nil)
+	}
+}
@@ -0,0 +1,215 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Match is a candidate found by Nearest.
+type Match struct {
+	// Index is the position of the candidate in the list of candidates.
+	Index int
+
+	// Distance is the distance from the query to the candidate.
+	Distance int64
+}
+
+// NearestOptions holds the options for Nearest.
+type NearestOptions struct {
+	// Workers is the number of goroutines searching candidates
+	// concurrently. It defaults to one.
+	Workers int
+}
+
+// Nearest returns the k candidates closest to query, as computed by
+// Distance(query, candidate, f, 0), ordered by distance and then by index.
+// Candidates at an inhibited distance are never returned. Costs returned
+// by f must not be negative. The options may be nil.
+//
+// Candidates are organized in a trie, so the columns of the cost matrix
+// for a common prefix are computed only once. Once k candidates are found,
+// prefixes whose columns are all above the distance of the k-th best one
+// are skipped with every candidate starting with them. The result is the
+// same regardless of the number of workers.
+func Nearest(query []any, candidates [][]any, k int, f CostFunc, options *NearestOptions) []Match {
+	return NearestOf(query, candidates, k, boxedCost(f), options)
+}
+
+// NearestOf is the generic form of Nearest.
+func NearestOf[T comparable](query []T, candidates [][]T, k int, f CostFuncOf[T], options *NearestOptions) []Match {
+	if k <= 0 {
+		return nil
+	}
+	workers := 1
+	if options != nil && options.Workers > 1 {
+		workers = options.Workers
+	}
+
+	root := &trieNode[T]{}
+	for i, candidate := range candidates {
+		root.add(candidate, i)
+	}
+	s := &nearestSearch[T]{query: query, f: f, k: k}
+	s.bound.Store(int64(Inhibit))
+
+	// The column for the empty prefix holds the cost of deleting
+	// every element of the query.
+	first := make([]CostInt, len(query)+1)
+	for i := range query {
+		first[i+1] = addCost(first[i], f(&query[i], nil).DeleteA)
+	}
+	s.found(root, first)
+
+	next := make(chan *trieNode[T])
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var columns [][]CostInt
+			for node := range next {
+				s.search(node, first, 1, &columns)
+			}
+		}()
+	}
+	for _, child := range root.children {
+		next <- child
+	}
+	close(next)
+	wg.Wait()
+
+	result := make([]Match, len(s.best))
+	copy(result, s.best)
+	sort.Slice(result, func(i, j int) bool { return matchLess(result[i], result[j]) })
+	return result
+}
+
+// trieNode holds the candidates with a common prefix, ending with elem.
+type trieNode[T comparable] struct {
+	elem     T
+	ends     []int
+	children []*trieNode[T]
+	index    map[T]*trieNode[T]
+}
+
+func (n *trieNode[T]) add(candidate []T, i int) {
+	for j := range candidate {
+		child, ok := n.index[candidate[j]]
+		if !ok {
+			if n.index == nil {
+				n.index = make(map[T]*trieNode[T])
+			}
+			child = &trieNode[T]{elem: candidate[j]}
+			n.index[candidate[j]] = child
+			n.children = append(n.children, child)
+		}
+		n = child
+	}
+	n.ends = append(n.ends, i)
+}
+
+type nearestSearch[T comparable] struct {
+	query []T
+	f     CostFuncOf[T]
+	k     int
+
+	mu   sync.Mutex
+	best matchHeap
+
+	// bound holds the distance of the k-th best match so far,
+	// or Inhibit while there are fewer matches.
+	bound atomic.Int64
+}
+
+// search visits node, depth elements deep into the trie, computing its
+// column from the one of its parent.
+func (s *nearestSearch[T]) search(node *trieNode[T], parent []CostInt, depth int, columns *[][]CostInt) {
+	for len(*columns) < depth {
+		*columns = append(*columns, make([]CostInt, len(s.query)+1))
+	}
+	col := (*columns)[depth-1]
+	br := &node.elem
+	col[0] = addCost(parent[0], s.f(nil, br).InsertB)
+	lowest := col[0]
+	for i := range s.query {
+		ar := &s.query[i]
+		cost := s.f(ar, br)
+		best := addCost(parent[i], cost.SwapAB)
+		if *ar == *br {
+			best = parent[i]
+		}
+		best = min(best, addCost(col[i], cost.DeleteA))
+		best = min(best, addCost(parent[i+1], cost.InsertB))
+		col[i+1] = best
+		lowest = min(lowest, best)
+	}
+
+	// Every path to a candidate crosses this column, and costs are not
+	// negative, so none below this node can beat the current bound.
+	if lowest == Inhibit || int64(lowest) > s.bound.Load() {
+		return
+	}
+	s.found(node, col)
+	for _, child := range node.children {
+		s.search(child, col, depth+1, columns)
+	}
+}
+
+// found records the candidates ending at node, with their distance at
+// the end of col.
+func (s *nearestSearch[T]) found(node *trieNode[T], col []CostInt) {
+	dist := col[len(col)-1]
+	if len(node.ends) == 0 || dist == Inhibit || int64(dist) > s.bound.Load() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, i := range node.ends {
+		m := Match{Index: i, Distance: int64(dist)}
+		if len(s.best) < s.k {
+			heap.Push(&s.best, m)
+		} else if matchLess(m, s.best[0]) {
+			s.best[0] = m
+			heap.Fix(&s.best, 0)
+		}
+	}
+	if len(s.best) == s.k {
+		s.bound.Store(s.best[0].Distance)
+	}
+}
+
+func matchLess(a, b Match) bool {
+	return a.Distance < b.Distance || a.Distance == b.Distance && a.Index < b.Index
+}
+
+// matchHeap holds the best matches so far, with the worst one at the top.
+type matchHeap []Match
+
+func (h matchHeap) Len() int           { return len(h) }
+func (h matchHeap) Less(i, j int) bool { return matchLess(h[j], h[i]) }
+func (h matchHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *matchHeap) Push(x any)        { *h = append(*h, x.(Match)) }
+
+func (h *matchHeap) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
@@ -0,0 +1,77 @@
+package listdist_test
+
+import (
+	"math/rand"
+	"sort"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+func (s *S) TestNearest(c *C) {
+	candidates := [][]any{
+		splitString("hello"),
+		splitString("help"),
+		splitString("world"),
+		splitString("hello"),
+		splitString(""),
+		splitString("yellow"),
+	}
+	query := splitString("hallo")
+	matches := listdist.Nearest(query, candidates, 3, listdist.StandardCost, nil)
+	c.Assert(matches, DeepEquals, []listdist.Match{
+		{Index: 0, Distance: 1},
+		{Index: 3, Distance: 1},
+		{Index: 1, Distance: 3},
+	})
+	c.Assert(listdist.Nearest(query, candidates, 0, listdist.StandardCost, nil), IsNil)
+	c.Assert(listdist.Nearest(query, nil, 3, listdist.StandardCost, nil), HasLen, 0)
+
+	matches = listdist.Nearest(query, candidates, 10, listdist.StandardCost, nil)
+	c.Assert(matches, HasLen, len(candidates))
+	c.Assert(matches[len(matches)-1], Equals, listdist.Match{Index: 4, Distance: 5})
+}
+
+func (s *S) TestNearestInhibit(c *C) {
+	noInsert := func(ar, br any) listdist.Cost {
+		return listdist.Cost{SwapAB: 1, DeleteA: 1, InsertB: listdist.Inhibit}
+	}
+	candidates := [][]any{splitString("abc"), splitString("ab"), splitString("abcd")}
+	matches := listdist.Nearest(splitString("abc"), candidates, 3, noInsert, nil)
+	c.Assert(matches, DeepEquals, []listdist.Match{{Index: 0, Distance: 0}, {Index: 1, Distance: 1}})
+}
+
+func (s *S) TestNearestRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 50; i++ {
+		query := randomBytes(rnd, rnd.Intn(10))
+		candidates := make([][]byte, rnd.Intn(100))
+		for j := range candidates {
+			if j > 0 && rnd.Intn(2) == 0 {
+				// Share a prefix with an earlier candidate.
+				other := candidates[rnd.Intn(j)]
+				prefix := other[:rnd.Intn(len(other)+1)]
+				candidates[j] = append(append([]byte(nil), prefix...), randomBytes(rnd, rnd.Intn(5))...)
+			} else {
+				candidates[j] = randomBytes(rnd, rnd.Intn(10))
+			}
+		}
+		var expected []listdist.Match
+		for j, candidate := range candidates {
+			expected = append(expected, listdist.Match{Index: j, Distance: listdist.DistanceOf(query, candidate, contextCost, 0)})
+		}
+		sort.Slice(expected, func(i, j int) bool {
+			a, b := expected[i], expected[j]
+			return a.Distance < b.Distance || a.Distance == b.Distance && a.Index < b.Index
+		})
+		k := 1 + rnd.Intn(10)
+		if k < len(expected) {
+			expected = expected[:k]
+		}
+		for _, workers := range []int{0, 4} {
+			matches := listdist.NearestOf(query, candidates, k, contextCost, &listdist.NearestOptions{Workers: workers})
+			c.Assert(matches, DeepEquals, expected)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+import (
+	"iter"
+)
+
+// DistanceSeq is the same as DistanceOf, except that the lists are read
+// from iterators, such as one yielding the lines of a large file, or one
+// ranging over a channel. Only b is held in memory, while the elements of
+// a are consumed one at a time, so memory use is proportional to the
+// length of b alone, and a should be the longer list if costs allow
+// swapping them.
+func DistanceSeq[T comparable](a, b iter.Seq[T], f CostFuncOf[T]) int64 {
+	return new(Calculator[T]).DistanceSeq(a, b, f)
+}
+
+// DistanceSeq is the same as the DistanceSeq function.
+func (c *Calculator[T]) DistanceSeq(a, b iter.Seq[T], f CostFuncOf[T]) int64 {
+	var bs []T
+	for elem := range b {
+		bs = append(bs, elem)
+	}
+	lst := c.firstRow(bs, f, nil)
+	ai := 0
+	for elem := range a {
+		c.nextRow(lst, ai, &elem, bs, f, nil, 0)
+		ai++
+	}
+	return int64(lst[len(lst)-1])
+}
@@ -0,0 +1,68 @@
+package listdist_test
+
+import (
+	"bufio"
+	"iter"
+	"math/rand"
+	"slices"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+// lines yields the lines read from text one at a time.
+func lines(text string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		scanner := bufio.NewScanner(strings.NewReader(text))
+		for scanner.Scan() {
+			if !yield(scanner.Text()) {
+				return
+			}
+		}
+	}
+}
+
+// chanSeq yields the elements received from ch until it's closed.
+func chanSeq[T any](ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for elem := range ch {
+			if !yield(elem) {
+				return
+			}
+		}
+	}
+}
+
+func (s *S) TestDistanceSeq(c *C) {
+	a := "one\ntwo\nthree\nfour\n"
+	b := "one\n2\nthree\nfour\nfive\n"
+	dist := listdist.DistanceSeq(lines(a), lines(b), listdist.StandardCostOf[string])
+	c.Assert(dist, Equals, int64(2))
+	c.Assert(listdist.DistanceSeq(lines(""), lines(b), listdist.StandardCostOf[string]), Equals, int64(5))
+	c.Assert(listdist.DistanceSeq(lines(a), lines(""), listdist.StandardCostOf[string]), Equals, int64(4))
+
+	ch := make(chan byte)
+	go func() {
+		for _, elem := range []byte("kitten") {
+			ch <- elem
+		}
+		close(ch)
+	}()
+	dist = listdist.DistanceSeq(chanSeq(ch), slices.Values([]byte("sitting")), listdist.StandardCostOf[byte])
+	c.Assert(dist, Equals, int64(3))
+}
+
+func (s *S) TestDistanceSeqRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	var calc listdist.Calculator[byte]
+	for i := 0; i < 200; i++ {
+		a := randomBytes(rnd, rnd.Intn(20))
+		b := randomBytes(rnd, rnd.Intn(20))
+		f := randomCost(rnd)
+		c.Logf("Test %d: %q %q", i, a, b)
+		want := listdist.DistanceOf(a, b, f, 0)
+		c.Assert(calc.DistanceSeq(slices.Values(a), slices.Values(b), f), Equals, want)
+	}
+}
@@ -0,0 +1,55 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+// Similarity returns a score between 0 and 1 for how similar lists a and b
+// are, where 1 means they're equal under f and 0 that they have nothing
+// in common.
+//
+// The score is one minus the distance between the lists normalized by the
+// cost of deleting every element of a and inserting every element of b,
+// which accounts for the weights of the costs returned by f. With
+// InsertDeleteCost, that's the same as the ratio computed by Python's
+// difflib: twice the length of the longest common subsequence over the
+// total number of elements. Two empty lists are equal, and lists at an
+// inhibited distance have a score of 0.
+func Similarity(a, b []any, f CostFunc) float64 {
+	return SimilarityOf(a, b, boxedCost(f))
+}
+
+// SimilarityOf is the generic form of Similarity.
+func SimilarityOf[T comparable](a, b []T, f CostFuncOf[T]) float64 {
+	dist := CostInt(DistanceOf(a, b, f, 0))
+	if dist == Inhibit {
+		return 0
+	}
+	var worst CostInt
+	for i := range a {
+		worst = addCost(worst, f(&a[i], nil).DeleteA)
+	}
+	for j := range b {
+		worst = addCost(worst, f(nil, &b[j]).InsertB)
+	}
+	switch {
+	case dist == 0:
+		return 1
+	case worst == Inhibit:
+		return 0
+	case dist >= worst:
+		return 0
+	}
+	return 1 - float64(dist)/float64(worst)
+}
@@ -0,0 +1,41 @@
+package listdist_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+type similarityTest struct {
+	a, b string
+	f    listdist.CostFunc
+	r    float64
+}
+
+func inhibitCost(ar, br any) listdist.Cost {
+	return listdist.Cost{SwapAB: listdist.Inhibit, DeleteA: listdist.Inhibit, InsertB: listdist.Inhibit}
+}
+
+var similarityTests = []similarityTest{
+	{a: "", b: "", r: 1},
+	{a: "abc", b: "abc", r: 1},
+	{a: "abc", b: "", r: 0},
+	{a: "abc", b: "xyz", f: listdist.InsertDeleteCost, r: 0},
+	{a: "abcd", b: "bcde", f: listdist.InsertDeleteCost, r: 0.75},
+	{a: "abcd", b: "abce", r: 0.875},
+	{a: "abc", b: "abd", f: uniqueCost, r: 1 - 1.0/24},
+	{a: "abc", b: "abd", f: inhibitCost, r: 0},
+	{a: "abc", b: "abc", f: inhibitCost, r: 1},
+}
+
+func (s *S) TestSimilarity(c *C) {
+	for _, test := range similarityTests {
+		c.Logf("Test: %v", test)
+		f := test.f
+		if f == nil {
+			f = listdist.StandardCost
+		}
+		r := listdist.Similarity(splitString(test.a), splitString(test.b), f)
+		c.Assert(r, Equals, test.r)
+	}
+}
@@ -0,0 +1,163 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// StringOptions holds the options for StringDistance.
+type StringOptions struct {
+	// Cost returns the cost of editing the elements of the strings, each
+	// holding a single rune, or a single grapheme cluster if Graphemes is
+	// set. It defaults to StandardCostOf.
+	Cost CostFuncOf[string]
+
+	// Cut is the same as the cut parameter of Distance.
+	Cut int64
+
+	// Graphemes compares user-perceived characters rather than runes, so
+	// that a letter followed by a combining accent or an emoji sequence
+	// is inserted, deleted, or swapped as a whole.
+	Graphemes bool
+
+	// Normalize, if set, is applied to both strings before comparing them.
+	// With norm.NFC.String from golang.org/x/text/unicode/norm, for example,
+	// composed and decomposed forms of the same text are at zero distance.
+	Normalize func(s string) string
+}
+
+// StringDistance returns the edit distance between strings a and b, taking
+// the strings as lists of runes, or of grapheme clusters if requested in the
+// options, which may be nil. Elements are passed to the cost function as
+// substrings of a and b, so splitting the strings does not allocate per
+// element, and strings with ASCII text only are compared byte by byte.
+func StringDistance(a, b string, options *StringOptions) int64 {
+	if options == nil {
+		options = &StringOptions{}
+	}
+	if options.Normalize != nil {
+		a = options.Normalize(a)
+		b = options.Normalize(b)
+	}
+	f := options.Cost
+	if f == nil {
+		f = StandardCostOf[string]
+	}
+	if options.Graphemes {
+		return DistanceOf(graphemes(a), graphemes(b), f, options.Cut)
+	}
+	if isASCII(a) && isASCII(b) {
+		if options.Cost == nil {
+			return DistanceOf([]byte(a), []byte(b), StandardCostOf[byte], options.Cut)
+		}
+		return DistanceOf([]byte(a), []byte(b), byteCost(f), options.Cut)
+	}
+	return DistanceOf(runes(a), runes(b), f, options.Cut)
+}
+
+// byteCost adapts f to work on the bytes of ASCII strings. Converting a
+// single byte into a string does not allocate.
+func byteCost(f CostFuncOf[string]) CostFuncOf[byte] {
+	return func(ar, br *byte) Cost {
+		var as, bs *string
+		if ar != nil {
+			s := string(*ar)
+			as = &s
+		}
+		if br != nil {
+			s := string(*br)
+			bs = &s
+		}
+		return f(as, bs)
+	}
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// runes splits s into substrings holding one rune each.
+func runes(s string) []string {
+	result := make([]string, 0, utf8.RuneCountInString(s))
+	for i := 0; i < len(s); {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		result = append(result, s[i:i+size])
+		i += size
+	}
+	return result
+}
+
+const zeroWidthJoiner = '\u200d'
+
+// graphemes splits s into substrings holding one grapheme cluster each.
+//
+// This approximates the extended grapheme clusters of Unicode Standard
+// Annex #29 without its property tables: combining marks, variation
+// selectors, and emoji modifiers join the preceding rune, a zero width
+// joiner joins the runes around it, regional indicators pair into flags,
+// and CR LF is kept together. Hangul syllables built from individual
+// jamo are not joined.
+func graphemes(s string) []string {
+	var result []string
+	start := 0
+	var prev rune = -1
+	regional := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		join := false
+		switch {
+		case prev == -1:
+		case prev == '\r' && r == '\n':
+			join = true
+		case prev == zeroWidthJoiner:
+			join = true
+		case r == zeroWidthJoiner || isExtend(r):
+			join = prev != '\r' && prev != '\n'
+		case isRegional(r) && isRegional(prev):
+			join = regional%2 == 1
+		}
+		if isRegional(r) {
+			regional++
+		} else {
+			regional = 0
+		}
+		if !join && i > start {
+			result = append(result, s[start:i])
+			start = i
+		}
+		prev = r
+		i += size
+	}
+	if start < len(s) {
+		result = append(result, s[start:])
+	}
+	return result
+}
+
+func isExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc, unicode.Variation_Selector) || r >= 0x1f3fb && r <= 0x1f3ff
+}
+
+func isRegional(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
@@ -0,0 +1,100 @@
+package listdist_test
+
+import (
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+type stringDistanceTest struct {
+	summary string
+	a, b    string
+	options *listdist.StringOptions
+	r       int64
+}
+
+var stringDistanceTests = []stringDistanceTest{{
+	summary: "ASCII",
+	a:       "kitten",
+	b:       "sitting",
+	r:       3,
+}, {
+	summary: "ASCII with cut",
+	a:       "abcdef",
+	b:       "abc",
+	options: &listdist.StringOptions{Cut: 2},
+	r:       2,
+}, {
+	summary: "Runes",
+	a:       "naïve café",
+	b:       "naive cafe",
+	r:       2,
+}, {
+	summary: "Combining marks count as runes",
+	a:       "cafe\u0301",
+	b:       "cafe",
+	r:       1,
+}, {
+	summary: "Combining marks are part of graphemes",
+	a:       "cafe\u0301",
+	b:       "cafe",
+	options: &listdist.StringOptions{Graphemes: true},
+	r:       1,
+}, {
+	summary: "Composed and decomposed graphemes differ without normalization",
+	a:       "café",
+	b:       "cafe\u0301",
+	options: &listdist.StringOptions{Graphemes: true},
+	r:       1,
+}, {
+	summary: "Normalization",
+	a:       "café",
+	b:       "cafe\u0301",
+	options: &listdist.StringOptions{Normalize: composeAcute},
+	r:       0,
+}, {
+	summary: "Emoji sequences are graphemes",
+	a:       "hi 👩‍👩‍👧 👍🏽",
+	b:       "hi 👍",
+	options: &listdist.StringOptions{Graphemes: true},
+	r:       3,
+}, {
+	summary: "Flags are graphemes",
+	a:       "🇧🇷🇺🇸",
+	b:       "🇺🇸",
+	options: &listdist.StringOptions{Graphemes: true},
+	r:       1,
+}, {
+	summary: "Custom cost",
+	a:       "Hello",
+	b:       "hello",
+	options: &listdist.StringOptions{Cost: foldCost},
+	r:       0,
+}, {
+	summary: "Custom cost on runes",
+	a:       "Ünïcode",
+	b:       "ünïcode",
+	options: &listdist.StringOptions{Cost: foldCost},
+	r:       0,
+}}
+
+// composeAcute stands in for a real normalization function in tests.
+func composeAcute(s string) string {
+	return strings.ReplaceAll(s, "e\u0301", "é")
+}
+
+func foldCost(ar, br *string) listdist.Cost {
+	if ar != nil && br != nil && strings.EqualFold(*ar, *br) {
+		return listdist.Cost{SwapAB: 0, DeleteA: 1, InsertB: 1}
+	}
+	return listdist.Cost{SwapAB: 1, DeleteA: 1, InsertB: 1}
+}
+
+func (s *S) TestStringDistance(c *C) {
+	for _, test := range stringDistanceTests {
+		c.Logf("Summary: %s", test.summary)
+		c.Assert(listdist.StringDistance(test.a, test.b, test.options), Equals, test.r)
+	}
+}
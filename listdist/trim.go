@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package listdist
+
+import (
+	"reflect"
+	"sync"
+)
+
+var insertDeleteCostName = funcName(InsertDeleteCostOf[byte])
+
+// markedCosts holds the kind of the cost functions returned by CostsOf,
+// by their address.
+var markedCosts sync.Map
+
+// markCost records f as a cost function of the given kind, and returns it.
+func markCost[T any](f CostFuncOf[T], kind costKind) CostFuncOf[T] {
+	markedCosts.Store(reflect.ValueOf(f).Pointer(), kind)
+	return f
+}
+
+// costKind tells apart the cost functions with which distances may be
+// computed faster.
+type costKind int
+
+const (
+	otherCost costKind = iota
+	standardCost
+	insertDeleteCost
+)
+
+// trimmable reports whether the distance with costs of kind k is known to
+// be the same after removing the elements that lists have in common at
+// their start and end. That holds when every element is inserted and
+// deleted at the same cost, but not for every cost function swapping equal
+// elements at no cost: if deleting x costs 0, deleting y costs 100, and
+// swapping y for x costs 0, the distance from "xy" to "x" is 0 while from
+// "y" to "" it's 100.
+func (k costKind) trimmable() bool {
+	return k == standardCost || k == insertDeleteCost
+}
+
+// costKindOf returns the kind of f. Functions marked by markCost are found
+// by their address. Instances of StandardCostOf and InsertDeleteCostOf have
+// a different address for each type, and for each place they're taken
+// from, so these are found by the name of their generic function instead.
+func costKindOf[T any](f CostFuncOf[T]) costKind {
+	if f == nil {
+		return otherCost
+	}
+	if kind, ok := markedCosts.Load(reflect.ValueOf(f).Pointer()); ok {
+		return kind.(costKind)
+	}
+	switch funcName(f) {
+	case standardCostName:
+		return standardCost
+	case insertDeleteCostName:
+		return insertDeleteCost
+	}
+	return otherCost
+}
+
+// costKind returns the kind of f. Finding it takes an allocation, so the
+// kind of the last function seen is kept in c, along with its address.
+func (c *Calculator[T]) costKind(f CostFuncOf[T]) costKind {
+	if f == nil {
+		return otherCost
+	}
+	if pc := reflect.ValueOf(f).Pointer(); pc != c.kindPC {
+		c.kindPC, c.kind = pc, costKindOf(f)
+	}
+	return c.kind
+}
+
+// trimCommon returns a and b without the elements they have in common at
+// their start and end, which for similar lists is most of them.
+func trimCommon[T comparable](a, b []T) ([]T, []T) {
+	for len(a) > 0 && len(b) > 0 && a[0] == b[0] {
+		a, b = a[1:], b[1:]
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+	return a, b
+}
@@ -0,0 +1,63 @@
+package listdist_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+func boxBytes(s []byte) []any {
+	r := make([]any, len(s))
+	for i, e := range s {
+		r[i] = e
+	}
+	return r
+}
+
+func (s *S) TestDistanceTrim(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		// Lists sharing their ends, with a few changes in between.
+		prefix := randomBytes(rnd, rnd.Intn(10))
+		suffix := randomBytes(rnd, rnd.Intn(10))
+		a := append(append(append([]byte{}, prefix...), randomBytes(rnd, rnd.Intn(4))...), suffix...)
+		b := append(append(append([]byte{}, prefix...), randomBytes(rnd, rnd.Intn(4))...), suffix...)
+		c.Logf("Test %d: %q, %q", i, a, b)
+
+		// The costs given as local functions are not recognized, so the
+		// ends are not trimmed with them.
+		expected := listdist.DistanceOf(a, b, insertDeleteCost, 0)
+		c.Assert(listdist.Distance(boxBytes(a), boxBytes(b), listdist.InsertDeleteCost, 0), Equals, expected)
+		r, ok := listdist.DistanceBounded(boxBytes(a), boxBytes(b), listdist.InsertDeleteCost, expected)
+		c.Assert(ok, Equals, true)
+		c.Assert(r, Equals, expected)
+
+		expected = listdist.DistanceOf(a, b, uniformCost, 0)
+		c.Assert(listdist.DistanceOf(a, b, listdist.StandardCostOf[byte], 0), Equals, expected)
+		r, ok = listdist.DistanceBoundedOf(a, b, listdist.StandardCostOf[byte], expected)
+		c.Assert(ok, Equals, true)
+		c.Assert(r, Equals, expected)
+	}
+
+	// Trimming is not safe with any cost function. Here deleting the x at
+	// the start and swapping the y for an x beats deleting the y.
+	f := func(ar, br *byte) listdist.Cost {
+		if ar != nil && *ar == 'y' {
+			return listdist.Cost{SwapAB: 0, DeleteA: 100, InsertB: 100}
+		}
+		return listdist.Cost{SwapAB: 100, DeleteA: 0, InsertB: 100}
+	}
+	c.Assert(listdist.DistanceOf([]byte("xy"), []byte("x"), f, 0), Equals, int64(0))
+}
+
+var nearA = boxBytes([]byte(string(longA[:500]) + "one change" + string(longA[500:])))
+var nearB = boxBytes([]byte(string(longA[:500]) + "two changes" + string(longA[500:])))
+
+func BenchmarkDistanceTrimmed(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		listdist.Distance(nearA, nearB, listdist.InsertDeleteCost, 0)
+	}
+}
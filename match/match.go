@@ -0,0 +1,258 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package match computes stable matchings between proposers and acceptors
+// ranking each other by preference, as in the classic problems of stable
+// marriage and of hospitals and residents.
+//
+// Unlike the assign package, which finds the matching with the lowest
+// total cost, a stable matching has no proposer and acceptor that would
+// both rather be matched with each other than with their assigned partners.
+package match
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// Prefs lists the partners acceptable to someone, from the most to the
+// least preferred, as groups of partners that are equally preferred.
+// Partners not listed are unacceptable, and are never matched.
+type Prefs [][]int
+
+// Problem describes the preferences and capacities of proposers and
+// acceptors, which are identified by their index in the respective slices.
+type Problem struct {
+	// Proposers holds the preferences of each proposer over acceptors.
+	Proposers []Prefs
+
+	// Acceptors holds the preferences of each acceptor over proposers.
+	Acceptors []Prefs
+
+	// Capacities holds the number of proposers each acceptor may be
+	// matched with. If nil, every acceptor has a capacity of one.
+	Capacities []int
+}
+
+// Matching is the result of Stable.
+type Matching struct {
+	// Acceptor holds the acceptor matched with each proposer,
+	// or -1 if the proposer is unmatched.
+	Acceptor []int
+
+	// Proposers holds the proposers matched with each acceptor,
+	// in increasing order.
+	Proposers [][]int
+}
+
+// Stable returns a stable matching for the problem, computed with the
+// Gale-Shapley algorithm. A proposer and an acceptor are only matched when
+// both find each other acceptable.
+//
+// When preferences are strict, the result is the best stable matching for
+// every proposer. When preferences have ties, they are broken by the order
+// in which partners are listed within each group, and the result is weakly
+// stable: no proposer and acceptor strictly prefer each other to their
+// assigned partners, as reported by Blocking.
+//
+// Stable panics if the problem refers to proposers or acceptors that
+// don't exist, or if a capacity is negative.
+func Stable(problem *Problem) *Matching {
+	n := len(problem.Proposers)
+	m := len(problem.Acceptors)
+	if problem.Capacities != nil && len(problem.Capacities) != m {
+		panic(fmt.Sprintf("match: got %d capacities for %d acceptors", len(problem.Capacities), m))
+	}
+
+	proposerPrefs := make([][]int, n)
+	for p, prefs := range problem.Proposers {
+		for _, group := range prefs {
+			for _, a := range group {
+				if a < 0 || a >= m {
+					panic(fmt.Sprintf("match: proposer %d prefers unknown acceptor %d", p, a))
+				}
+				proposerPrefs[p] = append(proposerPrefs[p], a)
+			}
+		}
+	}
+	rank := make([][]int, m)
+	for a := range rank {
+		rank[a] = ranks(problem.Acceptors[a], n, func(p int) {
+			panic(fmt.Sprintf("match: acceptor %d prefers unknown proposer %d", a, p))
+		})
+	}
+
+	accepted := make([]acceptedHeap, m)
+	for a := range accepted {
+		accepted[a].rank = rank[a]
+		if problem.Capacities != nil && problem.Capacities[a] < 0 {
+			panic(fmt.Sprintf("match: acceptor %d has negative capacity %d", a, problem.Capacities[a]))
+		}
+	}
+	capacity := func(a int) int {
+		if problem.Capacities == nil {
+			return 1
+		}
+		return problem.Capacities[a]
+	}
+
+	// Free proposers propose in turn to the next acceptor in their
+	// preferences, who holds on to the best proposals received so far
+	// and rejects the others.
+	next := make([]int, n)
+	free := make([]int, n)
+	for p := range free {
+		free[p] = p
+	}
+	for len(free) > 0 {
+		p := free[0]
+		free = free[1:]
+		for next[p] < len(proposerPrefs[p]) {
+			a := proposerPrefs[p][next[p]]
+			next[p]++
+			if rank[a][p] < 0 || capacity(a) == 0 {
+				continue
+			}
+			h := &accepted[a]
+			if h.Len() < capacity(a) {
+				heap.Push(h, p)
+				break
+			}
+			if worst := h.proposers[0]; rank[a][p] < rank[a][worst] {
+				h.proposers[0] = p
+				heap.Fix(h, 0)
+				free = append(free, worst)
+				break
+			}
+		}
+	}
+
+	matching := &Matching{
+		Acceptor:  make([]int, n),
+		Proposers: make([][]int, m),
+	}
+	for p := range matching.Acceptor {
+		matching.Acceptor[p] = -1
+	}
+	for a := range accepted {
+		for _, p := range accepted[a].proposers {
+			matching.Acceptor[p] = a
+		}
+	}
+	for p, a := range matching.Acceptor {
+		if a >= 0 {
+			matching.Proposers[a] = append(matching.Proposers[a], p)
+		}
+	}
+	return matching
+}
+
+// Blocking returns a proposer and an acceptor that strictly prefer each
+// other to some of their partners in the matching, or are unmatched while
+// acceptable to each other and with capacity to spare. If there is no such
+// pair, the matching is stable and found is false.
+//
+// Blocking is meant to be used in tests, for verifying matchings.
+func Blocking(problem *Problem, matching *Matching) (proposer, acceptor int, found bool) {
+	n := len(problem.Proposers)
+	m := len(problem.Acceptors)
+	proposerRank := make([][]int, n)
+	for p := range proposerRank {
+		proposerRank[p] = ranks(problem.Proposers[p], m, nil)
+	}
+	acceptorRank := make([][]int, m)
+	for a := range acceptorRank {
+		acceptorRank[a] = ranks(problem.Acceptors[a], n, nil)
+	}
+
+	for p := 0; p < n; p++ {
+		current := matching.Acceptor[p]
+		for a := 0; a < m; a++ {
+			if a == current || proposerRank[p][a] < 0 || acceptorRank[a][p] < 0 {
+				continue
+			}
+			if current >= 0 && proposerRank[p][a] >= proposerRank[p][current] {
+				continue
+			}
+			capacity := 1
+			if problem.Capacities != nil {
+				capacity = problem.Capacities[a]
+			}
+			if len(matching.Proposers[a]) < capacity {
+				return p, a, true
+			}
+			for _, other := range matching.Proposers[a] {
+				if acceptorRank[a][p] < acceptorRank[a][other] {
+					return p, a, true
+				}
+			}
+		}
+	}
+	return -1, -1, false
+}
+
+// ranks returns the position of the group holding each of the n partners
+// in prefs, or -1 for partners not listed. The unknown function is called
+// for partners out of range, which are otherwise ignored.
+func ranks(prefs Prefs, n int, unknown func(i int)) []int {
+	rank := make([]int, n)
+	for i := range rank {
+		rank[i] = -1
+	}
+	for r, group := range prefs {
+		for _, i := range group {
+			if i < 0 || i >= n {
+				if unknown != nil {
+					unknown(i)
+				}
+				continue
+			}
+			if rank[i] < 0 {
+				rank[i] = r
+			}
+		}
+	}
+	return rank
+}
+
+// acceptedHeap holds the proposals accepted so far by an acceptor, with
+// the least preferred one at the top. Among equally preferred proposers,
+// the one with the highest index is at the top.
+type acceptedHeap struct {
+	rank      []int
+	proposers []int
+}
+
+func (h *acceptedHeap) Len() int { return len(h.proposers) }
+
+func (h *acceptedHeap) Less(i, j int) bool {
+	pi, pj := h.proposers[i], h.proposers[j]
+	if h.rank[pi] != h.rank[pj] {
+		return h.rank[pi] > h.rank[pj]
+	}
+	return pi > pj
+}
+
+func (h *acceptedHeap) Swap(i, j int) {
+	h.proposers[i], h.proposers[j] = h.proposers[j], h.proposers[i]
+}
+
+func (h *acceptedHeap) Push(x any) { h.proposers = append(h.proposers, x.(int)) }
+
+func (h *acceptedHeap) Pop() any {
+	last := h.proposers[len(h.proposers)-1]
+	h.proposers = h.proposers[:len(h.proposers)-1]
+	return last
+}
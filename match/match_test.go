@@ -0,0 +1,188 @@
+package match_test
+
+import (
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/match"
+)
+
+// strict returns preferences without ties.
+func strict(order ...int) match.Prefs {
+	prefs := make(match.Prefs, len(order))
+	for i, partner := range order {
+		prefs[i] = []int{partner}
+	}
+	return prefs
+}
+
+type stableTest struct {
+	summary  string
+	problem  match.Problem
+	acceptor []int
+}
+
+var stableTests = []stableTest{{
+	summary:  "Empty problem",
+	problem:  match.Problem{},
+	acceptor: []int{},
+}, {
+	summary: "Proposers get their first choices",
+	problem: match.Problem{
+		Proposers: []match.Prefs{strict(0, 1), strict(1, 0)},
+		Acceptors: []match.Prefs{strict(1, 0), strict(0, 1)},
+	},
+	acceptor: []int{0, 1},
+}, {
+	summary: "Acceptors trade up",
+	problem: match.Problem{
+		Proposers: []match.Prefs{strict(0, 1, 2), strict(0, 2, 1), strict(1, 0, 2)},
+		Acceptors: []match.Prefs{strict(1, 0, 2), strict(0, 2, 1), strict(2, 1, 0)},
+	},
+	acceptor: []int{1, 0, 2},
+}, {
+	summary: "Unacceptable partners are never matched",
+	problem: match.Problem{
+		Proposers: []match.Prefs{strict(0), strict(0, 1)},
+		Acceptors: []match.Prefs{strict(0, 1), strict(0)},
+	},
+	acceptor: []int{0, -1},
+}, {
+	summary: "Capacities",
+	problem: match.Problem{
+		Proposers:  []match.Prefs{strict(0, 1), strict(0, 1), strict(0, 1), strict(1)},
+		Acceptors:  []match.Prefs{strict(2, 1, 0), strict(3, 0, 1, 2)},
+		Capacities: []int{2, 1},
+	},
+	acceptor: []int{-1, 0, 0, 1},
+}, {
+	summary: "Zero capacity",
+	problem: match.Problem{
+		Proposers:  []match.Prefs{strict(0, 1)},
+		Acceptors:  []match.Prefs{strict(0), strict(0)},
+		Capacities: []int{0, 1},
+	},
+	acceptor: []int{1},
+}, {
+	summary: "Ties are broken by listing order",
+	problem: match.Problem{
+		Proposers: []match.Prefs{{{1, 0}}, {{0, 1}}},
+		Acceptors: []match.Prefs{{{0, 1}}, {{0, 1}}},
+	},
+	acceptor: []int{1, 0},
+}, {
+	summary: "Tied proposers don't displace each other",
+	problem: match.Problem{
+		Proposers: []match.Prefs{strict(0, 1), strict(0, 1)},
+		Acceptors: []match.Prefs{{{0, 1}}, strict(0, 1)},
+	},
+	acceptor: []int{0, 1},
+}}
+
+func (*S) TestStable(c *C) {
+	for _, test := range stableTests {
+		c.Logf("Summary: %s", test.summary)
+		matching := match.Stable(&test.problem)
+		c.Assert(matching.Acceptor, DeepEquals, test.acceptor)
+		_, _, found := match.Blocking(&test.problem, matching)
+		c.Assert(found, Equals, false)
+	}
+}
+
+func (*S) TestProposers(c *C) {
+	problem := &match.Problem{
+		Proposers:  []match.Prefs{strict(0), strict(0), strict(0)},
+		Acceptors:  []match.Prefs{strict(2, 0, 1)},
+		Capacities: []int{2},
+	}
+	matching := match.Stable(problem)
+	c.Assert(matching.Proposers, DeepEquals, [][]int{{0, 2}})
+}
+
+func (*S) TestBlocking(c *C) {
+	problem := &match.Problem{
+		Proposers: []match.Prefs{strict(0, 1), strict(0, 1)},
+		Acceptors: []match.Prefs{strict(0, 1), strict(0, 1)},
+	}
+	matching := &match.Matching{Acceptor: []int{1, 0}, Proposers: [][]int{{1}, {0}}}
+	p, a, found := match.Blocking(problem, matching)
+	c.Assert(found, Equals, true)
+	c.Assert([]int{p, a}, DeepEquals, []int{0, 0})
+
+	matching = &match.Matching{Acceptor: []int{0, -1}, Proposers: [][]int{{0}, nil}}
+	p, a, found = match.Blocking(problem, matching)
+	c.Assert(found, Equals, true)
+	c.Assert([]int{p, a}, DeepEquals, []int{1, 1})
+}
+
+func (*S) TestPanics(c *C) {
+	c.Assert(func() {
+		match.Stable(&match.Problem{Proposers: []match.Prefs{strict(1)}, Acceptors: []match.Prefs{strict(0)}})
+	}, PanicMatches, "match: proposer 0 prefers unknown acceptor 1")
+	c.Assert(func() {
+		match.Stable(&match.Problem{Proposers: []match.Prefs{strict(0)}, Acceptors: []match.Prefs{strict(2)}})
+	}, PanicMatches, "match: acceptor 0 prefers unknown proposer 2")
+	c.Assert(func() {
+		match.Stable(&match.Problem{Acceptors: []match.Prefs{nil}, Capacities: []int{-1}})
+	}, PanicMatches, "match: acceptor 0 has negative capacity -1")
+	c.Assert(func() {
+		match.Stable(&match.Problem{Acceptors: []match.Prefs{nil}, Capacities: []int{}})
+	}, PanicMatches, "match: got 0 capacities for 1 acceptors")
+}
+
+// randomPrefs returns random preferences over n partners, with some of
+// them unacceptable and some tied when ties is true.
+func randomPrefs(rnd *rand.Rand, n int, ties bool) match.Prefs {
+	var prefs match.Prefs
+	for _, partner := range rnd.Perm(n) {
+		if rnd.Intn(5) == 0 {
+			continue
+		}
+		if ties && len(prefs) > 0 && rnd.Intn(3) == 0 {
+			prefs[len(prefs)-1] = append(prefs[len(prefs)-1], partner)
+		} else {
+			prefs = append(prefs, []int{partner})
+		}
+	}
+	return prefs
+}
+
+func (*S) TestRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		n, m := 1+rnd.Intn(6), 1+rnd.Intn(4)
+		ties := i%2 == 0
+		problem := &match.Problem{
+			Proposers:  make([]match.Prefs, n),
+			Acceptors:  make([]match.Prefs, m),
+			Capacities: make([]int, m),
+		}
+		for p := range problem.Proposers {
+			problem.Proposers[p] = randomPrefs(rnd, m, ties)
+		}
+		for a := range problem.Acceptors {
+			problem.Acceptors[a] = randomPrefs(rnd, n, ties)
+			problem.Capacities[a] = rnd.Intn(3)
+		}
+		matching := match.Stable(problem)
+
+		total := 0
+		for a, proposers := range matching.Proposers {
+			c.Assert(len(proposers) <= problem.Capacities[a], Equals, true)
+			for _, p := range proposers {
+				c.Assert(matching.Acceptor[p], Equals, a)
+			}
+			total += len(proposers)
+		}
+		for _, a := range matching.Acceptor {
+			if a >= 0 {
+				total--
+			}
+		}
+		c.Assert(total, Equals, 0)
+
+		p, a, found := match.Blocking(problem, matching)
+		c.Assert(found, Equals, false, Commentf("proposer %d and acceptor %d block %v", p, a, matching.Acceptor))
+	}
+}
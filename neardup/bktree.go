@@ -0,0 +1,78 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package neardup
+
+// bkTree is a https://en.wikipedia.org/wiki/BK-tree indexing items by
+// their distance to each other, so that items within a given distance
+// of a query may be found without comparing the query against all of them.
+//
+// Items are removed lazily, by marking their node as deleted, since their
+// position in the tree is still needed to find the nodes below them.
+type bkTree struct {
+	root     *bkNode
+	distance func(a, b []any) int64
+}
+
+type bkNode struct {
+	item     []any
+	seq      int
+	deleted  bool
+	children map[int64]*bkNode
+}
+
+func (t *bkTree) insert(item []any, seq int) *bkNode {
+	node := &bkNode{item: item, seq: seq}
+	if t.root == nil {
+		t.root = node
+		return node
+	}
+	parent := t.root
+	for {
+		dist := t.distance(item, parent.item)
+		child, ok := parent.children[dist]
+		if !ok {
+			if parent.children == nil {
+				parent.children = make(map[int64]*bkNode)
+			}
+			parent.children[dist] = node
+			return node
+		}
+		parent = child
+	}
+}
+
+// search calls visit for every live item within max distance of query.
+func (t *bkTree) search(query []any, max int64, visit func(node *bkNode, dist int64)) {
+	if t.root == nil {
+		return
+	}
+	pending := []*bkNode{t.root}
+	for len(pending) > 0 {
+		node := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		dist := t.distance(query, node.item)
+		if dist <= max && !node.deleted {
+			visit(node, dist)
+		}
+		// By the triangle inequality, items below the child at distance d
+		// from node are at least |dist - d| away from the query.
+		for d, child := range node.children {
+			if d >= dist-max && d <= dist+max {
+				pending = append(pending, child)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package neardup detects near-duplicate items in a stream, by comparing
+// each new item against a sliding window of the most recent ones using
+// the edit distance computed by the listdist package.
+package neardup
+
+import (
+	"github.com/canonical/go-algo/listdist"
+)
+
+// Detector flags items that are within a distance threshold of any of the
+// most recent items added to it.
+//
+// The recent items are indexed in a BK-tree, which relies on the triangle
+// inequality to avoid comparing new items against all recent ones. The cost
+// function must therefore produce a metric distance, never inhibiting edits.
+// See listdist.CheckMetric for verifying that in tests.
+type Detector struct {
+	window    int
+	threshold int64
+	tree      bkTree
+
+	// recent holds the nodes of the items within the window,
+	// in the order they were added.
+	recent  []*bkNode
+	deleted int
+	seq     int
+}
+
+// Match describes an earlier item found near a new one.
+type Match struct {
+	// Seq is the position of the earlier item in the stream,
+	// counting from zero for the first item added to the detector.
+	Seq int
+
+	// Item is the earlier item itself.
+	Item []any
+
+	// Distance is the edit distance between the earlier and the new item.
+	Distance int64
+}
+
+// New returns a detector comparing each new item against the previous
+// window items, flagging those within threshold distance as computed by
+// listdist.Distance with the provided cost function.
+func New(window int, threshold int64, f listdist.CostFunc) *Detector {
+	if window < 1 {
+		panic("neardup: window must be positive")
+	}
+	d := &Detector{window: window, threshold: threshold}
+	d.tree.distance = func(a, b []any) int64 {
+		return listdist.Distance(a, b, f, 0)
+	}
+	return d
+}
+
+// Add compares item against the recent items and then records it as the
+// most recent one. It returns the closest recent item within the threshold
+// distance, preferring the most recent one on ties, and whether such an
+// item was found.
+func (d *Detector) Add(item []any) (match Match, found bool) {
+	d.tree.search(item, d.threshold, func(node *bkNode, dist int64) {
+		if !found || dist < match.Distance || dist == match.Distance && node.seq > match.Seq {
+			match = Match{Seq: node.seq, Item: node.item, Distance: dist}
+			found = true
+		}
+	})
+
+	d.recent = append(d.recent, d.tree.insert(item, d.seq))
+	d.seq++
+	if len(d.recent) > d.window {
+		d.recent[0].deleted = true
+		d.recent[0] = nil
+		d.recent = d.recent[1:]
+		d.deleted++
+	}
+	if d.deleted > d.window {
+		d.rebuild()
+	}
+	return match, found
+}
+
+// rebuild recreates the tree with only the recent items, dropping the
+// nodes of expired items that are still in the tree.
+func (d *Detector) rebuild() {
+	d.tree.root = nil
+	for i, node := range d.recent {
+		d.recent[i] = d.tree.insert(node.item, node.seq)
+	}
+	d.recent = append([]*bkNode(nil), d.recent...)
+	d.deleted = 0
+}
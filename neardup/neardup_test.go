@@ -0,0 +1,84 @@
+package neardup_test
+
+import (
+	"fmt"
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/listdist"
+	"github.com/canonical/go-algo/neardup"
+)
+
+func splitString(s string) []any {
+	r := make([]any, len(s))
+	for i, c := range s {
+		r[i] = string(c)
+	}
+	return r
+}
+
+func joinString(item []any) string {
+	s := ""
+	for _, elem := range item {
+		s += elem.(string)
+	}
+	return s
+}
+
+type addResult struct {
+	item     string
+	match    string
+	seq      int
+	distance int64
+}
+
+func (s *S) TestAdd(c *C) {
+	detector := neardup.New(3, 1, listdist.StandardCost)
+	results := []addResult{
+		{item: "hello"},
+		{item: "world"},
+		{item: "abcde"},
+		{item: "word", match: "world", seq: 1, distance: 1},
+		{item: "hello"},
+		{item: "hello", match: "hello", seq: 4, distance: 0},
+		{item: "hallo", match: "hello", seq: 5, distance: 1},
+	}
+	for _, result := range results {
+		c.Logf("Item: %s", result.item)
+		match, found := detector.Add(splitString(result.item))
+		if result.match == "" {
+			c.Assert(found, Equals, false)
+			continue
+		}
+		c.Assert(found, Equals, true)
+		c.Assert(joinString(match.Item), Equals, result.match)
+		c.Assert(match.Seq, Equals, result.seq)
+		c.Assert(match.Distance, Equals, result.distance)
+	}
+}
+
+func (s *S) TestBruteForce(c *C) {
+	const window = 10
+	const threshold = 2
+	rng := rand.New(rand.NewSource(42))
+	detector := neardup.New(window, threshold, listdist.StandardCost)
+	var items [][]any
+	for i := 0; i < 500; i++ {
+		item := splitString(fmt.Sprintf("%04d", rng.Intn(300)))
+		match, found := detector.Add(item)
+
+		var want neardup.Match
+		var wantFound bool
+		for seq := max(0, i-window); seq < i; seq++ {
+			dist := listdist.Distance(item, items[seq], listdist.StandardCost, 0)
+			if dist <= threshold && (!wantFound || dist <= want.Distance) {
+				want = neardup.Match{Seq: seq, Item: items[seq], Distance: dist}
+				wantFound = true
+			}
+		}
+		c.Assert(found, Equals, wantFound)
+		c.Assert(match, DeepEquals, want)
+		items = append(items, item)
+	}
+}
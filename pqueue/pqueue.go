@@ -0,0 +1,190 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pqueue implements an indexed priority queue, which unlike the
+// heaps from container/heap keeps track of where each item is, so that the
+// priority of any item may be changed, or the item removed, in O(log n)
+// time. This is what Dijkstra-like searches need to lower the cost of the
+// nodes they reach again through a cheaper path.
+package pqueue
+
+import (
+	"fmt"
+)
+
+// Queue is a priority queue of items identified by non-negative integers,
+// such as the index of nodes in a graph, each with a priority of type P.
+// Items with lower priority according to the less function are taken
+// first. The zero value is not usable, so use New to create a queue.
+//
+// Memory use grows with the highest id pushed, so ids should be dense.
+type Queue[P any] struct {
+	less  func(a, b P) bool
+	items []item[P]
+
+	// index holds the position in items of each id plus one, so that
+	// zero means the id isn't queued.
+	index []int
+}
+
+type item[P any] struct {
+	id       int
+	priority P
+}
+
+// New returns an empty queue ordering priorities with less.
+func New[P any](less func(a, b P) bool) *Queue[P] {
+	return &Queue[P]{less: less}
+}
+
+// Len returns the number of items in the queue.
+func (q *Queue[P]) Len() int {
+	return len(q.items)
+}
+
+// Contains reports whether id is in the queue.
+func (q *Queue[P]) Contains(id int) bool {
+	return id >= 0 && id < len(q.index) && q.index[id] > 0
+}
+
+// Priority returns the priority of id, and whether it's in the queue.
+func (q *Queue[P]) Priority(id int) (priority P, ok bool) {
+	if !q.Contains(id) {
+		return priority, false
+	}
+	return q.items[q.index[id]-1].priority, true
+}
+
+// Push adds id to the queue with the given priority. It panics if id is
+// negative or already in the queue.
+func (q *Queue[P]) Push(id int, priority P) {
+	if id < 0 {
+		panic(fmt.Sprintf("pqueue: negative id %d", id))
+	}
+	if q.Contains(id) {
+		panic(fmt.Sprintf("pqueue: id %d pushed twice", id))
+	}
+	for len(q.index) <= id {
+		q.index = append(q.index, 0)
+	}
+	q.items = append(q.items, item[P]{id, priority})
+	q.index[id] = len(q.items)
+	q.up(len(q.items) - 1)
+}
+
+// Peek returns the item with the lowest priority without removing it.
+// It panics if the queue is empty.
+func (q *Queue[P]) Peek() (id int, priority P) {
+	if len(q.items) == 0 {
+		panic("pqueue: Peek called on empty queue")
+	}
+	return q.items[0].id, q.items[0].priority
+}
+
+// Pop removes and returns the item with the lowest priority. When several
+// items have the same priority, which one is returned only depends on the
+// operations done on the queue. It panics if the queue is empty.
+func (q *Queue[P]) Pop() (id int, priority P) {
+	if len(q.items) == 0 {
+		panic("pqueue: Pop called on empty queue")
+	}
+	top := q.items[0]
+	q.removeAt(0)
+	return top.id, top.priority
+}
+
+// UpdatePriority changes the priority of id, which may be either lower or
+// higher than before. It panics if id isn't in the queue.
+func (q *Queue[P]) UpdatePriority(id int, priority P) {
+	if !q.Contains(id) {
+		panic(fmt.Sprintf("pqueue: id %d is not in the queue", id))
+	}
+	i := q.index[id] - 1
+	q.items[i].priority = priority
+	if !q.down(i) {
+		q.up(i)
+	}
+}
+
+// Remove removes id from the queue, and reports whether it was there.
+func (q *Queue[P]) Remove(id int) bool {
+	if !q.Contains(id) {
+		return false
+	}
+	q.removeAt(q.index[id] - 1)
+	return true
+}
+
+// Clear removes every item from the queue, keeping its memory for reuse.
+func (q *Queue[P]) Clear() {
+	for _, it := range q.items {
+		q.index[it.id] = 0
+	}
+	clear(q.items)
+	q.items = q.items[:0]
+}
+
+func (q *Queue[P]) removeAt(i int) {
+	last := len(q.items) - 1
+	q.index[q.items[i].id] = 0
+	if i != last {
+		q.items[i] = q.items[last]
+		q.index[q.items[i].id] = i + 1
+	}
+	var zero item[P]
+	q.items[last] = zero
+	q.items = q.items[:last]
+	if i != last && !q.down(i) {
+		q.up(i)
+	}
+}
+
+func (q *Queue[P]) swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.index[q.items[i].id] = i + 1
+	q.index[q.items[j].id] = j + 1
+}
+
+func (q *Queue[P]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(q.items[i].priority, q.items[parent].priority) {
+			break
+		}
+		q.swap(i, parent)
+		i = parent
+	}
+}
+
+// down moves the item at i down the heap, and reports whether it moved.
+func (q *Queue[P]) down(i int) bool {
+	start := i
+	n := len(q.items)
+	for {
+		child := 2*i + 1
+		if child >= n {
+			break
+		}
+		if right := child + 1; right < n && q.less(q.items[right].priority, q.items[child].priority) {
+			child = right
+		}
+		if !q.less(q.items[child].priority, q.items[i].priority) {
+			break
+		}
+		q.swap(i, child)
+		i = child
+	}
+	return i > start
+}
@@ -0,0 +1,99 @@
+package pqueue_test
+
+import (
+	"math/rand"
+
+	"github.com/canonical/go-algo/pqueue"
+
+	. "gopkg.in/check.v1"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func (*S) TestQueue(c *C) {
+	q := pqueue.New(intLess)
+	q.Push(3, 30)
+	q.Push(1, 10)
+	q.Push(7, 70)
+	q.Push(2, 20)
+	c.Assert(q.Len(), Equals, 4)
+	c.Assert(q.Contains(7), Equals, true)
+	c.Assert(q.Contains(5), Equals, false)
+	c.Assert(q.Contains(100), Equals, false)
+
+	q.UpdatePriority(7, 5)
+	priority, ok := q.Priority(7)
+	c.Assert(priority, Equals, 5)
+	c.Assert(ok, Equals, true)
+	id, priority := q.Peek()
+	c.Assert([]int{id, priority}, DeepEquals, []int{7, 5})
+
+	q.UpdatePriority(1, 40)
+	c.Assert(q.Remove(2), Equals, true)
+	c.Assert(q.Remove(2), Equals, false)
+
+	var ids []int
+	for q.Len() > 0 {
+		id, _ := q.Pop()
+		ids = append(ids, id)
+	}
+	c.Assert(ids, DeepEquals, []int{7, 3, 1})
+
+	q.Push(1, 1)
+	q.Clear()
+	c.Assert(q.Len(), Equals, 0)
+	c.Assert(q.Contains(1), Equals, false)
+	q.Push(1, 1)
+
+	c.Assert(func() { q.Push(1, 2) }, PanicMatches, "pqueue: id 1 pushed twice")
+	c.Assert(func() { q.Push(-1, 2) }, PanicMatches, "pqueue: negative id -1")
+	c.Assert(func() { q.UpdatePriority(2, 2) }, PanicMatches, "pqueue: id 2 is not in the queue")
+	q.Pop()
+	c.Assert(func() { q.Pop() }, PanicMatches, "pqueue: Pop called on empty queue")
+	c.Assert(func() { q.Peek() }, PanicMatches, "pqueue: Peek called on empty queue")
+}
+
+func (*S) TestQueueRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		c.Logf("Test %d", i)
+		q := pqueue.New(intLess)
+		model := make(map[int]int)
+		for op := 0; op < 300; op++ {
+			id := rnd.Intn(20)
+			priority := rnd.Intn(50)
+			switch rnd.Intn(4) {
+			case 0:
+				if _, ok := model[id]; !ok {
+					q.Push(id, priority)
+					model[id] = priority
+				}
+			case 1:
+				if _, ok := model[id]; ok {
+					q.UpdatePriority(id, priority)
+					model[id] = priority
+				}
+			case 2:
+				_, ok := model[id]
+				c.Assert(q.Remove(id), Equals, ok)
+				delete(model, id)
+			case 3:
+				if len(model) == 0 {
+					continue
+				}
+				id, priority := q.Pop()
+				c.Assert(model[id], Equals, priority)
+				for _, other := range model {
+					c.Assert(priority <= other, Equals, true)
+				}
+				delete(model, id)
+			}
+			c.Assert(q.Len(), Equals, len(model))
+			for id, priority := range model {
+				got, ok := q.Priority(id)
+				c.Assert(ok, Equals, true)
+				c.Assert(got, Equals, priority)
+			}
+		}
+	}
+}
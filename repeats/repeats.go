@@ -0,0 +1,198 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package repeats finds blocks of elements that are repeated within a single
+// sequence, such as duplicated lines in configuration or source files.
+//
+// The implementation builds a suffix array (https://en.wikipedia.org/wiki/Suffix_array)
+// of the sequence and its longest common prefix array, and then walks the
+// intervals of suffixes sharing a common prefix.
+package repeats
+
+import (
+	"sort"
+)
+
+// Region describes a block of elements repeated in a sequence.
+type Region struct {
+	// Positions holds the sorted indexes where the block starts.
+	// Occurrences may overlap, as in "aaa" repeated at 0 and 1 in "aaaa".
+	Positions []int
+
+	// Length is the number of elements in the block.
+	Length int
+}
+
+// Longest returns the longest block repeated in seq, or a zero Region if
+// no element is repeated. Ties are broken by the earliest position.
+func Longest[T comparable](seq []T) Region {
+	regions := Find(seq, 1)
+	if len(regions) == 0 {
+		return Region{}
+	}
+	return regions[0]
+}
+
+// Find returns the maximal blocks with at least minLength elements that are
+// repeated in seq, longest first and then by earliest position.
+//
+// A block is maximal when its occurrences can't all be extended to the left
+// or to the right, so a repeated block is not reported again for each of its
+// own sub-blocks, unless those occur elsewhere too.
+func Find[T comparable](seq []T, minLength int) []Region {
+	if minLength < 1 {
+		minLength = 1
+	}
+	n := len(seq)
+	if n < 2 {
+		return nil
+	}
+	sa := suffixArray(seq)
+	lcp := lcpArray(seq, sa)
+
+	type interval struct {
+		lcp int
+		lb  int
+	}
+	var result []Region
+	report := func(iv interval, rb int) {
+		if iv.lcp < minLength {
+			return
+		}
+		positions := append([]int(nil), sa[iv.lb:rb+1]...)
+		sort.Ints(positions)
+		if !leftMaximal(seq, positions) {
+			return
+		}
+		result = append(result, Region{Positions: positions, Length: iv.lcp})
+	}
+
+	stack := []interval{{0, 0}}
+	for i := 1; i <= n; i++ {
+		cur := 0
+		if i < n {
+			cur = lcp[i]
+		}
+		lb := i - 1
+		for cur < stack[len(stack)-1].lcp {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			report(top, i-1)
+			lb = top.lb
+		}
+		if cur > stack[len(stack)-1].lcp {
+			stack = append(stack, interval{cur, lb})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Length != result[j].Length {
+			return result[i].Length > result[j].Length
+		}
+		return result[i].Positions[0] < result[j].Positions[0]
+	})
+	return result
+}
+
+// leftMaximal returns whether the occurrences at the given positions can't
+// all be extended to the left by the same element.
+func leftMaximal[T comparable](seq []T, positions []int) bool {
+	if positions[0] == 0 {
+		return true
+	}
+	prev := seq[positions[0]-1]
+	for _, pos := range positions[1:] {
+		if seq[pos-1] != prev {
+			return true
+		}
+	}
+	return false
+}
+
+// suffixArray returns the starting positions of all suffixes of seq in
+// sorted order, using prefix doubling. The order of the elements themselves
+// is arbitrary but consistent, which is all that's needed to find repeats.
+func suffixArray[T comparable](seq []T) []int {
+	n := len(seq)
+	ranks := make(map[T]int)
+	rank := make([]int, n)
+	for i, elem := range seq {
+		r, ok := ranks[elem]
+		if !ok {
+			r = len(ranks)
+			ranks[elem] = r
+		}
+		rank[i] = r
+	}
+	sa := make([]int, n)
+	for i := range sa {
+		sa[i] = i
+	}
+	tmp := make([]int, n)
+	for k := 1; ; k *= 2 {
+		second := func(i int) int {
+			if i+k < n {
+				return rank[i+k]
+			}
+			return -1
+		}
+		less := func(a, b int) bool {
+			if rank[a] != rank[b] {
+				return rank[a] < rank[b]
+			}
+			return second(a) < second(b)
+		}
+		sort.Slice(sa, func(i, j int) bool { return less(sa[i], sa[j]) })
+		tmp[sa[0]] = 0
+		for i := 1; i < n; i++ {
+			tmp[sa[i]] = tmp[sa[i-1]]
+			if less(sa[i-1], sa[i]) {
+				tmp[sa[i]]++
+			}
+		}
+		copy(rank, tmp)
+		if rank[sa[n-1]] == n-1 {
+			break
+		}
+	}
+	return sa
+}
+
+// lcpArray returns for each i > 0 the length of the longest common prefix
+// between the suffixes at sa[i-1] and sa[i], using Kasai's algorithm.
+func lcpArray[T comparable](seq []T, sa []int) []int {
+	n := len(seq)
+	rank := make([]int, n)
+	for i, pos := range sa {
+		rank[pos] = i
+	}
+	lcp := make([]int, n)
+	h := 0
+	for pos := 0; pos < n; pos++ {
+		if rank[pos] == 0 {
+			h = 0
+			continue
+		}
+		prev := sa[rank[pos]-1]
+		for pos+h < n && prev+h < n && seq[pos+h] == seq[prev+h] {
+			h++
+		}
+		lcp[rank[pos]] = h
+		if h > 0 {
+			h--
+		}
+	}
+	return lcp
+}
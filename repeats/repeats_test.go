@@ -0,0 +1,82 @@
+package repeats_test
+
+import (
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/repeats"
+)
+
+type findTest struct {
+	seq       string
+	minLength int
+	result    []repeats.Region
+}
+
+var findTests = []findTest{{
+	seq:    "",
+	result: nil,
+}, {
+	seq:    "abcd",
+	result: nil,
+}, {
+	seq:       "banana",
+	minLength: 1,
+	result: []repeats.Region{
+		{Positions: []int{1, 3}, Length: 3},
+		{Positions: []int{1, 3, 5}, Length: 1},
+	},
+}, {
+	seq:       "banana",
+	minLength: 2,
+	result: []repeats.Region{
+		{Positions: []int{1, 3}, Length: 3},
+	},
+}, {
+	seq:       "xabcyabcz",
+	minLength: 2,
+	result: []repeats.Region{
+		{Positions: []int{1, 5}, Length: 3},
+	},
+}, {
+	seq:       "aaaa",
+	minLength: 2,
+	result: []repeats.Region{
+		{Positions: []int{0, 1}, Length: 3},
+		{Positions: []int{0, 1, 2}, Length: 2},
+	},
+}, {
+	seq:       "abcab_abcxab",
+	minLength: 2,
+	result: []repeats.Region{
+		{Positions: []int{0, 6}, Length: 3},
+		{Positions: []int{0, 3, 6, 10}, Length: 2},
+	},
+}}
+
+func (s *S) TestFind(c *C) {
+	for _, test := range findTests {
+		c.Logf("Test: %q", test.seq)
+		c.Assert(repeats.Find([]byte(test.seq), test.minLength), DeepEquals, test.result)
+	}
+}
+
+func (s *S) TestLongest(c *C) {
+	c.Assert(repeats.Longest([]rune("mississippi")), DeepEquals, repeats.Region{Positions: []int{1, 4}, Length: 4})
+	c.Assert(repeats.Longest([]rune("abc")), DeepEquals, repeats.Region{})
+}
+
+func (s *S) TestLines(c *C) {
+	config := strings.Split(`[a]
+key = 1
+other = 2
+[b]
+key = 1
+other = 2
+[c]
+key = 3`, "\n")
+	c.Assert(repeats.Find(config, 2), DeepEquals, []repeats.Region{
+		{Positions: []int{1, 4}, Length: 2},
+	})
+}
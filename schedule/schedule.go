@@ -0,0 +1,206 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schedule assigns tasks to agents, given the skills each agent
+// has and each task requires, how many tasks each agent may take, how
+// important each task is, and what it costs for an agent to do a task.
+//
+// The assignment is found as a minimum cost flow with the flow package,
+// so it is optimal rather than greedy.
+package schedule
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/canonical/go-algo/flow"
+)
+
+// Agent is someone or something that may be assigned tasks.
+type Agent struct {
+	Name string
+
+	// Capacity is the largest number of tasks the agent may be assigned.
+	Capacity int
+
+	// Skills holds the skills of the agent, which must include those
+	// required by the tasks assigned to it.
+	Skills []string
+}
+
+// Task is something to be done by an agent.
+type Task struct {
+	Name string
+
+	// Requires holds the skills an agent must have to do the task.
+	Requires []string
+
+	// Priority is how important it is to assign the task, starting from
+	// zero. When not every task can be assigned, those with a higher
+	// priority are assigned first.
+	Priority int
+}
+
+// Options holds the options for Solve.
+type Options struct {
+	// Cost returns the cost of agent doing task, and whether the agent
+	// may do it at all besides having the skills required. Costs must not
+	// be negative. Every pair of agent and task with the skills required
+	// costs zero if Cost is nil.
+	Cost func(agent *Agent, task *Task) (cost int64, ok bool)
+}
+
+// Assignment is a task assigned to an agent.
+type Assignment struct {
+	Agent *Agent
+	Task  *Task
+	Cost  int64
+}
+
+// Plan is the outcome of scheduling tasks.
+type Plan struct {
+	// Assignments holds the tasks assigned, in the order they were given.
+	Assignments []Assignment
+
+	// Unassigned holds the tasks that couldn't be assigned, in the order
+	// they were given.
+	Unassigned []*Task
+
+	// Cost is the total cost of the assignments.
+	Cost int64
+}
+
+// Solve assigns tasks to agents, each task to at most one agent with the
+// skills it requires, and each agent at most as many tasks as its capacity.
+//
+// The plan found maximizes the total priority of the tasks assigned, with
+// each task counting one more than its priority, so that any task is worth
+// assigning and tasks of a higher priority are worth more. Among the plans
+// that do so, the plan found has the lowest total cost. Solve returns an
+// error if a capacity, priority, or cost is negative, or if priorities and
+// costs are too large to be weighed against each other.
+func Solve(agents []*Agent, tasks []*Task, options *Options) (*Plan, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	for _, agent := range agents {
+		if agent.Capacity < 0 {
+			return nil, fmt.Errorf("agent %q has negative capacity %d", agent.Name, agent.Capacity)
+		}
+	}
+
+	// The costs of the agents that may do each task, or -1 for the others.
+	costs := make([][]int64, len(tasks))
+	var maxCosts int64
+	for j, task := range tasks {
+		if task.Priority < 0 {
+			return nil, fmt.Errorf("task %q has negative priority %d", task.Name, task.Priority)
+		}
+		costs[j] = make([]int64, len(agents))
+		var maxCost int64
+		for i, agent := range agents {
+			costs[j][i] = -1
+			if !hasSkills(agent, task) {
+				continue
+			}
+			var cost int64
+			if options.Cost != nil {
+				var ok bool
+				if cost, ok = options.Cost(agent, task); !ok {
+					continue
+				}
+				if cost < 0 {
+					return nil, fmt.Errorf("agent %q doing task %q has negative cost %d", agent.Name, task.Name, cost)
+				}
+			}
+			costs[j][i] = cost
+			maxCost = max(maxCost, cost)
+		}
+		if maxCosts > math.MaxInt64-maxCost {
+			return nil, fmt.Errorf("costs are too large")
+		}
+		maxCosts += maxCost
+	}
+
+	// Every task receives one unit of flow, either through an agent or
+	// through the edge leaving it unassigned. Leaving a task unassigned
+	// costs more than all the assignments together for each unit of
+	// priority, so assigning tasks comes first, and costs come second.
+	unit := maxCosts + 1
+	var total int64
+	for _, task := range tasks {
+		weight := int64(task.Priority) + 1
+		if unit > math.MaxInt64/weight || total > math.MaxInt64-unit*weight {
+			return nil, fmt.Errorf("priorities and costs are too large")
+		}
+		total += unit * weight
+	}
+
+	// Nodes are the source, the agents, the tasks, and the sink.
+	source := 0
+	sink := 1 + len(agents) + len(tasks)
+	g := flow.New(sink + 1)
+	for i, agent := range agents {
+		g.AddEdge(source, 1+i, int64(agent.Capacity), 0)
+	}
+	edges := make([][]int, len(tasks))
+	for j, task := range tasks {
+		node := 1 + len(agents) + j
+		edges[j] = make([]int, len(agents))
+		for i := range agents {
+			edges[j][i] = -1
+			if costs[j][i] >= 0 {
+				edges[j][i] = g.AddEdge(1+i, node, 1, costs[j][i])
+			}
+		}
+		g.AddEdge(source, node, 1, unit*(int64(task.Priority)+1))
+		g.AddEdge(node, sink, 1, 0)
+	}
+	g.MinCostFlow(source, sink, -1)
+
+	plan := &Plan{}
+	for j, task := range tasks {
+		assigned := false
+		for i, agent := range agents {
+			if edges[j][i] >= 0 && g.Flow(edges[j][i]) > 0 {
+				plan.Assignments = append(plan.Assignments, Assignment{Agent: agent, Task: task, Cost: costs[j][i]})
+				plan.Cost += costs[j][i]
+				assigned = true
+				break
+			}
+		}
+		if !assigned {
+			plan.Unassigned = append(plan.Unassigned, task)
+		}
+	}
+	return plan, nil
+}
+
+// hasSkills reports whether agent has every skill required by task.
+func hasSkills(agent *Agent, task *Task) bool {
+	for _, required := range task.Requires {
+		found := false
+		for _, skill := range agent.Skills {
+			if skill == required {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
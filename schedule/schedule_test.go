@@ -0,0 +1,208 @@
+package schedule_test
+
+import (
+	"fmt"
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/schedule"
+)
+
+// planString renders plan compactly, for comparing them in tests.
+func planString(plan *schedule.Plan) string {
+	s := ""
+	for _, a := range plan.Assignments {
+		s += fmt.Sprintf("%s:%s ", a.Task.Name, a.Agent.Name)
+	}
+	for _, task := range plan.Unassigned {
+		s += fmt.Sprintf("%s:- ", task.Name)
+	}
+	return s + fmt.Sprintf("cost=%d", plan.Cost)
+}
+
+var solveTests = []struct {
+	summary string
+	agents  []*schedule.Agent
+	tasks   []*schedule.Task
+	costs   map[string]int64
+	plan    string
+}{{
+	summary: "Skills required",
+	agents: []*schedule.Agent{
+		{Name: "a", Capacity: 2, Skills: []string{"go"}},
+		{Name: "b", Capacity: 2, Skills: []string{"go", "sql"}},
+	},
+	tasks: []*schedule.Task{
+		{Name: "t1", Requires: []string{"sql"}},
+		{Name: "t2", Requires: []string{"go"}},
+		{Name: "t3", Requires: []string{"rust"}},
+	},
+	plan: "t1:b t2:a t3:- cost=0",
+}, {
+	summary: "Capacities exhausted by priority",
+	agents:  []*schedule.Agent{{Name: "a", Capacity: 1}},
+	tasks: []*schedule.Task{
+		{Name: "t1", Priority: 0},
+		{Name: "t2", Priority: 2},
+		{Name: "t3", Priority: 1},
+	},
+	plan: "t2:a t1:- t3:- cost=0",
+}, {
+	summary: "Lowest cost among the plans assigning most",
+	agents: []*schedule.Agent{
+		{Name: "a", Capacity: 1},
+		{Name: "b", Capacity: 1},
+	},
+	tasks: []*schedule.Task{{Name: "t1"}, {Name: "t2"}},
+	costs: map[string]int64{"a/t1": 1, "a/t2": 2, "b/t1": 5, "b/t2": 10},
+	plan:  "t1:b t2:a cost=7",
+}, {
+	summary: "Expensive assignments rather than none",
+	agents:  []*schedule.Agent{{Name: "a", Capacity: 3}},
+	tasks:   []*schedule.Task{{Name: "t1"}, {Name: "t2"}},
+	costs:   map[string]int64{"a/t1": 1000, "a/t2": 1},
+	plan:    "t1:a t2:a cost=1001",
+}, {
+	summary: "Pairs excluded by the cost function",
+	agents: []*schedule.Agent{
+		{Name: "a", Capacity: 1},
+		{Name: "b", Capacity: 1},
+	},
+	tasks: []*schedule.Task{{Name: "t1"}, {Name: "t2"}},
+	costs: map[string]int64{"a/t1": 0},
+	plan:  "t1:a t2:- cost=0",
+}, {
+	summary: "No agents",
+	tasks:   []*schedule.Task{{Name: "t1"}},
+	plan:    "t1:- cost=0",
+}}
+
+func costFunc(costs map[string]int64) func(agent *schedule.Agent, task *schedule.Task) (int64, bool) {
+	if costs == nil {
+		return nil
+	}
+	return func(agent *schedule.Agent, task *schedule.Task) (int64, bool) {
+		cost, ok := costs[agent.Name+"/"+task.Name]
+		return cost, ok
+	}
+}
+
+func (*S) TestSolve(c *C) {
+	for _, test := range solveTests {
+		c.Logf("Summary: %s", test.summary)
+		plan, err := schedule.Solve(test.agents, test.tasks, &schedule.Options{Cost: costFunc(test.costs)})
+		c.Assert(err, IsNil)
+		c.Assert(planString(plan), Equals, test.plan)
+	}
+}
+
+func (*S) TestSolveErrors(c *C) {
+	_, err := schedule.Solve([]*schedule.Agent{{Name: "a", Capacity: -1}}, nil, nil)
+	c.Assert(err, ErrorMatches, `agent "a" has negative capacity -1`)
+
+	_, err = schedule.Solve(nil, []*schedule.Task{{Name: "t", Priority: -1}}, nil)
+	c.Assert(err, ErrorMatches, `task "t" has negative priority -1`)
+
+	agents := []*schedule.Agent{{Name: "a", Capacity: 1}}
+	tasks := []*schedule.Task{{Name: "t"}}
+	_, err = schedule.Solve(agents, tasks, &schedule.Options{Cost: costFunc(map[string]int64{"a/t": -1})})
+	c.Assert(err, ErrorMatches, `agent "a" doing task "t" has negative cost -1`)
+
+	tasks = []*schedule.Task{{Name: "t", Priority: 1 << 40}}
+	_, err = schedule.Solve(agents, tasks, &schedule.Options{Cost: costFunc(map[string]int64{"a/t": 1 << 30})})
+	c.Assert(err, ErrorMatches, `priorities and costs are too large`)
+}
+
+// bruteForce returns the best total priority and the lowest cost reaching
+// it, by trying every assignment.
+func bruteForce(agents []*schedule.Agent, tasks []*schedule.Task, cost func(*schedule.Agent, *schedule.Task) (int64, bool)) (bestWeight, bestCost int64) {
+	used := make([]int, len(agents))
+	bestCost = -1
+	var try func(j int, weight, total int64)
+	try = func(j int, weight, total int64) {
+		if j == len(tasks) {
+			if weight > bestWeight || (weight == bestWeight && (bestCost < 0 || total < bestCost)) {
+				bestWeight, bestCost = weight, total
+			}
+			return
+		}
+		try(j+1, weight, total)
+	next:
+		for i, agent := range agents {
+			if used[i] >= agent.Capacity {
+				continue
+			}
+			for _, required := range tasks[j].Requires {
+				found := false
+				for _, skill := range agent.Skills {
+					found = found || skill == required
+				}
+				if !found {
+					continue next
+				}
+			}
+			taskCost, ok := cost(agent, tasks[j])
+			if !ok {
+				continue
+			}
+			used[i]++
+			try(j+1, weight+int64(tasks[j].Priority)+1, total+taskCost)
+			used[i]--
+		}
+	}
+	try(0, 0, 0)
+	return bestWeight, bestCost
+}
+
+func (*S) TestSolveRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	skills := []string{"x", "y", "z"}
+	for n := 0; n < 300; n++ {
+		agents := make([]*schedule.Agent, rnd.Intn(4))
+		for i := range agents {
+			agents[i] = &schedule.Agent{Name: fmt.Sprint("a", i), Capacity: rnd.Intn(3)}
+			for _, skill := range skills {
+				if rnd.Intn(2) == 0 {
+					agents[i].Skills = append(agents[i].Skills, skill)
+				}
+			}
+		}
+		tasks := make([]*schedule.Task, rnd.Intn(6))
+		for j := range tasks {
+			tasks[j] = &schedule.Task{Name: fmt.Sprint("t", j), Priority: rnd.Intn(3)}
+			if rnd.Intn(2) == 0 {
+				tasks[j].Requires = []string{skills[rnd.Intn(len(skills))]}
+			}
+		}
+		costs := make(map[string]int64)
+		for _, agent := range agents {
+			for _, task := range tasks {
+				if rnd.Intn(5) > 0 {
+					costs[agent.Name+"/"+task.Name] = rnd.Int63n(20)
+				}
+			}
+		}
+		cost := costFunc(costs)
+
+		plan, err := schedule.Solve(agents, tasks, &schedule.Options{Cost: cost})
+		c.Assert(err, IsNil)
+		c.Assert(len(plan.Assignments)+len(plan.Unassigned), Equals, len(tasks))
+		var weight, total int64
+		used := make(map[*schedule.Agent]int)
+		for _, a := range plan.Assignments {
+			expected, ok := cost(a.Agent, a.Task)
+			c.Assert(ok, Equals, true)
+			c.Assert(a.Cost, Equals, expected)
+			used[a.Agent]++
+			weight += int64(a.Task.Priority) + 1
+			total += a.Cost
+		}
+		for agent, n := range used {
+			c.Assert(n <= agent.Capacity, Equals, true)
+		}
+		c.Assert(total, Equals, plan.Cost)
+		bestWeight, bestCost := bruteForce(agents, tasks, cost)
+		c.Assert([]int64{weight, total}, DeepEquals, []int64{bestWeight, bestCost})
+	}
+}
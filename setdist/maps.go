@@ -0,0 +1,71 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package setdist
+
+import (
+	"cmp"
+	"slices"
+)
+
+// MapResult holds the keys that differ between a map a and a map b, each
+// in increasing order.
+type MapResult[K cmp.Ordered] struct {
+	// Added holds the keys in b but not in a.
+	Added []K
+
+	// Removed holds the keys in a but not in b.
+	Removed []K
+
+	// Changed holds the keys in both maps with values that differ.
+	Changed []K
+}
+
+// Empty reports whether the maps compared have the same keys and no
+// values that differ.
+func (r *MapResult[K]) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
+
+// DiffMap returns the keys added to map a, removed from it, and changed
+// in it to turn it into map b, with the values of keys in both maps
+// compared by differ, which reports whether they differ. A nil differ
+// reports that no values differ, so that only keys are compared.
+func DiffMap[K cmp.Ordered, V any](a, b map[K]V, differ func(a, b V) bool) *MapResult[K] {
+	result := &MapResult[K]{}
+	for k, av := range a {
+		bv, ok := b[k]
+		switch {
+		case !ok:
+			result.Removed = append(result.Removed, k)
+		case differ != nil && differ(av, bv):
+			result.Changed = append(result.Changed, k)
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			result.Added = append(result.Added, k)
+		}
+	}
+	slices.Sort(result.Added)
+	slices.Sort(result.Removed)
+	slices.Sort(result.Changed)
+	return result
+}
+
+// DiffMapOf is like DiffMap for values that may be compared with ==.
+func DiffMapOf[K cmp.Ordered, V comparable](a, b map[K]V) *MapResult[K] {
+	return DiffMap(a, b, func(a, b V) bool { return a != b })
+}
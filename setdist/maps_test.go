@@ -0,0 +1,68 @@
+package setdist_test
+
+import (
+	"math/rand"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/setdist"
+)
+
+func (*S) TestDiffMap(c *C) {
+	a := map[string]string{"a": "1", "b": "2", "c": "3", "d": "four"}
+	b := map[string]string{"a": "1", "b": "two", "d": "FOUR", "e": "5", "f": "6"}
+
+	result := setdist.DiffMapOf(a, b)
+	c.Assert(result, DeepEquals, &setdist.MapResult[string]{
+		Added:   []string{"e", "f"},
+		Removed: []string{"c"},
+		Changed: []string{"b", "d"},
+	})
+	c.Assert(result.Empty(), Equals, false)
+
+	result = setdist.DiffMap(a, b, func(x, y string) bool { return !strings.EqualFold(x, y) })
+	c.Assert(result.Changed, DeepEquals, []string{"b"})
+
+	result = setdist.DiffMap(a, b, nil)
+	c.Assert(result.Changed, IsNil)
+	c.Assert(result.Added, HasLen, 2)
+
+	c.Assert(setdist.DiffMapOf(a, a).Empty(), Equals, true)
+	c.Assert(setdist.DiffMapOf[int, int](nil, nil).Empty(), Equals, true)
+}
+
+func (*S) TestDiffMapRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		a := make(map[int]int)
+		b := make(map[int]int)
+		for k := 0; k < 20; k++ {
+			if rnd.Intn(3) > 0 {
+				a[k] = rnd.Intn(3)
+			}
+			if rnd.Intn(3) > 0 {
+				b[k] = rnd.Intn(3)
+			}
+		}
+		c.Logf("Test %d: %v %v", i, a, b)
+		result := setdist.DiffMapOf(a, b)
+
+		// Applying the result to a must turn it into b.
+		applied := make(map[int]int)
+		for k, v := range a {
+			applied[k] = v
+		}
+		for _, k := range result.Removed {
+			delete(applied, k)
+		}
+		for _, k := range append(result.Added, result.Changed...) {
+			applied[k] = b[k]
+		}
+		c.Assert(applied, DeepEquals, b)
+		c.Assert(len(result.Changed) <= len(a), Equals, true)
+		for _, k := range result.Changed {
+			c.Assert(a[k] != b[k], Equals, true)
+		}
+	}
+}
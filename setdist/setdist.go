@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package setdist computes the edits of lowest cost turning one unordered
+// collection into another, pairing elements of both sides and deleting or
+// inserting the rest.
+//
+// It's built on the assign package, and takes care of the details that
+// are easy to get wrong when using it directly for this purpose, such as
+// deleting and inserting elements that are cheaper to replace than to
+// pair with each other.
+package setdist
+
+import (
+	"math"
+	"sort"
+
+	"github.com/canonical/go-algo/assign"
+)
+
+// CostFunc returns the cost of replacing a with b, of deleting a if b is
+// nil, or of inserting b if a is nil. Costs must not be negative, and the
+// total cost of the edits must fit in an int64.
+type CostFunc[T any] func(a, b *T) int64
+
+// StandardCost compares the collections as multisets: equal elements are
+// paired at no cost, and every other element is deleted or inserted at a
+// cost of one.
+func StandardCost[T comparable](a, b *T) int64 {
+	switch {
+	case a == nil || b == nil:
+		return 1
+	case *a == *b:
+		return 0
+	}
+	return 2
+}
+
+// Pair pairs element A of the first collection with element B of the
+// second one.
+type Pair struct {
+	A, B int
+	Cost int64
+}
+
+// Result holds the edits turning a collection a into a collection b, with
+// elements referred to by their index in the respective collection.
+type Result struct {
+	// Pairs holds the elements replaced by each other, ordered by A.
+	Pairs []Pair
+
+	// Deletes holds the elements of a deleted, in increasing order.
+	Deletes []int
+
+	// Inserts holds the elements of b inserted, in increasing order.
+	Inserts []int
+
+	// Cost is the total cost of the edits.
+	Cost int64
+}
+
+// Diff returns the edits of lowest total cost turning collection a into
+// collection b, in any order, with the cost of each edit computed by f.
+//
+// Elements whose replacement costs as much as deleting one and inserting
+// the other are deleted and inserted rather than paired.
+func Diff[T any](a, b []T, f CostFunc[T]) *Result {
+	result := &Result{}
+	del := make([]int64, len(a))
+	for i := range a {
+		del[i] = f(&a[i], nil)
+	}
+	ins := make([]int64, len(b))
+	for j := range b {
+		ins[j] = f(nil, &b[j])
+	}
+
+	// Pairing costs are capped at the cost of deleting and inserting the
+	// elements, which is what pairs at the cap turn into. This makes the
+	// assignment, which always pairs as many elements as possible, find
+	// the edits of lowest cost.
+	sources := make([]int, len(a))
+	for i := range sources {
+		sources[i] = i
+	}
+	targets := make([]int, len(b))
+	for j := range targets {
+		targets[j] = j
+	}
+	pairs := assign.AssignTyped(sources, targets, &assign.TypedOptions[int, int, int64]{
+		EditCost: func(i, j int) int64 {
+			return min(f(&a[i], &b[j]), del[i]+ins[j])
+		},
+		DeleteCost: func(i int) int64 { return del[i] },
+		InsertCost: func(j int) int64 { return ins[j] },
+		MaxCost:    math.MaxInt64,
+	})
+
+	for _, pair := range pairs {
+		i, j := pair.SourceIndex, pair.TargetIndex
+		switch {
+		case i >= 0 && j >= 0 && pair.Cost < del[i]+ins[j]:
+			result.Pairs = append(result.Pairs, Pair{A: i, B: j, Cost: pair.Cost})
+			result.Cost += pair.Cost
+		default:
+			if i >= 0 {
+				result.Deletes = append(result.Deletes, i)
+				result.Cost += del[i]
+			}
+			if j >= 0 {
+				result.Inserts = append(result.Inserts, j)
+				result.Cost += ins[j]
+			}
+		}
+	}
+	sort.Slice(result.Pairs, func(x, y int) bool { return result.Pairs[x].A < result.Pairs[y].A })
+	sort.Ints(result.Deletes)
+	sort.Ints(result.Inserts)
+	return result
+}
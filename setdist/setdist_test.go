@@ -0,0 +1,141 @@
+package setdist_test
+
+import (
+	"math/rand"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/setdist"
+)
+
+var diffTests = []struct {
+	summary string
+	a, b    []string
+	result  setdist.Result
+}{{
+	summary: "Empty collections",
+	result:  setdist.Result{},
+}, {
+	summary: "Equal elements in any order",
+	a:       []string{"a", "b", "c"},
+	b:       []string{"c", "a", "b"},
+	result: setdist.Result{
+		Pairs: []setdist.Pair{{A: 0, B: 1}, {A: 1, B: 2}, {A: 2, B: 0}},
+	},
+}, {
+	summary: "Repeated elements",
+	a:       []string{"a", "a", "b", "a"},
+	b:       []string{"b", "a", "c", "b"},
+	result: setdist.Result{
+		Pairs:   []setdist.Pair{{A: 0, B: 1}, {A: 2, B: 0}},
+		Deletes: []int{1, 3},
+		Inserts: []int{2, 3},
+		Cost:    4,
+	},
+}, {
+	summary: "Everything deleted",
+	a:       []string{"a", "b"},
+	result:  setdist.Result{Deletes: []int{0, 1}, Cost: 2},
+}, {
+	summary: "Everything inserted",
+	b:       []string{"a", "b"},
+	result:  setdist.Result{Inserts: []int{0, 1}, Cost: 2},
+}}
+
+func (*S) TestDiff(c *C) {
+	for _, test := range diffTests {
+		c.Logf("Summary: %s", test.summary)
+		result := setdist.Diff(test.a, test.b, setdist.StandardCost[string])
+		c.Assert(result, DeepEquals, &test.result)
+	}
+}
+
+func (*S) TestDiffReplace(c *C) {
+	// Numbers close to each other are cheaper to replace than to delete
+	// and insert, but far ones aren't.
+	cost := func(a, b *int) int64 {
+		if a == nil || b == nil {
+			return 10
+		}
+		return int64(max(*a-*b, *b-*a))
+	}
+	result := setdist.Diff([]int{1, 50, 100}, []int{98, 3, 20}, cost)
+	c.Assert(result, DeepEquals, &setdist.Result{
+		Pairs:   []setdist.Pair{{A: 0, B: 1, Cost: 2}, {A: 2, B: 0, Cost: 2}},
+		Deletes: []int{1},
+		Inserts: []int{2},
+		Cost:    24,
+	})
+}
+
+// bruteForce returns the lowest cost of the edits turning a[i:] into the
+// elements of b not yet used.
+func bruteForce(a, b []int, i int, used []bool, f setdist.CostFunc[int]) int64 {
+	if i == len(a) {
+		var cost int64
+		for j := range b {
+			if !used[j] {
+				cost += f(nil, &b[j])
+			}
+		}
+		return cost
+	}
+	best := f(&a[i], nil) + bruteForce(a, b, i+1, used, f)
+	for j := range b {
+		if !used[j] {
+			used[j] = true
+			best = min(best, f(&a[i], &b[j])+bruteForce(a, b, i+1, used, f))
+			used[j] = false
+		}
+	}
+	return best
+}
+
+func (*S) TestDiffRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for n := 0; n < 300; n++ {
+		a := make([]int, rnd.Intn(6))
+		for i := range a {
+			a[i] = rnd.Intn(20)
+		}
+		b := make([]int, rnd.Intn(6))
+		for j := range b {
+			b[j] = rnd.Intn(20)
+		}
+		cost := func(x, y *int) int64 {
+			switch {
+			case x == nil:
+				return int64(*y%5 + 1)
+			case y == nil:
+				return int64(*x%3 + 2)
+			}
+			return int64(max(*x-*y, *y-*x))
+		}
+		c.Logf("Test: %v => %v", a, b)
+		result := setdist.Diff(a, b, cost)
+		c.Assert(result.Cost, Equals, bruteForce(a, b, 0, make([]bool, len(b)), cost))
+
+		// Every element must be used once, and the costs add up.
+		var total int64
+		usedA := make([]int, len(a))
+		usedB := make([]int, len(b))
+		for _, pair := range result.Pairs {
+			c.Assert(pair.Cost, Equals, cost(&a[pair.A], &b[pair.B]))
+			total += pair.Cost
+			usedA[pair.A]++
+			usedB[pair.B]++
+		}
+		for _, i := range result.Deletes {
+			total += cost(&a[i], nil)
+			usedA[i]++
+		}
+		for _, j := range result.Inserts {
+			total += cost(nil, &b[j])
+			usedB[j]++
+		}
+		c.Assert(total, Equals, result.Cost)
+		for _, used := range append(usedA, usedB...) {
+			c.Assert(used, Equals, 1)
+		}
+	}
+}
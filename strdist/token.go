@@ -0,0 +1,211 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strdist
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/canonical/go-algo/listdist"
+)
+
+// Words splits s into lowercase words made of letters and digits, so that
+// names such as "web-server_01" and "Web Server 01" have the same words.
+func Words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Lines splits s into lines, without their line terminators. A final
+// line terminator does not start a new line.
+func Lines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// NGrams returns the substrings of s holding n consecutive runes, in order
+// and with repetitions. A non-empty string shorter than n is its only
+// n-gram. NGrams panics if n is not positive.
+func NGrams(s string, n int) []string {
+	if n < 1 {
+		panic("strdist: n-gram size must be positive")
+	}
+	if s == "" {
+		return nil
+	}
+	var starts []int
+	for i := range s {
+		starts = append(starts, i)
+	}
+	if len(starts) <= n {
+		return []string{s}
+	}
+	starts = append(starts, len(s))
+	grams := make([]string, 0, len(starts)-n)
+	for i := 0; i+n < len(starts); i++ {
+		grams = append(grams, s[starts[i]:starts[i+n]])
+	}
+	return grams
+}
+
+// Jaccard returns the size of the intersection over the size of the union
+// of the sets of tokens in a and b, ignoring repetitions. The similarity is
+// 1 for the same sets, including two empty ones, and 0 for disjoint sets.
+func Jaccard(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	set := make(map[string]bool, len(a))
+	for _, token := range a {
+		set[token] = true
+	}
+	union := len(set)
+	common := 0
+	seen := make(map[string]bool, len(b))
+	for _, token := range b {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+		if set[token] {
+			common++
+		} else {
+			union++
+		}
+	}
+	return float64(common) / float64(union)
+}
+
+// Cosine returns the cosine similarity between the token counts of a and b,
+// which compared as NGrams of the same size is a similarity of strings that
+// is insensitive to the reordering of their parts. The similarity is 1 for
+// the same counts, including two empty lists, and 0 for disjoint tokens.
+func Cosine(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	counts := make(map[string]float64, len(a))
+	for _, token := range a {
+		counts[token]++
+	}
+	var normA, normB, dot float64
+	for _, count := range counts {
+		normA += count * count
+	}
+	countsB := make(map[string]float64, len(b))
+	for _, token := range b {
+		countsB[token]++
+	}
+	for token, count := range countsB {
+		normB += count * count
+		dot += count * counts[token]
+	}
+	if dot == 0 {
+		return 0
+	}
+	return math.Min(dot/math.Sqrt(normA*normB), 1)
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity between a and b, which
+// favors strings sharing a prefix, as names of the same kind often do.
+// The similarity is 1 for equal strings and 0 for strings with no runes
+// in common.
+//
+// See https://en.wikipedia.org/wiki/Jaro%E2%80%93Winkler_distance.
+func JaroWinkler(a, b string) float64 {
+	ar, br := []rune(a), []rune(b)
+	sim := jaro(ar, br)
+	prefix := 0
+	for prefix < 4 && prefix < len(ar) && prefix < len(br) && ar[prefix] == br[prefix] {
+		prefix++
+	}
+	return sim + float64(prefix)*0.1*(1-sim)
+}
+
+// jaro returns the Jaro similarity between a and b.
+func jaro(a, b []rune) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	window := max(0, max(len(a), len(b))/2-1)
+	matchedA := make([]bool, len(a))
+	matchedB := make([]bool, len(b))
+	matches := 0
+	for i := range a {
+		for j := max(0, i-window); j < min(len(b), i+window+1); j++ {
+			if !matchedB[j] && a[i] == b[j] {
+				matchedA[i] = true
+				matchedB[j] = true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+	transposed := 0
+	j := 0
+	for i := range a {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if a[i] != b[j] {
+			transposed++
+		}
+		j++
+	}
+	m := float64(matches)
+	return (m/float64(len(a)) + m/float64(len(b)) + (m-float64(transposed/2))/m) / 3
+}
+
+// TokenDistance returns the edit distance between the lists of tokens a
+// and b, such as Words or Lines. Inserting or deleting a token costs its
+// length in runes, and swapping two tokens costs the edit distance between
+// their characters, computed by listdist, so that slightly misspelled
+// tokens are closer than unrelated ones.
+func TokenDistance(a, b []string) int64 {
+	return listdist.DistanceOf(a, b, tokenCost, 0)
+}
+
+func tokenCost(ar, br *string) listdist.Cost {
+	var cost listdist.Cost
+	if ar != nil {
+		cost.DeleteA = listdist.CostInt(utf8.RuneCountInString(*ar))
+	}
+	if br != nil {
+		cost.InsertB = listdist.CostInt(utf8.RuneCountInString(*br))
+	}
+	if ar != nil && br != nil {
+		cost.SwapAB = listdist.CostInt(listdist.StringDistance(*ar, *br, nil))
+	}
+	return cost
+}
@@ -0,0 +1,122 @@
+package strdist_test
+
+import (
+	"math"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/strdist"
+)
+
+func (s *S) TestTokenizers(c *C) {
+	c.Assert(strdist.Words("Web-Server_01 (eu)"), DeepEquals, []string{"web", "server", "01", "eu"})
+	c.Assert(strdist.Words(" - "), HasLen, 0)
+
+	c.Assert(strdist.Lines("a\r\nb\n\nc\n"), DeepEquals, []string{"a", "b", "", "c"})
+	c.Assert(strdist.Lines("a"), DeepEquals, []string{"a"})
+	c.Assert(strdist.Lines("\n"), DeepEquals, []string{""})
+	c.Assert(strdist.Lines(""), HasLen, 0)
+
+	c.Assert(strdist.NGrams("abcd", 2), DeepEquals, []string{"ab", "bc", "cd"})
+	c.Assert(strdist.NGrams("añb", 2), DeepEquals, []string{"añ", "ñb"})
+	c.Assert(strdist.NGrams("ab", 3), DeepEquals, []string{"ab"})
+	c.Assert(strdist.NGrams("abc", 3), DeepEquals, []string{"abc"})
+	c.Assert(strdist.NGrams("", 3), HasLen, 0)
+	c.Assert(func() { strdist.NGrams("abc", 0) }, PanicMatches, "strdist: n-gram size must be positive")
+}
+
+var similarityTests = []struct {
+	summary string
+	a, b    string
+	jaccard float64
+	cosine  float64
+}{{
+	summary: "Equal",
+	a:       "web server",
+	b:       "server web",
+	jaccard: 1,
+	cosine:  1,
+}, {
+	summary: "Empty",
+	jaccard: 1,
+	cosine:  1,
+}, {
+	summary: "One empty",
+	a:       "web",
+	jaccard: 0,
+	cosine:  0,
+}, {
+	summary: "Disjoint",
+	a:       "web server",
+	b:       "db replica",
+	jaccard: 0,
+	cosine:  0,
+}, {
+	summary: "Overlap",
+	a:       "web server eu",
+	b:       "web server us",
+	jaccard: 2.0 / 4,
+	cosine:  2.0 / 3,
+}, {
+	summary: "Repetitions",
+	a:       "web web db",
+	b:       "web db db",
+	jaccard: 1,
+	cosine:  4.0 / 5,
+}}
+
+func (s *S) TestSimilarity(c *C) {
+	for _, test := range similarityTests {
+		c.Logf("Summary: %s", test.summary)
+		a, b := strdist.Words(test.a), strdist.Words(test.b)
+		c.Assert(strdist.Jaccard(a, b), Equals, test.jaccard)
+		c.Assert(strdist.Jaccard(b, a), Equals, test.jaccard)
+		c.Assert(math.Abs(strdist.Cosine(a, b)-test.cosine) < 1e-9, Equals, true)
+		c.Assert(math.Abs(strdist.Cosine(b, a)-test.cosine) < 1e-9, Equals, true)
+	}
+
+	c.Assert(strdist.Cosine(strdist.NGrams("nginx-proxy", 3), strdist.NGrams("proxy-nginx", 3)) > 0.6, Equals, true)
+}
+
+var jaroWinklerTests = []struct {
+	a, b string
+	sim  float64
+}{
+	{"", "", 1},
+	{"a", "", 0},
+	{"abc", "abc", 1},
+	{"abc", "xyz", 0},
+	{"MARTHA", "MARHTA", 0.961},
+	{"DWAYNE", "DUANE", 0.84},
+	{"DIXON", "DICKSONX", 0.813},
+	{"crate", "trace", 0.733},
+}
+
+func (s *S) TestJaroWinkler(c *C) {
+	for _, test := range jaroWinklerTests {
+		c.Logf("Test: %q, %q", test.a, test.b)
+		c.Assert(math.Abs(strdist.JaroWinkler(test.a, test.b)-test.sim) < 0.001, Equals, true)
+		c.Assert(math.Abs(strdist.JaroWinkler(test.b, test.a)-test.sim) < 0.001, Equals, true)
+	}
+}
+
+var tokenDistanceTests = []struct {
+	a, b string
+	r    int64
+}{
+	{"", "", 0},
+	{"web server", "web server", 0},
+	{"web server", "", 9},
+	{"", "web", 3},
+	{"web server", "web servre", 2},
+	{"web server", "web", 6},
+	{"web server", "db server", 2},
+	{"web server eu", "web eu", 6},
+}
+
+func (s *S) TestTokenDistance(c *C) {
+	for _, test := range tokenDistanceTests {
+		c.Logf("Test: %q, %q", test.a, test.b)
+		c.Assert(strdist.TokenDistance(strdist.Words(test.a), strdist.Words(test.b)), Equals, test.r)
+	}
+}
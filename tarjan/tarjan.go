@@ -35,6 +35,9 @@ import (
 // node IDs to their successor node IDs. Each strongly connected component
 // is either a single node with no cycles, or a set of nodes that are
 // mutually reachable. Components are returned in topological order.
+//
+// Sort is superseded by the Components method of graph.Graph, which works
+// on numbered nodes without recursion.
 func Sort(successors map[string][]string) [][]string {
 	data := &tarjanData{
 		successors: successors,
@@ -0,0 +1,140 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tfidf implements a small text vectorization scheme based on
+// https://en.wikipedia.org/wiki/Tf%E2%80%93idf, so that documents may be
+// compared by the cosine similarity of their vectors.
+//
+// The similarity is in the [0, 1] range, so a cost suitable for the assign
+// package may be obtained by scaling its complement:
+//
+//	EditCost: func(source, target any) assign.Cost {
+//		...
+//		return uintCost(1000 * tfidf.Distance(sourceVector, targetVector))
+//	}
+package tfidf
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Tokenize splits text into lowercase terms made of letters and digits.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Corpus holds the document frequency of terms across a set of documents.
+// The zero value is an empty corpus ready to use.
+type Corpus struct {
+	docs int
+	df   map[string]int
+}
+
+// Add accounts for a document holding the provided terms.
+func (c *Corpus) Add(terms []string) {
+	if c.df == nil {
+		c.df = make(map[string]int)
+	}
+	c.docs++
+	seen := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			c.df[term]++
+		}
+	}
+}
+
+// Len returns the number of documents added to the corpus.
+func (c *Corpus) Len() int {
+	return c.docs
+}
+
+// IDF returns the smoothed inverse document frequency of term, which is
+// higher for rarer terms. Terms absent from the corpus get the highest value.
+func (c *Corpus) IDF(term string) float64 {
+	return math.Log(float64(1+c.docs)/float64(1+c.df[term])) + 1
+}
+
+// Vector returns the TF-IDF vector for a document holding the provided terms,
+// weighting each term count by its inverse document frequency in the corpus.
+// The document does not have to be part of the corpus.
+func (c *Corpus) Vector(terms []string) Vector {
+	sorted := append([]string(nil), terms...)
+	sort.Strings(sorted)
+	var v Vector
+	for i, term := range sorted {
+		if i > 0 && term == sorted[i-1] {
+			v[len(v)-1].Weight++
+		} else {
+			v = append(v, TermWeight{term, 1})
+		}
+	}
+	for i := range v {
+		v[i].Weight *= c.IDF(v[i].Term)
+	}
+	return v
+}
+
+// TermWeight holds the weight of a term in a document.
+type TermWeight struct {
+	Term   string
+	Weight float64
+}
+
+// Vector holds the weight of the terms in a document, sorted by term.
+type Vector []TermWeight
+
+// Norm returns the euclidean length of v.
+func (v Vector) Norm() float64 {
+	var sum float64
+	for _, tw := range v {
+		sum += tw.Weight * tw.Weight
+	}
+	return math.Sqrt(sum)
+}
+
+// Cosine returns the cosine similarity between a and b, which is 1 for
+// documents with proportional term weights and 0 for documents sharing no
+// terms. Empty vectors have no similarity to anything.
+func Cosine(a, b Vector) float64 {
+	var dot float64
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i].Term < b[j].Term:
+			i++
+		case a[i].Term > b[j].Term:
+			j++
+		default:
+			dot += a[i].Weight * b[j].Weight
+			i++
+			j++
+		}
+	}
+	if dot == 0 {
+		return 0
+	}
+	return math.Min(dot/(a.Norm()*b.Norm()), 1)
+}
+
+// Distance returns 1 minus the cosine similarity between a and b.
+func Distance(a, b Vector) float64 {
+	return 1 - Cosine(a, b)
+}
@@ -0,0 +1,52 @@
+package tfidf_test
+
+import (
+	"math"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/tfidf"
+)
+
+func (s *S) TestTokenize(c *C) {
+	c.Assert(tfidf.Tokenize("Hello, World! snap-2 ção"), DeepEquals, []string{"hello", "world", "snap", "2", "ção"})
+	c.Assert(tfidf.Tokenize(" ,. "), HasLen, 0)
+}
+
+func (s *S) TestIDF(c *C) {
+	var corpus tfidf.Corpus
+	corpus.Add(tfidf.Tokenize("the cat sat"))
+	corpus.Add(tfidf.Tokenize("the dog sat on the dog"))
+	corpus.Add(tfidf.Tokenize("the bird"))
+	c.Assert(corpus.Len(), Equals, 3)
+	c.Assert(corpus.IDF("the"), Equals, 1.0)
+	c.Assert(corpus.IDF("dog"), Equals, math.Log(2)+1)
+	c.Assert(corpus.IDF("sat") < corpus.IDF("dog"), Equals, true)
+	c.Assert(corpus.IDF("fish") > corpus.IDF("dog"), Equals, true)
+
+	v := corpus.Vector(tfidf.Tokenize("the dog the dog"))
+	c.Assert(v, DeepEquals, tfidf.Vector{{"dog", 2 * corpus.IDF("dog")}, {"the", 2}})
+}
+
+func (s *S) TestCosine(c *C) {
+	var corpus tfidf.Corpus
+	docs := []string{
+		"install the snap package",
+		"remove the snap package",
+		"configure network interfaces",
+	}
+	var vectors []tfidf.Vector
+	for _, doc := range docs {
+		corpus.Add(tfidf.Tokenize(doc))
+	}
+	for _, doc := range docs {
+		vectors = append(vectors, corpus.Vector(tfidf.Tokenize(doc)))
+	}
+	c.Assert(tfidf.Cosine(vectors[0], vectors[0]), Equals, 1.0)
+	c.Assert(tfidf.Cosine(vectors[0], vectors[2]), Equals, 0.0)
+	c.Assert(tfidf.Distance(vectors[0], vectors[2]), Equals, 1.0)
+	similar := tfidf.Cosine(vectors[0], vectors[1])
+	c.Assert(similar > 0 && similar < 1, Equals, true)
+	c.Assert(tfidf.Cosine(vectors[1], vectors[0]), Equals, similar)
+	c.Assert(tfidf.Cosine(nil, vectors[0]), Equals, 0.0)
+}
@@ -0,0 +1,111 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package topk
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// Select reorders items in place so that items[k] holds the item that
+// would be there if items were sorted, with no item before it being higher
+// and no item after it being lower. The k lowest items are then the first
+// ones, in no particular order.
+//
+// This is quickselect, running in O(n) time on average. Pivots are the
+// median of three items, and ranges that take too many rounds to narrow
+// down are sorted instead, so the worst case is O(n log n). Select panics
+// if k is out of range.
+func Select[T any](items []T, k int, less func(a, b T) bool) {
+	if k < 0 || k >= len(items) {
+		panic(fmt.Sprintf("topk: index %d is out of range for %d items", k, len(items)))
+	}
+	lo, hi := 0, len(items)
+	rounds := 2 * bits.Len(uint(len(items)))
+	for hi-lo > 1 {
+		if rounds == 0 {
+			sub := items[lo:hi]
+			sort.Slice(sub, func(i, j int) bool { return less(sub[i], sub[j]) })
+			return
+		}
+		rounds--
+		lt, gt := partition(items, lo, hi, less)
+		switch {
+		case k < lt:
+			hi = lt
+		case k >= gt:
+			lo = gt
+		default:
+			return
+		}
+	}
+}
+
+// partition reorders the items from lo up to hi around a pivot, so that
+// the items from lt up to gt are equal to it, the ones before are lower,
+// and the ones after are higher.
+func partition[T any](items []T, lo, hi int, less func(a, b T) bool) (lt, gt int) {
+	mid := lo + (hi-lo)/2
+	last := hi - 1
+	// Order the first, middle, and last items, so the middle is the median.
+	if less(items[mid], items[lo]) {
+		items[mid], items[lo] = items[lo], items[mid]
+	}
+	if less(items[last], items[mid]) {
+		items[last], items[mid] = items[mid], items[last]
+		if less(items[mid], items[lo]) {
+			items[mid], items[lo] = items[lo], items[mid]
+		}
+	}
+	pivot := items[mid]
+
+	// Dutch national flag partitioning, which keeps items equal to the
+	// pivot together so that many equal items don't slow it down.
+	lt, i, gt := lo, lo, hi
+	for i < gt {
+		switch {
+		case less(items[i], pivot):
+			items[lt], items[i] = items[i], items[lt]
+			lt++
+			i++
+		case less(pivot, items[i]):
+			gt--
+			items[gt], items[i] = items[i], items[gt]
+		default:
+			i++
+		}
+	}
+	return lt, gt
+}
+
+// Best returns the k lowest items, from lowest to highest, or all of them
+// if there are fewer than k. Items are not modified. It runs in
+// O(n + k log k) time on average.
+func Best[T any](items []T, k int, less func(a, b T) bool) []T {
+	if k < 0 {
+		panic(fmt.Sprintf("topk: negative k %d", k))
+	}
+	best := append([]T(nil), items...)
+	if k < len(best) {
+		if k > 0 {
+			Select(best, k-1, less)
+		}
+		best = best[:k:k]
+	}
+	sort.Slice(best, func(i, j int) bool { return less(best[i], best[j]) })
+	return best
+}
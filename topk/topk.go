@@ -0,0 +1,151 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package topk selects the k best items without sorting all of them,
+// either from a stream of items of unknown length with a Selector, or
+// from a slice with Select and Best.
+//
+// Items are ordered by a less function, and the best items are the
+// lowest ones, such as the candidates closest to a query.
+package topk
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Selector keeps the k best items pushed into it, in O(log k) time per
+// item and O(k) space. When several items are equally good, those pushed
+// first are kept.
+//
+// The worst item kept is a bound that pushed items must beat, so it may
+// be used to give up early on computing candidates that can't make it,
+// such as with listdist.DistanceBounded.
+type Selector[T any] struct {
+	k    int
+	less func(a, b T) bool
+	seq  int
+
+	// heap holds the items kept with the worst one at the root.
+	heap []ranked[T]
+}
+
+type ranked[T any] struct {
+	item T
+	seq  int
+}
+
+// NewSelector returns a selector keeping the k lowest items according to
+// less. It panics if k is negative.
+func NewSelector[T any](k int, less func(a, b T) bool) *Selector[T] {
+	if k < 0 {
+		panic(fmt.Sprintf("topk: negative k %d", k))
+	}
+	return &Selector[T]{k: k, less: less}
+}
+
+// worse reports whether a is worse than b, which is the case if b is
+// lower or if they're equal and a was pushed later.
+func (s *Selector[T]) worse(a, b ranked[T]) bool {
+	if s.less(b.item, a.item) {
+		return true
+	}
+	return !s.less(a.item, b.item) && a.seq > b.seq
+}
+
+// Push offers item to the selector, and reports whether it's kept, which
+// may cause the worst item kept so far to be dropped.
+func (s *Selector[T]) Push(item T) bool {
+	r := ranked[T]{item, s.seq}
+	s.seq++
+	if len(s.heap) < s.k {
+		s.heap = append(s.heap, r)
+		s.up(len(s.heap) - 1)
+		return true
+	}
+	if s.k == 0 || !s.worse(s.heap[0], r) {
+		return false
+	}
+	s.heap[0] = r
+	s.down(0)
+	return true
+}
+
+// Len returns the number of items kept, which is at most k.
+func (s *Selector[T]) Len() int {
+	return len(s.heap)
+}
+
+// Full reports whether k items are kept, so that further items must beat
+// the one returned by Worst to be kept.
+func (s *Selector[T]) Full() bool {
+	return len(s.heap) == s.k
+}
+
+// Worst returns the worst item kept, and false if there are none.
+func (s *Selector[T]) Worst() (item T, ok bool) {
+	if len(s.heap) == 0 {
+		return item, false
+	}
+	return s.heap[0].item, true
+}
+
+// Items returns the items kept, from best to worst, with equally good
+// items in the order they were pushed.
+func (s *Selector[T]) Items() []T {
+	sorted := append([]ranked[T](nil), s.heap...)
+	sort.Slice(sorted, func(i, j int) bool { return s.worse(sorted[j], sorted[i]) })
+	items := make([]T, len(sorted))
+	for i, r := range sorted {
+		items[i] = r.item
+	}
+	return items
+}
+
+// Reset drops every item kept, so the selector may be reused.
+func (s *Selector[T]) Reset() {
+	clear(s.heap)
+	s.heap = s.heap[:0]
+	s.seq = 0
+}
+
+func (s *Selector[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !s.worse(s.heap[i], s.heap[parent]) {
+			break
+		}
+		s.heap[i], s.heap[parent] = s.heap[parent], s.heap[i]
+		i = parent
+	}
+}
+
+func (s *Selector[T]) down(i int) {
+	n := len(s.heap)
+	for {
+		child := 2*i + 1
+		if child >= n {
+			return
+		}
+		if right := child + 1; right < n && s.worse(s.heap[right], s.heap[child]) {
+			child = right
+		}
+		if !s.worse(s.heap[child], s.heap[i]) {
+			return
+		}
+		s.heap[i], s.heap[child] = s.heap[child], s.heap[i]
+		i = child
+	}
+}
@@ -0,0 +1,122 @@
+package topk_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/canonical/go-algo/topk"
+
+	. "gopkg.in/check.v1"
+)
+
+func intLess(a, b int) bool { return a < b }
+
+func (*S) TestSelector(c *C) {
+	s := topk.NewSelector(3, intLess)
+	_, ok := s.Worst()
+	c.Assert(ok, Equals, false)
+	for _, item := range []int{5, 1, 9} {
+		c.Assert(s.Push(item), Equals, true)
+	}
+	c.Assert(s.Full(), Equals, true)
+	worst, _ := s.Worst()
+	c.Assert(worst, Equals, 9)
+	c.Assert(s.Push(9), Equals, false)
+	c.Assert(s.Push(2), Equals, true)
+	c.Assert(s.Push(7), Equals, false)
+	c.Assert(s.Items(), DeepEquals, []int{1, 2, 5})
+	c.Assert(s.Len(), Equals, 3)
+
+	s.Reset()
+	c.Assert(s.Len(), Equals, 0)
+	c.Assert(topk.NewSelector(0, intLess).Push(1), Equals, false)
+	c.Assert(func() { topk.NewSelector(-1, intLess) }, PanicMatches, "topk: negative k -1")
+}
+
+type candidate struct {
+	name     string
+	distance int
+}
+
+func (*S) TestSelectorTies(c *C) {
+	s := topk.NewSelector(2, func(a, b candidate) bool { return a.distance < b.distance })
+	for _, name := range []string{"a", "b", "c", "d"} {
+		s.Push(candidate{name, 1})
+	}
+	s.Push(candidate{"e", 0})
+	c.Assert(s.Items(), DeepEquals, []candidate{{"e", 0}, {"a", 1}})
+}
+
+func (*S) TestSelect(c *C) {
+	items := []int{7, 3, 9, 1, 3, 8, 2}
+	topk.Select(items, 2, intLess)
+	c.Assert(items[2], Equals, 3)
+	for _, item := range items[:2] {
+		c.Assert(item <= 3, Equals, true)
+	}
+	for _, item := range items[3:] {
+		c.Assert(item >= 3, Equals, true)
+	}
+	c.Assert(func() { topk.Select(items, 7, intLess) }, PanicMatches, "topk: index 7 is out of range for 7 items")
+
+	c.Assert(topk.Best([]int{4, 2, 8, 6}, 2, intLess), DeepEquals, []int{2, 4})
+	c.Assert(topk.Best([]int{4, 2}, 5, intLess), DeepEquals, []int{2, 4})
+	c.Assert(topk.Best([]int{4, 2}, 0, intLess), HasLen, 0)
+}
+
+func (*S) TestRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		n := 1 + rnd.Intn(100)
+		k := rnd.Intn(n + 3)
+		items := make([]int, n)
+		for j := range items {
+			// Few distinct values make for many ties.
+			items[j] = rnd.Intn(1 + i%50)
+		}
+		c.Logf("Test %d: %d items, k=%d", i, n, k)
+		sorted := append([]int(nil), items...)
+		sort.Ints(sorted)
+		want := sorted[:min(k, n)]
+
+		s := topk.NewSelector(k, intLess)
+		for _, item := range items {
+			s.Push(item)
+		}
+		c.Assert(s.Items(), DeepEquals, want)
+		c.Assert(topk.Best(items, k, intLess), DeepEquals, want)
+
+		if k < n {
+			selected := append([]int(nil), items...)
+			topk.Select(selected, k, intLess)
+			c.Assert(selected[k], Equals, sorted[k])
+			for j, item := range selected {
+				c.Assert(j < k && item <= sorted[k] || j >= k && item >= sorted[k], Equals, true)
+			}
+		}
+	}
+
+	// Sorted input with equal items is the worst case for naive pivots.
+	items := make([]int, 10000)
+	for j := range items {
+		items[j] = j / 100
+	}
+	topk.Select(items, 5000, intLess)
+	c.Assert(items[5000], Equals, 50)
+}
+
+func BenchmarkSelector(b *testing.B) {
+	rnd := rand.New(rand.NewSource(42))
+	items := make([]int, 100000)
+	for i := range items {
+		items[i] = rnd.Int()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := topk.NewSelector(10, intLess)
+		for _, item := range items {
+			s.Push(item)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+//
+// Copyright (c) 2025 Canonical Ltd
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package treedist computes the edit distance between ordered trees, such
+// as parsed documents, where nodes may be relabeled, deleted with their
+// children moving up to the parent, or inserted taking over a run of
+// siblings as children.
+//
+// Unlike flattening the trees and pairing their nodes with the assign
+// package, the edits found always preserve the ancestry and the order of
+// the nodes kept, and their total cost is the lowest possible.
+package treedist
+
+import (
+	"fmt"
+)
+
+// Node is a node of an ordered tree, with its children in order.
+type Node[T any] struct {
+	Label    T
+	Children []*Node[T]
+}
+
+// CostFunc returns the cost of relabeling node a as node b, of deleting a
+// if b is nil, or of inserting b if a is nil. Costs must not be negative.
+type CostFunc[T any] func(a, b *Node[T]) int64
+
+// StandardCost charges one for every insertion and deletion, and for
+// relabeling nodes with different labels.
+func StandardCost[T comparable](a, b *Node[T]) int64 {
+	if a != nil && b != nil && a.Label == b.Label {
+		return 0
+	}
+	return 1
+}
+
+// Op classifies the edit represented by an Edit.
+type Op int
+
+const (
+	// Keep pairs a node of a with a node of b at no cost.
+	Keep Op = iota + 1
+	// Update pairs a node of a with a node of b it is relabeled as.
+	Update
+	// Insert adds a node of b.
+	Insert
+	// Delete removes a node of a.
+	Delete
+)
+
+var opNames = []string{"", "keep", "update", "insert", "delete"}
+
+func (op Op) String() string {
+	if op > 0 && int(op) < len(opNames) {
+		return opNames[op]
+	}
+	return fmt.Sprintf("Op(%d)", int(op))
+}
+
+// Edit is an edit in a script turning tree a into tree b. A is nil for
+// insertions, and B is nil for deletions.
+type Edit[T any] struct {
+	A, B *Node[T]
+	Cost int64
+	Op   Op
+}
+
+// Distance returns the lowest total cost of the edits turning tree a into
+// tree b, with the cost of each edit computed by f. Either tree may be nil
+// for an empty tree.
+//
+// This is the algorithm by Zhang and Shasha in "Simple fast algorithms for
+// the editing distance between trees and related problems" (1989). For
+// trees with n and m nodes it takes O(n·m) space and O(n·m·h²) time in the
+// worst case, where h is the smallest of the depth and the number of
+// leaves among both trees.
+func Distance[T any](a, b *Node[T], f CostFunc[T]) int64 {
+	z := newZhangShasha(a, b, f)
+	if len(z.a.nodes) == 0 || len(z.b.nodes) == 0 {
+		return sum(z.del) + sum(z.ins)
+	}
+	z.solve()
+	return z.td[len(z.td)-1]
+}
+
+// Diff returns the edits of lowest total cost turning tree a into tree b,
+// as computed by Distance. Nodes paired with each other come first, then
+// deletions and then insertions, each ordered by the position of the
+// nodes in a postorder traversal of their tree.
+//
+// The nodes paired have the same ancestry and order in both trees: if
+// a node of a is paired with a node of b, the ancestors of one are only
+// paired with ancestors of the other, and nodes to their left are only
+// paired with nodes to their left.
+func Diff[T any](a, b *Node[T], f CostFunc[T]) []Edit[T] {
+	z := newZhangShasha(a, b, f)
+	n, m := len(z.a.nodes), len(z.b.nodes)
+	pairs := make([]int, n)
+	for i := range pairs {
+		pairs[i] = -1
+	}
+	paired := make([]bool, m)
+	if n > 0 && m > 0 {
+		z.solve()
+		z.traceback(pairs, paired)
+	}
+
+	var edits []Edit[T]
+	for i, j := range pairs {
+		if j < 0 {
+			continue
+		}
+		edit := Edit[T]{A: z.a.nodes[i], B: z.b.nodes[j], Op: Update}
+		edit.Cost = f(edit.A, edit.B)
+		if edit.Cost == 0 {
+			edit.Op = Keep
+		}
+		edits = append(edits, edit)
+	}
+	for i, j := range pairs {
+		if j < 0 {
+			edits = append(edits, Edit[T]{A: z.a.nodes[i], Cost: z.del[i], Op: Delete})
+		}
+	}
+	for j, ok := range paired {
+		if !ok {
+			edits = append(edits, Edit[T]{B: z.b.nodes[j], Cost: z.ins[j], Op: Insert})
+		}
+	}
+	return edits
+}
+
+// tree holds the nodes of a tree in postorder.
+type tree[T any] struct {
+	nodes []*Node[T]
+
+	// leftmost[i] is the index of the leftmost leaf under nodes[i].
+	leftmost []int
+
+	// keyroots are the nodes with no ancestor sharing their leftmost
+	// leaf, in increasing order.
+	keyroots []int
+}
+
+func newTree[T any](root *Node[T]) tree[T] {
+	var t tree[T]
+	if root == nil {
+		return t
+	}
+	t.add(root)
+	seen := make(map[int]bool)
+	for i := len(t.nodes) - 1; i >= 0; i-- {
+		if !seen[t.leftmost[i]] {
+			seen[t.leftmost[i]] = true
+			t.keyroots = append(t.keyroots, i)
+		}
+	}
+	for i, j := 0, len(t.keyroots)-1; i < j; i, j = i+1, j-1 {
+		t.keyroots[i], t.keyroots[j] = t.keyroots[j], t.keyroots[i]
+	}
+	return t
+}
+
+// add appends node and its descendants to t in postorder, returning the
+// index of its leftmost leaf.
+func (t *tree[T]) add(node *Node[T]) int {
+	leftmost := -1
+	for i, child := range node.Children {
+		l := t.add(child)
+		if i == 0 {
+			leftmost = l
+		}
+	}
+	if leftmost < 0 {
+		leftmost = len(t.nodes)
+	}
+	t.nodes = append(t.nodes, node)
+	t.leftmost = append(t.leftmost, leftmost)
+	return leftmost
+}
+
+type zhangShasha[T any] struct {
+	a, b tree[T]
+	f    CostFunc[T]
+
+	// del and ins are the costs of deleting the nodes of a and inserting
+	// the nodes of b.
+	del, ins []int64
+
+	// td[i*len(b.nodes)+j] is the distance between the subtree under
+	// a.nodes[i] and the subtree under b.nodes[j].
+	td []int64
+
+	// fd holds the distances between the forests of the subtrees under
+	// the nodes last passed to forest, with cols columns.
+	fd   []int64
+	cols int
+}
+
+func newZhangShasha[T any](a, b *Node[T], f CostFunc[T]) *zhangShasha[T] {
+	z := &zhangShasha[T]{a: newTree(a), b: newTree(b), f: f}
+	z.del = make([]int64, len(z.a.nodes))
+	for i, node := range z.a.nodes {
+		z.del[i] = f(node, nil)
+	}
+	z.ins = make([]int64, len(z.b.nodes))
+	for j, node := range z.b.nodes {
+		z.ins[j] = f(nil, node)
+	}
+	return z
+}
+
+// solve computes the distance between every pair of subtrees.
+func (z *zhangShasha[T]) solve() {
+	z.td = make([]int64, len(z.a.nodes)*len(z.b.nodes))
+	for _, i := range z.a.keyroots {
+		for _, j := range z.b.keyroots {
+			z.forest(i, j)
+		}
+	}
+}
+
+// forest fills z.fd with the distances between the forests made of the
+// nodes of a from leftmost[i] up to each node under i and the same for
+// the nodes of b under j, recording in z.td the distances between the
+// subtrees found along the way. Entry (x, y) of z.fd is for the first x
+// nodes from leftmost[i] and the first y nodes from leftmost[j].
+func (z *zhangShasha[T]) forest(i, j int) {
+	li, lj := z.a.leftmost[i], z.b.leftmost[j]
+	rows, cols := i-li+2, j-lj+2
+	if cap(z.fd) < rows*cols {
+		z.fd = make([]int64, rows*cols)
+	}
+	fd := z.fd[:rows*cols]
+	z.cols = cols
+	m := len(z.b.nodes)
+
+	fd[0] = 0
+	for x := 1; x < rows; x++ {
+		fd[x*cols] = fd[(x-1)*cols] + z.del[li+x-1]
+	}
+	for y := 1; y < cols; y++ {
+		fd[y] = fd[y-1] + z.ins[lj+y-1]
+	}
+	for x := 1; x < rows; x++ {
+		ax := li + x - 1
+		for y := 1; y < cols; y++ {
+			by := lj + y - 1
+			best := min(fd[(x-1)*cols+y]+z.del[ax], fd[x*cols+y-1]+z.ins[by])
+			if z.a.leftmost[ax] == li && z.b.leftmost[by] == lj {
+				// Both forests are whole subtrees, so their roots may
+				// be paired.
+				best = min(best, fd[(x-1)*cols+y-1]+z.f(z.a.nodes[ax], z.b.nodes[by]))
+				z.td[ax*m+by] = best
+			} else {
+				px, py := z.a.leftmost[ax]-li, z.b.leftmost[by]-lj
+				best = min(best, fd[px*cols+py]+z.td[ax*m+by])
+			}
+			fd[x*cols+y] = best
+		}
+	}
+}
+
+// traceback sets pairs[i] to the node of b paired with node i of a, and
+// paired[j] for the nodes of b paired, in a script of lowest cost.
+//
+// The script is found by walking back through the forest distances of the
+// whole trees, and then those of every pair of subtrees found to be mapped
+// onto each other, which are computed again as needed.
+func (z *zhangShasha[T]) traceback(pairs []int, paired []bool) {
+	m := len(z.b.nodes)
+	stack := [][2]int{{len(z.a.nodes) - 1, m - 1}}
+	for len(stack) > 0 {
+		i, j := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+		z.forest(i, j)
+		fd, cols := z.fd, z.cols
+		li, lj := z.a.leftmost[i], z.b.leftmost[j]
+		x, y := i-li+1, j-lj+1
+		for x > 0 || y > 0 {
+			ax, by := li+x-1, lj+y-1
+			current := fd[x*cols+y]
+			if x > 0 && y > 0 {
+				if z.a.leftmost[ax] == li && z.b.leftmost[by] == lj {
+					if current == fd[(x-1)*cols+y-1]+z.f(z.a.nodes[ax], z.b.nodes[by]) {
+						pairs[ax] = by
+						paired[by] = true
+						x--
+						y--
+						continue
+					}
+				} else {
+					px, py := z.a.leftmost[ax]-li, z.b.leftmost[by]-lj
+					if current == fd[px*cols+py]+z.td[ax*m+by] {
+						stack = append(stack, [2]int{ax, by})
+						x, y = px, py
+						continue
+					}
+				}
+			}
+			if x > 0 && current == fd[(x-1)*cols+y]+z.del[ax] {
+				x--
+			} else {
+				y--
+			}
+		}
+	}
+}
+
+func sum(costs []int64) int64 {
+	var total int64
+	for _, cost := range costs {
+		total += cost
+	}
+	return total
+}
@@ -0,0 +1,253 @@
+package treedist_test
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/go-algo/treedist"
+)
+
+type node = treedist.Node[string]
+
+// parse builds a tree from text such as "f(d(a c(b)) e)".
+func parse(text string) *node {
+	if text == "" {
+		return nil
+	}
+	var stack [][]*node
+	var top []*node
+	var label strings.Builder
+	flush := func() {
+		if label.Len() > 0 {
+			top = append(top, &node{Label: label.String()})
+			label.Reset()
+		}
+	}
+	for _, r := range text {
+		switch r {
+		case '(':
+			flush()
+			stack = append(stack, top)
+			top = nil
+		case ')':
+			flush()
+			children := top
+			top = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			top[len(top)-1].Children = children
+		case ' ':
+			flush()
+		default:
+			label.WriteRune(r)
+		}
+	}
+	flush()
+	return top[0]
+}
+
+func format(n *node) string {
+	if n == nil {
+		return "-"
+	}
+	if len(n.Children) == 0 {
+		return n.Label
+	}
+	var children []string
+	for _, child := range n.Children {
+		children = append(children, format(child))
+	}
+	return n.Label + "(" + strings.Join(children, " ") + ")"
+}
+
+var distanceTests = []struct {
+	a, b  string
+	dist  int64
+	edits []string
+}{{
+	a:     "",
+	b:     "",
+	dist:  0,
+	edits: nil,
+}, {
+	a:     "a(b c)",
+	b:     "",
+	dist:  3,
+	edits: []string{"delete b -", "delete c -", "delete a(b c) -"},
+}, {
+	a:     "",
+	b:     "a(b)",
+	dist:  2,
+	edits: []string{"insert - b", "insert - a(b)"},
+}, {
+	a:     "a(b c)",
+	b:     "a(b c)",
+	dist:  0,
+	edits: []string{"keep b b", "keep c c", "keep a(b c) a(b c)"},
+}, {
+	a:     "f(d(a c(b)) e)",
+	b:     "f(c(d(a b)) e)",
+	dist:  2,
+	edits: []string{"keep a a", "keep b b", "keep d(a c(b)) d(a b)", "keep e e", "keep f(d(a c(b)) e) f(c(d(a b)) e)", "delete c(b) -", "insert - c(d(a b))"},
+}, {
+	a:     "a(b c d)",
+	b:     "a(b x(c d))",
+	dist:  1,
+	edits: []string{"keep b b", "keep c c", "keep d d", "keep a(b c d) a(b x(c d))", "insert - x(c d)"},
+}, {
+	a:     "a(b c)",
+	b:     "x(b y)",
+	dist:  2,
+	edits: []string{"keep b b", "update c y", "update a(b c) x(b y)"},
+}}
+
+func (s *S) TestDistance(c *C) {
+	for _, test := range distanceTests {
+		c.Logf("Test: %s => %s", test.a, test.b)
+		a, b := parse(test.a), parse(test.b)
+		c.Assert(treedist.Distance(a, b, treedist.StandardCost[string]), Equals, test.dist)
+		var edits []string
+		for _, edit := range treedist.Diff(a, b, treedist.StandardCost[string]) {
+			edits = append(edits, fmt.Sprintf("%s %s %s", edit.Op, format(edit.A), format(edit.B)))
+		}
+		c.Assert(edits, DeepEquals, test.edits)
+	}
+	c.Assert(treedist.Keep.String(), Equals, "keep")
+	c.Assert(treedist.Op(42).String(), Equals, "Op(42)")
+}
+
+// forestDistance computes the distance between forests using the
+// recursive definition directly.
+func forestDistance(f, g []*node, cost treedist.CostFunc[string]) int64 {
+	if len(f) == 0 && len(g) == 0 {
+		return 0
+	}
+	var best int64 = 1 << 62
+	if len(f) > 0 {
+		v := f[len(f)-1]
+		rest := append(append([]*node(nil), f[:len(f)-1]...), v.Children...)
+		best = min(best, forestDistance(rest, g, cost)+cost(v, nil))
+	}
+	if len(g) > 0 {
+		w := g[len(g)-1]
+		rest := append(append([]*node(nil), g[:len(g)-1]...), w.Children...)
+		best = min(best, forestDistance(f, rest, cost)+cost(nil, w))
+	}
+	if len(f) > 0 && len(g) > 0 {
+		v, w := f[len(f)-1], g[len(g)-1]
+		best = min(best, forestDistance(v.Children, w.Children, cost)+forestDistance(f[:len(f)-1], g[:len(g)-1], cost)+cost(v, w))
+	}
+	return best
+}
+
+func randomTree(rnd *rand.Rand, size int) *node {
+	if size == 0 {
+		return nil
+	}
+	nodes := []*node{{Label: string(rune('a' + rnd.Intn(3)))}}
+	for len(nodes) < size {
+		child := &node{Label: string(rune('a' + rnd.Intn(3)))}
+		parent := nodes[rnd.Intn(len(nodes))]
+		parent.Children = append(parent.Children, child)
+		nodes = append(nodes, child)
+	}
+	return nodes[0]
+}
+
+// weightedCost makes deletions and insertions of some labels cheaper
+// than relabeling them.
+func weightedCost(a, b *node) int64 {
+	switch {
+	case a == nil:
+		return int64(len(b.Label)) + 1
+	case b == nil:
+		return 2
+	case a.Label == b.Label:
+		return 0
+	}
+	return 3
+}
+
+// postorder returns the nodes under n in postorder, and for each of them
+// the position of the leftmost leaf under it.
+func postorder(n *node, nodes []*node, leftmost []int) ([]*node, []int) {
+	if n == nil {
+		return nodes, leftmost
+	}
+	first := -1
+	for _, child := range n.Children {
+		if first < 0 {
+			first = len(nodes)
+		}
+		nodes, leftmost = postorder(child, nodes, leftmost)
+	}
+	if first < 0 {
+		first = len(nodes)
+	} else {
+		first = leftmost[first]
+	}
+	return append(nodes, n), append(leftmost, first)
+}
+
+func (s *S) TestDistanceRandom(c *C) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 300; i++ {
+		a := randomTree(rnd, rnd.Intn(7))
+		b := randomTree(rnd, rnd.Intn(7))
+		for _, cost := range []treedist.CostFunc[string]{treedist.StandardCost[string], weightedCost} {
+			c.Logf("Test: %s => %s", format(a), format(b))
+			var f, g []*node
+			if a != nil {
+				f = []*node{a}
+			}
+			if b != nil {
+				g = []*node{b}
+			}
+			dist := treedist.Distance(a, b, cost)
+			c.Assert(dist, Equals, forestDistance(f, g, cost))
+
+			// The edits must add up to the distance, and cover every
+			// node once, with pairs preserving ancestry and order.
+			aNodes, aLeft := postorder(a, nil, nil)
+			bNodes, bLeft := postorder(b, nil, nil)
+			aIndex := make(map[*node]int)
+			for i, n := range aNodes {
+				aIndex[n] = i
+			}
+			bIndex := make(map[*node]int)
+			for i, n := range bNodes {
+				bIndex[n] = i
+			}
+			var total int64
+			var pairs [][2]int
+			seen := make(map[*node]bool)
+			for _, edit := range treedist.Diff(a, b, cost) {
+				total += edit.Cost
+				c.Assert(edit.Cost, Equals, cost(edit.A, edit.B))
+				if edit.A != nil {
+					c.Assert(seen[edit.A], Equals, false)
+					seen[edit.A] = true
+				}
+				if edit.B != nil {
+					c.Assert(seen[edit.B], Equals, false)
+					seen[edit.B] = true
+				}
+				if edit.A != nil && edit.B != nil {
+					pairs = append(pairs, [2]int{aIndex[edit.A], bIndex[edit.B]})
+				}
+			}
+			c.Assert(total, Equals, dist)
+			c.Assert(seen, HasLen, len(aNodes)+len(bNodes))
+			for _, p := range pairs {
+				for _, q := range pairs {
+					aAncestor := aLeft[p[0]] <= q[0] && q[0] < p[0]
+					bAncestor := bLeft[p[1]] <= q[1] && q[1] < p[1]
+					c.Assert(aAncestor, Equals, bAncestor)
+					c.Assert(p[0] < q[0], Equals, p[1] < q[1])
+				}
+			}
+		}
+	}
+}